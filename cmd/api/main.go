@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -30,6 +32,12 @@ func main() {
 
 	cfg := config.Load()
 
+	if cfg.EventTypesFile != "" {
+		if err := config.LoadEventTypes(cfg.EventTypesFile); err != nil {
+			log.Fatalf("Failed to load event types: %v", err)
+		}
+	}
+
 	db, err := config.NewPostgresDB(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -48,7 +56,11 @@ func main() {
 	}
 
 	repos := repository.NewRepositories(db)
+	backfillNameKeys(repos)
 	services := service.NewServices(repos, redis, minioClient, cfg)
+	importGazetteer(services)
+	go dispatchOutbox(services)
+	go expireChangeRequests(services)
 	handlers := handler.NewHandlers(services)
 
 	app := fiber.New(fiber.Config{
@@ -82,6 +94,59 @@ func main() {
 	}
 }
 
+// backfillNameKeys computes the name search keys of rows stored before the
+// keys were introduced. Later writes keep them up to date.
+func backfillNameKeys(repos *repository.Repositories) {
+	ctx := context.Background()
+	if n, err := repos.Person.BackfillNameKeys(ctx); err != nil {
+		log.Printf("Warning: Failed to backfill person name keys: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled name keys for %d persons", n)
+	}
+	if n, err := repos.PersonName.BackfillNameKeys(ctx); err != nil {
+		log.Printf("Warning: Failed to backfill alternate name keys: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled name keys for %d alternate names", n)
+	}
+}
+
+// importGazetteer seeds the places table from the bundled Indonesian
+// gazetteer. Regions already present are left untouched.
+func importGazetteer(services *service.Services) {
+	if n, err := services.Place.ImportGazetteer(context.Background()); err != nil {
+		log.Printf("Warning: Failed to import place gazetteer: %v", err)
+	} else if n > 0 {
+		log.Printf("Imported %d places from the gazetteer", n)
+	}
+}
+
+// dispatchOutbox periodically delivers notifications that were not
+// delivered right after their transaction committed, e.g. because the
+// server stopped or delivery failed.
+func dispatchOutbox(services *service.Services) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := services.Outbox.Dispatch(context.Background()); err != nil {
+			log.Printf("Warning: Failed to dispatch notification outbox: %v", err)
+		}
+	}
+}
+
+// expireChangeRequests periodically closes change requests that saw no
+// activity within CHANGE_REQUEST_EXPIRY.
+func expireChangeRequests(services *service.Services) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if n, err := services.ChangeRequest.ExpireStale(context.Background()); err != nil {
+			log.Printf("Warning: Failed to expire change requests: %v", err)
+		} else if n > 0 {
+			log.Printf("Expired %d stale change requests", n)
+		}
+	}
+}
+
 func setupRoutes(app *fiber.App, h *handler.Handlers, authService auth.Service) {
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "ok"})
@@ -114,9 +179,24 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, authService auth.Service)
 	persons.Post("/", middleware.RequireRole("editor"), h.Person.Create)
 	persons.Get("/", h.Person.List)
 	persons.Get("/search", h.Person.Search)
+	persons.Get("/duplicates", middleware.RequireRole("editor"), h.Duplicate.Report)
+	persons.Get("/living-status", middleware.RequireRole("editor"), h.Living.Report)
+	persons.Post("/living-status/correct", middleware.RequireRole("developer"), h.Living.Correct)
 	persons.Get("/:personId", h.Person.Get)
 	persons.Put("/:personId", middleware.RequireRole("editor"), h.Person.Update)
 	persons.Delete("/:personId", middleware.RequireRole("editor"), h.Person.Delete)
+	persons.Get("/:personId/events", h.Event.ListByPerson)
+	persons.Get("/:personId/timeline", h.Timeline.GetPersonTimeline)
+	persons.Get("/:personId/possible-duplicates", h.Duplicate.ListForPerson)
+	persons.Get("/:personId/living-status", h.Living.Get)
+	persons.Post("/:personId/merge", middleware.RequireRole("member"), h.Person.Merge)
+	persons.Get("/:personId/names", h.PersonName.List)
+	persons.Post("/:personId/names", middleware.RequireRole("editor"), h.PersonName.Create)
+	persons.Put("/:personId/names/:nameId", middleware.RequireRole("editor"), h.PersonName.Update)
+	persons.Delete("/:personId/names/:nameId", middleware.RequireRole("editor"), h.PersonName.Delete)
+	persons.Get("/:personId/custom-fields", h.CustomField.ListPersonValues)
+	persons.Put("/:personId/custom-fields/:fieldId", middleware.RequireRole("editor"), h.CustomField.SetPersonValue)
+	persons.Delete("/:personId/custom-fields/:fieldId", middleware.RequireRole("editor"), h.CustomField.DeletePersonValue)
 
 	relationships := protected.Group("/relationships")
 	relationships.Post("/", middleware.RequireRole("editor"), h.Relationship.Create)
@@ -125,11 +205,52 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, authService auth.Service)
 	relationships.Put("/:relationshipId", middleware.RequireRole("editor"), h.Relationship.Update)
 	relationships.Delete("/:relationshipId", middleware.RequireRole("editor"), h.Relationship.Delete)
 
+	events := protected.Group("/events")
+	events.Get("/types", h.Event.ListTypes)
+	events.Post("/", middleware.RequireRole("editor"), h.Event.Create)
+	events.Get("/:eventId", h.Event.Get)
+	events.Put("/:eventId", middleware.RequireRole("editor"), h.Event.Update)
+	events.Delete("/:eventId", middleware.RequireRole("editor"), h.Event.Delete)
+
+	places := protected.Group("/places")
+	places.Get("/", h.Place.List)
+	places.Get("/search", h.Place.Search)
+	places.Post("/", middleware.RequireRole("editor"), h.Place.Create)
+	places.Get("/:placeId", h.Place.Get)
+	places.Put("/:placeId", middleware.RequireRole("editor"), h.Place.Update)
+	places.Delete("/:placeId", middleware.RequireRole("editor"), h.Place.Delete)
+
+	sources := protected.Group("/sources")
+	sources.Get("/", h.Source.ListSources)
+	sources.Post("/", middleware.RequireRole("editor"), h.Source.CreateSource)
+	sources.Get("/:sourceId", h.Source.GetSource)
+	sources.Put("/:sourceId", middleware.RequireRole("editor"), h.Source.UpdateSource)
+	sources.Delete("/:sourceId", middleware.RequireRole("editor"), h.Source.DeleteSource)
+	sources.Get("/:sourceId/citations", h.Source.ListSourceCitations)
+
+	citations := protected.Group("/citations")
+	citations.Get("/", h.Source.ListCitations)
+	citations.Post("/", middleware.RequireRole("editor"), h.Source.CreateCitation)
+	citations.Put("/:citationId", middleware.RequireRole("editor"), h.Source.UpdateCitation)
+	citations.Delete("/:citationId", middleware.RequireRole("editor"), h.Source.DeleteCitation)
+
+	customFields := protected.Group("/custom-fields")
+	customFields.Get("/", h.CustomField.ListFields)
+	customFields.Post("/", middleware.RequireRole("developer"), h.CustomField.CreateField)
+	customFields.Put("/:fieldId", middleware.RequireRole("developer"), h.CustomField.UpdateField)
+	customFields.Delete("/:fieldId", middleware.RequireRole("developer"), h.CustomField.DeleteField)
+
+	trash := protected.Group("/trash", middleware.RequireRole("editor"))
+	trash.Get("/", h.Trash.List)
+	trash.Post("/purge", middleware.RequireRole("developer"), h.Trash.Purge)
+	trash.Post("/:entityType/:entityId/restore", h.Trash.Restore)
+
 	graph := protected.Group("/graph")
 	graph.Get("/", h.Graph.GetFullGraph)
 	graph.Get("/ancestors/:personId", h.Graph.GetAncestors)
 	graph.Get("/ancestors/:personId/split", h.Graph.GetSplitAncestors)
 	graph.Get("/descendants/:personId", h.Graph.GetDescendants)
+	graph.Get("/migration/:personId", h.Graph.GetMigrationMap)
 	graph.Get("/path", h.Graph.FindRelationshipPath)
 	graph.Post("/resolve", h.Graph.ResolveRelationship)
 
@@ -137,13 +258,27 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, authService auth.Service)
 	changeRequests.Post("/", h.ChangeRequest.Create)
 	changeRequests.Get("/", h.ChangeRequest.List)
 	changeRequests.Get("/:requestId", h.ChangeRequest.Get)
+	changeRequests.Put("/:requestId", h.ChangeRequest.Amend)
+	changeRequests.Get("/:requestId/diff", h.ChangeRequest.Diff)
+	changeRequests.Get("/:requestId/merge", middleware.RequireRole("editor"), h.ChangeRequest.Merge)
+	changeRequests.Get("/:requestId/approvals", h.ChangeRequest.Approvals)
 	changeRequests.Post("/:requestId/approve", h.ChangeRequest.Approve)
 	changeRequests.Post("/:requestId/reject", h.ChangeRequest.Reject)
+	changeRequests.Post("/:requestId/request-changes", h.ChangeRequest.RequestChanges)
+	changeRequests.Post("/:requestId/withdraw", h.ChangeRequest.Withdraw)
+	changeRequests.Get("/:requestId/revisions", h.ChangeRequest.Revisions)
+	changeRequests.Get("/:requestId/comments", h.ChangeRequest.ListComments)
+	changeRequests.Post("/:requestId/comments", h.ChangeRequest.AddComment)
+
+	approvalPolicies := protected.Group("/approval-policies", middleware.RequireRole("editor"))
+	approvalPolicies.Get("/", h.ChangeRequest.ListPolicies)
+	approvalPolicies.Put("/:entityType/:action", middleware.RequireRole("developer"), h.ChangeRequest.SetPolicy)
 
 	media := protected.Group("/media")
 	media.Post("/", middleware.RequireRole("member"), h.Media.Upload)
 	media.Get("/", h.Media.List)
 	media.Get("/:mediaId", h.Media.Get)
+	media.Put("/:mediaId", middleware.RequireRole("member"), h.Media.Update)
 	media.Delete("/:mediaId", middleware.RequireRole("member"), h.Media.Delete)
 
 	comments := protected.Group("/persons/:personId/comments")
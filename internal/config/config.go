@@ -28,6 +28,14 @@ type Config struct {
 
 	CORSOrigins string
 
+	// EventTypesFile adds to or redefines the built-in event type catalog.
+	EventTypesFile string
+
+	TrashRetention time.Duration
+	// ChangeRequestExpiry is how long an open change request may go without
+	// activity before it expires; zero disables expiry.
+	ChangeRequestExpiry time.Duration
+
 	ResendAPIKey string
 	FromEmail    string
 	Domain       string
@@ -56,6 +64,11 @@ func Load() *Config {
 
 		CORSOrigins: getEnv("CORS_ORIGINS", "http://localhost:5173"),
 
+		EventTypesFile: getEnv("EVENT_TYPES_FILE", ""),
+
+		TrashRetention:      getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		ChangeRequestExpiry: getDurationEnv("CHANGE_REQUEST_EXPIRY", 60*24*time.Hour),
+
 		ResendAPIKey: getEnv("RESEND_API_KEY", ""),
 		FromEmail:    getEnv("FROM_EMAIL", "noreply@example.com"),
 		Domain:       getEnv("DOMAIN", "localhost:5173"),
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+// LoadEventTypes merges the event types defined in the YAML file at path
+// into the catalog.
+func LoadEventTypes(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file struct {
+		EventTypes []domain.EventTypeDefinition `yaml:"EVENT_TYPES"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return domain.SetEventTypes(file.EventTypes)
+}
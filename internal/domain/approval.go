@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAlreadyApproved       = errors.New("you already approved this change request")
+	ErrInvalidApprovalPolicy = errors.New("invalid approval policy")
+)
+
+// MaxRequiredApprovals mirrors chk_required_approvals.
+const MaxRequiredApprovals = 10
+
+// ApprovalPolicy sets how many reviewers must approve change requests of
+// one entity type and action, and whether the user linked to the person
+// they touch must approve as well.
+type ApprovalPolicy struct {
+	EntityType        EntityType   `json:"entity_type" db:"entity_type"`
+	Action            ChangeAction `json:"action" db:"action"`
+	RequiredApprovals int          `json:"required_approvals" db:"required_approvals"`
+	RequireConsent    bool         `json:"require_consent" db:"require_consent"`
+	UpdatedBy         *uuid.UUID   `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt         time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultApprovalPolicy applies where no policy is stored: one reviewer.
+func DefaultApprovalPolicy(entityType EntityType, action ChangeAction) ApprovalPolicy {
+	return ApprovalPolicy{EntityType: entityType, Action: action, RequiredApprovals: 1}
+}
+
+type SetApprovalPolicyInput struct {
+	RequiredApprovals int  `json:"required_approvals" validate:"required,min=1,max=10"`
+	RequireConsent    bool `json:"require_consent"`
+}
+
+func (in SetApprovalPolicyInput) Validate() error {
+	if in.RequiredApprovals < 1 || in.RequiredApprovals > MaxRequiredApprovals {
+		return fmt.Errorf("%w: required approvals must be between 1 and %d", ErrInvalidApprovalPolicy, MaxRequiredApprovals)
+	}
+	return nil
+}
+
+// ChangeRequestApproval is one reviewer's approval of a change request.
+// ReviewerRole is the reviewer's role when they approved.
+type ChangeRequestApproval struct {
+	RequestID    uuid.UUID `json:"request_id" db:"request_id"`
+	ReviewerID   uuid.UUID `json:"reviewer_id" db:"reviewer_id"`
+	ReviewerRole UserRole  `json:"reviewer_role" db:"reviewer_role"`
+	Note         *string   `json:"note,omitempty" db:"note"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+
+	Reviewer *User `json:"reviewer,omitempty" db:"-"`
+}
+
+// ApprovalProgress is how far a change request is from meeting its policy.
+type ApprovalProgress struct {
+	Policy    ApprovalPolicy          `json:"policy"`
+	Approvals []ChangeRequestApproval `json:"approvals"`
+	// Reviews counts approvals by editors and developers.
+	Reviews int `json:"reviews"`
+	// ConsentUserIDs are the users whose consent the policy asks for; empty
+	// when the policy does not, nobody is linked or the only linked user
+	// filed the request themselves.
+	ConsentUserIDs []uuid.UUID `json:"consent_user_ids"`
+	// ConsentGiven is set once every one of them approved.
+	ConsentGiven bool `json:"consent_given"`
+	Met          bool `json:"met"`
+}
+
+// NewApprovalProgress evaluates the approvals against the policy.
+func NewApprovalProgress(policy ApprovalPolicy, approvals []ChangeRequestApproval, consentUserIDs []uuid.UUID) ApprovalProgress {
+	if approvals == nil {
+		approvals = []ChangeRequestApproval{}
+	}
+	if consentUserIDs == nil {
+		consentUserIDs = []uuid.UUID{}
+	}
+	p := ApprovalProgress{
+		Policy:         policy,
+		Approvals:      approvals,
+		ConsentUserIDs: consentUserIDs,
+	}
+	approved := map[uuid.UUID]bool{}
+	for _, a := range approvals {
+		if a.ReviewerRole.AtLeast(RoleEditor) {
+			p.Reviews++
+		}
+		approved[a.ReviewerID] = true
+	}
+	p.ConsentGiven = true
+	for _, id := range consentUserIDs {
+		if !approved[id] {
+			p.ConsentGiven = false
+		}
+	}
+	p.Met = p.Reviews >= policy.RequiredApprovals && p.ConsentGiven
+	return p
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrChangeConflict is returned when a change request was filed against an
+// older version of its entity and both changed the same field.
+var ErrChangeConflict = errors.New("change request conflicts with later edits")
+
+// ChangeConflictError lists the fields that need a resolution before the
+// change request can be approved; it wraps ErrChangeConflict.
+type ChangeConflictError struct {
+	Fields []string
+}
+
+func (e *ChangeConflictError) Error() string {
+	return ErrChangeConflict.Error() + ": " + strings.Join(e.Fields, ", ")
+}
+
+func (e *ChangeConflictError) Unwrap() error {
+	return ErrChangeConflict
+}
+
+// MergeField is one patched field in a three-way merge: its value when the
+// change request was filed, its value now and the value the request
+// proposes. Conflict is set when the field was edited on both sides.
+type MergeField struct {
+	Field    string `json:"field"`
+	Base     any    `json:"base"`
+	Current  any    `json:"current"`
+	Proposed any    `json:"proposed"`
+	Conflict bool   `json:"conflict"`
+}
+
+// ChangeRequestMerge is the three-way merge of an UPDATE change request
+// against the current entity. Stale is set when the entity was edited after
+// the request was filed.
+type ChangeRequestMerge struct {
+	RequestID        uuid.UUID    `json:"request_id"`
+	Stale            bool         `json:"stale"`
+	BaseUpdatedAt    *time.Time   `json:"base_updated_at,omitempty"`
+	CurrentUpdatedAt *time.Time   `json:"current_updated_at,omitempty"`
+	Fields           []MergeField `json:"fields"`
+	Conflicts        []string     `json:"conflicts"`
+}
+
+// PatchFields returns the fields an UPDATE payload changes. Fields the
+// payload omits are left alone, an explicit null clears a field, and with a
+// base snapshot fields the payload repeats unchanged are dropped, so they
+// cannot overwrite later edits. Values are read through the type of entity,
+// so a date sent as "ABT 1920" equals the snapshot's GenDate; entity may be
+// nil when there is nothing to compare with.
+func PatchFields(base, payload json.RawMessage, entity any) (map[string]any, error) {
+	patch, err := canonicalFields(payload, entity)
+	if err != nil {
+		return nil, err
+	}
+	if len(base) == 0 {
+		return patch, nil
+	}
+	b, err := canonicalFields(base, entity)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range patch {
+		if reflect.DeepEqual(b[k], v) {
+			delete(patch, k)
+		}
+	}
+	return patch, nil
+}
+
+// ThreeWayMerge compares each patched field across the base snapshot, the
+// current entity and the patch. A field conflicts when it changed since the
+// base to something other than the proposed value.
+func ThreeWayMerge(base json.RawMessage, current any, patch map[string]any) ([]MergeField, error) {
+	b, err := canonicalFields(base, current)
+	if err != nil {
+		return nil, err
+	}
+	c, err := canonicalFields(current, current)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]MergeField, 0, len(patch))
+	for k, p := range patch {
+		fields = append(fields, MergeField{
+			Field:    k,
+			Base:     b[k],
+			Current:  c[k],
+			Proposed: p,
+			Conflict: !reflect.DeepEqual(b[k], c[k]) && !reflect.DeepEqual(c[k], p),
+		})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields, nil
+}
+
+// canonicalFields flattens v like flattenFields with nulls kept, after
+// decoding it into a value of the same type as entity and encoding it
+// again, so each field is in the form the entity itself produces. Fields
+// the entity type does not have, or that do not decode, keep their raw
+// value.
+func canonicalFields(v any, entity any) (map[string]any, error) {
+	raw, err := flattenFields(v, true)
+	if err != nil || entity == nil || len(raw) == 0 {
+		return raw, err
+	}
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	typed := reflect.New(t).Interface()
+	if err := json.Unmarshal(data, typed); err != nil {
+		return raw, nil
+	}
+	if rel, ok := typed.(*Relationship); ok {
+		rel.Metadata = canonicalSpouseMetadata(rel.Metadata)
+	}
+	canonical, err := flattenFields(typed, true)
+	if err != nil {
+		return nil, err
+	}
+	for k := range raw {
+		if c, ok := canonical[k]; ok && raw[k] != nil {
+			raw[k] = c
+		}
+	}
+	return raw, nil
+}
+
+// canonicalSpouseMetadata re-encodes the keys SpouseMetadata knows,
+// leaving other keys as they are.
+func canonicalSpouseMetadata(metadata json.RawMessage) json.RawMessage {
+	var raw map[string]any
+	var meta SpouseMetadata
+	if json.Unmarshal(metadata, &raw) != nil || json.Unmarshal(metadata, &meta) != nil {
+		return metadata
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return metadata
+	}
+	var known map[string]any
+	if err := json.Unmarshal(data, &known); err != nil {
+		return metadata
+	}
+	for k, v := range raw {
+		if c, ok := known[k]; ok && v != nil {
+			raw[k] = c
+		}
+	}
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return metadata
+	}
+	return data
+}
+
+// ApplyPatch writes current with the patch applied into dst, which is
+// usually a fresh value of the same type as current.
+func ApplyPatch(current any, patch map[string]any, dst any) error {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if m == nil {
+		m = map[string]any{}
+	}
+
+	for k, v := range patch {
+		parent, child, nested := strings.Cut(k, ".")
+		if !nested {
+			m[k] = v
+			continue
+		}
+		obj, _ := m[parent].(map[string]any)
+		if obj == nil {
+			obj = map[string]any{}
+		}
+		if v == nil {
+			delete(obj, child)
+		} else {
+			obj[child] = v
+		}
+		m[parent] = obj
+	}
+
+	data, err = json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
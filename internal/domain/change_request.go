@@ -2,7 +2,13 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -21,9 +27,17 @@ type ChangeRequest struct {
 	ReviewNote    *string             `json:"review_note,omitempty" db:"review_note"`
 	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time           `json:"updated_at" db:"updated_at"`
+	// BaseUpdatedAt and BaseSnapshot record the entity an UPDATE was filed
+	// against, to detect edits made since.
+	BaseUpdatedAt *time.Time      `json:"base_updated_at,omitempty" db:"base_updated_at"`
+	BaseSnapshot  json.RawMessage `json:"-" db:"base_snapshot"`
+	// Revision counts the versions of the request; amending it adds one.
+	Revision int `json:"revision" db:"revision"`
 
 	Requester *User `json:"requester,omitempty" db:"-"`
 	Reviewer  *User `json:"reviewer,omitempty" db:"-"`
+	// Summary is a one-line description of the change for list views.
+	Summary string `json:"summary,omitempty" db:"-"`
 }
 
 type EntityType string
@@ -32,6 +46,10 @@ const (
 	EntityPerson       EntityType = "PERSON"
 	EntityRelationship EntityType = "RELATIONSHIP"
 	EntityMedia        EntityType = "MEDIA"
+	EntityEvent        EntityType = "EVENT"
+	EntityComment      EntityType = "COMMENT"
+	// EntityChangeset is a change request whose payload is a Changeset.
+	EntityChangeset EntityType = "CHANGESET"
 )
 
 type ChangeAction string
@@ -40,16 +58,36 @@ const (
 	ActionCreate ChangeAction = "CREATE"
 	ActionUpdate ChangeAction = "UPDATE"
 	ActionDelete ChangeAction = "DELETE"
+	ActionMerge  ChangeAction = "MERGE"
 )
 
+// ErrChangeRequestNotPending is returned when reviewing a change request
+// that was already approved or rejected, possibly by a concurrent review.
+var ErrChangeRequestNotPending = errors.New("change request is not pending")
+
+// ErrChangeRequestClosed is returned when withdrawing or amending a change
+// request that was already approved, rejected, withdrawn or expired.
+var ErrChangeRequestClosed = errors.New("change request is closed")
+
 type ChangeRequestStatus string
 
 const (
 	StatusPending  ChangeRequestStatus = "PENDING"
 	StatusApproved ChangeRequestStatus = "APPROVED"
 	StatusRejected ChangeRequestStatus = "REJECTED"
+	// StatusChangesRequested waits for the requester to amend the request.
+	StatusChangesRequested ChangeRequestStatus = "CHANGES_REQUESTED"
+	StatusWithdrawn        ChangeRequestStatus = "WITHDRAWN"
+	// StatusExpired closes a request nobody touched for too long.
+	StatusExpired ChangeRequestStatus = "EXPIRED"
 )
 
+// IsOpen reports whether a request in the status can still be amended,
+// withdrawn or expire.
+func (s ChangeRequestStatus) IsOpen() bool {
+	return s == StatusPending || s == StatusChangesRequested
+}
+
 type CreateChangeRequestInput struct {
 	EntityType    EntityType      `json:"entity_type" validate:"required"`
 	EntityID      *uuid.UUID      `json:"entity_id,omitempty"`
@@ -58,6 +96,225 @@ type CreateChangeRequestInput struct {
 	RequesterNote *string         `json:"requester_note,omitempty" validate:"omitempty,max=500"`
 }
 
+// AmendChangeRequestInput replaces the payload and note of an open change
+// request; the entity and action stay as filed.
+type AmendChangeRequestInput struct {
+	Payload       json.RawMessage `json:"payload" validate:"required"`
+	RequesterNote *string         `json:"requester_note,omitempty" validate:"omitempty,max=500"`
+}
+
+// ChangeRequestRevision is a version of a change request that an
+// amendment replaced.
+type ChangeRequestRevision struct {
+	RequestID     uuid.UUID       `json:"request_id" db:"request_id"`
+	Revision      int             `json:"revision" db:"revision"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	RequesterNote *string         `json:"requester_note,omitempty" db:"requester_note"`
+	// ReviewNote is the note of the reviewer who asked for changes.
+	ReviewNote   *string   `json:"review_note,omitempty" db:"review_note"`
+	SupersededAt time.Time `json:"superseded_at" db:"superseded_at"`
+}
+
+// ChangeRequestComment is part of the discussion of a change request.
+// Replies name the comment they answer in ParentID.
+type ChangeRequestComment struct {
+	ID        uuid.UUID  `json:"id" db:"comment_id"`
+	RequestID uuid.UUID  `json:"request_id" db:"request_id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	ParentID  *uuid.UUID `json:"parent_id" db:"parent_id"`
+	Content   string     `json:"content" db:"content"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	User    *CommentUser           `json:"user,omitempty" db:"-"`
+	Replies []ChangeRequestComment `json:"replies,omitempty" db:"-"`
+}
+
+type CreateChangeRequestCommentInput struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+	Content  string     `json:"content" validate:"required,min=1,max=2000"`
+}
+
+// ErrInvalidComment is returned for an empty comment or one over
+// MaxCommentLength characters.
+var ErrInvalidComment = errors.New("comment must have between 1 and 2000 characters")
+
+const MaxCommentLength = 2000
+
+func (in CreateChangeRequestCommentInput) Validate() error {
+	return validateCommentContent(in.Content)
+}
+
+func validateCommentContent(content string) error {
+	n := utf8.RuneCountInString(strings.TrimSpace(content))
+	if n == 0 || n > MaxCommentLength {
+		return ErrInvalidComment
+	}
+	return nil
+}
+
+// ThreadComments nests replies under the comments they answer, keeping
+// the order given at each level. Replies to a missing comment are dropped.
+func ThreadComments(comments []ChangeRequestComment) []ChangeRequestComment {
+	children := map[uuid.UUID][]ChangeRequestComment{}
+	var roots []ChangeRequestComment
+	for _, c := range comments {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var attach func(list []ChangeRequestComment) []ChangeRequestComment
+	attach = func(list []ChangeRequestComment) []ChangeRequestComment {
+		for i := range list {
+			list[i].Replies = attach(children[list[i].ID])
+		}
+		return list
+	}
+	if roots == nil {
+		return []ChangeRequestComment{}
+	}
+	return attach(roots)
+}
+
 type ReviewChangeRequestInput struct {
 	Note *string `json:"note,omitempty" validate:"omitempty,max=500"`
+	// Resolutions gives the final value of each conflicting field when
+	// approving a change request filed against an older version.
+	Resolutions map[string]json.RawMessage `json:"resolutions,omitempty"`
+}
+
+// FieldChange is one field of an entity before and after a change request
+// is applied. Values are in their JSON form; labels name the person behind
+// a person ID.
+type FieldChange struct {
+	Field       string `json:"field"`
+	Before      any    `json:"before"`
+	After       any    `json:"after"`
+	BeforeLabel string `json:"before_label,omitempty"`
+	AfterLabel  string `json:"after_label,omitempty"`
+}
+
+// ChangeRequestDiff previews what approving a change request would do.
+type ChangeRequestDiff struct {
+	RequestID  uuid.UUID     `json:"request_id"`
+	EntityType EntityType    `json:"entity_type"`
+	EntityID   *uuid.UUID    `json:"entity_id,omitempty"`
+	Action     ChangeAction  `json:"action"`
+	Summary    string        `json:"summary"`
+	Changes    []FieldChange `json:"changes"`
+	// EntityMissing is set when the entity to update or delete no longer
+	// exists, so approval would fail.
+	EntityMissing bool `json:"entity_missing,omitempty"`
+	// Stale is set when the entity was edited after an UPDATE was filed;
+	// the merge endpoint shows whether the edits conflict.
+	Stale bool `json:"stale,omitempty"`
+	// Duplicates lists existing persons a CREATE would likely duplicate.
+	Duplicates []DuplicateCandidate `json:"duplicates,omitempty"`
+	// Operations previews each operation of a changeset, in order.
+	Operations []ChangeRequestDiff `json:"operations,omitempty"`
+}
+
+// diffIgnoredFields are bookkeeping and derived fields that a change
+// request neither sets nor needs to show.
+var diffIgnoredFields = map[string]bool{
+	"id": true, "created_by": true, "created_at": true, "updated_at": true,
+	"redacted": true, "names": true, "custom_fields": true,
+	"person_a_data": true, "person_b_data": true, "uploaded_by": true, "url": true,
+}
+
+// diffNestedFields are JSON objects compared key by key, as
+// "metadata.marriage_date", rather than as a whole.
+var diffNestedFields = map[string]bool{"metadata": true}
+
+// DiffFields compares two entities field by field through their JSON form.
+// Either side may be nil, for a CREATE or a DELETE; fields that are empty on
+// both sides are left out. Changes are sorted by field name.
+func DiffFields(before, after any) ([]FieldChange, error) {
+	b, err := flattenJSON(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := flattenJSON(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range b {
+		keys[k] = true
+	}
+	for k := range a {
+		keys[k] = true
+	}
+
+	changes := []FieldChange{}
+	for k := range keys {
+		if !reflect.DeepEqual(b[k], a[k]) {
+			changes = append(changes, FieldChange{Field: k, Before: b[k], After: a[k]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func flattenJSON(v any) (map[string]any, error) {
+	return flattenFields(v, false)
+}
+
+// flattenFields decodes v into its top-level JSON fields, with nested
+// fields spelled "parent.child". Null fields are dropped unless keepNull is
+// set, in which case they mean "clear this field".
+func flattenFields(v any, keepNull bool) (map[string]any, error) {
+	out := map[string]any{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return out, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for k, val := range m {
+		if diffIgnoredFields[k] || (val == nil && !keepNull) {
+			continue
+		}
+		if nested, ok := val.(map[string]any); ok && diffNestedFields[k] {
+			for nk, nv := range nested {
+				if nv != nil || keepNull {
+					out[k+"."+nk] = nv
+				}
+			}
+			continue
+		}
+		out[k] = val
+	}
+	return out, nil
+}
+
+// SummarizeChange describes a change request in one line, e.g.
+// "Update person Siti Aminah: birth_date, phone".
+func SummarizeChange(action ChangeAction, entity EntityType, label string, changes []FieldChange) string {
+	verb := strings.ToLower(string(action))
+	if verb != "" {
+		verb = strings.ToUpper(verb[:1]) + verb[1:]
+	}
+	summary := strings.TrimSpace(fmt.Sprintf("%s %s %s", verb, strings.ToLower(string(entity)), label))
+	if action != ActionUpdate || len(changes) == 0 {
+		return summary
+	}
+
+	fields := make([]string, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	const maxFields = 5
+	if len(fields) > maxFields {
+		fields = append(fields[:maxFields], fmt.Sprintf("+%d more", len(fields)-maxFields))
+	}
+	return summary + ": " + strings.Join(fields, ", ")
 }
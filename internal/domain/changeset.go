@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidChangeset is returned for a changeset whose operations cannot
+// be applied in the order given.
+var ErrInvalidChangeset = errors.New("invalid changeset")
+
+// MaxChangesetOperations bounds the size of one changeset.
+const MaxChangesetOperations = 50
+
+// Changeset is the payload of a CHANGESET change request: operations that
+// are reviewed together and applied in order, all or none.
+type Changeset struct {
+	Operations []ChangeOperation `json:"operations"`
+}
+
+// ChangeOperation is one step of a changeset. A CREATE may name the entity
+// it makes with Ref; later operations refer to it in their payload as
+// "$" + Ref, e.g. {"person_a": "$T1"}, which is replaced by the new ID when
+// the changeset is applied.
+type ChangeOperation struct {
+	Ref        string          `json:"ref,omitempty"`
+	EntityType EntityType      `json:"entity_type"`
+	EntityID   *uuid.UUID      `json:"entity_id,omitempty"`
+	Action     ChangeAction    `json:"action"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// OperationBase is the version of its entity an UPDATE operation of a
+// changeset was filed against. A changeset keeps them, indexed like its
+// operations, as its base snapshot.
+type OperationBase struct {
+	UpdatedAt time.Time       `json:"updated_at"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+}
+
+// RefToken is how payloads spell a reference to ref.
+func RefToken(ref string) string {
+	return "$" + ref
+}
+
+// Validate checks the refs of the changeset: each is declared once, by a
+// CREATE, and only used by operations after it.
+func (c Changeset) Validate() error {
+	if len(c.Operations) == 0 {
+		return fmt.Errorf("%w: no operations", ErrInvalidChangeset)
+	}
+	if len(c.Operations) > MaxChangesetOperations {
+		return fmt.Errorf("%w: more than %d operations", ErrInvalidChangeset, MaxChangesetOperations)
+	}
+
+	seen := map[string]bool{}
+	for i, op := range c.Operations {
+		if op.Ref == "" {
+			continue
+		}
+		if op.Action != ActionCreate {
+			return fmt.Errorf("%w: operation %d: only a create can declare a ref", ErrInvalidChangeset, i+1)
+		}
+		if strings.HasPrefix(op.Ref, "$") {
+			return fmt.Errorf("%w: operation %d: ref %q must not start with $", ErrInvalidChangeset, i+1, op.Ref)
+		}
+		if seen[op.Ref] {
+			return fmt.Errorf("%w: ref %q declared twice", ErrInvalidChangeset, op.Ref)
+		}
+		seen[op.Ref] = true
+	}
+
+	declared := c.DeclaredRefs()
+	for i, op := range c.Operations {
+		refs, err := op.Refs(declared)
+		if err != nil {
+			return fmt.Errorf("%w: operation %d: %v", ErrInvalidChangeset, i+1, err)
+		}
+		for _, ref := range refs {
+			if declared[ref] >= i {
+				return fmt.Errorf("%w: operation %d uses %s before it is created", ErrInvalidChangeset, i+1, RefToken(ref))
+			}
+		}
+	}
+	return nil
+}
+
+// DeclaredRefs maps each ref to the index of the operation declaring it.
+func (c Changeset) DeclaredRefs() map[string]int {
+	declared := map[string]int{}
+	for i, op := range c.Operations {
+		if op.Ref != "" {
+			declared[op.Ref] = i
+		}
+	}
+	return declared
+}
+
+// Refs lists the declared refs the operation's payload uses.
+func (op ChangeOperation) Refs(declared map[string]int) ([]string, error) {
+	var refs []string
+	_, err := replaceRefs(op.Payload, func(s string) (string, bool) {
+		ref, ok := strings.CutPrefix(s, "$")
+		if _, known := declared[ref]; ok && known {
+			refs = append(refs, ref)
+		}
+		return s, false
+	})
+	return refs, err
+}
+
+// ResolveRefs returns the payload with every reference to a ref in ids
+// replaced by its ID.
+func (op ChangeOperation) ResolveRefs(ids map[string]uuid.UUID) (json.RawMessage, error) {
+	if len(ids) == 0 {
+		return op.Payload, nil
+	}
+	return replaceRefs(op.Payload, func(s string) (string, bool) {
+		ref, ok := strings.CutPrefix(s, "$")
+		if !ok {
+			return s, false
+		}
+		id, known := ids[ref]
+		if !known {
+			return s, false
+		}
+		return id.String(), true
+	})
+}
+
+// replaceRefs walks the strings of a JSON document, values and not keys,
+// through fn and re-encodes it when fn changed any.
+func replaceRefs(payload json.RawMessage, fn func(string) (string, bool)) (json.RawMessage, error) {
+	if len(payload) == 0 {
+		return payload, nil
+	}
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, err
+	}
+
+	changed := false
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch t := v.(type) {
+		case string:
+			if s, ok := fn(t); ok {
+				changed = true
+				return s
+			}
+		case map[string]any:
+			for k, child := range t {
+				t[k] = walk(child)
+			}
+		case []any:
+			for i, child := range t {
+				t[i] = walk(child)
+			}
+		}
+		return v
+	}
+	doc = walk(doc)
+
+	if !changed {
+		return payload, nil
+	}
+	return json.Marshal(doc)
+}
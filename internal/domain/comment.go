@@ -1,23 +1,26 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Comment struct {
-	ID        uuid.UUID  `json:"id" db:"comment_id"`
-	PersonID  uuid.UUID  `json:"person_id" db:"person_id"`
-	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
-	ParentID  *uuid.UUID `json:"parent_id" db:"parent_id"`
-	Content   string     `json:"content" db:"content"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt *time.Time `json:"-" db:"deleted_at"`
-
-	User *CommentUser `json:"user,omitempty"`
-	Replies []Comment `json:"replies,omitempty"`
+	ID         uuid.UUID  `json:"id" db:"comment_id"`
+	PersonID   uuid.UUID  `json:"person_id" db:"person_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	ParentID   *uuid.UUID `json:"parent_id" db:"parent_id"`
+	Content    string     `json:"content" db:"content"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt  *time.Time `json:"-" db:"deleted_at"`
+	DeletedBy  *uuid.UUID `json:"-" db:"deleted_by"`
+	DeletionID *uuid.UUID `json:"-" db:"deletion_id"`
+
+	User    *CommentUser `json:"user,omitempty"`
+	Replies []Comment    `json:"replies,omitempty"`
 }
 
 type CommentUser struct {
@@ -26,6 +29,11 @@ type CommentUser struct {
 	AvatarURL *string   `json:"avatar_url" db:"user_avatar_url"`
 }
 
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("insufficient permissions to change this comment")
+)
+
 type CreateCommentInput struct {
 	ParentID *uuid.UUID `json:"parent_id"`
 	Content  string     `json:"content" validate:"required,min=1,max=2000"`
@@ -34,3 +42,18 @@ type CreateCommentInput struct {
 type UpdateCommentInput struct {
 	Content string `json:"content" validate:"required,min=1,max=2000"`
 }
+
+func (in CreateCommentInput) Validate() error {
+	return validateCommentContent(in.Content)
+}
+
+func (in UpdateCommentInput) Validate() error {
+	return validateCommentContent(in.Content)
+}
+
+// ProposeCommentInput is the payload of a COMMENT create change request,
+// which names the person the comment is about.
+type ProposeCommentInput struct {
+	PersonID uuid.UUID `json:"person_id"`
+	CreateCommentInput
+}
@@ -0,0 +1,293 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CustomFieldType is the kind of value a custom field holds.
+type CustomFieldType string
+
+const (
+	CustomFieldText   CustomFieldType = "TEXT"
+	CustomFieldNumber CustomFieldType = "NUMBER"
+	CustomFieldDate   CustomFieldType = "DATE"
+	CustomFieldEnum   CustomFieldType = "ENUM"
+	CustomFieldPerson CustomFieldType = "PERSON"
+)
+
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldEnum, CustomFieldPerson:
+		return true
+	}
+	return false
+}
+
+var (
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldKeyTaken = errors.New("custom field key already exists")
+	ErrInvalidCustomField  = errors.New("invalid custom field")
+	ErrInvalidCustomValue  = errors.New("invalid custom field value")
+	ErrCustomValueNotFound = errors.New("custom field value not found")
+	ErrCustomValueSelfRef  = errors.New("a person cannot reference themselves")
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// CustomFieldRules holds the per-field validation. Length and pattern rules
+// apply to TEXT fields, Min and Max to NUMBER fields.
+type CustomFieldRules struct {
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Pattern   *string  `json:"pattern,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+}
+
+func (r *CustomFieldRules) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*r = CustomFieldRules{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into CustomFieldRules", src)
+	}
+	return json.Unmarshal(data, r)
+}
+
+func (r CustomFieldRules) Value() (driver.Value, error) {
+	data, err := json.Marshal(r)
+	return string(data), err
+}
+
+// CustomField defines an extra fact recorded on persons, such as marga or
+// blood type.
+type CustomField struct {
+	ID          uuid.UUID        `json:"id" db:"field_id"`
+	Key         string           `json:"key" db:"key"`
+	Label       string           `json:"label" db:"label"`
+	Description *string          `json:"description,omitempty" db:"description"`
+	Type        CustomFieldType  `json:"type" db:"type"`
+	Options     pq.StringArray   `json:"options" db:"options"`
+	Rules       CustomFieldRules `json:"rules" db:"rules"`
+	// Visibility is the lowest role that may see values of the field.
+	Visibility UserRole   `json:"visibility" db:"visibility"`
+	SortOrder  int        `json:"sort_order" db:"sort_order"`
+	CreatedBy  uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt  *time.Time `json:"-" db:"deleted_at"`
+}
+
+// Validate checks the definition: options are required for ENUM fields
+// only and rules must fit the field type.
+func (f *CustomField) Validate() error {
+	if !customFieldKeyPattern.MatchString(f.Key) {
+		return fmt.Errorf("%w: key must be 2-50 lowercase letters, digits or underscores", ErrInvalidCustomField)
+	}
+	if strings.TrimSpace(f.Label) == "" {
+		return fmt.Errorf("%w: label is required", ErrInvalidCustomField)
+	}
+	if !f.Type.IsValid() {
+		return fmt.Errorf("%w: invalid type", ErrInvalidCustomField)
+	}
+	if !f.Visibility.IsValid() {
+		return fmt.Errorf("%w: visibility must be member, editor or developer", ErrInvalidCustomField)
+	}
+
+	if f.Type == CustomFieldEnum {
+		if len(f.Options) == 0 {
+			return fmt.Errorf("%w: an ENUM field needs options", ErrInvalidCustomField)
+		}
+		seen := make(map[string]bool, len(f.Options))
+		for _, o := range f.Options {
+			if strings.TrimSpace(o) == "" || seen[strings.ToLower(o)] {
+				return fmt.Errorf("%w: options must be non-empty and unique", ErrInvalidCustomField)
+			}
+			seen[strings.ToLower(o)] = true
+		}
+	} else if len(f.Options) > 0 {
+		return fmt.Errorf("%w: only ENUM fields have options", ErrInvalidCustomField)
+	}
+
+	r := f.Rules
+	if f.Type != CustomFieldText && (r.MinLength != nil || r.MaxLength != nil || r.Pattern != nil) {
+		return fmt.Errorf("%w: length and pattern rules apply to TEXT fields", ErrInvalidCustomField)
+	}
+	if f.Type != CustomFieldNumber && (r.Min != nil || r.Max != nil) {
+		return fmt.Errorf("%w: min and max rules apply to NUMBER fields", ErrInvalidCustomField)
+	}
+	if (r.MinLength != nil && *r.MinLength < 0) || (r.MaxLength != nil && *r.MaxLength < 1) {
+		return fmt.Errorf("%w: invalid length rule", ErrInvalidCustomField)
+	}
+	if r.MinLength != nil && r.MaxLength != nil && *r.MinLength > *r.MaxLength {
+		return fmt.Errorf("%w: min_length is greater than max_length", ErrInvalidCustomField)
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return fmt.Errorf("%w: min is greater than max", ErrInvalidCustomField)
+	}
+	if r.Pattern != nil {
+		if _, err := regexp.Compile(*r.Pattern); err != nil {
+			return fmt.Errorf("%w: invalid pattern", ErrInvalidCustomField)
+		}
+	}
+	return nil
+}
+
+// VisibleTo reports whether a user with the given role may see values of
+// the field.
+func (f *CustomField) VisibleTo(role UserRole) bool {
+	return role.AtLeast(f.Visibility)
+}
+
+// ParseValue validates raw against the field and returns the value in its
+// canonical form with the typed columns used for search filled in. PERSON
+// values are only checked for a well-formed ID here.
+func (f *CustomField) ParseValue(raw string) (*PersonCustomValue, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("%w: value is required", ErrInvalidCustomValue)
+	}
+	v := &PersonCustomValue{FieldID: f.ID, Key: f.Key, Label: f.Label, Type: f.Type, Visibility: f.Visibility}
+
+	switch f.Type {
+	case CustomFieldText:
+		n := utf8.RuneCountInString(raw)
+		if f.Rules.MinLength != nil && n < *f.Rules.MinLength {
+			return nil, fmt.Errorf("%w: %s must be at least %d characters", ErrInvalidCustomValue, f.Label, *f.Rules.MinLength)
+		}
+		if f.Rules.MaxLength != nil && n > *f.Rules.MaxLength {
+			return nil, fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidCustomValue, f.Label, *f.Rules.MaxLength)
+		}
+		if f.Rules.Pattern != nil && !regexp.MustCompile(*f.Rules.Pattern).MatchString(raw) {
+			return nil, fmt.Errorf("%w: %s has an invalid format", ErrInvalidCustomValue, f.Label)
+		}
+		v.Value = raw
+
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidCustomValue, f.Label)
+		}
+		if f.Rules.Min != nil && n < *f.Rules.Min {
+			return nil, fmt.Errorf("%w: %s must be at least %g", ErrInvalidCustomValue, f.Label, *f.Rules.Min)
+		}
+		if f.Rules.Max != nil && n > *f.Rules.Max {
+			return nil, fmt.Errorf("%w: %s must be at most %g", ErrInvalidCustomValue, f.Label, *f.Rules.Max)
+		}
+		v.Value = strconv.FormatFloat(n, 'f', -1, 64)
+		v.NumberValue = &n
+
+	case CustomFieldDate:
+		d, err := ParseGenDate(raw)
+		if err != nil || !d.HasDate() {
+			return nil, fmt.Errorf("%w: %s must be a date", ErrInvalidCustomValue, f.Label)
+		}
+		v.Value = d.String()
+		v.DateMin = d.Earliest()
+		v.DateMax = d.Latest()
+
+	case CustomFieldEnum:
+		for _, o := range f.Options {
+			if strings.EqualFold(o, raw) {
+				v.Value = o
+			}
+		}
+		if v.Value == "" {
+			return nil, fmt.Errorf("%w: %s must be one of %s", ErrInvalidCustomValue, f.Label, strings.Join(f.Options, ", "))
+		}
+
+	case CustomFieldPerson:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a person ID", ErrInvalidCustomValue, f.Label)
+		}
+		v.Value = id.String()
+		v.PersonRef = &id
+	}
+
+	v.Display = v.Value
+	return v, nil
+}
+
+// PersonCustomValue is the value of one custom field for a person, joined
+// with the field definition.
+type PersonCustomValue struct {
+	PersonID   uuid.UUID       `json:"person_id" db:"person_id"`
+	FieldID    uuid.UUID       `json:"field_id" db:"field_id"`
+	Key        string          `json:"key" db:"key"`
+	Label      string          `json:"label" db:"label"`
+	Type       CustomFieldType `json:"type" db:"type"`
+	Visibility UserRole        `json:"-" db:"visibility"`
+	Value      string          `json:"value" db:"value"`
+	// Display is the value as shown to people: the name of the referenced
+	// person for PERSON fields, the value itself otherwise.
+	Display     string     `json:"display" db:"display"`
+	NumberValue *float64   `json:"-" db:"value_number"`
+	DateMin     *time.Time `json:"-" db:"value_date_min"`
+	DateMax     *time.Time `json:"-" db:"value_date_max"`
+	PersonRef   *uuid.UUID `json:"person_ref,omitempty" db:"value_person_id"`
+	UpdatedBy   uuid.UUID  `json:"updated_by" db:"updated_by"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// VisibleCustomValues returns the values a user with the given role may see.
+func VisibleCustomValues(values []PersonCustomValue, role UserRole) []PersonCustomValue {
+	visible := []PersonCustomValue{}
+	for _, v := range values {
+		if role.AtLeast(v.Visibility) {
+			visible = append(visible, v)
+		}
+	}
+	return visible
+}
+
+// CustomFieldFilter matches persons by a custom field value: TEXT values
+// contain Value, NUMBER values equal it, DATE values overlap the year it
+// names and ENUM and PERSON values equal it ignoring case.
+type CustomFieldFilter struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type CreateCustomFieldInput struct {
+	Key         string           `json:"key"`
+	Label       string           `json:"label"`
+	Description *string          `json:"description,omitempty"`
+	Type        CustomFieldType  `json:"type"`
+	Options     []string         `json:"options,omitempty"`
+	Rules       CustomFieldRules `json:"rules"`
+	Visibility  UserRole         `json:"visibility,omitempty"`
+	SortOrder   int              `json:"sort_order"`
+}
+
+// UpdateCustomFieldInput changes a field definition. Key and type are fixed
+// once created so stored values and saved searches keep their meaning.
+type UpdateCustomFieldInput struct {
+	Label       *string           `json:"label,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Options     []string          `json:"options,omitempty"`
+	Rules       *CustomFieldRules `json:"rules,omitempty"`
+	Visibility  *UserRole         `json:"visibility,omitempty"`
+	SortOrder   *int              `json:"sort_order,omitempty"`
+}
+
+type SetCustomValueInput struct {
+	Value string `json:"value"`
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPossibleDuplicate = errors.New("person looks like an existing record")
+
+// DuplicateWarningThreshold is the score from which a new person is held
+// back as a likely duplicate until the caller confirms it.
+const DuplicateWarningThreshold = 0.7
+
+// SimilarPerson is a person matched by trigram similarity on any of their
+// names.
+type SimilarPerson struct {
+	Person
+	Similarity float64 `db:"similarity"`
+}
+
+// SimilarPair is two persons whose names are trigram-similar, ordered so
+// PersonA < PersonB.
+type SimilarPair struct {
+	PersonA    uuid.UUID `db:"person_a"`
+	PersonB    uuid.UUID `db:"person_b"`
+	Similarity float64   `db:"similarity"`
+}
+
+// DuplicateSignals explains a duplicate score. Year gaps are nil when either
+// side has no date, GenderMatch is nil when either gender is unknown.
+type DuplicateSignals struct {
+	NameSimilarity  float64 `json:"name_similarity"`
+	BirthYearGap    *int    `json:"birth_year_gap,omitempty"`
+	DeathYearGap    *int    `json:"death_year_gap,omitempty"`
+	GenderMatch     *bool   `json:"gender_match,omitempty"`
+	SameBirthPlace  bool    `json:"same_birth_place"`
+	SharedRelatives int     `json:"shared_relatives"`
+}
+
+type DuplicateCandidate struct {
+	Person  Person           `json:"person"`
+	Score   float64          `json:"score"`
+	Signals DuplicateSignals `json:"signals"`
+}
+
+type DuplicatePair struct {
+	PersonA Person           `json:"person_a"`
+	PersonB Person           `json:"person_b"`
+	Score   float64          `json:"score"`
+	Signals DuplicateSignals `json:"signals"`
+}
+
+// DuplicateWarningError is returned when a person about to be created
+// matches existing records; it wraps ErrPossibleDuplicate.
+type DuplicateWarningError struct {
+	Candidates []DuplicateCandidate
+}
+
+func (e *DuplicateWarningError) Error() string {
+	return ErrPossibleDuplicate.Error()
+}
+
+func (e *DuplicateWarningError) Unwrap() error {
+	return ErrPossibleDuplicate
+}
+
+// ScoreDuplicate combines the signals for two persons into a score between
+// 0 and 1. Name similarity carries most of the weight; dates and places
+// raise it, and a clear mismatch in dates or gender pulls it down.
+func ScoreDuplicate(a, b *Person, nameSimilarity float64, sharedRelatives int) (float64, DuplicateSignals) {
+	signals := DuplicateSignals{
+		NameSimilarity:  nameSimilarity,
+		SharedRelatives: sharedRelatives,
+	}
+	score := 0.6 * nameSimilarity
+
+	if gap, ok := yearGap(a.BirthDate, b.BirthDate); ok {
+		signals.BirthYearGap = &gap
+		switch {
+		case gap == 0:
+			score += 0.2
+		case gap <= 2:
+			score += 0.1
+		case gap > 10:
+			score -= 0.3
+		}
+	}
+	if gap, ok := yearGap(a.DeathDate, b.DeathDate); ok {
+		signals.DeathYearGap = &gap
+		switch {
+		case gap == 0:
+			score += 0.1
+		case gap <= 2:
+			score += 0.05
+		case gap > 10:
+			score -= 0.2
+		}
+	}
+
+	if a.Gender != GenderUnknown && b.Gender != GenderUnknown && a.Gender != "" && b.Gender != "" {
+		match := a.Gender == b.Gender
+		signals.GenderMatch = &match
+		if match {
+			score += 0.05
+		} else {
+			score -= 0.4
+		}
+	}
+
+	// Place references are authoritative when both records have one;
+	// otherwise fall back to comparing the free text.
+	if a.BirthPlaceID != nil && b.BirthPlaceID != nil {
+		signals.SameBirthPlace = *a.BirthPlaceID == *b.BirthPlaceID
+	} else if a.BirthPlace != nil && b.BirthPlace != nil {
+		signals.SameBirthPlace = strings.EqualFold(strings.TrimSpace(*a.BirthPlace), strings.TrimSpace(*b.BirthPlace))
+	}
+	if signals.SameBirthPlace {
+		score += 0.05
+	}
+
+	score += min(0.1*float64(sharedRelatives), 0.2)
+
+	return max(0, min(1, score)), signals
+}
+
+// yearGap is the number of calendar years between two date ranges, zero
+// when they overlap.
+func yearGap(a, b *GenDate) (int, bool) {
+	aMin, aMax := a.Earliest(), a.Latest()
+	bMin, bMax := b.Earliest(), b.Latest()
+	if aMin == nil || aMax == nil || bMin == nil || bMax == nil {
+		return 0, false
+	}
+	switch {
+	case aMax.Before(*bMin):
+		return yearsBetween(*aMax, *bMin), true
+	case bMax.Before(*aMin):
+		return yearsBetween(*bMax, *aMin), true
+	}
+	return 0, true
+}
+
+func yearsBetween(from, to time.Time) int {
+	return to.Year() - from.Year()
+}
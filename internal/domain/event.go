@@ -2,6 +2,9 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,35 +13,368 @@ import (
 type EventType string
 
 const (
-	EventTypeBirth    EventType = "BIRTH"
-	EventTypeDeath    EventType = "DEATH"
-	EventTypeMarriage EventType = "MARRIAGE"
-	EventTypeDivorce  EventType = "DIVORCE"
-	EventTypeOther    EventType = "OTHER"
+	EventTypeBirth           EventType = "BIRTH"
+	EventTypeDeath           EventType = "DEATH"
+	EventTypeMarriage        EventType = "MARRIAGE"
+	EventTypeDivorce         EventType = "DIVORCE"
+	EventTypeAqiqah          EventType = "AQIQAH"
+	EventTypeKhitan          EventType = "KHITAN"
+	EventTypeAkadNikah       EventType = "AKAD_NIKAH"
+	EventTypeResepsi         EventType = "RESEPSI"
+	EventTypeGraduation      EventType = "GRADUATION"
+	EventTypeHajj            EventType = "HAJJ"
+	EventTypeMigration       EventType = "MIGRATION"
+	EventTypeBurial          EventType = "BURIAL"
+	EventTypeHaul            EventType = "HAUL"
+	EventTypeMilitaryService EventType = "MILITARY_SERVICE"
+	EventTypeOther           EventType = "OTHER"
+)
+
+var (
+	ErrEventNotFound        = errors.New("event not found")
+	ErrInvalidEventType     = errors.New("invalid event type")
+	ErrInvalidEventMetadata = errors.New("invalid event metadata")
 )
 
 func (e EventType) IsValid() bool {
-	switch e {
-	case EventTypeBirth, EventTypeDeath, EventTypeMarriage, EventTypeDivorce, EventTypeOther:
-		return true
+	_, ok := e.Definition()
+	return ok
+}
+
+func (e EventType) Definition() (EventTypeDefinition, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	def, ok := eventTypeCatalog[e]
+	return def, ok
+}
+
+type EventScope string
+
+const (
+	EventScopePerson       EventScope = "PERSON"
+	EventScopeRelationship EventScope = "RELATIONSHIP"
+	EventScopeAny          EventScope = "ANY"
+)
+
+type MetadataFieldType string
+
+const (
+	MetadataString  MetadataFieldType = "string"
+	MetadataNumber  MetadataFieldType = "number"
+	MetadataBoolean MetadataFieldType = "boolean"
+	MetadataDate    MetadataFieldType = "date"
+)
+
+type EventMetadataField struct {
+	Key      string            `json:"key" yaml:"key"`
+	Type     MetadataFieldType `json:"type" yaml:"type"`
+	Required bool              `json:"required" yaml:"required"`
+}
+
+// EventTypeDefinition describes an entry in the event type catalog: where the
+// event may be attached, which metadata keys it accepts and how it is written
+// to GEDCOM. Labels are looked up in the locale files under EVENT_<TYPE>,
+// falling back to Labels for types added in the catalog file.
+type EventTypeDefinition struct {
+	Type       EventType            `json:"type" yaml:"type"`
+	Label      string               `json:"label" yaml:"-"`
+	Labels     map[string]string    `json:"-" yaml:"labels"`
+	Scope      EventScope           `json:"scope" yaml:"scope"`
+	GedcomTag  string               `json:"gedcom_tag" yaml:"gedcom_tag"`
+	GedcomType string               `json:"gedcom_type,omitempty" yaml:"gedcom_type"`
+	Metadata   []EventMetadataField `json:"metadata" yaml:"metadata"`
+	OpenSchema bool                 `json:"open_schema" yaml:"open_schema"`
+}
+
+func (d EventTypeDefinition) LabelKey() string {
+	return "EVENT_" + string(d.Type)
+}
+
+// Validate checks a definition read from the catalog file.
+func (d EventTypeDefinition) Validate() error {
+	if d.Type == "" {
+		return fmt.Errorf("%w: type is required", ErrInvalidEventType)
+	}
+	switch d.Scope {
+	case EventScopePerson, EventScopeRelationship, EventScopeAny:
+	default:
+		return fmt.Errorf("%w: %s has unknown scope %q", ErrInvalidEventType, d.Type, d.Scope)
+	}
+	if d.GedcomTag == "" {
+		return fmt.Errorf("%w: %s needs a gedcom_tag", ErrInvalidEventType, d.Type)
+	}
+	seen := make(map[string]bool, len(d.Metadata))
+	for _, field := range d.Metadata {
+		if field.Key == "" || seen[field.Key] {
+			return fmt.Errorf("%w: %s has a blank or repeated metadata key", ErrInvalidEventType, d.Type)
+		}
+		seen[field.Key] = true
+		switch field.Type {
+		case MetadataString, MetadataNumber, MetadataBoolean, MetadataDate:
+		default:
+			return fmt.Errorf("%w: %s.%s has unknown type %q", ErrInvalidEventType, d.Type, field.Key, field.Type)
+		}
+	}
+	return nil
+}
+
+func (d EventTypeDefinition) ValidateMetadata(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = json.RawMessage("{}")
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return fmt.Errorf("%w: metadata must be a JSON object", ErrInvalidEventMetadata)
+	}
+
+	known := make(map[string]EventMetadataField, len(d.Metadata))
+	for _, field := range d.Metadata {
+		known[field.Key] = field
+		if _, ok := values[field.Key]; !ok && field.Required {
+			return fmt.Errorf("%w: %s is required for %s", ErrInvalidEventMetadata, field.Key, d.Type)
+		}
+	}
+
+	for key, value := range values {
+		field, ok := known[key]
+		if !ok {
+			if d.OpenSchema {
+				continue
+			}
+			return fmt.Errorf("%w: unknown key %s for %s", ErrInvalidEventMetadata, key, d.Type)
+		}
+		if value == nil {
+			if field.Required {
+				return fmt.Errorf("%w: %s is required for %s", ErrInvalidEventMetadata, key, d.Type)
+			}
+			continue
+		}
+		if !field.Type.accepts(value) {
+			return fmt.Errorf("%w: %s must be a %s", ErrInvalidEventMetadata, key, field.Type)
+		}
+	}
+
+	return nil
+}
+
+func (t MetadataFieldType) accepts(value any) bool {
+	switch t {
+	case MetadataString:
+		_, ok := value.(string)
+		return ok
+	case MetadataNumber:
+		_, ok := value.(float64)
+		return ok
+	case MetadataBoolean:
+		_, ok := value.(bool)
+		return ok
+	case MetadataDate:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := ParseGenDate(s)
+		return err == nil
 	}
 	return false
 }
 
+var catalogMu sync.RWMutex
+
+// eventTypeOrder and eventTypeCatalog hold the built-in catalog.
+// SetEventTypes adds to or redefines it from the catalog file.
+var eventTypeOrder = []EventType{
+	EventTypeBirth,
+	EventTypeAqiqah,
+	EventTypeKhitan,
+	EventTypeGraduation,
+	EventTypeAkadNikah,
+	EventTypeResepsi,
+	EventTypeMarriage,
+	EventTypeDivorce,
+	EventTypeHajj,
+	EventTypeMigration,
+	EventTypeMilitaryService,
+	EventTypeDeath,
+	EventTypeBurial,
+	EventTypeHaul,
+	EventTypeOther,
+}
+
+var eventTypeCatalog = map[EventType]EventTypeDefinition{
+	EventTypeBirth: {
+		Type: EventTypeBirth, Scope: EventScopePerson, GedcomTag: "BIRT",
+		Metadata: []EventMetadataField{
+			{Key: "birth_order", Type: MetadataNumber},
+			{Key: "attended_by", Type: MetadataString},
+		},
+	},
+	EventTypeDeath: {
+		Type: EventTypeDeath, Scope: EventScopePerson, GedcomTag: "DEAT",
+		Metadata: []EventMetadataField{
+			{Key: "cause", Type: MetadataString},
+		},
+	},
+	EventTypeMarriage: {
+		Type: EventTypeMarriage, Scope: EventScopeAny, GedcomTag: "MARR",
+		Metadata: []EventMetadataField{
+			{Key: "officiant", Type: MetadataString},
+		},
+	},
+	EventTypeDivorce: {
+		Type: EventTypeDivorce, Scope: EventScopeAny, GedcomTag: "DIV",
+		Metadata: []EventMetadataField{
+			{Key: "court", Type: MetadataString},
+			{Key: "case_number", Type: MetadataString},
+		},
+	},
+	EventTypeAqiqah: {
+		Type: EventTypeAqiqah, Scope: EventScopePerson, GedcomTag: "EVEN", GedcomType: "Aqiqah",
+		Metadata: []EventMetadataField{
+			{Key: "animal_type", Type: MetadataString},
+			{Key: "animal_count", Type: MetadataNumber},
+			{Key: "name_given", Type: MetadataString},
+		},
+	},
+	EventTypeKhitan: {
+		Type: EventTypeKhitan, Scope: EventScopePerson, GedcomTag: "EVEN", GedcomType: "Khitan",
+		Metadata: []EventMetadataField{
+			{Key: "performed_by", Type: MetadataString},
+			{Key: "age", Type: MetadataNumber},
+		},
+	},
+	EventTypeAkadNikah: {
+		Type: EventTypeAkadNikah, Scope: EventScopeRelationship, GedcomTag: "MARR", GedcomType: "Akad Nikah",
+		Metadata: []EventMetadataField{
+			{Key: "wali", Type: MetadataString},
+			{Key: "penghulu", Type: MetadataString},
+			{Key: "mahar", Type: MetadataString},
+			{Key: "witnesses", Type: MetadataString},
+			{Key: "kua", Type: MetadataString},
+			{Key: "certificate_number", Type: MetadataString},
+		},
+	},
+	EventTypeResepsi: {
+		Type: EventTypeResepsi, Scope: EventScopeRelationship, GedcomTag: "EVEN", GedcomType: "Resepsi",
+		Metadata: []EventMetadataField{
+			{Key: "venue", Type: MetadataString},
+			{Key: "adat", Type: MetadataString},
+		},
+	},
+	EventTypeGraduation: {
+		Type: EventTypeGraduation, Scope: EventScopePerson, GedcomTag: "GRAD",
+		Metadata: []EventMetadataField{
+			{Key: "institution", Type: MetadataString, Required: true},
+			{Key: "degree", Type: MetadataString},
+			{Key: "field_of_study", Type: MetadataString},
+		},
+	},
+	EventTypeHajj: {
+		Type: EventTypeHajj, Scope: EventScopePerson, GedcomTag: "EVEN", GedcomType: "Hajj",
+		Metadata: []EventMetadataField{
+			{Key: "hijri_year", Type: MetadataNumber},
+			{Key: "embarkation", Type: MetadataString},
+			{Key: "kloter", Type: MetadataString},
+		},
+	},
+	EventTypeMigration: {
+		Type: EventTypeMigration, Scope: EventScopePerson, GedcomTag: "EMIG",
+		Metadata: []EventMetadataField{
+			{Key: "from_place", Type: MetadataString},
+			{Key: "to_place", Type: MetadataString, Required: true},
+			{Key: "reason", Type: MetadataString},
+		},
+	},
+	EventTypeBurial: {
+		Type: EventTypeBurial, Scope: EventScopePerson, GedcomTag: "BURI",
+		Metadata: []EventMetadataField{
+			{Key: "cemetery", Type: MetadataString},
+			{Key: "grave_location", Type: MetadataString},
+		},
+	},
+	EventTypeHaul: {
+		Type: EventTypeHaul, Scope: EventScopePerson, GedcomTag: "EVEN", GedcomType: "Haul",
+		Metadata: []EventMetadataField{
+			{Key: "haul_number", Type: MetadataNumber},
+			{Key: "organizer", Type: MetadataString},
+		},
+	},
+	EventTypeMilitaryService: {
+		Type: EventTypeMilitaryService, Scope: EventScopePerson, GedcomTag: "EVEN", GedcomType: "Military Service",
+		Metadata: []EventMetadataField{
+			{Key: "branch", Type: MetadataString},
+			{Key: "rank", Type: MetadataString},
+			{Key: "unit", Type: MetadataString},
+			{Key: "service_number", Type: MetadataString},
+			{Key: "end_date", Type: MetadataDate},
+		},
+	},
+	EventTypeOther: {
+		Type: EventTypeOther, Scope: EventScopeAny, GedcomTag: "EVEN", OpenSchema: true,
+	},
+}
+
+func EventTypeDefinitions() []EventTypeDefinition {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	defs := make([]EventTypeDefinition, 0, len(eventTypeOrder))
+	for _, t := range eventTypeOrder {
+		defs = append(defs, eventTypeCatalog[t])
+	}
+	return defs
+}
+
+// SetEventTypes merges definitions into the catalog. A definition for a
+// type already in the catalog replaces it in place; new types are listed
+// before OTHER. Nothing is changed if any definition is invalid.
+func SetEventTypes(defs []EventTypeDefinition) error {
+	for _, def := range defs {
+		if err := def.Validate(); err != nil {
+			return err
+		}
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	for _, def := range defs {
+		if _, ok := eventTypeCatalog[def.Type]; !ok {
+			last := len(eventTypeOrder) - 1
+			if last >= 0 && eventTypeOrder[last] == EventTypeOther {
+				eventTypeOrder = append(eventTypeOrder[:last], def.Type, EventTypeOther)
+			} else {
+				eventTypeOrder = append(eventTypeOrder, def.Type)
+			}
+		}
+		eventTypeCatalog[def.Type] = def
+	}
+	return nil
+}
+
 type Event struct {
 	ID             uuid.UUID       `json:"id" db:"event_id"`
 	PersonID       *uuid.UUID      `json:"person_id" db:"person_id"`
 	RelationshipID *uuid.UUID      `json:"relationship_id,omitempty" db:"relationship_id"`
 	Type           EventType       `json:"type" db:"type"`
 	Title          string          `json:"title" db:"title"`
-	Date           *time.Time      `json:"date,omitempty" db:"date"`
+	Date           *GenDate        `json:"date,omitempty" db:"date"`
+	DateMin        *time.Time      `json:"-" db:"date_min"`
+	DateMax        *time.Time      `json:"-" db:"date_max"`
 	Place          *string         `json:"place,omitempty" db:"place"`
+	PlaceID        *uuid.UUID      `json:"place_id,omitempty" db:"place_id"`
 	Description    *string         `json:"description,omitempty" db:"description"`
 	Metadata       json.RawMessage `json:"metadata,omitempty" db:"metadata"`
 	CreatedBy      uuid.UUID       `json:"created_by" db:"created_by"`
 	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time      `json:"-" db:"deleted_at"`
+	DeletedBy      *uuid.UUID      `json:"-" db:"deleted_by"`
+	DeletionID     *uuid.UUID      `json:"-" db:"deletion_id"`
+}
+
+// SyncDateBounds refreshes the stored date range used to order events.
+func (e *Event) SyncDateBounds() {
+	e.DateMin, e.DateMax = e.Date.Earliest(), e.Date.Latest()
 }
 
 type CreateEventInput struct {
@@ -46,8 +382,9 @@ type CreateEventInput struct {
 	RelationshipID *uuid.UUID      `json:"relationship_id" validate:"required_without=PersonID"`
 	Type           EventType       `json:"type" validate:"required"`
 	Title          string          `json:"title" validate:"required,max=100"`
-	Date           *time.Time      `json:"date"`
+	Date           *GenDate        `json:"date"`
 	Place          *string         `json:"place" validate:"omitempty,max=200"`
+	PlaceID        *uuid.UUID      `json:"place_id"`
 	Description    *string         `json:"description"`
 	Metadata       json.RawMessage `json:"metadata"`
 }
@@ -55,8 +392,9 @@ type CreateEventInput struct {
 type UpdateEventInput struct {
 	Type        *EventType      `json:"type" validate:"omitempty"`
 	Title       *string         `json:"title" validate:"omitempty,max=100"`
-	Date        NullableTime    `json:"date"`
+	Date        NullableGenDate `json:"date"`
 	Place       NullableString  `json:"place" validate:"omitempty,max=200"`
+	PlaceID     NullableUUID    `json:"place_id"`
 	Description NullableString  `json:"description"`
 	Metadata    json.RawMessage `json:"metadata"`
 }
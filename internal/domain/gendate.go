@@ -0,0 +1,761 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"silsilah-keluarga/internal/pkg/calendar"
+)
+
+// DateQualifier follows the GEDCOM date modifiers. An empty qualifier means
+// the date is stated as-is (exact, or merely partial such as "MAR 1920").
+type DateQualifier string
+
+const (
+	DateExact      DateQualifier = ""
+	DateAbout      DateQualifier = "ABT"
+	DateCalculated DateQualifier = "CAL"
+	DateEstimated  DateQualifier = "EST"
+	DateBefore     DateQualifier = "BEF"
+	DateAfter      DateQualifier = "AFT"
+	DateBetween    DateQualifier = "BET"
+)
+
+func (q DateQualifier) IsValid() bool {
+	switch q {
+	case DateExact, DateAbout, DateCalculated, DateEstimated, DateBefore, DateAfter, DateBetween:
+		return true
+	}
+	return false
+}
+
+// IsApproximate reports whether the qualifier marks the date as a guess
+// rather than a bound.
+func (q DateQualifier) IsApproximate() bool {
+	return q == DateAbout || q == DateCalculated || q == DateEstimated
+}
+
+type DatePrecision string
+
+const (
+	PrecisionDay   DatePrecision = "DAY"
+	PrecisionMonth DatePrecision = "MONTH"
+	PrecisionYear  DatePrecision = "YEAR"
+)
+
+var ErrInvalidGenDate = errors.New("invalid date")
+
+// DatePoint is a date whose month and day may be unknown (zero). Its fields
+// are expressed in the calendar of the GenDate that holds it.
+type DatePoint struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+func (p DatePoint) IsZero() bool {
+	return p.Year == 0
+}
+
+func (p DatePoint) Precision() DatePrecision {
+	switch {
+	case p.Day != 0:
+		return PrecisionDay
+	case p.Month != 0:
+		return PrecisionMonth
+	}
+	return PrecisionYear
+}
+
+// ISO formats the point as "1920-03-12", "1920-03" or "1920".
+func (p DatePoint) ISO() string {
+	switch p.Precision() {
+	case PrecisionDay:
+		return fmt.Sprintf("%04d-%02d-%02d", p.Year, p.Month, p.Day)
+	case PrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", p.Year, p.Month)
+	}
+	return fmt.Sprintf("%04d", p.Year)
+}
+
+// first returns the earliest Gregorian day covered by the point.
+func (p DatePoint) first(cal calendar.Calendar) time.Time {
+	month, day := p.Month, p.Day
+	if month == 0 {
+		month = 1
+	}
+	if day == 0 {
+		day = 1
+	}
+	return cal.ToGregorian(p.Year, month, day)
+}
+
+// last returns the latest Gregorian day covered by the point.
+func (p DatePoint) last(cal calendar.Calendar) time.Time {
+	switch {
+	case p.Month == 0:
+		return cal.ToGregorian(p.Year, 12, cal.DaysInMonth(p.Year, 12))
+	case p.Day == 0:
+		return cal.ToGregorian(p.Year, p.Month, cal.DaysInMonth(p.Year, p.Month))
+	}
+	return p.first(cal)
+}
+
+// gedcom formats the point as "12 MAR 1920", "MAR 1920" or "1920", prefixed
+// with the calendar escape for non-Gregorian calendars.
+func (p DatePoint) gedcom(cal calendar.Calendar) string {
+	var value string
+	switch p.Precision() {
+	case PrecisionDay:
+		value = fmt.Sprintf("%d %s %d", p.Day, cal.MonthCodes()[p.Month-1], p.Year)
+	case PrecisionMonth:
+		value = fmt.Sprintf("%s %d", cal.MonthCodes()[p.Month-1], p.Year)
+	default:
+		value = strconv.Itoa(p.Year)
+	}
+	if escape := cal.Escape(); escape != "" {
+		return escape + " " + value
+	}
+	return value
+}
+
+func pointFromTime(t time.Time) DatePoint {
+	return DatePoint{Year: t.Year(), Month: int(t.Month()), Day: t.Day()}
+}
+
+// GenDate is a genealogical date: possibly partial (year or year-month),
+// qualified (about, before, after, between) and/or carrying the original
+// free-text phrase it was recorded as. Dates may be entered in the Hijri or
+// Javanese calendar; they keep their original calendar and values, while
+// Earliest and Latest are always Gregorian days. It is stored as its
+// GEDCOM-style string; Earliest and Latest give the range of days it can
+// denote and are what sorting and date constraints are based on.
+type GenDate struct {
+	Qualifier DateQualifier
+	Calendar  calendar.Calendar
+	Start     DatePoint
+	End       DatePoint
+	Phrase    string
+}
+
+func NewExactDate(t time.Time) *GenDate {
+	return &GenDate{Start: pointFromTime(t)}
+}
+
+func NewYearDate(q DateQualifier, year int) *GenDate {
+	return &GenDate{Qualifier: q, Start: DatePoint{Year: year}}
+}
+
+// cal returns the calendar of the date, Gregorian when unset.
+func (d GenDate) cal() calendar.Calendar {
+	if d.Calendar == "" {
+		return calendar.Gregorian
+	}
+	return d.Calendar
+}
+
+// HasDate reports whether the value carries a usable date rather than just
+// a phrase.
+func (d *GenDate) HasDate() bool {
+	return d != nil && !d.Start.IsZero()
+}
+
+func (d *GenDate) Precision() DatePrecision {
+	if !d.HasDate() {
+		return ""
+	}
+	return d.Start.Precision()
+}
+
+// IsExact reports whether the value denotes a single known day.
+func (d *GenDate) IsExact() bool {
+	return d.HasDate() && d.Qualifier == DateExact && d.Start.Precision() == PrecisionDay
+}
+
+// Earliest is the first Gregorian day the date can denote, or nil when it
+// is open towards the past (BEF) or has no date at all.
+func (d *GenDate) Earliest() *time.Time {
+	if !d.HasDate() {
+		return nil
+	}
+	var t time.Time
+	switch d.Qualifier {
+	case DateBefore:
+		return nil
+	case DateAfter:
+		t = d.Start.last(d.cal()).AddDate(0, 0, 1)
+	default:
+		t = d.Start.first(d.cal())
+	}
+	return &t
+}
+
+// Latest is the last Gregorian day the date can denote, or nil when it is
+// open towards the future (AFT) or has no date at all.
+func (d *GenDate) Latest() *time.Time {
+	if !d.HasDate() {
+		return nil
+	}
+	var t time.Time
+	switch d.Qualifier {
+	case DateAfter:
+		return nil
+	case DateBefore:
+		t = d.Start.first(d.cal()).AddDate(0, 0, -1)
+	case DateBetween:
+		t = d.End.last(d.cal())
+	default:
+		t = d.Start.last(d.cal())
+	}
+	return &t
+}
+
+// SortDate is the day used to order dates chronologically.
+func (d *GenDate) SortDate() *time.Time {
+	if t := d.Earliest(); t != nil {
+		return t
+	}
+	return d.Latest()
+}
+
+// Year returns the nominal Gregorian year of the date, or 0 when there is
+// none.
+func (d *GenDate) Year() int {
+	if !d.HasDate() {
+		return 0
+	}
+	if d.cal() == calendar.Gregorian {
+		return d.Start.Year
+	}
+	return d.Gregorian().Start.Year
+}
+
+// Gregorian returns the date converted to the Gregorian calendar. Single
+// days convert exactly and keep their qualifier; partial dates in another
+// calendar become the Gregorian range they cover. The phrase is kept.
+func (d *GenDate) Gregorian() *GenDate {
+	if d == nil || d.cal() == calendar.Gregorian {
+		return d
+	}
+	out := &GenDate{Qualifier: d.Qualifier, Phrase: d.Phrase}
+	if !d.HasDate() {
+		return out
+	}
+	c := d.cal()
+	switch d.Qualifier {
+	case DateBefore:
+		out.Start = pointFromTime(d.Start.first(c))
+	case DateAfter:
+		out.Start = pointFromTime(d.Start.last(c))
+	case DateBetween:
+		out.Start = pointFromTime(d.Start.first(c))
+		out.End = pointFromTime(d.End.last(c))
+	default:
+		first, last := d.Start.first(c), d.Start.last(c)
+		out.Start = pointFromTime(first)
+		if !first.Equal(last) {
+			out.Qualifier = DateBetween
+			out.End = pointFromTime(last)
+		}
+	}
+	return out
+}
+
+// Display renders the date points in the calendar's own month names, e.g.
+// "12 Ramadan 1365 H" or "Sura 1867 J".
+func (d GenDate) Display() string {
+	if d.Start.IsZero() {
+		return d.Phrase
+	}
+	c := d.cal()
+	start := c.Format(d.Start.Year, d.Start.Month, d.Start.Day)
+	switch d.Qualifier {
+	case DateExact:
+		return start
+	case DateBetween:
+		return "BET " + start + " AND " + c.Format(d.End.Year, d.End.Month, d.End.Day)
+	}
+	return string(d.Qualifier) + " " + start
+}
+
+// Before reports whether d certainly lies before other, i.e. every day d can
+// denote precedes every day other can denote.
+func (d *GenDate) Before(other *GenDate) bool {
+	latest, earliest := d.Latest(), other.Earliest()
+	return latest != nil && earliest != nil && latest.Before(*earliest)
+}
+
+// After reports whether d certainly lies after other.
+func (d *GenDate) After(other *GenDate) bool {
+	return other.Before(d)
+}
+
+// String returns the canonical stored form: a GEDCOM date with the phrase
+// appended in parentheses, e.g. "ABT 1920", "BET 1900 AND 1910",
+// "@#DHIJRI@ 12 RAMAD 1365", "INT 1920 (sekitar zaman Jepang)" or
+// "(sebelum merdeka)".
+func (d GenDate) String() string {
+	value := d.dateString()
+	switch {
+	case d.Phrase == "":
+		return value
+	case value == "":
+		return "(" + d.Phrase + ")"
+	case d.Qualifier == DateExact:
+		return "INT " + value + " (" + d.Phrase + ")"
+	}
+	return value + " (" + d.Phrase + ")"
+}
+
+// GEDCOM returns the value as a GEDCOM 5.5.1 DATE payload. The phrase is
+// only kept where the grammar allows it: on interpreted dates and on
+// phrase-only values. Hijri and Javanese dates, which 5.5.1 readers do not
+// know, are exported converted to Gregorian with the original date as the
+// phrase where possible.
+func (d GenDate) GEDCOM() string {
+	if d.cal() != calendar.Gregorian && !d.Start.IsZero() {
+		g := d.Gregorian()
+		if g.Qualifier == DateExact {
+			g.Phrase = d.Display()
+		}
+		return g.GEDCOM()
+	}
+	if d.Qualifier != DateExact && !d.Start.IsZero() {
+		return d.dateString()
+	}
+	return d.String()
+}
+
+func (d GenDate) dateString() string {
+	if d.Start.IsZero() {
+		return ""
+	}
+	c := d.cal()
+	switch d.Qualifier {
+	case DateExact:
+		return d.Start.gedcom(c)
+	case DateBetween:
+		return "BET " + d.Start.gedcom(c) + " AND " + d.End.gedcom(c)
+	}
+	return string(d.Qualifier) + " " + d.Start.gedcom(c)
+}
+
+func (d GenDate) Validate() error {
+	if !d.Qualifier.IsValid() {
+		return fmt.Errorf("%w: unknown qualifier %q", ErrInvalidGenDate, d.Qualifier)
+	}
+	c := d.cal()
+	if !c.IsValid() {
+		return fmt.Errorf("%w: unknown calendar %q", ErrInvalidGenDate, d.Calendar)
+	}
+	if d.Start.IsZero() {
+		if d.Phrase == "" {
+			return fmt.Errorf("%w: empty date", ErrInvalidGenDate)
+		}
+		if d.Qualifier != DateExact || !d.End.IsZero() {
+			return fmt.Errorf("%w: qualifier without a date", ErrInvalidGenDate)
+		}
+		return nil
+	}
+	if err := c.Validate(d.Start.Year, d.Start.Month, d.Start.Day); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGenDate, err)
+	}
+	if d.Qualifier == DateBetween {
+		if d.End.IsZero() {
+			return fmt.Errorf("%w: BET requires an AND date", ErrInvalidGenDate)
+		}
+		if err := c.Validate(d.End.Year, d.End.Month, d.End.Day); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidGenDate, err)
+		}
+		if d.End.last(c).Before(d.Start.first(c)) {
+			return fmt.Errorf("%w: range ends before it starts", ErrInvalidGenDate)
+		}
+	} else if !d.End.IsZero() {
+		return fmt.Errorf("%w: only BET takes an end date", ErrInvalidGenDate)
+	}
+	if strings.ContainsAny(d.Phrase, "()") {
+		return fmt.Errorf("%w: phrase may not contain parentheses", ErrInvalidGenDate)
+	}
+	return nil
+}
+
+// ParseGenDate accepts the canonical form produced by String (which is a
+// superset of GEDCOM dates, including the @#DHIJRI@ and @#DJAVANESE@
+// calendar escapes), ISO dates and partial ISO dates ("1920", "1920-03")
+// and RFC 3339 timestamps as sent by older clients.
+func ParseGenDate(s string) (*GenDate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty date", ErrInvalidGenDate)
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return NewExactDate(t), nil
+	}
+
+	d := &GenDate{}
+	if strings.HasSuffix(s, ")") {
+		open := strings.LastIndex(s, "(")
+		if open < 0 {
+			return nil, fmt.Errorf("%w: unbalanced parenthesis", ErrInvalidGenDate)
+		}
+		d.Phrase = strings.TrimSpace(s[open+1 : len(s)-1])
+		s = strings.TrimSpace(s[:open])
+		if s == "" {
+			if err := d.Validate(); err != nil {
+				return nil, err
+			}
+			return d, nil
+		}
+	}
+
+	fields := strings.Fields(strings.ToUpper(s))
+	switch q := DateQualifier(fields[0]); q {
+	case DateAbout, DateCalculated, DateEstimated, DateBefore, DateAfter, DateBetween:
+		d.Qualifier = q
+		fields = fields[1:]
+	case "INT":
+		fields = fields[1:]
+	}
+
+	if d.Qualifier == DateBetween {
+		and := -1
+		for i, f := range fields {
+			if f == "AND" {
+				and = i
+				break
+			}
+		}
+		if and < 0 {
+			return nil, fmt.Errorf("%w: BET requires an AND date", ErrInvalidGenDate)
+		}
+		cal, end, err := parseDatePoint(fields[and+1:])
+		if err != nil {
+			return nil, err
+		}
+		d.Calendar, d.End = cal, end
+		fields = fields[:and]
+	}
+
+	cal, start, err := parseDatePoint(fields)
+	if err != nil {
+		return nil, err
+	}
+	if d.Qualifier == DateBetween && cal != d.Calendar {
+		return nil, fmt.Errorf("%w: range mixes calendars", ErrInvalidGenDate)
+	}
+	d.Calendar, d.Start = cal, start
+	if d.Calendar == calendar.Gregorian {
+		d.Calendar = ""
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// parseDatePoint parses an optionally calendar-escaped GEDCOM date ("12 MAR
+// 1920", "@#DHIJRI@ RAMAD 1365") or an ISO date. It checks syntax only;
+// ranges are checked by GenDate.Validate.
+func parseDatePoint(fields []string) (calendar.Calendar, DatePoint, error) {
+	cal := calendar.Gregorian
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "@#") {
+		c, ok := calendar.FromEscape(fields[0])
+		if !ok {
+			return cal, DatePoint{}, fmt.Errorf("%w: unknown calendar %q", ErrInvalidGenDate, fields[0])
+		}
+		cal, fields = c, fields[1:]
+	}
+
+	invalid := fmt.Errorf("%w: %q", ErrInvalidGenDate, strings.Join(fields, " "))
+	var p DatePoint
+	switch len(fields) {
+	case 1:
+		if parts := strings.Split(fields[0], "-"); len(parts) > 1 {
+			p, err := parseISOPoint(parts)
+			return cal, p, err
+		}
+		year, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return cal, p, invalid
+		}
+		p.Year = year
+	case 2:
+		p.Month = cal.MonthFromCode(fields[0])
+		year, err := strconv.Atoi(fields[1])
+		if p.Month == 0 || err != nil {
+			return cal, p, invalid
+		}
+		p.Year = year
+	case 3:
+		day, err := strconv.Atoi(fields[0])
+		if err != nil || day < 1 {
+			return cal, p, invalid
+		}
+		p.Month = cal.MonthFromCode(fields[1])
+		year, err := strconv.Atoi(fields[2])
+		if p.Month == 0 || err != nil {
+			return cal, p, invalid
+		}
+		p.Day, p.Year = day, year
+	default:
+		return cal, p, invalid
+	}
+	return cal, p, nil
+}
+
+func parseISOPoint(parts []string) (DatePoint, error) {
+	var p DatePoint
+	invalid := fmt.Errorf("%w: %q", ErrInvalidGenDate, strings.Join(parts, "-"))
+	if len(parts) > 3 {
+		return p, invalid
+	}
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return p, invalid
+		}
+		values[i] = v
+	}
+	p.Year, p.Month = values[0], values[1]
+	if len(values) == 3 {
+		p.Day = values[2]
+		if p.Day == 0 {
+			return p, fmt.Errorf("%w: day out of range", ErrInvalidGenDate)
+		}
+	}
+	if p.Month == 0 {
+		return p, fmt.Errorf("%w: month out of range", ErrInvalidGenDate)
+	}
+	return p, nil
+}
+
+// genDateJSON is the wire form of a GenDate. Date and EndDate are ISO-style
+// values in the date's own calendar; Earliest, Latest and Gregorian are
+// always Gregorian.
+type genDateJSON struct {
+	Value     string            `json:"value"`
+	Qualifier DateQualifier     `json:"qualifier,omitempty"`
+	Calendar  calendar.Calendar `json:"calendar,omitempty"`
+	Precision DatePrecision     `json:"precision,omitempty"`
+	Date      string            `json:"date,omitempty"`
+	EndDate   string            `json:"end_date,omitempty"`
+	Phrase    string            `json:"phrase,omitempty"`
+	Earliest  string            `json:"earliest,omitempty"`
+	Latest    string            `json:"latest,omitempty"`
+	Gregorian string            `json:"gregorian,omitempty"`
+	Display   string            `json:"display,omitempty"`
+}
+
+func (d GenDate) MarshalJSON() ([]byte, error) {
+	out := genDateJSON{
+		Value:     d.String(),
+		Qualifier: d.Qualifier,
+		Calendar:  d.Calendar,
+		Precision: d.Precision(),
+		Phrase:    d.Phrase,
+	}
+	if d.cal() != calendar.Gregorian {
+		out.Gregorian = d.Gregorian().String()
+		out.Display = d.Display()
+	}
+	if !d.Start.IsZero() {
+		out.Date = d.Start.ISO()
+	}
+	if !d.End.IsZero() {
+		out.EndDate = d.End.ISO()
+	}
+	if t := d.Earliest(); t != nil {
+		out.Earliest = t.Format("2006-01-02")
+	}
+	if t := d.Latest(); t != nil {
+		out.Latest = t.Format("2006-01-02")
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts either a string understood by ParseGenDate or an
+// object in the shape produced by MarshalJSON. For objects a non-empty
+// "value" wins; otherwise the date is assembled from its parts.
+func (d *GenDate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := ParseGenDate(s)
+		if err != nil {
+			return err
+		}
+		*d = *parsed
+		return nil
+	}
+
+	var in genDateJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Value != "" {
+		parsed, err := ParseGenDate(in.Value)
+		if err != nil {
+			return err
+		}
+		*d = *parsed
+		return nil
+	}
+
+	out := GenDate{
+		Qualifier: DateQualifier(strings.ToUpper(string(in.Qualifier))),
+		Calendar:  calendar.Calendar(strings.ToUpper(string(in.Calendar))),
+		Phrase:    strings.TrimSpace(in.Phrase),
+	}
+	if out.Calendar == calendar.Gregorian {
+		out.Calendar = ""
+	}
+	if in.Date != "" {
+		p, err := parseISOOrYear(in.Date)
+		if err != nil {
+			return err
+		}
+		out.Start = p
+	}
+	if in.EndDate != "" {
+		p, err := parseISOOrYear(in.EndDate)
+		if err != nil {
+			return err
+		}
+		out.End = p
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*d = out
+	return nil
+}
+
+func parseISOOrYear(s string) (DatePoint, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) == 1 {
+		year, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return DatePoint{}, fmt.Errorf("%w: %q", ErrInvalidGenDate, s)
+		}
+		return DatePoint{Year: year}, nil
+	}
+	return parseISOPoint(parts)
+}
+
+func (d *GenDate) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case time.Time:
+		*d = *NewExactDate(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into GenDate", src)
+	}
+	parsed, err := ParseGenDate(s)
+	if err != nil {
+		return err
+	}
+	*d = *parsed
+	return nil
+}
+
+func (d GenDate) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+type NullableGenDate struct {
+	Value *GenDate
+	Set   bool
+}
+
+func (n NullableGenDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
+func (n *NullableGenDate) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var d GenDate
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	n.Value = &d
+	return nil
+}
+
+// Age is the age of a person at a given date. When either date is not an
+// exact day, Min and Max bound the possible completed years and Years is
+// computed from the nominal sort dates.
+type Age struct {
+	Years       int  `json:"years"`
+	Min         int  `json:"min"`
+	Max         int  `json:"max"`
+	Approximate bool `json:"approximate"`
+}
+
+// AgeAt returns the age at the given date, or nil when either date is
+// unknown or the moment certainly lies before the birth.
+func AgeAt(birth, at *GenDate) *Age {
+	birthSort, atSort := birth.SortDate(), at.SortDate()
+	if birthSort == nil || atSort == nil {
+		return nil
+	}
+
+	years := completedYears(*birthSort, *atSort)
+	minAge, maxAge := years, years
+	if b, a := birth.Latest(), at.Earliest(); b != nil && a != nil {
+		minAge = completedYears(*b, *a)
+	}
+	if b, a := birth.Earliest(), at.Latest(); b != nil && a != nil {
+		maxAge = completedYears(*b, *a)
+	}
+	if maxAge < 0 || (years < 0 && birth.IsExact() && at.IsExact()) {
+		return nil
+	}
+	clamp := func(v int) int {
+		if v < 0 {
+			return 0
+		}
+		return v
+	}
+	minAge, years = clamp(minAge), clamp(years)
+	if maxAge < years {
+		maxAge = years
+	}
+	if minAge > years {
+		minAge = years
+	}
+
+	return &Age{
+		Years:       years,
+		Min:         minAge,
+		Max:         maxAge,
+		Approximate: !birth.IsExact() || !at.IsExact(),
+	}
+}
+
+func completedYears(from, to time.Time) int {
+	age := to.Year() - from.Year()
+	if to.Month() < from.Month() || (to.Month() == from.Month() && to.Day() < from.Day()) {
+		age--
+	}
+	return age
+}
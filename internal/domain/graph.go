@@ -12,6 +12,10 @@ type GraphNode struct {
 	IsAlive   bool      `json:"is_alive" db:"is_alive"`
 	BirthYear *int      `json:"birth_year,omitempty" db:"birth_year"`
 	DeathYear *int      `json:"death_year,omitempty" db:"death_year"`
+	Title     *string   `json:"title,omitempty" db:"-"`
+	AlternateNames []string `json:"alternate_names,omitempty" db:"-"`
+	PrivacyLevel PrivacyLevel `json:"privacy_level,omitempty" db:"-"`
+	Redacted     bool         `json:"redacted,omitempty" db:"-"`
 	
 	Generation *int `json:"generation,omitempty" db:"generation"`
 	X          *float64 `json:"x,omitempty" db:"x"`
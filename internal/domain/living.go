@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GenerationYears is the assumed age gap between a parent and a child when
+// a birth year has to be estimated from relatives.
+const GenerationYears = 25
+
+// LivingBasis tells what a living-status estimate rests on.
+type LivingBasis string
+
+const (
+	// LivingBasisDeath means a death date, death or burial event, or the
+	// person being marked deceased.
+	LivingBasisDeath LivingBasis = "DEATH"
+	// LivingBasisBirth means the person's own birth date.
+	LivingBasisBirth LivingBasis = "BIRTH"
+	// LivingBasisParents and LivingBasisChildren mean a birth year
+	// estimated one generation from a dated parent or child.
+	LivingBasisParents  LivingBasis = "PARENTS"
+	LivingBasisChildren LivingBasis = "CHILDREN"
+	// LivingBasisGenerations means a birth year estimated from the nearest
+	// dated relative several generations away.
+	LivingBasisGenerations LivingBasis = "GENERATIONS"
+	// LivingBasisNone means nothing dated is connected to the person, who
+	// is then presumed living.
+	LivingBasisNone LivingBasis = "NONE"
+)
+
+// LivingIssueCode identifies an inconsistency between a person's living
+// status and their dates.
+type LivingIssueCode string
+
+const (
+	IssueAliveWithDeath    LivingIssueCode = "ALIVE_WITH_DEATH"
+	IssueAliveBeyondMaxAge LivingIssueCode = "ALIVE_BEYOND_MAX_AGE"
+	IssueDeathBeforeBirth  LivingIssueCode = "DEATH_BEFORE_BIRTH"
+	IssueBornBeforeParent  LivingIssueCode = "BORN_BEFORE_PARENT"
+)
+
+type LivingIssue struct {
+	Code    LivingIssueCode `json:"code"`
+	Message string          `json:"message"`
+}
+
+// LivingEstimate is the inferred living status of a person next to the
+// stored is_alive flag.
+type LivingEstimate struct {
+	PersonID           uuid.UUID     `json:"person_id"`
+	Name               string        `json:"name"`
+	IsAlive            bool          `json:"is_alive"`
+	ProbablyLiving     bool          `json:"probably_living"`
+	EstimatedBirthYear *int          `json:"estimated_birth_year,omitempty"`
+	Basis              LivingBasis   `json:"basis"`
+	Issues             []LivingIssue `json:"issues,omitempty"`
+	PrivacyLevel       PrivacyLevel  `json:"privacy_level"`
+}
+
+// NeedsCorrection reports whether the person is stored as alive but is
+// inferred to be deceased. The opposite is never corrected: a person marked
+// deceased without any dates is a legitimate record.
+func (e *LivingEstimate) NeedsCorrection() bool {
+	return e.IsAlive && !e.ProbablyLiving
+}
+
+// LivingCorrectionResult is the outcome of a bulk is_alive correction run.
+type LivingCorrectionResult struct {
+	DryRun    bool             `json:"dry_run"`
+	Corrected int              `json:"corrected"`
+	Persons   []LivingEstimate `json:"persons"`
+}
+
+type birthEstimate struct {
+	year  int
+	basis LivingBasis
+}
+
+// InferLiving estimates the living status of every person. A person without
+// a birth date borrows one from the nearest dated parent or child, walking
+// further up and down the tree until one is found, and shifting the year by
+// GenerationYears per generation. Among equally near relatives the latest
+// estimate wins, so a person is only presumed deceased when every near
+// relative says so.
+func InferLiving(persons []Person, rels []Relationship, events []Event, now time.Time) []LivingEstimate {
+	byID := make(map[uuid.UUID]*Person, len(persons))
+	for i := range persons {
+		byID[persons[i].ID] = &persons[i]
+	}
+
+	parents := make(map[uuid.UUID][]uuid.UUID)
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, r := range rels {
+		if r.Type != RelTypeParent {
+			continue
+		}
+		if byID[r.PersonA] == nil || byID[r.PersonB] == nil {
+			continue
+		}
+		parents[r.PersonA] = append(parents[r.PersonA], r.PersonB)
+		children[r.PersonB] = append(children[r.PersonB], r.PersonA)
+	}
+
+	diedEvent := make(map[uuid.UUID]bool)
+	for _, ev := range events {
+		if ev.PersonID != nil && (ev.Type == EventTypeDeath || ev.Type == EventTypeBurial) {
+			diedEvent[*ev.PersonID] = true
+		}
+	}
+
+	births := estimateBirthYears(persons, parents, children)
+
+	estimates := make([]LivingEstimate, 0, len(persons))
+	for i := range persons {
+		p := &persons[i]
+		e := LivingEstimate{
+			PersonID: p.ID,
+			Name:     p.FullName(),
+			IsAlive:  p.IsAlive,
+			Basis:    LivingBasisNone,
+
+			PrivacyLevel: p.PrivacyLevel,
+		}
+		birth, hasBirth := births[p.ID]
+		if hasBirth {
+			year := birth.year
+			e.EstimatedBirthYear = &year
+			e.Basis = birth.basis
+		}
+
+		died := p.DeathDate.HasDate() || diedEvent[p.ID]
+		switch {
+		case died || !p.IsAlive:
+			e.Basis = LivingBasisDeath
+		case hasBirth:
+			e.ProbablyLiving = now.Year()-birth.year < LivingMaxAge
+		default:
+			e.ProbablyLiving = true
+		}
+
+		if p.IsAlive && died {
+			e.Issues = append(e.Issues, LivingIssue{IssueAliveWithDeath, "Marked alive but has a death date or death event"})
+		}
+		if p.IsAlive && !died && hasBirth && !e.ProbablyLiving {
+			e.Issues = append(e.Issues, LivingIssue{IssueAliveBeyondMaxAge, "Marked alive but would be older than the maximum age"})
+		}
+		if birthAt, deathAt := p.BirthDate.Earliest(), p.DeathDate.Latest(); birthAt != nil && deathAt != nil && deathAt.Before(*birthAt) {
+			e.Issues = append(e.Issues, LivingIssue{IssueDeathBeforeBirth, "Death date is before the birth date"})
+		}
+		if year := p.BirthDate.Year(); year != 0 {
+			for _, parentID := range parents[p.ID] {
+				if py := byID[parentID].BirthDate.Year(); py != 0 && py >= year {
+					e.Issues = append(e.Issues, LivingIssue{IssueBornBeforeParent, "Born in or before the birth year of a parent"})
+					break
+				}
+			}
+		}
+
+		estimates = append(estimates, e)
+	}
+	return estimates
+}
+
+// estimateBirthYears runs a breadth-first search from every person with a
+// birth year over parent and child links, one generation per step.
+func estimateBirthYears(persons []Person, parents, children map[uuid.UUID][]uuid.UUID) map[uuid.UUID]birthEstimate {
+	births := make(map[uuid.UUID]birthEstimate)
+	var frontier []uuid.UUID
+	for i := range persons {
+		if year := persons[i].BirthDate.Year(); year != 0 {
+			births[persons[i].ID] = birthEstimate{year: year, basis: LivingBasisBirth}
+			frontier = append(frontier, persons[i].ID)
+		}
+	}
+
+	for depth := 1; len(frontier) > 0; depth++ {
+		next := make(map[uuid.UUID]birthEstimate)
+		step := func(id uuid.UUID, year int, basis LivingBasis) {
+			if _, done := births[id]; done {
+				return
+			}
+			if depth > 1 {
+				basis = LivingBasisGenerations
+			}
+			if cur, ok := next[id]; !ok || year > cur.year {
+				next[id] = birthEstimate{year: year, basis: basis}
+			}
+		}
+		for _, id := range frontier {
+			year := births[id].year
+			for _, parentID := range parents[id] {
+				step(parentID, year-GenerationYears, LivingBasisChildren)
+			}
+			for _, childID := range children[id] {
+				step(childID, year+GenerationYears, LivingBasisParents)
+			}
+		}
+
+		frontier = frontier[:0]
+		for id, b := range next {
+			births[id] = b
+			frontier = append(frontier, id)
+		}
+	}
+	return births
+}
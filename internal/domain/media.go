@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -20,6 +23,8 @@ type Media struct {
 	TakenAt     *time.Time `json:"taken_at,omitempty" db:"taken_at"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	DeletedAt   *time.Time `json:"-" db:"deleted_at"`
+	DeletedBy   *uuid.UUID `json:"-" db:"deleted_by"`
+	DeletionID  *uuid.UUID `json:"-" db:"deletion_id"`
 }
 
 type UploadMediaInput struct {
@@ -27,3 +32,31 @@ type UploadMediaInput struct {
 	Caption  *string    `json:"caption,omitempty" validate:"omitempty,max=500"`
 	TakenAt  *time.Time `json:"taken_at,omitempty"`
 }
+
+var (
+	ErrMediaNotFound    = errors.New("media not found")
+	ErrInvalidMediaEdit = errors.New("invalid media edit")
+)
+
+// MaxCaptionLength bounds media captions.
+const MaxCaptionLength = 500
+
+// UpdateMediaInput edits the description of an uploaded file; the file
+// itself and the person it belongs to stay as uploaded.
+type UpdateMediaInput struct {
+	Caption NullableString `json:"caption,omitzero"`
+	TakenAt NullableTime   `json:"taken_at,omitzero"`
+}
+
+func (in UpdateMediaInput) Validate(now time.Time) error {
+	if !in.Caption.Set && !in.TakenAt.Set {
+		return fmt.Errorf("%w: nothing to change", ErrInvalidMediaEdit)
+	}
+	if in.Caption.Value != nil && utf8.RuneCountInString(*in.Caption.Value) > MaxCaptionLength {
+		return fmt.Errorf("%w: caption is longer than %d characters", ErrInvalidMediaEdit, MaxCaptionLength)
+	}
+	if in.TakenAt.Value != nil && in.TakenAt.Value.After(now) {
+		return fmt.Errorf("%w: taken date is in the future", ErrInvalidMediaEdit)
+	}
+	return nil
+}
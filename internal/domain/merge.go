@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMergeSamePerson   = errors.New("cannot merge a person into themselves")
+	ErrInvalidMergeField = errors.New("invalid merge field")
+	ErrMergeLinkedUsers  = errors.New("both persons are linked to user accounts")
+)
+
+// MergeChoice selects which record a field's value is taken from.
+type MergeChoice string
+
+const (
+	MergeKeepTarget MergeChoice = "TARGET"
+	MergeKeepSource MergeChoice = "SOURCE"
+)
+
+func (c MergeChoice) IsValid() bool {
+	return c == MergeKeepTarget || c == MergeKeepSource
+}
+
+// MergePersonInput merges the person in the URL (the source) into TargetID,
+// which survives. Fields not listed keep the target's value unless the
+// target has none.
+type MergePersonInput struct {
+	TargetID uuid.UUID              `json:"target_id" validate:"required"`
+	Fields   map[string]MergeChoice `json:"fields,omitempty"`
+}
+
+// MergeResult reports what a merge moved from the merged person to the
+// survivor.
+type MergeResult struct {
+	Survivor               *Person     `json:"survivor"`
+	MergedID               uuid.UUID   `json:"merged_id"`
+	RepointedRelationships int         `json:"repointed_relationships"`
+	DroppedRelationships   []uuid.UUID `json:"dropped_relationships"`
+	Events                 int64       `json:"events"`
+	Media                  int64       `json:"media"`
+	Comments               int64       `json:"comments"`
+	Names                  int64       `json:"names"`
+	Citations              int64       `json:"citations"`
+	LinkedUsers            int64       `json:"linked_users"`
+	CustomValues           int64       `json:"custom_values"`
+}
+
+type mergeField struct {
+	empty func(p *Person) bool
+	copy  func(dst, src *Person)
+}
+
+func stringMergeField(get func(p *Person) **string) mergeField {
+	return mergeField{
+		empty: func(p *Person) bool { v := *get(p); return v == nil || *v == "" },
+		copy:  func(dst, src *Person) { *get(dst) = *get(src) },
+	}
+}
+
+// placeMergeField moves a place reference together with its free-text
+// fallback; the field is empty only when both are.
+func placeMergeField(text func(p *Person) **string, id func(p *Person) **uuid.UUID) mergeField {
+	return mergeField{
+		empty: func(p *Person) bool { v := *text(p); return *id(p) == nil && (v == nil || *v == "") },
+		copy: func(dst, src *Person) {
+			*text(dst) = *text(src)
+			*id(dst) = *id(src)
+		},
+	}
+}
+
+func dateMergeField(get func(p *Person) **GenDate) mergeField {
+	return mergeField{
+		empty: func(p *Person) bool { return *get(p) == nil },
+		copy:  func(dst, src *Person) { *get(dst) = *get(src) },
+	}
+}
+
+var mergeFields = map[string]mergeField{
+	"first_name": {
+		empty: func(p *Person) bool { return p.FirstName == "" },
+		copy:  func(dst, src *Person) { dst.FirstName = src.FirstName },
+	},
+	"gender": {
+		empty: func(p *Person) bool { return p.Gender == "" || p.Gender == GenderUnknown },
+		copy:  func(dst, src *Person) { dst.Gender = src.Gender },
+	},
+	"is_alive": {
+		empty: func(p *Person) bool { return false },
+		copy:  func(dst, src *Person) { dst.IsAlive = src.IsAlive },
+	},
+	"birth_date":  dateMergeField(func(p *Person) **GenDate { return &p.BirthDate }),
+	"death_date":  dateMergeField(func(p *Person) **GenDate { return &p.DeathDate }),
+	"last_name":   stringMergeField(func(p *Person) **string { return &p.LastName }),
+	"nickname":    stringMergeField(func(p *Person) **string { return &p.Nickname }),
+	"birth_place": placeMergeField(func(p *Person) **string { return &p.BirthPlace }, func(p *Person) **uuid.UUID { return &p.BirthPlaceID }),
+	"death_place": placeMergeField(func(p *Person) **string { return &p.DeathPlace }, func(p *Person) **uuid.UUID { return &p.DeathPlaceID }),
+	"bio":         stringMergeField(func(p *Person) **string { return &p.Bio }),
+	"avatar_url":  stringMergeField(func(p *Person) **string { return &p.AvatarURL }),
+	"occupation":  stringMergeField(func(p *Person) **string { return &p.Occupation }),
+	"religion":    stringMergeField(func(p *Person) **string { return &p.Religion }),
+	"nationality": stringMergeField(func(p *Person) **string { return &p.Nationality }),
+	"education":   stringMergeField(func(p *Person) **string { return &p.Education }),
+	"phone":       stringMergeField(func(p *Person) **string { return &p.Phone }),
+	"email":       stringMergeField(func(p *Person) **string { return &p.Email }),
+	"address":     stringMergeField(func(p *Person) **string { return &p.Address }),
+}
+
+// ApplyMergeChoices copies field values from source into target according
+// to choices. A field without a choice is filled from source only when the
+// target has no value for it.
+func ApplyMergeChoices(target, source *Person, choices map[string]MergeChoice) error {
+	for name, choice := range choices {
+		if _, ok := mergeFields[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidMergeField, name)
+		}
+		if !choice.IsValid() {
+			return fmt.Errorf("%w: %s must be TARGET or SOURCE", ErrInvalidMergeField, name)
+		}
+	}
+
+	for name, field := range mergeFields {
+		choice, chosen := choices[name]
+		if choice == MergeKeepSource || (!chosen && field.empty(target) && !field.empty(source)) {
+			field.copy(target, source)
+		}
+	}
+	return nil
+}
+
+// DroppedRelationship is a relationship removed by a merge; ReplacedBy is
+// the survivor's equivalent relationship when it was a duplicate.
+type DroppedRelationship struct {
+	ID         uuid.UUID
+	ReplacedBy *uuid.UUID
+}
+
+// RelationshipMergePlan says which of the merged person's relationships
+// move to the survivor and which are dropped.
+type RelationshipMergePlan struct {
+	Repoint []uuid.UUID
+	Drop    []DroppedRelationship
+}
+
+// PlanRelationshipMerge decides what happens to the merged person's
+// relationships when they are re-pointed to the survivor. rels holds the
+// relationships of both persons. A relationship between the two would become
+// a self-relation and is dropped; one the survivor already has (same type and
+// persons, spouses in either order) is dropped in favour of the survivor's.
+func PlanRelationshipMerge(mergedID, survivorID uuid.UUID, rels []Relationship) RelationshipMergePlan {
+	type relKey struct {
+		relType RelationshipType
+		a, b    uuid.UUID
+	}
+	keyOf := func(t RelationshipType, a, b uuid.UUID) relKey {
+		if t == RelTypeSpouse && b.String() < a.String() {
+			a, b = b, a
+		}
+		return relKey{t, a, b}
+	}
+
+	existing := make(map[relKey]uuid.UUID)
+	for _, r := range rels {
+		if r.DeletedAt != nil || r.PersonA == mergedID || r.PersonB == mergedID {
+			continue
+		}
+		existing[keyOf(r.Type, r.PersonA, r.PersonB)] = r.ID
+	}
+
+	var plan RelationshipMergePlan
+	for _, r := range rels {
+		if r.DeletedAt != nil || (r.PersonA != mergedID && r.PersonB != mergedID) {
+			continue
+		}
+		if r.PersonA == survivorID || r.PersonB == survivorID {
+			plan.Drop = append(plan.Drop, DroppedRelationship{ID: r.ID})
+			continue
+		}
+
+		a, b := r.PersonA, r.PersonB
+		if a == mergedID {
+			a = survivorID
+		}
+		if b == mergedID {
+			b = survivorID
+		}
+		key := keyOf(r.Type, a, b)
+		if keep, ok := existing[key]; ok {
+			plan.Drop = append(plan.Drop, DroppedRelationship{ID: r.ID, ReplacedBy: &keep})
+			continue
+		}
+		existing[key] = r.ID
+		plan.Repoint = append(plan.Repoint, r.ID)
+	}
+	return plan
+}
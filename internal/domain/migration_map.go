@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// MigrationDirection selects whose places a migration map shows.
+type MigrationDirection string
+
+const (
+	MigrationAncestors   MigrationDirection = "ancestors"
+	MigrationDescendants MigrationDirection = "descendants"
+)
+
+var ErrInvalidMigrationDirection = errors.New("direction must be ancestors or descendants")
+
+func (d MigrationDirection) IsValid() bool {
+	return d == MigrationAncestors || d == MigrationDescendants
+}
+
+// Feature kinds of a migration map.
+const (
+	MigrationFeatureBirth = "birth"
+	MigrationFeatureDeath = "death"
+	MigrationFeatureArc   = "migration"
+)
+
+// GeoJSON types, see RFC 7946. Coordinates are [longitude, latitude].
+type GeoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// MigrationMap is a GeoJSON FeatureCollection. RootPerson, Direction and
+// Unlocated are foreign members describing the map as a whole.
+type MigrationMap struct {
+	Type       string             `json:"type"`
+	Features   []GeoJSONFeature   `json:"features"`
+	RootPerson uuid.UUID          `json:"root_person"`
+	Direction  MigrationDirection `json:"direction"`
+	Unlocated  []uuid.UUID        `json:"unlocated"`
+}
+
+// MigrationMapInput is everything BuildMigrationMap needs. Generations are
+// relative to the root: negative for ancestors, positive for descendants.
+// Links are parent/child pairs; Places holds every referenced place that has
+// coordinates, possibly borrowed from an enclosing region.
+type MigrationMapInput struct {
+	RootPerson  uuid.UUID
+	Direction   MigrationDirection
+	Persons     []Person
+	Generations map[uuid.UUID]int
+	Links       []ParentChildLink
+	Places      map[uuid.UUID]*Place
+}
+
+type ParentChildLink struct {
+	Parent uuid.UUID
+	Child  uuid.UUID
+}
+
+// BuildMigrationMap places each person's birth and death on the map and
+// draws an arc from every parent's birth place to their child's when the
+// two differ. Place names are given as they were in the year of the event,
+// so a birth in 1900 shows Batavia rather than Jakarta.
+func BuildMigrationMap(input MigrationMapInput) *MigrationMap {
+	result := &MigrationMap{
+		Type:       "FeatureCollection",
+		Features:   []GeoJSONFeature{},
+		RootPerson: input.RootPerson,
+		Direction:  input.Direction,
+		Unlocated:  []uuid.UUID{},
+	}
+
+	persons := make(map[uuid.UUID]*Person, len(input.Persons))
+	for i := range input.Persons {
+		p := &input.Persons[i]
+		persons[p.ID] = p
+
+		birth := input.place(p.BirthPlaceID)
+		death := input.place(p.DeathPlaceID)
+		if birth == nil && death == nil {
+			result.Unlocated = append(result.Unlocated, p.ID)
+			continue
+		}
+		if birth != nil {
+			result.Features = append(result.Features, input.personPoint(p, MigrationFeatureBirth, birth, p.BirthDate))
+		}
+		if death != nil {
+			result.Features = append(result.Features, input.personPoint(p, MigrationFeatureDeath, death, p.DeathDate))
+		}
+	}
+
+	for _, link := range input.Links {
+		parent, child := persons[link.Parent], persons[link.Child]
+		if parent == nil || child == nil {
+			continue
+		}
+		from, to := input.place(parent.BirthPlaceID), input.place(child.BirthPlaceID)
+		if from == nil || to == nil || from.ID == to.ID {
+			continue
+		}
+
+		props := map[string]any{
+			"kind":       MigrationFeatureArc,
+			"parent_id":  parent.ID,
+			"child_id":   child.ID,
+			"generation": input.Generations[child.ID],
+			"from_place": placeName(from, parent.BirthDate),
+			"to_place":   placeName(to, child.BirthDate),
+		}
+		if child.BirthDate != nil {
+			props["year"] = child.BirthDate.Year()
+		}
+		result.Features = append(result.Features, GeoJSONFeature{
+			Type: "Feature",
+			Geometry: GeoJSONGeometry{
+				Type:        "LineString",
+				Coordinates: [][2]float64{lonLat(from), lonLat(to)},
+			},
+			Properties: props,
+		})
+	}
+
+	return result
+}
+
+func (in MigrationMapInput) place(id *uuid.UUID) *Place {
+	if id == nil {
+		return nil
+	}
+	p := in.Places[*id]
+	if !p.HasCoordinates() {
+		return nil
+	}
+	return p
+}
+
+func (in MigrationMapInput) personPoint(p *Person, kind string, place *Place, date *GenDate) GeoJSONFeature {
+	props := map[string]any{
+		"kind":       kind,
+		"person_id":  p.ID,
+		"name":       p.FullName(),
+		"gender":     p.Gender,
+		"generation": in.Generations[p.ID],
+		"place_id":   place.ID,
+		"place":      placeName(place, date),
+	}
+	if date != nil {
+		props["year"] = date.Year()
+	}
+	return GeoJSONFeature{
+		Type:       "Feature",
+		Geometry:   GeoJSONGeometry{Type: "Point", Coordinates: lonLat(place)},
+		Properties: props,
+	}
+}
+
+func placeName(p *Place, date *GenDate) string {
+	if date == nil {
+		return p.Name
+	}
+	return p.NameAt(date.Year())
+}
+
+func lonLat(p *Place) [2]float64 {
+	return [2]float64{*p.Longitude, *p.Latitude}
+}
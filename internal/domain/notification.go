@@ -2,11 +2,16 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrNotificationExists is returned when a notification with the same ID
+// was already saved.
+var ErrNotificationExists = errors.New("notification already exists")
+
 type Notification struct {
 	ID        uuid.UUID        `json:"id" db:"notification_id"`
 	UserID    uuid.UUID        `json:"user_id" db:"user_id"`
@@ -28,4 +33,28 @@ const (
 	NotifNewComment        NotificationType = "NEW_COMMENT"
 	NotifPersonAdded       NotificationType = "PERSON_ADDED"
 	NotifRelationshipAdded NotificationType = "RELATIONSHIP_ADDED"
+	NotifChangesRequested  NotificationType = "CHANGES_REQUESTED"
+	NotifChangeWithdrawn   NotificationType = "CHANGE_WITHDRAWN"
+	NotifChangeAmended     NotificationType = "CHANGE_AMENDED"
+	NotifChangeExpired     NotificationType = "CHANGE_EXPIRED"
+	NotifChangeComment     NotificationType = "CHANGE_REQUEST_COMMENT"
 )
+
+// OutboxMessage is a notification recorded in the same transaction as the
+// change it announces and delivered after commit. EntityID is the change
+// request, person or relationship the notification is about; ActorID the
+// user who caused it, if any. A message with Notification set carries a
+// notification prepared when it was enqueued, delivered as is.
+type OutboxMessage struct {
+	ID           uuid.UUID        `json:"id" db:"outbox_id"`
+	Type         NotificationType `json:"type" db:"type"`
+	EntityID     uuid.UUID        `json:"entity_id" db:"entity_id"`
+	ActorID      uuid.UUID        `json:"actor_id" db:"actor_id"`
+	Notification json.RawMessage  `json:"notification,omitempty" db:"notification"`
+	Attempts     int              `json:"attempts" db:"attempts"`
+	LastError    *string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	ClaimedAt    *time.Time       `json:"claimed_at,omitempty" db:"claimed_at"`
+	DispatchedAt *time.Time       `json:"dispatched_at,omitempty" db:"dispatched_at"`
+	NextAttempt  *time.Time       `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/pkg/namenorm"
 )
 
 type NullableString struct {
@@ -13,6 +15,12 @@ type NullableString struct {
 	Set   bool
 }
 
+// MarshalJSON writes the value, or null when it was cleared. Tag fields
+// omitzero so that a field never set is left out.
+func (n NullableString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
 func (n *NullableString) UnmarshalJSON(data []byte) error {
 	n.Set = true
 	if string(data) == "null" {
@@ -32,6 +40,10 @@ type NullableTime struct {
 	Set   bool
 }
 
+func (n NullableTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
 func (n *NullableTime) UnmarshalJSON(data []byte) error {
 	n.Set = true
 	if string(data) == "null" {
@@ -51,6 +63,10 @@ type NullableGender struct {
 	Set   bool
 }
 
+func (n NullableGender) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
 func (n *NullableGender) UnmarshalJSON(data []byte) error {
 	n.Set = true
 	if string(data) == "null" {
@@ -65,16 +81,45 @@ func (n *NullableGender) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type NullableUUID struct {
+	Value *uuid.UUID
+	Set   bool
+}
+
+func (n NullableUUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
+func (n *NullableUUID) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var id uuid.UUID
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	n.Value = &id
+	return nil
+}
+
 type Person struct {
 	ID         uuid.UUID  `json:"id" db:"person_id"`
 	FirstName  string     `json:"first_name" db:"first_name"`
 	LastName   *string    `json:"last_name,omitempty" db:"last_name"`
 	Nickname   *string    `json:"nickname,omitempty" db:"nickname"`
 	Gender     Gender     `json:"gender" db:"gender"`
-	BirthDate  *time.Time `json:"birth_date,omitempty" db:"birth_date"`
+	BirthDate  *GenDate   `json:"birth_date,omitempty" db:"birth_date"`
+	BirthDateMin *time.Time `json:"-" db:"birth_date_min"`
+	BirthDateMax *time.Time `json:"-" db:"birth_date_max"`
 	BirthPlace  *string    `json:"birth_place,omitempty" db:"birth_place"`
-	DeathDate   *time.Time `json:"death_date,omitempty" db:"death_date"`
+	BirthPlaceID *uuid.UUID `json:"birth_place_id,omitempty" db:"birth_place_id"`
+	DeathDate   *GenDate   `json:"death_date,omitempty" db:"death_date"`
+	DeathDateMin *time.Time `json:"-" db:"death_date_min"`
+	DeathDateMax *time.Time `json:"-" db:"death_date_max"`
 	DeathPlace  *string    `json:"death_place,omitempty" db:"death_place"`
+	DeathPlaceID *uuid.UUID `json:"death_place_id,omitempty" db:"death_place_id"`
 	Bio         *string    `json:"bio,omitempty" db:"bio"`
 	AvatarURL   *string    `json:"avatar_url,omitempty" db:"avatar_url"`
 	Occupation  *string    `json:"occupation,omitempty" db:"occupation"`
@@ -85,10 +130,22 @@ type Person struct {
 	Email       *string    `json:"email,omitempty" db:"email"`
 	Address     *string    `json:"address,omitempty" db:"address"`
 	IsAlive     bool       `json:"is_alive" db:"is_alive"`
+	PrivacyLevel PrivacyLevel `json:"privacy_level" db:"privacy_level"`
 	CreatedBy   uuid.UUID  `json:"created_by" db:"created_by"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time `json:"-" db:"deleted_at"`
+	DeletedBy   *uuid.UUID `json:"-" db:"deleted_by"`
+	DeletionID  *uuid.UUID `json:"-" db:"deletion_id"`
+
+	NameNormalized *string `json:"-" db:"name_normalized"`
+	NamePhonetic   *string `json:"-" db:"name_phonetic"`
+
+	Names        []PersonName        `json:"names,omitempty" db:"-"`
+	CustomFields []PersonCustomValue `json:"custom_fields,omitempty" db:"-"`
+
+	// Redacted marks a record stripped for the viewer; see Redact.
+	Redacted bool `json:"redacted,omitempty" db:"-"`
 }
 
 type Gender string
@@ -100,7 +157,8 @@ const (
 )
 
 var (
-	ErrPersonNotFound = errors.New("person not found")
+	ErrPersonNotFound   = errors.New("person not found")
+	ErrInvalidLifeDates = errors.New("death date cannot be before birth date")
 )
 
 func (g Gender) IsValid() bool {
@@ -116,10 +174,12 @@ type CreatePersonInput struct {
 	LastName    *string    `json:"last_name,omitempty" validate:"omitempty,max=100"`
 	Nickname    *string    `json:"nickname,omitempty" validate:"omitempty,max=50"`
 	Gender      Gender     `json:"gender" validate:"required"`
-	BirthDate   *time.Time `json:"birth_date,omitempty"`
+	BirthDate   *GenDate   `json:"birth_date,omitempty"`
 	BirthPlace  *string    `json:"birth_place,omitempty" validate:"omitempty,max=200"`
-	DeathDate   *time.Time `json:"death_date,omitempty"`
+	BirthPlaceID *uuid.UUID `json:"birth_place_id,omitempty"`
+	DeathDate   *GenDate   `json:"death_date,omitempty"`
 	DeathPlace  *string    `json:"death_place,omitempty" validate:"omitempty,max=200"`
+	DeathPlaceID *uuid.UUID `json:"death_place_id,omitempty"`
 	Bio         *string    `json:"bio,omitempty" validate:"omitempty,max=2000"`
 	Occupation  *string    `json:"occupation,omitempty" validate:"omitempty,max=200"`
 	Religion    *string    `json:"religion,omitempty" validate:"omitempty,max=50"`
@@ -129,33 +189,57 @@ type CreatePersonInput struct {
 	Email       *string    `json:"email,omitempty" validate:"omitempty,email,max=255"`
 	Address     *string    `json:"address,omitempty" validate:"omitempty,max=500"`
 	IsAlive     *bool      `json:"is_alive,omitempty"`
+	PrivacyLevel *PrivacyLevel `json:"privacy_level,omitempty"`
+
+	// IgnoreDuplicates confirms the person is new even though similar
+	// records exist.
+	IgnoreDuplicates bool `json:"ignore_duplicates,omitempty"`
 }
 
 type UpdatePersonInput struct {
-	FirstName   *string        `json:"first_name" validate:"omitempty,min=1,max=100"`
-	LastName    NullableString `json:"last_name" validate:"omitempty,max=100"`
-	Nickname    NullableString `json:"nickname" validate:"omitempty,max=50"`
-	Gender      NullableGender `json:"gender"`
-	BirthDate   NullableTime   `json:"birth_date"`
-	BirthPlace  NullableString `json:"birth_place" validate:"omitempty,max=200"`
-	DeathDate   NullableTime   `json:"death_date"`
-	DeathPlace  NullableString `json:"death_place" validate:"omitempty,max=200"`
-	Bio         NullableString `json:"bio" validate:"omitempty,max=2000"`
-	AvatarURL   NullableString `json:"avatar_url"`
-	Occupation  NullableString `json:"occupation" validate:"omitempty,max=200"`
-	Religion    NullableString `json:"religion" validate:"omitempty,max=50"`
-	Nationality NullableString `json:"nationality" validate:"omitempty,max=100"`
-	Education   NullableString `json:"education" validate:"omitempty,max=200"`
-	Phone       NullableString `json:"phone" validate:"omitempty,max=20"`
-	Email       NullableString `json:"email" validate:"omitempty,email,max=255"`
-	Address     NullableString `json:"address" validate:"omitempty,max=500"`
-	IsAlive     *bool          `json:"is_alive"`
-}
-
-type PersonSearchInput struct {
-	Query  string `json:"query" validate:"required,min=2"`
-	Limit  int    `json:"limit" validate:"omitempty,min=1,max=50"`
-	Offset int    `json:"offset" validate:"omitempty,min=0"`
+	FirstName   *string        `json:"first_name,omitempty" validate:"omitempty,min=1,max=100"`
+	LastName    NullableString `json:"last_name,omitzero" validate:"omitempty,max=100"`
+	Nickname    NullableString `json:"nickname,omitzero" validate:"omitempty,max=50"`
+	Gender      NullableGender `json:"gender,omitzero"`
+	BirthDate   NullableGenDate `json:"birth_date,omitzero"`
+	BirthPlace  NullableString `json:"birth_place,omitzero" validate:"omitempty,max=200"`
+	BirthPlaceID NullableUUID  `json:"birth_place_id,omitzero"`
+	DeathDate   NullableGenDate `json:"death_date,omitzero"`
+	DeathPlace  NullableString `json:"death_place,omitzero" validate:"omitempty,max=200"`
+	DeathPlaceID NullableUUID  `json:"death_place_id,omitzero"`
+	Bio         NullableString `json:"bio,omitzero" validate:"omitempty,max=2000"`
+	AvatarURL   NullableString `json:"avatar_url,omitzero"`
+	Occupation  NullableString `json:"occupation,omitzero" validate:"omitempty,max=200"`
+	Religion    NullableString `json:"religion,omitzero" validate:"omitempty,max=50"`
+	Nationality NullableString `json:"nationality,omitzero" validate:"omitempty,max=100"`
+	Education   NullableString `json:"education,omitzero" validate:"omitempty,max=200"`
+	Phone       NullableString `json:"phone,omitzero" validate:"omitempty,max=20"`
+	Email       NullableString `json:"email,omitzero" validate:"omitempty,email,max=255"`
+	Address     NullableString `json:"address,omitzero" validate:"omitempty,max=500"`
+	IsAlive     *bool          `json:"is_alive,omitempty"`
+	PrivacyLevel *PrivacyLevel `json:"privacy_level,omitempty"`
+}
+
+// ValidateLifeDates rejects a death date that certainly precedes the birth
+// date; overlapping approximate dates are accepted, mirroring chk_dates.
+func (p *Person) ValidateLifeDates() error {
+	if p.DeathDate.Before(p.BirthDate) {
+		return ErrInvalidLifeDates
+	}
+	return nil
+}
+
+// SyncDateBounds refreshes the stored date ranges that back sorting and the
+// chk_dates constraint from BirthDate and DeathDate.
+func (p *Person) SyncDateBounds() {
+	p.BirthDateMin, p.BirthDateMax = p.BirthDate.Earliest(), p.BirthDate.Latest()
+	p.DeathDateMin, p.DeathDateMax = p.DeathDate.Earliest(), p.DeathDate.Latest()
+}
+
+// SyncNameKeys refreshes the normalized and phonetic forms of the full name
+// used for spelling-tolerant search.
+func (p *Person) SyncNameKeys() {
+	p.NameNormalized, p.NamePhonetic = nameKeys(p.FullName())
 }
 
 func (p *Person) FullName() string {
@@ -167,11 +251,13 @@ func (p *Person) FullName() string {
 
 type PersonWithRelationships struct {
 	Person
+	Patronymic    *string            `json:"patronymic,omitempty"`
 	Parents       []ParentInfo       `json:"parents"`
 	Spouses       []SpouseInfo       `json:"spouses"`
 	Children      []Person           `json:"children"`
 	Siblings      []SiblingInfo      `json:"siblings"`
 	Relationships []RelationshipInfo `json:"relationships"`
+	Citations     []Citation         `json:"citations"`
 }
 
 type RelationshipInfo struct {
@@ -197,3 +283,8 @@ type SiblingInfo struct {
 	Person     Person `json:"person"`
 	SiblingType string `json:"sibling_type"`
 }
+
+func nameKeys(name string) (normalized, phonetic *string) {
+	n, ph := namenorm.Normalize(name), namenorm.Phonetic(name)
+	return &n, &ph
+}
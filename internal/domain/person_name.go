@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NameType classifies the alternate names a person is known by in addition
+// to the primary FirstName/LastName on Person.
+type NameType string
+
+const (
+	NameTypeBirth     NameType = "BIRTH"
+	NameTypeMarried   NameType = "MARRIED"
+	NameTypeReligious NameType = "RELIGIOUS"
+	NameTypeArabic    NameType = "ARABIC"
+	NameTypeTitle     NameType = "TITLE"
+	NameTypeAlias     NameType = "ALIAS"
+)
+
+var (
+	ErrPersonNameNotFound  = errors.New("person name not found")
+	ErrInvalidNameType     = errors.New("invalid name type")
+	ErrInvalidNameValidity = errors.New("name validity ends before it starts")
+)
+
+func (t NameType) IsValid() bool {
+	switch t {
+	case NameTypeBirth, NameTypeMarried, NameTypeReligious, NameTypeArabic, NameTypeTitle, NameTypeAlias:
+		return true
+	}
+	return false
+}
+
+type PersonName struct {
+	ID        uuid.UUID  `json:"id" db:"name_id"`
+	PersonID  uuid.UUID  `json:"person_id" db:"person_id"`
+	Type      NameType   `json:"type" db:"type"`
+	Name      string     `json:"name" db:"name"`
+	ValidFrom *GenDate   `json:"valid_from,omitempty" db:"valid_from"`
+	ValidTo   *GenDate   `json:"valid_to,omitempty" db:"valid_to"`
+	CreatedBy uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+
+	NameNormalized *string `json:"-" db:"name_normalized"`
+	NamePhonetic   *string `json:"-" db:"name_phonetic"`
+}
+
+// SyncNameKeys refreshes the normalized and phonetic forms of Name.
+func (n *PersonName) SyncNameKeys() {
+	n.NameNormalized, n.NamePhonetic = nameKeys(n.Name)
+}
+
+// ValidateValidity rejects a validity period that certainly ends before it
+// starts.
+func (n *PersonName) ValidateValidity() error {
+	if n.ValidTo.Before(n.ValidFrom) {
+		return ErrInvalidNameValidity
+	}
+	return nil
+}
+
+// IsCurrentAt reports whether the name may be in use at the given moment.
+// Open or unknown bounds count as valid.
+func (n *PersonName) IsCurrentAt(at time.Time) bool {
+	if from := n.ValidFrom.Earliest(); from != nil && from.After(at) {
+		return false
+	}
+	if to := n.ValidTo.Latest(); to != nil && to.Before(at) {
+		return false
+	}
+	return true
+}
+
+type CreatePersonNameInput struct {
+	Type      NameType `json:"type" validate:"required"`
+	Name      string   `json:"name" validate:"required,min=1,max=200"`
+	ValidFrom *GenDate `json:"valid_from,omitempty"`
+	ValidTo   *GenDate `json:"valid_to,omitempty"`
+}
+
+type UpdatePersonNameInput struct {
+	Type      *NameType       `json:"type"`
+	Name      *string         `json:"name" validate:"omitempty,min=1,max=200"`
+	ValidFrom NullableGenDate `json:"valid_from"`
+	ValidTo   NullableGenDate `json:"valid_to"`
+}
+
+// CurrentTitle returns the gelar currently held according to names, if any.
+func CurrentTitle(names []PersonName, at time.Time) *string {
+	for i := range names {
+		if names[i].Type == NameTypeTitle && names[i].IsCurrentAt(at) {
+			return &names[i].Name
+		}
+	}
+	return nil
+}
+
+// Patronymic returns the "X bin Y" (sons) or "X binti Y" (daughters) form of
+// a person's name built from the father's first name. It returns nil when
+// the father or the person's gender is unknown.
+func Patronymic(p *Person, father *Person) *string {
+	if p == nil || father == nil {
+		return nil
+	}
+	var link string
+	switch p.Gender {
+	case GenderMale:
+		link = "bin"
+	case GenderFemale:
+		link = "binti"
+	default:
+		return nil
+	}
+	name := p.FirstName + " " + link + " " + father.FirstName
+	return &name
+}
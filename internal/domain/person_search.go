@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+type PersonSortField string
+
+const (
+	SortByName      PersonSortField = "name"
+	SortByBirthDate PersonSortField = "birth_date"
+	SortByDeathDate PersonSortField = "death_date"
+	SortByCreatedAt PersonSortField = "created_at"
+	SortByUpdatedAt PersonSortField = "updated_at"
+)
+
+func (f PersonSortField) IsValid() bool {
+	switch f {
+	case SortByName, SortByBirthDate, SortByDeathDate, SortByCreatedAt, SortByUpdatedAt:
+		return true
+	}
+	return false
+}
+
+// PersonSearchInput filters persons. Every filter is optional; year ranges
+// match any person whose possible dates overlap the range, so "ABT 1920"
+// is found by a 1921 search.
+type PersonSearchInput struct {
+	Query         string              `json:"query"`
+	Gender        *Gender             `json:"gender,omitempty"`
+	IsAlive       *bool               `json:"is_alive,omitempty"`
+	BirthYearFrom *int                `json:"birth_year_from,omitempty"`
+	BirthYearTo   *int                `json:"birth_year_to,omitempty"`
+	DeathYearFrom *int                `json:"death_year_from,omitempty"`
+	DeathYearTo   *int                `json:"death_year_to,omitempty"`
+	BirthPlace    string              `json:"birth_place,omitempty"`
+	Occupation    string              `json:"occupation,omitempty"`
+	Religion      string              `json:"religion,omitempty"`
+	CreatedBy     *uuid.UUID          `json:"created_by,omitempty"`
+	NoParents     bool                `json:"no_parents,omitempty"`
+	NoChildren    bool                `json:"no_children,omitempty"`
+	CustomFields  []CustomFieldFilter `json:"custom_fields,omitempty"`
+	// Viewer limits filters to what the searching user may see: custom
+	// fields above their role, and redacted facts of hidden living persons,
+	// cannot be probed through search.
+	Viewer     Viewer          `json:"-"`
+	Sort       PersonSortField `json:"sort"`
+	Descending bool            `json:"descending"`
+	Limit      int             `json:"limit"`
+	Offset     int             `json:"offset"`
+}
+
+// Validate checks the filters and fills in paging and sort defaults.
+func (in *PersonSearchInput) Validate() error {
+	if in.Query != "" && len([]rune(in.Query)) < 2 {
+		return fmt.Errorf("%w: search query must be at least 2 characters", ErrInvalidSearch)
+	}
+	if in.Gender != nil && !in.Gender.IsValid() {
+		return fmt.Errorf("%w: invalid gender", ErrInvalidSearch)
+	}
+	if in.BirthYearFrom != nil && in.BirthYearTo != nil && *in.BirthYearFrom > *in.BirthYearTo {
+		return fmt.Errorf("%w: birth_year_from is after birth_year_to", ErrInvalidSearch)
+	}
+	if in.DeathYearFrom != nil && in.DeathYearTo != nil && *in.DeathYearFrom > *in.DeathYearTo {
+		return fmt.Errorf("%w: death_year_from is after death_year_to", ErrInvalidSearch)
+	}
+	for _, f := range in.CustomFields {
+		if f.Key == "" || strings.TrimSpace(f.Value) == "" {
+			return fmt.Errorf("%w: custom field filters need a key and a value", ErrInvalidSearch)
+		}
+	}
+	if in.Sort == "" {
+		in.Sort = SortByName
+	}
+	if !in.Sort.IsValid() {
+		return fmt.Errorf("%w: invalid sort field", ErrInvalidSearch)
+	}
+	if in.Limit <= 0 {
+		in.Limit = 20
+	}
+	if in.Limit > 100 {
+		in.Limit = 100
+	}
+	if in.Offset < 0 {
+		in.Offset = 0
+	}
+	return nil
+}
+
+type FacetCount struct {
+	Value string `json:"value" db:"value"`
+	Count int64  `json:"count" db:"count"`
+}
+
+// PersonSearchFacets counts the persons matching a search by a few
+// dimensions. Persons without a value are counted under "".
+type PersonSearchFacets struct {
+	Gender      []FacetCount `json:"gender"`
+	Living      []FacetCount `json:"living"`
+	Religion    []FacetCount `json:"religion"`
+	BirthDecade []FacetCount `json:"birth_decade"`
+}
+
+type PersonSearchResult struct {
+	Data       []Person            `json:"data"`
+	TotalItems int64               `json:"total_items"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+	Facets     *PersonSearchFacets `json:"facets"`
+}
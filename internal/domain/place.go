@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlaceLevel is a tier of the Indonesian administrative hierarchy. KABUPATEN
+// covers both kabupaten and kota, DESA both desa and kelurahan.
+type PlaceLevel string
+
+const (
+	PlaceLevelCountry   PlaceLevel = "COUNTRY"
+	PlaceLevelProvinsi  PlaceLevel = "PROVINSI"
+	PlaceLevelKabupaten PlaceLevel = "KABUPATEN"
+	PlaceLevelKecamatan PlaceLevel = "KECAMATAN"
+	PlaceLevelDesa      PlaceLevel = "DESA"
+)
+
+var (
+	ErrPlaceNotFound         = errors.New("place not found")
+	ErrInvalidPlaceLevel     = errors.New("invalid place level")
+	ErrInvalidPlaceHierarchy = errors.New("place must sit below its parent's level")
+	ErrInvalidCoordinates    = errors.New("invalid coordinates")
+	ErrPlaceInUse            = errors.New("place has sub-places or is referenced")
+)
+
+var placeLevelRank = map[PlaceLevel]int{
+	PlaceLevelCountry:   0,
+	PlaceLevelProvinsi:  1,
+	PlaceLevelKabupaten: 2,
+	PlaceLevelKecamatan: 3,
+	PlaceLevelDesa:      4,
+}
+
+func (l PlaceLevel) IsValid() bool {
+	_, ok := placeLevelRank[l]
+	return ok
+}
+
+// Rank orders levels from the country (0) down to the desa (4).
+func (l PlaceLevel) Rank() int {
+	return placeLevelRank[l]
+}
+
+// HistoricalName is an earlier name of a place, e.g. Batavia for Jakarta.
+// Open years mean the bound is unknown.
+type HistoricalName struct {
+	Name     string `json:"name"`
+	FromYear *int   `json:"from_year,omitempty"`
+	ToYear   *int   `json:"to_year,omitempty"`
+}
+
+// HistoricalNames is stored as a JSONB array.
+type HistoricalNames []HistoricalName
+
+func (h *HistoricalNames) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*h = HistoricalNames{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into HistoricalNames", src)
+	}
+	return json.Unmarshal(data, h)
+}
+
+func (h HistoricalNames) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(h)
+	return string(data), err
+}
+
+type Place struct {
+	ID              uuid.UUID       `json:"id" db:"place_id"`
+	ParentID        *uuid.UUID      `json:"parent_id,omitempty" db:"parent_id"`
+	Level           PlaceLevel      `json:"level" db:"level"`
+	Name            string          `json:"name" db:"name"`
+	Code            *string         `json:"code,omitempty" db:"code"`
+	Latitude        *float64        `json:"latitude,omitempty" db:"latitude"`
+	Longitude       *float64        `json:"longitude,omitempty" db:"longitude"`
+	HistoricalNames HistoricalNames `json:"historical_names" db:"historical_names"`
+	CreatedBy       *uuid.UUID      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time      `json:"-" db:"deleted_at"`
+
+	FullName string `json:"full_name,omitempty" db:"-"`
+}
+
+// NameAt returns the name the place had in the given year, falling back to
+// its current name.
+func (p *Place) NameAt(year int) string {
+	for _, h := range p.HistoricalNames {
+		if (h.FromYear == nil || *h.FromYear <= year) && (h.ToYear == nil || year <= *h.ToYear) {
+			return h.Name
+		}
+	}
+	return p.Name
+}
+
+// HasCoordinates reports whether the place can be put on a map.
+func (p *Place) HasCoordinates() bool {
+	return p != nil && p.Latitude != nil && p.Longitude != nil
+}
+
+// PlacePath joins a place and its ancestors, most specific first, into a
+// display name such as "Kota Surabaya, Jawa Timur, Indonesia".
+func PlacePath(path []Place) string {
+	names := make([]string, len(path))
+	for i, p := range path {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// ValidateCoordinates requires both or neither coordinate, within range.
+func ValidateCoordinates(lat, lon *float64) error {
+	if (lat == nil) != (lon == nil) {
+		return fmt.Errorf("%w: latitude and longitude go together", ErrInvalidCoordinates)
+	}
+	if lat != nil && (*lat < -90 || *lat > 90 || *lon < -180 || *lon > 180) {
+		return fmt.Errorf("%w: out of range", ErrInvalidCoordinates)
+	}
+	return nil
+}
+
+// ValidatePlaceParent checks that a place of the given level may sit below
+// parent. Only countries may have no parent.
+func ValidatePlaceParent(level PlaceLevel, parent *Place) error {
+	if !level.IsValid() {
+		return ErrInvalidPlaceLevel
+	}
+	if parent == nil {
+		if level != PlaceLevelCountry {
+			return fmt.Errorf("%w: only a country has no parent", ErrInvalidPlaceHierarchy)
+		}
+		return nil
+	}
+	if level.Rank() <= parent.Level.Rank() {
+		return ErrInvalidPlaceHierarchy
+	}
+	return nil
+}
+
+type CreatePlaceInput struct {
+	ParentID        *uuid.UUID      `json:"parent_id"`
+	Level           PlaceLevel      `json:"level" validate:"required"`
+	Name            string          `json:"name" validate:"required,min=1,max=200"`
+	Latitude        *float64        `json:"latitude"`
+	Longitude       *float64        `json:"longitude"`
+	HistoricalNames HistoricalNames `json:"historical_names"`
+}
+
+type UpdatePlaceInput struct {
+	ParentID        NullableUUID     `json:"parent_id"`
+	Level           *PlaceLevel      `json:"level"`
+	Name            *string          `json:"name" validate:"omitempty,min=1,max=200"`
+	Latitude        *float64         `json:"latitude"`
+	Longitude       *float64         `json:"longitude"`
+	HistoricalNames *HistoricalNames `json:"historical_names"`
+}
@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrivacyLevel decides who may see the full record of a living person.
+// Deceased persons are never redacted.
+type PrivacyLevel string
+
+const (
+	// PrivacyPublic shows everything to every viewer.
+	PrivacyPublic PrivacyLevel = "PUBLIC"
+	// PrivacyFamily shows everything to signed-in members.
+	PrivacyFamily PrivacyLevel = "FAMILY"
+	// PrivacyEditors shows everything to editors and developers only.
+	PrivacyEditors PrivacyLevel = "EDITORS"
+	// PrivacyPrivate shows the person only to developers and the user
+	// linked to the person; everyone else does not even see the name.
+	PrivacyPrivate PrivacyLevel = "PRIVATE"
+)
+
+func (l PrivacyLevel) IsValid() bool {
+	switch l {
+	case PrivacyPublic, PrivacyFamily, PrivacyEditors, PrivacyPrivate:
+		return true
+	}
+	return false
+}
+
+var ErrInvalidPrivacyLevel = errors.New("invalid privacy level")
+
+// LivingMaxAge is the age after which a person without a death date is no
+// longer presumed living.
+const LivingMaxAge = 110
+
+// PrivateName replaces the name of a person hidden at the PRIVATE level.
+const PrivateName = "Private"
+
+// Viewer is who a response is redacted for. The zero Viewer is an outsider
+// and only sees PUBLIC records.
+type Viewer struct {
+	UserID uuid.UUID
+	Role   UserRole
+	// PersonID is the person linked to the viewer's account, who always
+	// sees their own record in full.
+	PersonID *uuid.UUID
+}
+
+// ViewerOf returns the viewer for a signed-in user.
+func ViewerOf(u *User) Viewer {
+	if u == nil {
+		return Viewer{}
+	}
+	return Viewer{UserID: u.ID, Role: UserRole(u.Role), PersonID: u.PersonID}
+}
+
+// CanSee reports whether the viewer may see the full record of a living
+// person with the given privacy level.
+func (v Viewer) CanSee(personID uuid.UUID, level PrivacyLevel) bool {
+	if v.PersonID != nil && *v.PersonID == personID {
+		return true
+	}
+	switch level {
+	case PrivacyPublic:
+		return true
+	case PrivacyFamily:
+		return v.Role.AtLeast(RoleMember)
+	case PrivacyEditors:
+		return v.Role.AtLeast(RoleEditor)
+	case PrivacyPrivate:
+		return v.Role.AtLeast(RoleDeveloper)
+	}
+	// Rows without a level are treated like the column default.
+	return v.Role.AtLeast(RoleMember)
+}
+
+// HiddenLevels returns the privacy levels whose living persons the viewer
+// sees redacted, apart from their own linked person.
+func (v Viewer) HiddenLevels() []PrivacyLevel {
+	var levels []PrivacyLevel
+	for _, l := range []PrivacyLevel{PrivacyPublic, PrivacyFamily, PrivacyEditors, PrivacyPrivate} {
+		if !v.CanSee(uuid.Nil, l) {
+			levels = append(levels, l)
+		}
+	}
+	return levels
+}
+
+// probablyLiving presumes a person is alive unless marked deceased, given a
+// death date or born more than LivingMaxAge years ago.
+func probablyLiving(isAlive, hasDeath bool, birthYear int, now time.Time) bool {
+	if !isAlive || hasDeath {
+		return false
+	}
+	return birthYear == 0 || now.Year()-birthYear < LivingMaxAge
+}
+
+// ProbablyLiving reports whether the person is, or may well be, still alive.
+func (p *Person) ProbablyLiving(now time.Time) bool {
+	return probablyLiving(p.IsAlive, p.DeathDate.HasDate(), p.BirthDate.Year(), now)
+}
+
+// SharedName is the first name to use in text not built for one viewer,
+// such as relationship narratives and notifications: PRIVATE living
+// persons are named PrivateName there.
+func (p *Person) SharedName(now time.Time) string {
+	if p.PrivacyLevel == PrivacyPrivate && p.ProbablyLiving(now) {
+		return PrivateName
+	}
+	return p.FirstName
+}
+
+// Redact strips what the viewer may not see from a living person: contact
+// details, bio, birth place, custom fields and the birth day and month. At
+// the PRIVATE level the name, photo and remaining facts go too.
+func (p *Person) Redact(v Viewer, now time.Time) {
+	if p.Redacted || !p.ProbablyLiving(now) || v.CanSee(p.ID, p.PrivacyLevel) {
+		return
+	}
+	p.Redacted = true
+
+	p.Phone, p.Email, p.Address, p.Bio = nil, nil, nil, nil
+	p.BirthPlace, p.BirthPlaceID = nil, nil
+	p.CustomFields = nil
+	if year := p.BirthDate.Year(); year != 0 {
+		p.BirthDate = NewYearDate(DateExact, year)
+	} else {
+		p.BirthDate = nil
+	}
+
+	if p.PrivacyLevel == PrivacyPrivate {
+		p.FirstName = PrivateName
+		p.LastName, p.Nickname, p.AvatarURL = nil, nil, nil
+		p.Occupation, p.Religion, p.Nationality, p.Education = nil, nil, nil, nil
+		p.BirthDate = nil
+		p.Names = nil
+	}
+}
+
+// RedactedFields names the JSON fields Redact would clear or coarsen for
+// the viewer, or nil when the person is shown in full.
+func (p *Person) RedactedFields(v Viewer, now time.Time) []string {
+	if !p.ProbablyLiving(now) || v.CanSee(p.ID, p.PrivacyLevel) {
+		return nil
+	}
+	fields := []string{"phone", "email", "address", "bio", "birth_place", "birth_place_id", "custom_fields", "birth_date"}
+	if p.PrivacyLevel == PrivacyPrivate {
+		fields = append(fields, "first_name", "last_name", "nickname", "avatar_url",
+			"occupation", "religion", "nationality", "education", "names")
+	}
+	return fields
+}
+
+// RedactPersons redacts every person in place.
+func RedactPersons(persons []Person, v Viewer, now time.Time) {
+	for i := range persons {
+		persons[i].Redact(v, now)
+	}
+}
+
+// Redact redacts the person and every related person in the response.
+// Citations on fields of a redacted person are dropped with the fields.
+func (p *PersonWithRelationships) Redact(v Viewer, now time.Time) {
+	p.Person.Redact(v, now)
+	if p.Person.Redacted {
+		p.Citations = []Citation{}
+		if p.PrivacyLevel == PrivacyPrivate {
+			p.Patronymic = nil
+		}
+	}
+	for i := range p.Parents {
+		p.Parents[i].Person.Redact(v, now)
+	}
+	for i := range p.Spouses {
+		p.Spouses[i].Person.Redact(v, now)
+	}
+	RedactPersons(p.Children, v, now)
+	for i := range p.Siblings {
+		p.Siblings[i].Person.Redact(v, now)
+	}
+	for i := range p.Relationships {
+		if p.Relationships[i].RelatedPerson != nil {
+			p.Relationships[i].RelatedPerson.Redact(v, now)
+		}
+	}
+}
+
+// Redact redacts the persons loaded with the relationship.
+func (r *Relationship) Redact(v Viewer, now time.Time) {
+	for _, p := range []*Person{r.PersonAData, r.PersonBData} {
+		if p != nil {
+			p.Redact(v, now)
+		}
+	}
+}
+
+// RedactRelationships redacts every relationship in place.
+func RedactRelationships(rels []Relationship, v Viewer, now time.Time) {
+	for i := range rels {
+		rels[i].Redact(v, now)
+	}
+}
+
+// Redact hides the name and estimated birth year of a living PRIVATE
+// person the viewer may not see.
+func (e *LivingEstimate) Redact(v Viewer) {
+	if e.ProbablyLiving && e.PrivacyLevel == PrivacyPrivate && !v.CanSee(e.PersonID, e.PrivacyLevel) {
+		e.Name = PrivateName
+		e.EstimatedBirthYear = nil
+	}
+}
+
+// Redact hides a living graph node the viewer may not see. Nodes keep the
+// birth year unless the person is PRIVATE.
+func (n *GraphNode) Redact(v Viewer, now time.Time) {
+	birthYear := 0
+	if n.BirthYear != nil {
+		birthYear = *n.BirthYear
+	}
+	if n.Redacted || !probablyLiving(n.IsAlive, n.DeathYear != nil, birthYear, now) || v.CanSee(n.ID, n.PrivacyLevel) {
+		return
+	}
+	n.Redacted = true
+
+	if n.PrivacyLevel == PrivacyPrivate {
+		n.FirstName = PrivateName
+		n.LastName, n.Nickname, n.AvatarURL, n.Title = nil, nil, nil, nil
+		n.AlternateNames = nil
+		n.BirthYear = nil
+	}
+}
+
+// RedactNodes redacts every node in place.
+func RedactNodes(nodes []GraphNode, v Viewer, now time.Time) {
+	for i := range nodes {
+		nodes[i].Redact(v, now)
+	}
+}
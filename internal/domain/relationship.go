@@ -17,6 +17,8 @@ type Relationship struct {
 	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time       `json:"-" db:"deleted_at"`
+	DeletedBy   *uuid.UUID       `json:"-" db:"deleted_by"`
+	DeletionID  *uuid.UUID       `json:"-" db:"deletion_id"`
 
 	PersonAData *Person `json:"person_a_data,omitempty" db:"-"`
 	PersonBData *Person `json:"person_b_data,omitempty" db:"-"`
@@ -38,9 +40,10 @@ func (r RelationshipType) IsValid() bool {
 }
 
 type SpouseMetadata struct {
-	MarriageDate        *time.Time  `json:"marriage_date,omitempty"`
+	MarriageDate        *GenDate    `json:"marriage_date,omitempty"`
 	MarriagePlace       *string     `json:"marriage_place,omitempty"`
-	DivorceDate         *time.Time  `json:"divorce_date,omitempty"`
+	MarriagePlaceID     *uuid.UUID  `json:"marriage_place_id,omitempty"`
+	DivorceDate         *GenDate    `json:"divorce_date,omitempty"`
 	IsConsanguineous    bool        `json:"is_consanguineous"`
 	ConsanguinityDegree *int        `json:"consanguinity_degree,omitempty"`
 	CommonAncestors     []uuid.UUID `json:"common_ancestors,omitempty"`
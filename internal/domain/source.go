@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SourceType string
+
+const (
+	SourceTypeBook        SourceType = "BOOK"
+	SourceTypeCertificate SourceType = "CERTIFICATE"
+	SourceTypeInterview   SourceType = "INTERVIEW"
+	SourceTypeGravestone  SourceType = "GRAVESTONE"
+	SourceTypeWebsite     SourceType = "WEBSITE"
+	SourceTypeDocument    SourceType = "DOCUMENT"
+	SourceTypeOther       SourceType = "OTHER"
+)
+
+func (t SourceType) IsValid() bool {
+	switch t {
+	case SourceTypeBook, SourceTypeCertificate, SourceTypeInterview, SourceTypeGravestone,
+		SourceTypeWebsite, SourceTypeDocument, SourceTypeOther:
+		return true
+	}
+	return false
+}
+
+// CitationConfidence rates the evidence a citation gives, from hearsay to a
+// primary record made at the time of the event.
+type CitationConfidence string
+
+const (
+	ConfidenceUnreliable   CitationConfidence = "UNRELIABLE"
+	ConfidenceQuestionable CitationConfidence = "QUESTIONABLE"
+	ConfidenceSecondary    CitationConfidence = "SECONDARY"
+	ConfidencePrimary      CitationConfidence = "PRIMARY"
+)
+
+var confidenceQuay = map[CitationConfidence]int{
+	ConfidenceUnreliable:   0,
+	ConfidenceQuestionable: 1,
+	ConfidenceSecondary:    2,
+	ConfidencePrimary:      3,
+}
+
+func (c CitationConfidence) IsValid() bool {
+	_, ok := confidenceQuay[c]
+	return ok
+}
+
+// Quay is the GEDCOM certainty assessment (0-3) of the confidence level.
+func (c CitationConfidence) Quay() int {
+	return confidenceQuay[c]
+}
+
+var (
+	ErrSourceNotFound        = errors.New("source not found")
+	ErrCitationNotFound      = errors.New("citation not found")
+	ErrInvalidSourceType     = errors.New("invalid source type")
+	ErrInvalidConfidence     = errors.New("invalid citation confidence")
+	ErrInvalidCitationEntity = errors.New("citations can be attached to persons, relationships and events only")
+	ErrInvalidCitationField  = errors.New("field cannot be cited on this entity")
+	ErrCitedEntityNotFound   = errors.New("cited entity not found")
+	ErrCitationMediaNotFound = errors.New("cited media not found")
+	ErrSourceInUse           = errors.New("source is still cited")
+)
+
+// citableFields lists the fields a citation may single out per entity type.
+var citableFields = map[EntityType]map[string]bool{
+	EntityPerson: {
+		"first_name": true, "last_name": true, "nickname": true, "gender": true,
+		"birth_date": true, "birth_place": true, "death_date": true, "death_place": true,
+		"occupation": true, "religion": true, "nationality": true, "education": true,
+	},
+	EntityRelationship: {
+		"marriage_date": true, "marriage_place": true, "divorce_date": true,
+	},
+	EntityEvent: {
+		"date": true, "place": true, "description": true,
+	},
+}
+
+// ValidateCitationTarget checks that a citation may point at the entity
+// type and, when given, the field.
+func ValidateCitationTarget(entityType EntityType, field *string) error {
+	fields, ok := citableFields[entityType]
+	if !ok {
+		return ErrInvalidCitationEntity
+	}
+	if field != nil && !fields[*field] {
+		return ErrInvalidCitationField
+	}
+	return nil
+}
+
+type Source struct {
+	ID          uuid.UUID  `json:"id" db:"source_id"`
+	Type        SourceType `json:"type" db:"type"`
+	Title       string     `json:"title" db:"title"`
+	Author      *string    `json:"author,omitempty" db:"author"`
+	Publication *string    `json:"publication,omitempty" db:"publication"`
+	Repository  *string    `json:"repository,omitempty" db:"repository"`
+	URL         *string    `json:"url,omitempty" db:"url"`
+	Notes       *string    `json:"notes,omitempty" db:"notes"`
+	CreatedBy   uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"-" db:"deleted_at"`
+}
+
+type Citation struct {
+	ID         uuid.UUID          `json:"id" db:"citation_id"`
+	SourceID   uuid.UUID          `json:"source_id" db:"source_id"`
+	EntityType EntityType         `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID          `json:"entity_id" db:"entity_id"`
+	Field      *string            `json:"field,omitempty" db:"field"`
+	Page       *string            `json:"page,omitempty" db:"page"`
+	Confidence CitationConfidence `json:"confidence" db:"confidence"`
+	Note       *string            `json:"note,omitempty" db:"note"`
+	CreatedBy  uuid.UUID          `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" db:"updated_at"`
+	DeletedAt  *time.Time         `json:"-" db:"deleted_at"`
+
+	MediaIDs []uuid.UUID `json:"media_ids" db:"-"`
+	Source   *Source     `json:"source,omitempty" db:"-"`
+}
+
+type CreateSourceInput struct {
+	Type        SourceType `json:"type" validate:"required"`
+	Title       string     `json:"title" validate:"required,min=1,max=300"`
+	Author      *string    `json:"author" validate:"omitempty,max=200"`
+	Publication *string    `json:"publication"`
+	Repository  *string    `json:"repository" validate:"omitempty,max=300"`
+	URL         *string    `json:"url" validate:"omitempty,url"`
+	Notes       *string    `json:"notes"`
+}
+
+type UpdateSourceInput struct {
+	Type        *SourceType    `json:"type"`
+	Title       *string        `json:"title" validate:"omitempty,min=1,max=300"`
+	Author      NullableString `json:"author" validate:"omitempty,max=200"`
+	Publication NullableString `json:"publication"`
+	Repository  NullableString `json:"repository" validate:"omitempty,max=300"`
+	URL         NullableString `json:"url"`
+	Notes       NullableString `json:"notes"`
+}
+
+type SourceListParams struct {
+	Query string
+	Type  *SourceType
+	PaginationParams
+}
+
+type CreateCitationInput struct {
+	SourceID   uuid.UUID          `json:"source_id" validate:"required"`
+	EntityType EntityType         `json:"entity_type" validate:"required"`
+	EntityID   uuid.UUID          `json:"entity_id" validate:"required"`
+	Field      *string            `json:"field"`
+	Page       *string            `json:"page"`
+	Confidence CitationConfidence `json:"confidence"`
+	Note       *string            `json:"note"`
+	MediaIDs   []uuid.UUID        `json:"media_ids"`
+}
+
+type UpdateCitationInput struct {
+	Field      NullableString      `json:"field"`
+	Page       NullableString      `json:"page"`
+	Confidence *CitationConfidence `json:"confidence"`
+	Note       NullableString      `json:"note"`
+	MediaIDs   *[]uuid.UUID        `json:"media_ids"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "github.com/google/uuid"
+
+type TimelineSource string
+
+const (
+	TimelineSourceEvent   TimelineSource = "EVENT"
+	TimelineSourceDerived TimelineSource = "DERIVED"
+	TimelineSourceMedia   TimelineSource = "MEDIA"
+)
+
+type TimelineItemType string
+
+const (
+	TimelineBirth       TimelineItemType = "BIRTH"
+	TimelineDeath       TimelineItemType = "DEATH"
+	TimelineMarriage    TimelineItemType = "MARRIAGE"
+	TimelineDivorce     TimelineItemType = "DIVORCE"
+	TimelineChildBirth  TimelineItemType = "CHILD_BIRTH"
+	TimelineParentDeath TimelineItemType = "PARENT_DEATH"
+	TimelineSpouseDeath TimelineItemType = "SPOUSE_DEATH"
+	TimelineMediaTaken  TimelineItemType = "MEDIA"
+)
+
+// TimelineItem is one entry of a timeline. Title carries only what users
+// wrote, an event title or a media caption; derived items are described by
+// Type and RelatedPerson and worded by the client.
+type TimelineItem struct {
+	Source         TimelineSource   `json:"source"`
+	Type           TimelineItemType `json:"type"`
+	EventType      *EventType       `json:"event_type,omitempty"`
+	Title          string           `json:"title,omitempty"`
+	Date           *GenDate         `json:"date,omitempty"`
+	Place          *string          `json:"place,omitempty"`
+	Age            *Age             `json:"age,omitempty"`
+	EventID        *uuid.UUID       `json:"event_id,omitempty"`
+	RelationshipID *uuid.UUID       `json:"relationship_id,omitempty"`
+	RelatedPerson  *Person          `json:"related_person,omitempty"`
+	Media          *Media           `json:"media,omitempty"`
+}
+
+type PersonTimeline struct {
+	PersonID uuid.UUID      `json:"person_id"`
+	Items    []TimelineItem `json:"items"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTrashItemNotFound  = errors.New("item not found in trash")
+	ErrInvalidTrashEntity = errors.New("invalid trash entity type")
+	ErrRestoreBlocked     = errors.New("item cannot be restored")
+)
+
+// IsTrashable reports whether soft-deleted rows of the entity type are shown
+// in the trash bin.
+func (t EntityType) IsTrashable() bool {
+	switch t {
+	case EntityPerson, EntityRelationship, EntityEvent, EntityMedia, EntityComment:
+		return true
+	}
+	return false
+}
+
+// TrashItem is a soft-deleted person, relationship, event, media or comment.
+type TrashItem struct {
+	EntityType    EntityType `json:"entity_type" db:"entity_type"`
+	EntityID      uuid.UUID  `json:"entity_id" db:"entity_id"`
+	Label         string     `json:"label" db:"label"`
+	PersonID      *uuid.UUID `json:"person_id,omitempty" db:"person_id"`
+	DeletedAt     time.Time  `json:"deleted_at" db:"deleted_at"`
+	DeletedBy     *uuid.UUID `json:"deleted_by,omitempty" db:"deleted_by"`
+	DeletedByName *string    `json:"deleted_by_name,omitempty" db:"deleted_by_name"`
+	PurgeAfter    time.Time  `json:"purge_after" db:"-"`
+
+	// Cascaded counts the relationships, events, media and comments that
+	// were deleted together with a person and are restored with it.
+	Cascaded int64 `json:"cascaded" db:"cascaded"`
+}
+
+type TrashListParams struct {
+	EntityType *EntityType
+	PaginationParams
+}
+
+type RestoreResult struct {
+	EntityType EntityType `json:"entity_type"`
+	EntityID   uuid.UUID  `json:"entity_id"`
+	// Relationships lists the relationships visible again after restoring a
+	// person.
+	Relationships []uuid.UUID `json:"relationships"`
+	// Cascaded counts the rows restored together with a person.
+	Cascaded int64 `json:"cascaded"`
+}
+
+type PurgeResult struct {
+	Before        time.Time `json:"before"`
+	Persons       int64     `json:"persons"`
+	Relationships int64     `json:"relationships"`
+	Events        int64     `json:"events"`
+	Media         int64     `json:"media"`
+	Comments      int64     `json:"comments"`
+	Citations     int64     `json:"citations"`
+
+	StoragePaths []string `json:"-"`
+}
@@ -76,6 +76,18 @@ func (r UserRole) IsValid() bool {
 	}
 }
 
+// AtLeast reports whether r has every permission of role min; roles are
+// ordered member < editor < developer.
+func (r UserRole) AtLeast(min UserRole) bool {
+	return roleRank[r] > 0 && roleRank[r] >= roleRank[min]
+}
+
+var roleRank = map[UserRole]int{
+	RoleMember:    1,
+	RoleEditor:    2,
+	RoleDeveloper: 3,
+}
+
 func (u *User) HasRole(requiredRole string) bool {
 	switch requiredRole {
 	case "developer":
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
@@ -30,12 +32,24 @@ func (h *ChangeRequestHandler) Create(c *fiber.Ctx) error {
 
 	cr, err := h.crService.Create(c.Context(), userID, input)
 	if err != nil {
-		return err
+		return changeRequestError(err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(cr)
 }
 
+// changeRequestError maps the errors of filing a change request to
+// responses.
+func changeRequestError(err error) error {
+	switch {
+	case errors.Is(err, changerequest.ErrInvalidChange):
+		return middleware.BadRequest(err.Error())
+	case errors.Is(err, changerequest.ErrEntityNotFound):
+		return middleware.NotFound("Entity not found")
+	}
+	return err
+}
+
 func (h *ChangeRequestHandler) List(c *fiber.Ctx) error {
 	params := getPaginationParams(c)
 
@@ -45,7 +59,7 @@ func (h *ChangeRequestHandler) List(c *fiber.Ctx) error {
 		status = &st
 	}
 
-	result, err := h.crService.List(c.Context(), status, params)
+	result, err := h.crService.List(c.Context(), status, params, viewerOf(c))
 	if err != nil {
 		return err
 	}
@@ -68,16 +82,55 @@ func (h *ChangeRequestHandler) Get(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(cr)
 }
 
+// Diff previews a change request field by field against the current state
+// of its entity.
+func (h *ChangeRequestHandler) Diff(c *fiber.Ctx) error {
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	diff, err := h.crService.Diff(c.Context(), requestID, viewerOf(c))
+	if err != nil {
+		if errors.Is(err, changerequest.ErrChangeRequestNotFound) {
+			return middleware.NotFound("Change request not found")
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(diff)
+}
+
+// Merge shows an UPDATE change request as a three-way merge against the
+// current entity, for resolving conflicts before approval.
+func (h *ChangeRequestHandler) Merge(c *fiber.Ctx) error {
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	merge, err := h.crService.Merge(c.Context(), requestID, viewerOf(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, changerequest.ErrChangeRequestNotFound):
+			return middleware.NotFound("Change request not found")
+		case errors.Is(err, changerequest.ErrEntityNotFound):
+			return middleware.NotFound("Entity not found")
+		case errors.Is(err, changerequest.ErrNotMergeable):
+			return middleware.BadRequest(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(merge)
+}
+
 func (h *ChangeRequestHandler) Approve(c *fiber.Ctx) error {
 	user := middleware.GetCurrentUser(c)
 	if user == nil {
 		return middleware.Unauthorized("User not authenticated")
 	}
 
-	if user.Role != string(domain.RoleEditor) && user.Role != string(domain.RoleDeveloper) {
-		return middleware.Forbidden("Insufficient permissions")
-	}
-
 	requestIDStr := c.Params("requestId")
 	requestID, err := uuid.Parse(requestIDStr)
 	if err != nil {
@@ -92,11 +145,35 @@ func (h *ChangeRequestHandler) Approve(c *fiber.Ctx) error {
 		UserAgent: middleware.GetUserAgentFromContext(c),
 	}
 
-	if err := h.crService.Approve(c.Context(), requestID, user.ID, input.Note, meta); err != nil {
+	progress, err := h.crService.Approve(c.Context(), requestID, user.ID, input.Note, input.Resolutions, meta)
+	if err != nil {
+		if errors.Is(err, domain.ErrChangeRequestNotPending) || errors.Is(err, domain.ErrAlreadyApproved) {
+			return middleware.Conflict(err.Error())
+		}
+		if errors.Is(err, changerequest.ErrInsufficientPermissions) {
+			return middleware.Forbidden("Insufficient permissions")
+		}
+		if errors.Is(err, changerequest.ErrInvalidChange) {
+			return middleware.BadRequest(err.Error())
+		}
+		var conflict *domain.ChangeConflictError
+		if errors.As(err, &conflict) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"code":      "CHANGE_CONFLICT",
+				"message":   "The entity was edited after this request was filed; resend with resolutions for the conflicting fields",
+				"conflicts": conflict.Fields,
+			})
+		}
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Change request approved"})
+	if !progress.Met {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message":  "Approval recorded; the change request needs further approval",
+			"approval": progress,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Change request approved", "approval": progress})
 }
 
 func (h *ChangeRequestHandler) Reject(c *fiber.Ctx) error {
@@ -105,10 +182,6 @@ func (h *ChangeRequestHandler) Reject(c *fiber.Ctx) error {
 		return middleware.Unauthorized("User not authenticated")
 	}
 
-	if user.Role != string(domain.RoleEditor) && user.Role != string(domain.RoleDeveloper) {
-		return middleware.Forbidden("Insufficient permissions")
-	}
-
 	requestIDStr := c.Params("requestId")
 	requestID, err := uuid.Parse(requestIDStr)
 	if err != nil {
@@ -124,8 +197,220 @@ func (h *ChangeRequestHandler) Reject(c *fiber.Ctx) error {
 	}
 
 	if err := h.crService.Reject(c.Context(), requestID, user.ID, input.Note, meta); err != nil {
+		if errors.Is(err, domain.ErrChangeRequestNotPending) {
+			return middleware.Conflict(err.Error())
+		}
+		if errors.Is(err, changerequest.ErrInsufficientPermissions) {
+			return middleware.Forbidden("Insufficient permissions")
+		}
 		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Change request rejected"})
 }
+
+// Approvals lists the approvals a change request has and what its
+// approval policy still needs.
+func (h *ChangeRequestHandler) Approvals(c *fiber.Ctx) error {
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	progress, err := h.crService.Approvals(c.Context(), requestID)
+	if err != nil {
+		if errors.Is(err, changerequest.ErrChangeRequestNotFound) {
+			return middleware.NotFound("Change request not found")
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(progress)
+}
+
+func (h *ChangeRequestHandler) ListPolicies(c *fiber.Ctx) error {
+	policies, err := h.crService.ListPolicies(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": policies})
+}
+
+// SetPolicy sets how many approvals change requests of one entity type and
+// action need.
+func (h *ChangeRequestHandler) SetPolicy(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var input domain.SetApprovalPolicyInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	entityType := domain.EntityType(c.Params("entityType"))
+	action := domain.ChangeAction(c.Params("action"))
+	policy, err := h.crService.SetPolicy(c.Context(), userID, entityType, action, input)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidApprovalPolicy) {
+			return middleware.BadRequest(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(policy)
+}
+
+// lifecycleError maps the errors of withdrawing, amending and discussing a
+// change request to responses.
+func lifecycleError(err error) error {
+	switch {
+	case errors.Is(err, changerequest.ErrChangeRequestNotFound):
+		return middleware.NotFound("Change request not found")
+	case errors.Is(err, changerequest.ErrCommentNotFound):
+		return middleware.NotFound("Comment not found")
+	case errors.Is(err, changerequest.ErrNotRequester), errors.Is(err, changerequest.ErrInsufficientPermissions):
+		return middleware.Forbidden(err.Error())
+	case errors.Is(err, domain.ErrChangeRequestClosed), errors.Is(err, domain.ErrChangeRequestNotPending):
+		return middleware.Conflict(err.Error())
+	case errors.Is(err, changerequest.ErrNoteRequired), errors.Is(err, domain.ErrInvalidComment):
+		return middleware.BadRequest(err.Error())
+	}
+	return changeRequestError(err)
+}
+
+// Withdraw closes the current user's own open change request.
+func (h *ChangeRequestHandler) Withdraw(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	meta := &changerequest.RequestMeta{
+		IPAddress: middleware.GetIPAddress(c),
+		UserAgent: middleware.GetUserAgentFromContext(c),
+	}
+
+	if err := h.crService.Withdraw(c.Context(), requestID, userID, meta); err != nil {
+		return lifecycleError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Change request withdrawn"})
+}
+
+// Amend replaces the payload of the current user's own open change request
+// and puts it up for review again.
+func (h *ChangeRequestHandler) Amend(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	var input domain.AmendChangeRequestInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	meta := &changerequest.RequestMeta{
+		IPAddress: middleware.GetIPAddress(c),
+		UserAgent: middleware.GetUserAgentFromContext(c),
+	}
+
+	cr, err := h.crService.Amend(c.Context(), requestID, userID, input, meta)
+	if err != nil {
+		return lifecycleError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(cr)
+}
+
+// RequestChanges sends a pending change request back to its requester.
+func (h *ChangeRequestHandler) RequestChanges(c *fiber.Ctx) error {
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	var input domain.ReviewChangeRequestInput
+	_ = c.BodyParser(&input)
+
+	meta := &changerequest.RequestMeta{
+		IPAddress: middleware.GetIPAddress(c),
+		UserAgent: middleware.GetUserAgentFromContext(c),
+	}
+
+	if err := h.crService.RequestChanges(c.Context(), requestID, user.ID, input.Note, meta); err != nil {
+		return lifecycleError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Changes requested"})
+}
+
+func (h *ChangeRequestHandler) Revisions(c *fiber.Ctx) error {
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	revisions, err := h.crService.Revisions(c.Context(), requestID)
+	if err != nil {
+		return lifecycleError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": revisions})
+}
+
+func (h *ChangeRequestHandler) ListComments(c *fiber.Ctx) error {
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	comments, err := h.crService.Comments(c.Context(), requestID)
+	if err != nil {
+		return lifecycleError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": comments})
+}
+
+func (h *ChangeRequestHandler) AddComment(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid request ID")
+	}
+
+	var input domain.CreateChangeRequestCommentInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	comment, err := h.crService.AddComment(c.Context(), requestID, userID, input)
+	if err != nil {
+		return lifecycleError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(comment)
+}
@@ -1,20 +1,55 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/middleware"
 	"silsilah-keluarga/internal/service/comment"
+	"silsilah-keluarga/internal/service/person"
 )
 
 type CommentHandler struct {
 	commentService comment.Service
+	personService  person.Service
 }
 
-func NewCommentHandler(commentService comment.Service) *CommentHandler {
-	return &CommentHandler{commentService: commentService}
+func NewCommentHandler(commentService comment.Service, personService person.Service) *CommentHandler {
+	return &CommentHandler{commentService: commentService, personService: personService}
+}
+
+// checkPersonVisible refuses the comment thread of a living person whose
+// record is redacted for the current user, as comments tend to discuss the
+// very details that were hidden.
+func (h *CommentHandler) checkPersonVisible(c *fiber.Ctx, personID uuid.UUID) error {
+	p, err := h.personService.GetByID(c.Context(), personID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPersonNotFound) {
+			return middleware.NotFound("Person not found")
+		}
+		return err
+	}
+	if p.ProbablyLiving(time.Now()) && !viewerOf(c).CanSee(p.ID, p.PrivacyLevel) {
+		return middleware.Forbidden("Comments on this person are private")
+	}
+	return nil
+}
+
+// commentError maps comment service errors to HTTP errors.
+func commentError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidComment):
+		return middleware.BadRequest(err.Error())
+	case errors.Is(err, domain.ErrCommentNotFound):
+		return middleware.NotFound("Comment not found")
+	case errors.Is(err, domain.ErrNotCommentAuthor):
+		return middleware.Forbidden("Only the author can change this comment")
+	}
+	return err
 }
 
 func (h *CommentHandler) Create(c *fiber.Ctx) error {
@@ -28,6 +63,9 @@ func (h *CommentHandler) Create(c *fiber.Ctx) error {
 	if err != nil {
 		return middleware.BadRequest("Invalid person ID")
 	}
+	if err := h.checkPersonVisible(c, personID); err != nil {
+		return err
+	}
 
 	var input domain.CreateCommentInput
 	if err := c.BodyParser(&input); err != nil {
@@ -36,7 +74,7 @@ func (h *CommentHandler) Create(c *fiber.Ctx) error {
 
 	comment, err := h.commentService.Create(c.Context(), personID, userID, input)
 	if err != nil {
-		return err
+		return commentError(err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(comment)
@@ -48,6 +86,9 @@ func (h *CommentHandler) List(c *fiber.Ctx) error {
 	if err != nil {
 		return middleware.BadRequest("Invalid person ID")
 	}
+	if err := h.checkPersonVisible(c, personID); err != nil {
+		return err
+	}
 
 	params := getPaginationParams(c)
 
@@ -77,7 +118,7 @@ func (h *CommentHandler) Update(c *fiber.Ctx) error {
 
 	comment, err := h.commentService.Update(c.Context(), userID, commentID, input)
 	if err != nil {
-		return err
+		return commentError(err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(comment)
@@ -95,7 +136,7 @@ func (h *CommentHandler) Delete(c *fiber.Ctx) error {
 	}
 
 	if err := h.commentService.Delete(c.Context(), userID, commentID); err != nil {
-		return err
+		return commentError(err)
 	}
 
 	return c.Status(fiber.StatusNoContent).SendString("")
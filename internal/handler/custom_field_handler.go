@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/customfield"
+)
+
+type CustomFieldHandler struct {
+	customFieldService customfield.Service
+}
+
+func NewCustomFieldHandler(customFieldService customfield.Service) *CustomFieldHandler {
+	return &CustomFieldHandler{customFieldService: customFieldService}
+}
+
+// ListFields returns the custom field definitions the current user may see.
+func (h *CustomFieldHandler) ListFields(c *fiber.Ctx) error {
+	fields, err := h.customFieldService.ListFields(c.Context(), viewerRole(c))
+	if err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fields)
+}
+
+func (h *CustomFieldHandler) CreateField(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	var input domain.CreateCustomFieldInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	field, err := h.customFieldService.CreateField(c.Context(), userID, input)
+	if err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(field)
+}
+
+func (h *CustomFieldHandler) UpdateField(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	fieldID, err := uuid.Parse(c.Params("fieldId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid field ID")
+	}
+
+	var input domain.UpdateCustomFieldInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	field, err := h.customFieldService.UpdateField(c.Context(), userID, fieldID, input)
+	if err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(field)
+}
+
+func (h *CustomFieldHandler) DeleteField(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	fieldID, err := uuid.Parse(c.Params("fieldId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid field ID")
+	}
+
+	if err := h.customFieldService.DeleteField(c.Context(), userID, fieldID); err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func (h *CustomFieldHandler) ListPersonValues(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	values, err := h.customFieldService.GetPersonValues(c.Context(), personID, viewerOf(c))
+	if err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(values)
+}
+
+func (h *CustomFieldHandler) SetPersonValue(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+	fieldID, err := uuid.Parse(c.Params("fieldId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid field ID")
+	}
+
+	var input domain.SetCustomValueInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	value, err := h.customFieldService.SetPersonValue(c.Context(), userID, personID, fieldID, input)
+	if err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(value)
+}
+
+func (h *CustomFieldHandler) DeletePersonValue(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+	fieldID, err := uuid.Parse(c.Params("fieldId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid field ID")
+	}
+
+	if err := h.customFieldService.DeletePersonValue(c.Context(), userID, personID, fieldID); err != nil {
+		return customFieldError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func customFieldError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrCustomFieldNotFound),
+		errors.Is(err, domain.ErrCustomValueNotFound),
+		errors.Is(err, domain.ErrPersonNotFound):
+		return middleware.NotFound(err.Error())
+	case errors.Is(err, domain.ErrCustomFieldKeyTaken):
+		return middleware.Conflict(err.Error())
+	case errors.Is(err, domain.ErrInvalidCustomField),
+		errors.Is(err, domain.ErrInvalidCustomValue),
+		errors.Is(err, domain.ErrCustomValueSelfRef):
+		return middleware.BadRequest(err.Error())
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/duplicate"
+)
+
+type DuplicateHandler struct {
+	duplicateService duplicate.Service
+}
+
+func NewDuplicateHandler(duplicateService duplicate.Service) *DuplicateHandler {
+	return &DuplicateHandler{duplicateService: duplicateService}
+}
+
+func (h *DuplicateHandler) ListForPerson(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	candidates, err := h.duplicateService.FindForPerson(c.Context(), personID, c.QueryInt("limit", 10))
+	if err != nil {
+		if errors.Is(err, domain.ErrPersonNotFound) {
+			return middleware.NotFound("Person not found")
+		}
+		return err
+	}
+
+	viewer, now := viewerOf(c), time.Now()
+	for i := range candidates {
+		candidates[i].Person.Redact(viewer, now)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(candidates)
+}
+
+func (h *DuplicateHandler) Report(c *fiber.Ctx) error {
+	minScore := c.QueryFloat("min_score", duplicate.DefaultMinScore)
+	if minScore < 0 || minScore > 1 {
+		return middleware.BadRequest("min_score must be between 0 and 1")
+	}
+
+	pairs, err := h.duplicateService.Report(c.Context(), minScore, c.QueryInt("limit", 50))
+	if err != nil {
+		return err
+	}
+
+	viewer, now := viewerOf(c), time.Now()
+	for i := range pairs {
+		pairs[i].PersonA.Redact(viewer, now)
+		pairs[i].PersonB.Redact(viewer, now)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(pairs)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/event"
+)
+
+type EventHandler struct {
+	eventService event.Service
+}
+
+func NewEventHandler(eventService event.Service) *EventHandler {
+	return &EventHandler{eventService: eventService}
+}
+
+func (h *EventHandler) ListTypes(c *fiber.Ctx) error {
+	locale := c.Get("Accept-Language", "id")
+	return c.Status(fiber.StatusOK).JSON(h.eventService.ListTypes(locale))
+}
+
+func (h *EventHandler) Create(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var input domain.CreateEventInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	ev, err := h.eventService.Create(c.Context(), userID, input)
+	if err != nil {
+		return eventError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ev)
+}
+
+func (h *EventHandler) Get(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("eventId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid event ID")
+	}
+
+	ev, err := h.eventService.GetByID(c.Context(), eventID)
+	if err != nil {
+		return eventError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ev)
+}
+
+func (h *EventHandler) Update(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	eventID, err := uuid.Parse(c.Params("eventId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid event ID")
+	}
+
+	var input domain.UpdateEventInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	ev, err := h.eventService.Update(c.Context(), userID, eventID, input)
+	if err != nil {
+		return eventError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ev)
+}
+
+func (h *EventHandler) Delete(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	eventID, err := uuid.Parse(c.Params("eventId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid event ID")
+	}
+
+	if err := h.eventService.Delete(c.Context(), userID, eventID); err != nil {
+		return eventError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func (h *EventHandler) ListByPerson(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	events, err := h.eventService.ListByPerson(c.Context(), personID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(events)
+}
+
+func eventError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrEventNotFound):
+		return middleware.NotFound("Event not found")
+	case errors.Is(err, domain.ErrPersonNotFound):
+		return middleware.NotFound("Person not found")
+	case errors.Is(err, event.ErrRelationshipNotFound):
+		return middleware.NotFound("Relationship not found")
+	case errors.Is(err, domain.ErrInvalidEventType),
+		errors.Is(err, domain.ErrInvalidEventMetadata),
+		errors.Is(err, event.ErrMissingSubject),
+		errors.Is(err, event.ErrPersonRequired),
+		errors.Is(err, event.ErrRelationshipRequired),
+		errors.Is(err, domain.ErrPlaceNotFound):
+		return middleware.BadRequest(err.Error())
+	}
+	return err
+}
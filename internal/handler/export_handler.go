@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/middleware"
 	"silsilah-keluarga/internal/service/export"
 )
@@ -20,8 +22,7 @@ func NewExportHandler(exportSvc export.Service) *ExportHandler {
 }
 
 func (h *ExportHandler) ExportJSON(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
+	if _, err := middleware.GetUserID(c); err != nil {
 		return middleware.Unauthorized("User not authenticated")
 	}
 
@@ -31,7 +32,7 @@ func (h *ExportHandler) ExportJSON(c *fiber.Ctx) error {
 		return middleware.BadRequest("Invalid person ID")
 	}
 
-	data, err := h.exportSvc.ExportJSON(c.Context(), userID, personID)
+	data, err := h.exportSvc.ExportJSON(c.Context(), viewerOf(c), personID)
 	if err != nil {
 		return err
 	}
@@ -44,12 +45,11 @@ func (h *ExportHandler) ExportJSON(c *fiber.Ctx) error {
 }
 
 func (h *ExportHandler) ExportGEDCOM(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserID(c)
-	if err != nil {
+	if _, err := middleware.GetUserID(c); err != nil {
 		return err
 	}
 
-	rootIDStr := c.Query("root_id")
+	rootIDStr := c.Query("root_id", c.Params("personId"))
 	if rootIDStr == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "root_id is required")
 	}
@@ -58,8 +58,11 @@ func (h *ExportHandler) ExportGEDCOM(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid root_id")
 	}
 
-	gedcomData, err := h.exportSvc.ExportGEDCOM(c.Context(), userID, rootID)
+	gedcomData, err := h.exportSvc.ExportGEDCOM(c.Context(), viewerOf(c), rootID)
 	if err != nil {
+		if errors.Is(err, domain.ErrPersonNotFound) {
+			return middleware.NotFound("Person not found")
+		}
 		return err
 	}
 
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/middleware"
 	"silsilah-keluarga/internal/service/graph"
 )
@@ -21,6 +25,7 @@ func (h *GraphHandler) GetFullGraph(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	domain.RedactNodes(graph.Nodes, viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(graph)
 }
@@ -38,6 +43,7 @@ func (h *GraphHandler) GetAncestors(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	domain.RedactNodes(ancestors.Ancestors, viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(ancestors)
 }
@@ -55,6 +61,11 @@ func (h *GraphHandler) GetSplitAncestors(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	for _, tree := range []*domain.AncestorTree{splitAncestors.Paternal, splitAncestors.Maternal} {
+		if tree != nil {
+			domain.RedactNodes(tree.Ancestors, viewerOf(c), time.Now())
+		}
+	}
 
 	return c.Status(fiber.StatusOK).JSON(splitAncestors)
 }
@@ -72,10 +83,36 @@ func (h *GraphHandler) GetDescendants(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	domain.RedactNodes(descendants.Descendants, viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(descendants)
 }
 
+// GetMigrationMap returns GeoJSON of where a person's ancestors (the
+// default) or descendants were born and died.
+func (h *GraphHandler) GetMigrationMap(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	direction := domain.MigrationDirection(c.Query("direction", string(domain.MigrationAncestors)))
+	maxDepth := c.QueryInt("max_depth", 10)
+
+	migration, err := h.graphService.GetMigrationMap(c.Context(), personID, direction, maxDepth, viewerOf(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPersonNotFound):
+			return middleware.NotFound("Person not found")
+		case errors.Is(err, domain.ErrInvalidMigrationDirection):
+			return middleware.BadRequest(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(migration, "application/geo+json")
+}
+
 func (h *GraphHandler) FindRelationshipPath(c *fiber.Ctx) error {
 	fromIDStr := c.Query("from")
 	toIDStr := c.Query("to")
@@ -6,7 +6,11 @@ type Handlers struct {
 	Auth          *AuthHandler
 	User          *UserHandler
 	Person        *PersonHandler
+	PersonName    *PersonNameHandler
+	Duplicate     *DuplicateHandler
 	Relationship  *RelationshipHandler
+	Event         *EventHandler
+	Timeline      *TimelineHandler
 	Graph         *GraphHandler
 	ChangeRequest *ChangeRequestHandler
 	Media         *MediaHandler
@@ -15,6 +19,11 @@ type Handlers struct {
 	Notification  *NotificationHandler
 	Dashboard     *DashboardHandler
 	Export        *ExportHandler
+	Place         *PlaceHandler
+	Source        *SourceHandler
+	Trash         *TrashHandler
+	CustomField   *CustomFieldHandler
+	Living        *LivingHandler
 }
 
 func NewHandlers(services *service.Services) *Handlers {
@@ -22,14 +31,23 @@ func NewHandlers(services *service.Services) *Handlers {
 		Auth:          NewAuthHandler(services.Auth),
 		User:          NewUserHandler(services.User, services.Person),
 		Person:        NewPersonHandler(services.Person, services.ChangeRequest),
+		PersonName:    NewPersonNameHandler(services.PersonName),
+		Duplicate:     NewDuplicateHandler(services.Duplicate),
 		Relationship:  NewRelationshipHandler(services.Relationship, services.ChangeRequest),
+		Event:         NewEventHandler(services.Event),
+		Timeline:      NewTimelineHandler(services.Timeline),
 		Graph:         NewGraphHandler(services.Graph),
 		ChangeRequest: NewChangeRequestHandler(services.ChangeRequest),
 		Media:         NewMediaHandler(services.Media, services.ChangeRequest),
-		Comment:       NewCommentHandler(services.Comment),
+		Comment:       NewCommentHandler(services.Comment, services.Person),
 		Audit:         NewAuditHandler(services.Audit),
 		Notification:  NewNotificationHandler(services.Notification),
 		Dashboard:     NewDashboardHandler(services.Dashboard),
 		Export:        NewExportHandler(services.Export),
+		Place:         NewPlaceHandler(services.Place),
+		Source:        NewSourceHandler(services.Source),
+		Trash:         NewTrashHandler(services.Trash),
+		CustomField:   NewCustomFieldHandler(services.CustomField),
+		Living:        NewLivingHandler(services.Living),
 	}
 }
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/living"
+)
+
+type LivingHandler struct {
+	livingService living.Service
+}
+
+func NewLivingHandler(livingService living.Service) *LivingHandler {
+	return &LivingHandler{livingService: livingService}
+}
+
+// Get returns the inferred living status of one person.
+func (h *LivingHandler) Get(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	estimate, err := h.livingService.Infer(c.Context(), personID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPersonNotFound) {
+			return middleware.NotFound("Person not found")
+		}
+		return err
+	}
+	estimate.Redact(viewerOf(c))
+
+	return c.Status(fiber.StatusOK).JSON(estimate)
+}
+
+// Report lists persons whose is_alive flag needs correcting or whose dates
+// are inconsistent.
+func (h *LivingHandler) Report(c *fiber.Ctx) error {
+	report, err := h.livingService.Report(c.Context())
+	if err != nil {
+		return err
+	}
+	viewer := viewerOf(c)
+	for i := range report {
+		report[i].Redact(viewer)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// Correct marks deceased the persons inferred to be deceased, or with
+// ?dry_run=true only lists them.
+func (h *LivingHandler) Correct(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	result, err := h.livingService.Correct(c.Context(), userID, c.QueryBool("dry_run"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
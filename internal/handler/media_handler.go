@@ -145,6 +145,65 @@ func (h *MediaHandler) Get(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(media)
 }
 
+// Update edits the caption and taken date of a media file. Members file
+// the edit as a change request.
+func (h *MediaHandler) Update(c *fiber.Ctx) error {
+	currentUser := middleware.GetCurrentUser(c)
+	if currentUser == nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	mediaIDStr := c.Params("mediaId")
+	mediaID, err := uuid.Parse(mediaIDStr)
+	if err != nil {
+		return middleware.BadRequest("Invalid media ID")
+	}
+
+	if currentUser.Role == "member" {
+		var requesterNote *string
+		requesterNoteStr := c.Query("requester_note")
+		if requesterNoteStr != "" {
+			requesterNote = &requesterNoteStr
+		}
+
+		input := domain.CreateChangeRequestInput{
+			EntityType:    domain.EntityMedia,
+			EntityID:      &mediaID,
+			Action:        domain.ActionUpdate,
+			Payload:       json.RawMessage(c.Body()),
+			RequesterNote: requesterNote,
+		}
+
+		request, err := h.crService.Create(c.Context(), currentUser.ID, input)
+		if err != nil {
+			return changeRequestError(err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message":        "Edit request submitted for approval",
+			"change_request": request,
+		})
+	}
+
+	var input domain.UpdateMediaInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	media, err := h.mediaService.Update(c.Context(), mediaID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrMediaNotFound):
+			return middleware.NotFound("Media not found")
+		case errors.Is(err, domain.ErrInvalidMediaEdit):
+			return middleware.BadRequest(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(media)
+}
+
 func (h *MediaHandler) Delete(c *fiber.Ctx) error {
 	currentUser := middleware.GetCurrentUser(c)
 	if currentUser == nil {
@@ -197,7 +256,7 @@ func (h *MediaHandler) Delete(c *fiber.Ctx) error {
 		}
 	}
 
-	if err := h.mediaService.Delete(c.Context(), mediaID); err != nil {
+	if err := h.mediaService.Delete(c.Context(), currentUser.ID, mediaID); err != nil {
 		return err
 	}
 
@@ -3,6 +3,11 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -56,7 +61,7 @@ func (h *PersonHandler) Create(c *fiber.Ctx) error {
 
 		cr, err := h.crService.Create(c.Context(), user.ID, crInput)
 		if err != nil {
-			return err
+			return changeRequestError(err)
 		}
 
 		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
@@ -67,8 +72,25 @@ func (h *PersonHandler) Create(c *fiber.Ctx) error {
 
 	person, err := h.personService.Create(c.Context(), user.ID, input)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLifeDates) || errors.Is(err, domain.ErrPlaceNotFound) ||
+			errors.Is(err, domain.ErrInvalidPrivacyLevel) {
+			return middleware.BadRequest(err.Error())
+		}
+		var dup *domain.DuplicateWarningError
+		if errors.As(err, &dup) {
+			viewer, now := viewerOf(c), time.Now()
+			for i := range dup.Candidates {
+				dup.Candidates[i].Person.Redact(viewer, now)
+			}
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"code":       "POSSIBLE_DUPLICATE",
+				"message":    "Similar persons already exist; resend with ignore_duplicates to create anyway",
+				"candidates": dup.Candidates,
+			})
+		}
 		return err
 	}
+	person.Redact(viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusCreated).JSON(person)
 }
@@ -80,24 +102,97 @@ func (h *PersonHandler) List(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	domain.RedactPersons(result.Data, viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
 func (h *PersonHandler) Search(c *fiber.Ctx) error {
-	query := c.Query("q")
-	limit := c.QueryInt("limit", 10)
-
-	if len(query) < 2 {
-		return middleware.BadRequest("Search query must be at least 2 characters")
+	input, err := parsePersonSearch(c)
+	if err != nil {
+		return middleware.BadRequest(err.Error())
 	}
 
-	persons, err := h.personService.Search(c.Context(), query, limit)
+	result, err := h.personService.Search(c.Context(), input)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearch) {
+			return middleware.BadRequest(err.Error())
+		}
 		return err
 	}
+	domain.RedactPersons(result.Data, viewerOf(c), time.Now())
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// parsePersonSearch reads the search filters from the query string, e.g.
+// ?q=siti&gender=FEMALE&birth_year_from=1900&no_parents=true&sort=birth_date&order=desc.
+// Custom fields are filtered with cf.<key>, e.g. cf.marga=Siregar.
+func parsePersonSearch(c *fiber.Ctx) (domain.PersonSearchInput, error) {
+	input := domain.PersonSearchInput{
+		Query:      strings.TrimSpace(c.Query("q")),
+		BirthPlace: c.Query("birth_place"),
+		Occupation: c.Query("occupation"),
+		Religion:   c.Query("religion"),
+		NoParents:  c.QueryBool("no_parents"),
+		NoChildren: c.QueryBool("no_children"),
+		Sort:       domain.PersonSortField(c.Query("sort")),
+		Descending: strings.EqualFold(c.Query("order"), "desc"),
+		Limit:      c.QueryInt("limit", 20),
+		Offset:     c.QueryInt("offset", 0),
+		Viewer:     viewerOf(c),
+	}
+
+	if v := c.Query("gender"); v != "" {
+		g := domain.Gender(strings.ToUpper(v))
+		input.Gender = &g
+	}
+	if v := c.Query("is_alive"); v != "" {
+		alive, err := strconv.ParseBool(v)
+		if err != nil {
+			return input, errors.New("is_alive must be true or false")
+		}
+		input.IsAlive = &alive
+	}
+	if v := c.Query("created_by"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return input, errors.New("invalid created_by")
+		}
+		input.CreatedBy = &id
+	}
+
+	years := []struct {
+		name string
+		dest **int
+	}{
+		{"birth_year_from", &input.BirthYearFrom},
+		{"birth_year_to", &input.BirthYearTo},
+		{"death_year_from", &input.DeathYearFrom},
+		{"death_year_to", &input.DeathYearTo},
+	}
+	for _, y := range years {
+		v := c.Query(y.name)
+		if v == "" {
+			continue
+		}
+		year, err := strconv.Atoi(v)
+		if err != nil {
+			return input, fmt.Errorf("%s must be a year", y.name)
+		}
+		*y.dest = &year
+	}
 
-	return c.Status(fiber.StatusOK).JSON(persons)
+	for key, value := range c.Queries() {
+		if fieldKey, ok := strings.CutPrefix(key, "cf."); ok {
+			input.CustomFields = append(input.CustomFields, domain.CustomFieldFilter{Key: fieldKey, Value: value})
+		}
+	}
+	sort.Slice(input.CustomFields, func(i, j int) bool {
+		return input.CustomFields[i].Key < input.CustomFields[j].Key
+	})
+
+	return input, nil
 }
 
 func (h *PersonHandler) Get(c *fiber.Ctx) error {
@@ -114,6 +209,8 @@ func (h *PersonHandler) Get(c *fiber.Ctx) error {
 		}
 		return err
 	}
+	person.CustomFields = domain.VisibleCustomValues(person.CustomFields, viewerRole(c))
+	person.Redact(viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(person)
 }
@@ -156,7 +253,10 @@ func (h *PersonHandler) Update(c *fiber.Ctx) error {
 
 		cr, err := h.crService.Create(c.Context(), user.ID, crInput)
 		if err != nil {
-			return err
+			if errors.Is(err, changerequest.ErrEntityNotFound) {
+				return middleware.NotFound("Person not found")
+			}
+			return changeRequestError(err)
 		}
 
 		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
@@ -170,8 +270,13 @@ func (h *PersonHandler) Update(c *fiber.Ctx) error {
 		if errors.Is(err, domain.ErrPersonNotFound) {
 			return middleware.NotFound("Person not found")
 		}
+		if errors.Is(err, domain.ErrInvalidLifeDates) || errors.Is(err, domain.ErrPlaceNotFound) ||
+			errors.Is(err, domain.ErrInvalidPrivacyLevel) {
+			return middleware.BadRequest(err.Error())
+		}
 		return err
 	}
+	person.Redact(viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(person)
 }
@@ -215,7 +320,7 @@ func (h *PersonHandler) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.personService.Delete(c.Context(), personID); err != nil {
+	if err := h.personService.Delete(c.Context(), user.ID, personID); err != nil {
 		if errors.Is(err, domain.ErrPersonNotFound) {
 			return middleware.NotFound("Person not found")
 		}
@@ -238,3 +343,71 @@ func getPaginationParams(c *fiber.Ctx) domain.PaginationParams {
 	params.Validate()
 	return params
 }
+
+func (h *PersonHandler) Merge(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	var input domain.MergePersonInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+	if input.TargetID == uuid.Nil {
+		return middleware.BadRequest("target_id is required")
+	}
+
+	if user.Role == string(domain.RoleMember) {
+		payload, err := json.Marshal(input)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to marshal payload")
+		}
+
+		var requesterNote *string
+		if note := c.Query("requester_note"); note != "" {
+			requesterNote = &note
+		}
+
+		crInput := domain.CreateChangeRequestInput{
+			EntityType:    domain.EntityPerson,
+			EntityID:      &personID,
+			Action:        domain.ActionMerge,
+			Payload:       payload,
+			RequesterNote: requesterNote,
+		}
+
+		cr, err := h.crService.Create(c.Context(), user.ID, crInput)
+		if err != nil {
+			return changeRequestError(err)
+		}
+
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message":        "Merge request submitted for approval",
+			"change_request": cr,
+		})
+	}
+
+	result, err := h.personService.Merge(c.Context(), user.ID, personID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPersonNotFound):
+			return middleware.NotFound("Person not found")
+		case errors.Is(err, domain.ErrMergeSamePerson),
+			errors.Is(err, domain.ErrInvalidMergeField),
+			errors.Is(err, domain.ErrInvalidLifeDates):
+			return middleware.BadRequest(err.Error())
+		case errors.Is(err, domain.ErrMergeLinkedUsers):
+			return middleware.Conflict(err.Error())
+		}
+		return err
+	}
+	result.Survivor.Redact(viewerOf(c), time.Now())
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/personname"
+)
+
+type PersonNameHandler struct {
+	nameService personname.Service
+}
+
+func NewPersonNameHandler(nameService personname.Service) *PersonNameHandler {
+	return &PersonNameHandler{nameService: nameService}
+}
+
+func (h *PersonNameHandler) List(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	names, err := h.nameService.ListByPerson(c.Context(), personID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(names)
+}
+
+func (h *PersonNameHandler) Create(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	var input domain.CreatePersonNameInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+	if input.Name == "" {
+		return middleware.BadRequest("Name is required")
+	}
+
+	name, err := h.nameService.Create(c.Context(), userID, personID, input)
+	if err != nil {
+		return personNameError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(name)
+}
+
+func (h *PersonNameHandler) Update(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+	nameID, err := uuid.Parse(c.Params("nameId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid name ID")
+	}
+
+	var input domain.UpdatePersonNameInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	name, err := h.nameService.Update(c.Context(), userID, personID, nameID, input)
+	if err != nil {
+		return personNameError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(name)
+}
+
+func (h *PersonNameHandler) Delete(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+	nameID, err := uuid.Parse(c.Params("nameId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid name ID")
+	}
+
+	if err := h.nameService.Delete(c.Context(), userID, personID, nameID); err != nil {
+		return personNameError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func personNameError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPersonNotFound):
+		return middleware.NotFound("Person not found")
+	case errors.Is(err, domain.ErrPersonNameNotFound):
+		return middleware.NotFound("Name not found")
+	case errors.Is(err, domain.ErrInvalidNameType),
+		errors.Is(err, domain.ErrInvalidNameValidity):
+		return middleware.BadRequest(err.Error())
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/place"
+)
+
+type PlaceHandler struct {
+	placeService place.Service
+}
+
+func NewPlaceHandler(placeService place.Service) *PlaceHandler {
+	return &PlaceHandler{placeService: placeService}
+}
+
+// List returns the sub-places of parent_id, the countries when no parent is
+// given, or every place of a level.
+func (h *PlaceHandler) List(c *fiber.Ctx) error {
+	var parentID *uuid.UUID
+	if v := c.Query("parent_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return middleware.BadRequest("Invalid parent ID")
+		}
+		parentID = &id
+	}
+	var level *domain.PlaceLevel
+	if v := c.Query("level"); v != "" {
+		l := domain.PlaceLevel(v)
+		level = &l
+	}
+
+	places, err := h.placeService.List(c.Context(), parentID, level)
+	if err != nil {
+		return placeError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(places)
+}
+
+func (h *PlaceHandler) Search(c *fiber.Ctx) error {
+	query := c.Query("q")
+	if len(query) < 2 {
+		return middleware.BadRequest("Query must be at least 2 characters")
+	}
+
+	places, err := h.placeService.Search(c.Context(), query, c.QueryInt("limit", 0))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(places)
+}
+
+func (h *PlaceHandler) Get(c *fiber.Ctx) error {
+	placeID, err := uuid.Parse(c.Params("placeId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid place ID")
+	}
+
+	p, err := h.placeService.Get(c.Context(), placeID)
+	if err != nil {
+		return placeError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(p)
+}
+
+func (h *PlaceHandler) Create(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	var input domain.CreatePlaceInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+	if input.Name == "" {
+		return middleware.BadRequest("Name is required")
+	}
+
+	p, err := h.placeService.Create(c.Context(), userID, input)
+	if err != nil {
+		return placeError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(p)
+}
+
+func (h *PlaceHandler) Update(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	placeID, err := uuid.Parse(c.Params("placeId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid place ID")
+	}
+
+	var input domain.UpdatePlaceInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	p, err := h.placeService.Update(c.Context(), userID, placeID, input)
+	if err != nil {
+		return placeError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(p)
+}
+
+func (h *PlaceHandler) Delete(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	placeID, err := uuid.Parse(c.Params("placeId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid place ID")
+	}
+
+	if err := h.placeService.Delete(c.Context(), userID, placeID); err != nil {
+		return placeError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func placeError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPlaceNotFound):
+		return middleware.NotFound(err.Error())
+	case errors.Is(err, domain.ErrPlaceInUse):
+		return middleware.Conflict(err.Error())
+	case errors.Is(err, domain.ErrInvalidPlaceLevel),
+		errors.Is(err, domain.ErrInvalidPlaceHierarchy),
+		errors.Is(err, domain.ErrInvalidCoordinates):
+		return middleware.BadRequest(err.Error())
+	}
+	return err
+}
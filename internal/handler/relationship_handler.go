@@ -2,6 +2,8 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -55,7 +57,7 @@ func (h *RelationshipHandler) Create(c *fiber.Ctx) error {
 
 		cr, err := h.crService.Create(c.Context(), user.ID, crInput)
 		if err != nil {
-			return err
+			return changeRequestError(err)
 		}
 
 		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
@@ -71,14 +73,20 @@ func (h *RelationshipHandler) Create(c *fiber.Ctx) error {
 			return middleware.BadRequest("Cannot create relationship with self")
 		case relationship.ErrInvalidRelationType:
 			return middleware.BadRequest("Invalid relationship type")
+		case relationship.ErrRelationshipCycle, relationship.ErrParentYounger:
+			return middleware.BadRequest(err.Error())
 		case domain.ErrPersonNotFound:
 			return middleware.NotFound("One or both persons not found")
 		case relationship.ErrDuplicateRelationship:
 			return middleware.Conflict("Relationship already exists between these two persons")
+		case domain.ErrPlaceNotFound:
+			return middleware.BadRequest("Marriage place not found")
 		}
 		return err
 	}
 
+	rel.Redact(viewerOf(c), time.Now())
+
 	return c.Status(fiber.StatusCreated).JSON(rel)
 }
 
@@ -93,6 +101,7 @@ func (h *RelationshipHandler) List(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	domain.RedactRelationships(relationships, viewerOf(c), time.Now())
 
 	return c.Status(fiber.StatusOK).JSON(relationships)
 }
@@ -109,9 +118,14 @@ func (h *RelationshipHandler) Get(c *fiber.Ctx) error {
 		if err == relationship.ErrRelationshipNotFound {
 			return middleware.NotFound("Relationship not found")
 		}
+		if err == domain.ErrPlaceNotFound {
+			return middleware.BadRequest("Marriage place not found")
+		}
 		return err
 	}
 
+	rel.Redact(viewerOf(c), time.Now())
+
 	return c.Status(fiber.StatusOK).JSON(rel)
 }
 
@@ -153,7 +167,10 @@ func (h *RelationshipHandler) Update(c *fiber.Ctx) error {
 
 		cr, err := h.crService.Create(c.Context(), user.ID, crInput)
 		if err != nil {
-			return err
+			if errors.Is(err, changerequest.ErrEntityNotFound) {
+				return middleware.NotFound("Relationship not found")
+			}
+			return changeRequestError(err)
 		}
 
 		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
@@ -170,6 +187,8 @@ func (h *RelationshipHandler) Update(c *fiber.Ctx) error {
 		return err
 	}
 
+	rel.Redact(viewerOf(c), time.Now())
+
 	return c.Status(fiber.StatusOK).JSON(rel)
 }
 
@@ -212,7 +231,7 @@ func (h *RelationshipHandler) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.relService.Delete(c.Context(), relID); err != nil {
+	if err := h.relService.Delete(c.Context(), user.ID, relID); err != nil {
 		return err
 	}
 
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/source"
+)
+
+type SourceHandler struct {
+	sourceService source.Service
+}
+
+func NewSourceHandler(sourceService source.Service) *SourceHandler {
+	return &SourceHandler{sourceService: sourceService}
+}
+
+func (h *SourceHandler) ListSources(c *fiber.Ctx) error {
+	params := domain.SourceListParams{
+		Query: c.Query("q"),
+		PaginationParams: domain.PaginationParams{
+			Page:     c.QueryInt("page", 1),
+			PageSize: c.QueryInt("page_size", 20),
+		},
+	}
+	if v := c.Query("type"); v != "" {
+		t := domain.SourceType(v)
+		params.Type = &t
+	}
+
+	result, err := h.sourceService.ListSources(c.Context(), params)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (h *SourceHandler) GetSource(c *fiber.Ctx) error {
+	sourceID, err := uuid.Parse(c.Params("sourceId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid source ID")
+	}
+
+	src, err := h.sourceService.GetSource(c.Context(), sourceID)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(src)
+}
+
+func (h *SourceHandler) CreateSource(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	var input domain.CreateSourceInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+	if input.Title == "" {
+		return middleware.BadRequest("Title is required")
+	}
+
+	src, err := h.sourceService.CreateSource(c.Context(), userID, input)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(src)
+}
+
+func (h *SourceHandler) UpdateSource(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	sourceID, err := uuid.Parse(c.Params("sourceId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid source ID")
+	}
+
+	var input domain.UpdateSourceInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	src, err := h.sourceService.UpdateSource(c.Context(), userID, sourceID, input)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(src)
+}
+
+func (h *SourceHandler) DeleteSource(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	sourceID, err := uuid.Parse(c.Params("sourceId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid source ID")
+	}
+
+	if err := h.sourceService.DeleteSource(c.Context(), userID, sourceID); err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func (h *SourceHandler) ListSourceCitations(c *fiber.Ctx) error {
+	sourceID, err := uuid.Parse(c.Params("sourceId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid source ID")
+	}
+
+	citations, err := h.sourceService.ListSourceCitations(c.Context(), sourceID)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(citations)
+}
+
+// ListCitations returns the citations attached to one person, relationship
+// or event.
+func (h *SourceHandler) ListCitations(c *fiber.Ctx) error {
+	entityID, err := uuid.Parse(c.Query("entity_id"))
+	if err != nil {
+		return middleware.BadRequest("Invalid entity ID")
+	}
+
+	citations, err := h.sourceService.ListCitations(c.Context(), domain.EntityType(c.Query("entity_type")), entityID)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(citations)
+}
+
+func (h *SourceHandler) CreateCitation(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	var input domain.CreateCitationInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	citation, err := h.sourceService.CreateCitation(c.Context(), userID, input)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(citation)
+}
+
+func (h *SourceHandler) UpdateCitation(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	citationID, err := uuid.Parse(c.Params("citationId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid citation ID")
+	}
+
+	var input domain.UpdateCitationInput
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.BadRequest("Invalid request body")
+	}
+
+	citation, err := h.sourceService.UpdateCitation(c.Context(), userID, citationID, input)
+	if err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(citation)
+}
+
+func (h *SourceHandler) DeleteCitation(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	citationID, err := uuid.Parse(c.Params("citationId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid citation ID")
+	}
+
+	if err := h.sourceService.DeleteCitation(c.Context(), userID, citationID); err != nil {
+		return sourceError(err)
+	}
+
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func sourceError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSourceNotFound),
+		errors.Is(err, domain.ErrCitationNotFound):
+		return middleware.NotFound(err.Error())
+	case errors.Is(err, domain.ErrSourceInUse):
+		return middleware.Conflict(err.Error())
+	case errors.Is(err, domain.ErrInvalidSourceType),
+		errors.Is(err, domain.ErrInvalidConfidence),
+		errors.Is(err, domain.ErrInvalidCitationEntity),
+		errors.Is(err, domain.ErrInvalidCitationField),
+		errors.Is(err, domain.ErrCitedEntityNotFound),
+		errors.Is(err, domain.ErrCitationMediaNotFound):
+		return middleware.BadRequest(err.Error())
+	}
+	return err
+}
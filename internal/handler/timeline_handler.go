@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/timeline"
+)
+
+type TimelineHandler struct {
+	timelineService timeline.Service
+}
+
+func NewTimelineHandler(timelineService timeline.Service) *TimelineHandler {
+	return &TimelineHandler{timelineService: timelineService}
+}
+
+func (h *TimelineHandler) GetPersonTimeline(c *fiber.Ctx) error {
+	personID, err := uuid.Parse(c.Params("personId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid person ID")
+	}
+
+	result, err := h.timelineService.GetPersonTimeline(c.Context(), personID, viewerOf(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrPersonNotFound) {
+			return middleware.NotFound("Person not found")
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+	"silsilah-keluarga/internal/service/trash"
+)
+
+type TrashHandler struct {
+	trashService trash.Service
+}
+
+func NewTrashHandler(trashService trash.Service) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+func (h *TrashHandler) List(c *fiber.Ctx) error {
+	params := domain.TrashListParams{
+		PaginationParams: domain.PaginationParams{
+			Page:     c.QueryInt("page", 1),
+			PageSize: c.QueryInt("page_size", 20),
+		},
+	}
+	if v := c.Query("entity_type"); v != "" {
+		t := domain.EntityType(strings.ToUpper(v))
+		params.EntityType = &t
+	}
+
+	result, err := h.trashService.List(c.Context(), params)
+	if err != nil {
+		return trashError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (h *TrashHandler) Restore(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	entityID, err := uuid.Parse(c.Params("entityId"))
+	if err != nil {
+		return middleware.BadRequest("Invalid entity ID")
+	}
+	entityType := domain.EntityType(strings.ToUpper(c.Params("entityType")))
+
+	result, err := h.trashService.Restore(c.Context(), userID, entityType, entityID)
+	if err != nil {
+		return trashError(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (h *TrashHandler) Purge(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return middleware.Unauthorized("User not authenticated")
+	}
+
+	result, err := h.trashService.Purge(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func trashError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrTrashItemNotFound):
+		return middleware.NotFound(err.Error())
+	case errors.Is(err, domain.ErrRestoreBlocked):
+		return middleware.Conflict(err.Error())
+	case errors.Is(err, domain.ErrInvalidTrashEntity):
+		return middleware.BadRequest(err.Error())
+	}
+	return err
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
@@ -159,6 +161,7 @@ func (h *UserHandler) GetAncestors(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	domain.RedactPersons(ancestors, domain.ViewerOf(user), time.Now())
 
 	return c.JSON(ancestors)
 }
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/middleware"
+)
+
+// viewerOf is the current user as seen by the privacy redaction.
+func viewerOf(c *fiber.Ctx) domain.Viewer {
+	return domain.ViewerOf(middleware.GetCurrentUser(c))
+}
+
+// viewerRole is the role of the current user, used to hide custom fields
+// they may not see.
+func viewerRole(c *fiber.Ctx) domain.UserRole {
+	return viewerOf(c).Role
+}
@@ -0,0 +1,199 @@
+// Package calendar converts dates between the Gregorian calendar and the
+// calendars found in Indonesian family records: the tabular Islamic (Hijri)
+// calendar and the Javanese Sultan Agungan calendar. All conversions go
+// through Julian Day Numbers and are purely arithmetic.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Calendar string
+
+const (
+	Gregorian Calendar = "GREGORIAN"
+	Hijri     Calendar = "HIJRI"
+	Javanese  Calendar = "JAVANESE"
+)
+
+var ErrInvalidDate = errors.New("invalid calendar date")
+
+// unixEpochJDN is the Julian Day Number of 1 January 1970.
+const unixEpochJDN = 2440588
+
+func (c Calendar) IsValid() bool {
+	switch c {
+	case Gregorian, Hijri, Javanese:
+		return true
+	}
+	return false
+}
+
+// Escape returns the GEDCOM calendar escape written before a date, e.g.
+// "@#DHIJRI@". Gregorian dates carry no escape.
+func (c Calendar) Escape() string {
+	if c == Gregorian {
+		return ""
+	}
+	return "@#D" + string(c) + "@"
+}
+
+// FromEscape parses a GEDCOM calendar escape such as "@#DHIJRI@".
+func FromEscape(s string) (Calendar, bool) {
+	s = strings.ToUpper(s)
+	if !strings.HasPrefix(s, "@#D") || !strings.HasSuffix(s, "@") || len(s) < 5 {
+		return "", false
+	}
+	c := Calendar(s[3 : len(s)-1])
+	if !c.IsValid() {
+		return "", false
+	}
+	return c, true
+}
+
+// MonthCodes returns the upper-case month codes used in GEDCOM-style date
+// strings for the calendar.
+func (c Calendar) MonthCodes() []string {
+	switch c {
+	case Hijri:
+		return hijriMonthCodes
+	case Javanese:
+		return javaneseMonthCodes
+	}
+	return gregorianMonthCodes
+}
+
+// MonthNames returns the human-readable month names of the calendar.
+func (c Calendar) MonthNames() []string {
+	switch c {
+	case Hijri:
+		return hijriMonthNames
+	case Javanese:
+		return javaneseMonthNames
+	}
+	return gregorianMonthNames
+}
+
+// MonthFromCode returns the 1-based month for a month code, or 0.
+func (c Calendar) MonthFromCode(code string) int {
+	code = strings.ToUpper(code)
+	for i, m := range c.MonthCodes() {
+		if m == code {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// MinYear is the first year the calendar can be converted from.
+func (c Calendar) MinYear() int {
+	if c == Javanese {
+		return javaneseEpochYear
+	}
+	return 1
+}
+
+func (c Calendar) DaysInMonth(year, month int) int {
+	switch c {
+	case Hijri:
+		return hijriDaysInMonth(year, month)
+	case Javanese:
+		return javaneseDaysInMonth(year, month)
+	}
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Validate checks a date in the calendar. Month and day may be zero for
+// partial dates, but a day requires a month.
+func (c Calendar) Validate(year, month, day int) error {
+	if !c.IsValid() {
+		return fmt.Errorf("%w: unknown calendar %q", ErrInvalidDate, c)
+	}
+	if year < c.MinYear() || year > 9999 {
+		return fmt.Errorf("%w: year out of range", ErrInvalidDate)
+	}
+	if month < 0 || month > 12 {
+		return fmt.Errorf("%w: month out of range", ErrInvalidDate)
+	}
+	if day != 0 {
+		if month == 0 {
+			return fmt.Errorf("%w: day without month", ErrInvalidDate)
+		}
+		if day < 1 || day > c.DaysInMonth(year, month) {
+			return fmt.Errorf("%w: day out of range", ErrInvalidDate)
+		}
+	}
+	return nil
+}
+
+// ToGregorian converts a valid date in the calendar to a Gregorian day
+// (midnight UTC).
+func (c Calendar) ToGregorian(year, month, day int) time.Time {
+	switch c {
+	case Hijri:
+		return fromJDN(hijriToJDN(year, month, day))
+	case Javanese:
+		return fromJDN(javaneseToJDN(year, month, day))
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// FromGregorian converts a Gregorian day to the calendar. It fails for days
+// the calendar does not cover, such as dates before the Javanese epoch.
+func (c Calendar) FromGregorian(t time.Time) (year, month, day int, err error) {
+	jdn := toJDN(t)
+	switch c {
+	case Hijri:
+		if jdn < hijriEpochJDN {
+			return 0, 0, 0, fmt.Errorf("%w: before the Hijri epoch", ErrInvalidDate)
+		}
+		year, month, day = jdnToHijri(jdn)
+	case Javanese:
+		if jdn < javaneseEpochJDN {
+			return 0, 0, 0, fmt.Errorf("%w: before the Sultan Agungan calendar", ErrInvalidDate)
+		}
+		year, month, day = jdnToJavanese(jdn)
+	default:
+		year, month, day = t.Year(), int(t.Month()), t.Day()
+	}
+	return year, month, day, nil
+}
+
+// Format renders a date for display, e.g. "12 Ramadan 1365 H" or
+// "Sura 1867 J". Month and day may be zero.
+func (c Calendar) Format(year, month, day int) string {
+	var parts []string
+	if day != 0 {
+		parts = append(parts, fmt.Sprint(day))
+	}
+	if month != 0 {
+		parts = append(parts, c.MonthNames()[month-1])
+	}
+	parts = append(parts, fmt.Sprint(year))
+	switch c {
+	case Hijri:
+		parts = append(parts, "H")
+	case Javanese:
+		parts = append(parts, "J")
+	}
+	return strings.Join(parts, " ")
+}
+
+func toJDN(t time.Time) int {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(t.Unix()/86400) + unixEpochJDN
+}
+
+func fromJDN(jdn int) time.Time {
+	return time.Unix(int64(jdn-unixEpochJDN)*86400, 0).UTC()
+}
+
+var gregorianMonthCodes = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+var gregorianMonthNames = []string{
+	"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December",
+}
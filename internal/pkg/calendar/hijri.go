@@ -0,0 +1,47 @@
+package calendar
+
+// The tabular (arithmetic) Islamic calendar: odd months have 30 days, even
+// months 29, and Dhu al-Hijjah gains a day in 11 leap years of each 30-year
+// cycle. It can differ by a day or two from dates fixed by moon sighting,
+// which is the usual precision of such records anyway.
+
+// hijriEpochJDN is 1 Muharram 1 AH (16 July 622 Julian).
+const hijriEpochJDN = 1948440
+
+var hijriMonthCodes = []string{
+	"MUHAR", "SAFAR", "RABIA", "RABIT", "JUMAA", "JUMAT",
+	"RAJAB", "SHAAB", "RAMAD", "SHAWW", "DHUAQ", "DHUAH",
+}
+
+var hijriMonthNames = []string{
+	"Muharram", "Safar", "Rabiul Awal", "Rabiul Akhir", "Jumadil Awal", "Jumadil Akhir",
+	"Rajab", "Syaban", "Ramadan", "Syawal", "Zulkaidah", "Zulhijah",
+}
+
+func hijriLeapYear(year int) bool {
+	return (14+11*year)%30 < 11
+}
+
+func hijriDaysInMonth(year, month int) int {
+	if month%2 == 1 || (month == 12 && hijriLeapYear(year)) {
+		return 30
+	}
+	return 29
+}
+
+func hijriToJDN(year, month, day int) int {
+	return day + (59*(month-1)+1)/2 + (year-1)*354 + (3+11*year)/30 + hijriEpochJDN - 1
+}
+
+func jdnToHijri(jdn int) (year, month, day int) {
+	year = (30*(jdn-hijriEpochJDN) + 10646) / 10631
+	if jdn < hijriToJDN(year, 1, 1) {
+		year--
+	}
+	month = 1
+	for month < 12 && jdn >= hijriToJDN(year, month+1, 1) {
+		month++
+	}
+	day = jdn - hijriToJDN(year, month, 1) + 1
+	return year, month, day
+}
@@ -0,0 +1,115 @@
+package calendar
+
+// The Javanese (Sultan Agungan) calendar continues the Saka year count but
+// has been lunar since 1 Sura 1555 AJ, which coincides with 1 Muharram
+// 1043 AH (8 July 1633). Years run in eight-year windu cycles (Alip, Ehe,
+// Jimawal, Je, Dal, Be, Wawu, Jimakir) in which Ehe, Dal and Jimakir have
+// 355 days and the rest 354. Every 120 years (a kurup) the last Jimakir
+// drops its extra day so the calendar keeps pace with the moon; kurups
+// start in 1627, 1747, 1867 and 1987 AJ. Dates before 1555 AJ belong to
+// the older solar Saka reckoning and are not converted.
+
+const (
+	javaneseEpochYear = 1555
+	// javaneseEpochJDN is 1 Sura 1555 AJ (8 July 1633 Gregorian).
+	javaneseEpochJDN = 2317690
+
+	winduYears = 8
+	winduDays  = 5*354 + 3*355
+	kurupYears = 120
+	// firstKurupEnd is the last year of the first, shortened kurup.
+	firstKurupEnd = 1626
+)
+
+var javaneseMonthCodes = []string{
+	"SURA", "SAPAR", "MULUD", "BAKDA", "JUMAW", "JUMAK",
+	"REJEB", "RUWAH", "PASA", "SAWAL", "SELA", "BESAR",
+}
+
+var javaneseMonthNames = []string{
+	"Sura", "Sapar", "Mulud", "Bakdamulud", "Jumadilawal", "Jumadilakir",
+	"Rejeb", "Ruwah", "Pasa", "Sawal", "Sela", "Besar",
+}
+
+// WinduYearNames lists the years of a windu in order; 1555 AJ is an Alip.
+var WinduYearNames = []string{"Alip", "Ehe", "Jimawal", "Je", "Dal", "Be", "Wawu", "Jimakir"}
+
+// WinduYear returns the name of a Javanese year within its windu.
+func WinduYear(year int) string {
+	return WinduYearNames[mod(year-javaneseEpochYear, winduYears)]
+}
+
+func javaneseKurupEnd(year int) bool {
+	return year >= firstKurupEnd && (year-firstKurupEnd)%kurupYears == 0
+}
+
+func javaneseYearDays(year int) int {
+	days := 354
+	switch mod(year-javaneseEpochYear, winduYears) {
+	case 1, 4, 7:
+		days++
+	}
+	if javaneseKurupEnd(year) {
+		days--
+	}
+	return days
+}
+
+// kurupDropsBefore counts the days dropped at kurup ends in years before
+// the given one.
+func kurupDropsBefore(year int) int {
+	if year <= firstKurupEnd {
+		return 0
+	}
+	return (year-firstKurupEnd-1)/kurupYears + 1
+}
+
+func javaneseDaysInMonth(year, month int) int {
+	if month%2 == 1 || (month == 12 && javaneseYearDays(year) == 355) {
+		return 30
+	}
+	return 29
+}
+
+// javaneseYearStart returns the JDN of 1 Sura of the given year.
+func javaneseYearStart(year int) int {
+	elapsed := year - javaneseEpochYear
+	jdn := javaneseEpochJDN + (elapsed/winduYears)*winduDays - kurupDropsBefore(year)
+	for y := year - elapsed%winduYears; y < year; y++ {
+		jdn += 354
+		switch mod(y-javaneseEpochYear, winduYears) {
+		case 1, 4, 7:
+			jdn++
+		}
+	}
+	return jdn
+}
+
+func javaneseToJDN(year, month, day int) int {
+	jdn := javaneseYearStart(year)
+	for m := 1; m < month; m++ {
+		jdn += javaneseDaysInMonth(year, m)
+	}
+	return jdn + day - 1
+}
+
+func jdnToJavanese(jdn int) (year, month, day int) {
+	year = javaneseEpochYear + (jdn-javaneseEpochJDN)/winduDays*winduYears
+	for javaneseYearStart(year) > jdn {
+		year--
+	}
+	for javaneseYearStart(year+1) <= jdn {
+		year++
+	}
+	day = jdn - javaneseYearStart(year) + 1
+	month = 1
+	for day > javaneseDaysInMonth(year, month) {
+		day -= javaneseDaysInMonth(year, month)
+		month++
+	}
+	return year, month, day
+}
+
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
@@ -0,0 +1,112 @@
+// Package gazetteer bundles an offline list of Indonesian administrative
+// regions: the country, every provinsi and the larger kabupaten and kota,
+// with approximate coordinates and the names they carried in the past.
+package gazetteer
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+//go:embed indonesia.csv
+var indonesiaCSV []byte
+
+// Entry is one region in the gazetteer. ParentCode is empty for the country.
+type Entry struct {
+	Code            string
+	ParentCode      string
+	Level           domain.PlaceLevel
+	Name            string
+	Latitude        float64
+	Longitude       float64
+	HistoricalNames domain.HistoricalNames
+}
+
+// Load parses the bundled gazetteer. Parents always precede their children.
+func Load() ([]Entry, error) {
+	r := csv.NewReader(bytes.NewReader(indonesiaCSV))
+	r.Comment = '#'
+	r.FieldsPerRecord = 7
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("gazetteer: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for _, rec := range records {
+		entry, err := parseEntry(rec)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer: %s: %w", rec[0], err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseEntry(rec []string) (Entry, error) {
+	entry := Entry{
+		Code:       rec[0],
+		ParentCode: rec[1],
+		Level:      domain.PlaceLevel(rec[2]),
+		Name:       rec[3],
+	}
+	if !entry.Level.IsValid() {
+		return entry, domain.ErrInvalidPlaceLevel
+	}
+
+	var err error
+	if entry.Latitude, err = strconv.ParseFloat(rec[4], 64); err != nil {
+		return entry, err
+	}
+	if entry.Longitude, err = strconv.ParseFloat(rec[5], 64); err != nil {
+		return entry, err
+	}
+	entry.HistoricalNames, err = parseHistoricalNames(rec[6])
+	return entry, err
+}
+
+// parseHistoricalNames reads "Batavia:1619-1942;Jayakarta:-1618".
+func parseHistoricalNames(s string) (domain.HistoricalNames, error) {
+	names := domain.HistoricalNames{}
+	if s == "" {
+		return names, nil
+	}
+	for _, part := range strings.Split(s, ";") {
+		i := strings.LastIndex(part, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("historical name %q has no year range", part)
+		}
+		from, to, ok := strings.Cut(part[i+1:], "-")
+		if !ok {
+			return nil, fmt.Errorf("historical name %q has a malformed year range", part)
+		}
+		h := domain.HistoricalName{Name: part[:i]}
+		var err error
+		if h.FromYear, err = parseYear(from); err != nil {
+			return nil, err
+		}
+		if h.ToYear, err = parseYear(to); err != nil {
+			return nil, err
+		}
+		names = append(names, h)
+	}
+	return names, nil
+}
+
+func parseYear(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	y, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &y, nil
+}
@@ -11,6 +11,11 @@ import (
 
 type Translations map[string]string
 
+var translationFiles = map[string]string{
+	"relationships.yaml": "RELATIONSHIPS",
+	"events.yaml":        "EVENTS",
+}
+
 var (
 	locales = make(map[string]Translations)
 	mu      sync.RWMutex
@@ -28,22 +33,27 @@ func LoadTranslations(localePath string) error {
 	for _, entry := range entries {
 		if entry.IsDir() {
 			locale := entry.Name()
-			filePath := filepath.Join(localePath, locale, "relationships.yaml")
-			
-			data, err := os.ReadFile(filePath)
-			if err != nil {
-				continue 
-			}
+			trans := make(Translations)
 
-			var config struct {
-				Relationships Translations `yaml:"RELATIONSHIPS"`
-			}
-			
-			if err := yaml.Unmarshal(data, &config); err != nil {
-				return fmt.Errorf("failed to parse %s: %w", filePath, err)
+			for file, section := range translationFiles {
+				filePath := filepath.Join(localePath, locale, file)
+
+				data, err := os.ReadFile(filePath)
+				if err != nil {
+					continue
+				}
+
+				var config map[string]Translations
+				if err := yaml.Unmarshal(data, &config); err != nil {
+					return fmt.Errorf("failed to parse %s: %w", filePath, err)
+				}
+
+				for key, val := range config[section] {
+					trans[key] = val
+				}
 			}
 
-			locales[locale] = config.Relationships
+			locales[locale] = trans
 		}
 	}
 
@@ -0,0 +1,136 @@
+// Package namenorm folds the many spellings of Indonesian names into
+// comparable keys. Normalize rewrites Old Spelling (Ejaan Soewandi and van
+// Ophuijsen) into EYD and maps common Arabic-name variants to one form, so
+// "Moehammad Djoenaedi" and "Muhammad Junaidi" normalize alike. Phonetic
+// goes further and ignores the vowel and consonant differences that
+// transliteration introduces, for fuzzy matching.
+package namenorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// oldSpelling maps Old Spelling digraphs to EYD, longest first.
+var oldSpelling = []struct{ old, new string }{
+	{"oe", "u"},
+	{"dj", "j"},
+	{"tj", "c"},
+	{"nj", "ny"},
+	{"sj", "sy"},
+	{"ch", "kh"},
+}
+
+// Normalize returns the canonical search form of a name: lower case,
+// without accents or punctuation, in EYD spelling and with Arabic-name
+// variants replaced by their common Indonesian form.
+func Normalize(name string) string {
+	tokens := tokenize(name)
+	for i, t := range tokens {
+		tokens[i] = normalizeToken(t)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Phonetic returns a sound-alike key for a name. Names whose keys are equal
+// are probably the same name written differently, e.g. Husein/Husain or
+// Yusuf/Jusuf.
+func Phonetic(name string) string {
+	tokens := tokenize(name)
+	for i, t := range tokens {
+		tokens[i] = phoneticToken(normalizeToken(t))
+	}
+	return strings.Join(tokens, " ")
+}
+
+func tokenize(name string) []string {
+	name, _, _ = transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	name = strings.ToLower(name)
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizeToken(t string) string {
+	if v, ok := arabicVariants[t]; ok {
+		return v
+	}
+
+	old := false
+	for _, r := range oldSpelling {
+		if strings.Contains(t, r.old) {
+			old = true
+			break
+		}
+	}
+	if old {
+		// In Old Spelling a bare "j" is the EYD "y"; rewrite it before
+		// "dj" becomes the new "j".
+		var b strings.Builder
+		for i := 0; i < len(t); i++ {
+			if t[i] == 'j' && (i == 0 || !strings.ContainsRune("dtns", rune(t[i-1]))) {
+				b.WriteByte('y')
+				continue
+			}
+			b.WriteByte(t[i])
+		}
+		t = b.String()
+		for _, r := range oldSpelling {
+			t = strings.ReplaceAll(t, r.old, r.new)
+		}
+	}
+
+	if v, ok := arabicVariants[t]; ok {
+		return v
+	}
+	return t
+}
+
+var phoneticDigraphs = strings.NewReplacer(
+	"kh", "h",
+	"sy", "s",
+	"sh", "s",
+	"ts", "s",
+	"th", "t",
+	"dh", "d",
+	"dz", "z",
+	"ph", "f",
+	"ck", "k",
+)
+
+var phoneticLetters = strings.NewReplacer(
+	"q", "k",
+	"v", "f",
+	"x", "ks",
+	"j", "y",
+	"e", "i",
+	"o", "u",
+)
+
+func phoneticToken(t string) string {
+	t = phoneticDigraphs.Replace(t)
+	t = phoneticLetters.Replace(t)
+	if len(t) > 1 {
+		// Diphthongs are written either way (Zainab/Zaenab), except at the
+		// start where they carry the name (Aisyah is not Isa).
+		rest := strings.ReplaceAll(t[1:], "ai", "i")
+		t = t[:1] + strings.ReplaceAll(rest, "au", "u")
+	}
+	if len(t) > 1 {
+		t = strings.TrimSuffix(t, "h")
+	}
+
+	var b strings.Builder
+	var prev rune
+	for _, r := range t {
+		if r != prev {
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
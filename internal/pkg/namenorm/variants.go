@@ -0,0 +1,42 @@
+package namenorm
+
+// arabicVariants maps spellings and abbreviations of common Arabic-derived
+// names to the form most used in Indonesia. Keys are looked up both before
+// and after the Old Spelling rewrite, so EYD forms of old spellings need no
+// entry of their own.
+var arabicVariants = map[string]string{}
+
+func init() {
+	for canonical, variants := range map[string][]string{
+		"muhammad":    {"mohammad", "mohamad", "muhamad", "mohamed", "mohammed", "muhammed", "mochammad", "mochamad", "mukhammad", "muhamat", "mohd", "muh", "moh", "mhd", "moch", "mochd"},
+		"ahmad":       {"achmad", "akhmad", "ahmed", "achmat", "ahmat"},
+		"abdul":       {"abdel", "abd", "abdol"},
+		"abdullah":    {"abdulah", "abdillah", "abdallah"},
+		"abdurrahman": {"abdurahman", "abdurrachman"},
+		"rahman":      {"rachman", "rakhman", "rochman", "rohman"},
+		"rahmat":      {"rachmat", "rakhmat", "rahmad", "rohmat"},
+		"siti":        {"sitti", "sity"},
+		"nur":         {"noor", "nour"},
+		"hasan":       {"hassan", "khasan"},
+		"husein":      {"husain", "hussein", "husin", "hussain"},
+		"fatimah":     {"fatima", "fatmah", "fathimah"},
+		"aisyah":      {"aisah", "aishah", "aisya", "aisha", "aiesyah"},
+		"khadijah":    {"khodijah", "khadija"},
+		"zainab":      {"zaenab", "zaynab"},
+		"yusuf":       {"jusuf", "yusup"},
+		"umar":        {"omar"},
+		"usman":       {"osman", "utsman", "othman"},
+		"syarif":      {"sharif", "syarief", "syarip"},
+		"mustafa":     {"mustofa", "mustapha", "musthafa", "mushtofa"},
+		"saleh":       {"salih", "soleh", "sholeh", "shaleh", "sholih"},
+		"ramadhan":    {"ramadan", "romadhon", "ramadlan"},
+		"ibrahim":     {"ibrohim", "iberahim"},
+		"ismail":      {"ismael"},
+		"zulkifli":    {"dzulkifli", "zulkifly"},
+		"hidayat":     {"hidajat"},
+	} {
+		for _, v := range variants {
+			arabicVariants[v] = canonical
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type ApprovalRepository interface {
+	// GetPolicy returns the stored policy, or nil when there is none.
+	GetPolicy(ctx context.Context, entityType domain.EntityType, action domain.ChangeAction) (*domain.ApprovalPolicy, error)
+	ListPolicies(ctx context.Context) ([]domain.ApprovalPolicy, error)
+	UpsertPolicy(ctx context.Context, policy *domain.ApprovalPolicy) error
+	// LockRequest holds the change request row until the transaction in ctx
+	// ends, so approvals of one request are counted one at a time, and
+	// returns its status as of the lock.
+	LockRequest(ctx context.Context, requestID uuid.UUID) (domain.ChangeRequestStatus, error)
+	// AddApproval returns domain.ErrAlreadyApproved when the reviewer
+	// approved before.
+	AddApproval(ctx context.Context, approval *domain.ChangeRequestApproval) error
+	ListApprovals(ctx context.Context, requestID uuid.UUID) ([]domain.ChangeRequestApproval, error)
+	// ClearApprovals drops the approvals of a request, e.g. because it was
+	// amended after they were given.
+	ClearApprovals(ctx context.Context, requestID uuid.UUID) error
+}
+
+type approvalRepository struct {
+	db *sqlx.DB
+}
+
+func NewApprovalRepository(db *sqlx.DB) ApprovalRepository {
+	return &approvalRepository{db: db}
+}
+
+func (r *approvalRepository) GetPolicy(ctx context.Context, entityType domain.EntityType, action domain.ChangeAction) (*domain.ApprovalPolicy, error) {
+	var policy domain.ApprovalPolicy
+	query := `SELECT * FROM approval_policies WHERE entity_type = $1 AND action = $2`
+
+	err := conn(ctx, r.db).GetContext(ctx, &policy, query, entityType, action)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *approvalRepository) ListPolicies(ctx context.Context) ([]domain.ApprovalPolicy, error) {
+	query := `SELECT * FROM approval_policies ORDER BY entity_type, action`
+
+	var policies []domain.ApprovalPolicy
+	err := conn(ctx, r.db).SelectContext(ctx, &policies, query)
+	return policies, err
+}
+
+func (r *approvalRepository) UpsertPolicy(ctx context.Context, policy *domain.ApprovalPolicy) error {
+	query := `
+		INSERT INTO approval_policies (entity_type, action, required_approvals, require_consent, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (entity_type, action) DO UPDATE SET
+			required_approvals = EXCLUDED.required_approvals,
+			require_consent = EXCLUDED.require_consent,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		policy.EntityType, policy.Action, policy.RequiredApprovals, policy.RequireConsent, policy.UpdatedBy,
+	).Scan(&policy.UpdatedAt)
+}
+
+func (r *approvalRepository) LockRequest(ctx context.Context, requestID uuid.UUID) (domain.ChangeRequestStatus, error) {
+	var status domain.ChangeRequestStatus
+	query := `SELECT status FROM change_requests WHERE request_id = $1 FOR UPDATE`
+	err := conn(ctx, r.db).GetContext(ctx, &status, query, requestID)
+	return status, err
+}
+
+func (r *approvalRepository) AddApproval(ctx context.Context, approval *domain.ChangeRequestApproval) error {
+	query := `
+		INSERT INTO change_request_approvals (request_id, reviewer_id, reviewer_role, note)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (request_id, reviewer_id) DO NOTHING
+		RETURNING created_at`
+
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query,
+		approval.RequestID, approval.ReviewerID, approval.ReviewerRole, approval.Note,
+	).Scan(&approval.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAlreadyApproved
+	}
+	return err
+}
+
+func (r *approvalRepository) ListApprovals(ctx context.Context, requestID uuid.UUID) ([]domain.ChangeRequestApproval, error) {
+	query := `SELECT * FROM change_request_approvals WHERE request_id = $1 ORDER BY created_at`
+
+	var approvals []domain.ChangeRequestApproval
+	err := conn(ctx, r.db).SelectContext(ctx, &approvals, query, requestID)
+	return approvals, err
+}
+
+func (r *approvalRepository) ClearApprovals(ctx context.Context, requestID uuid.UUID) error {
+	query := `DELETE FROM change_request_approvals WHERE request_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, requestID)
+	return err
+}
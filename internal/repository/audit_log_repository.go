@@ -30,7 +30,7 @@ func (r *auditLogRepository) Create(ctx context.Context, log *domain.AuditLog) e
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING created_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		log.ID, log.UserID, log.Action, log.EntityType, log.EntityID,
 		log.OldValue, log.NewValue, log.IPAddress, log.UserAgent,
 	).Scan(&log.CreatedAt)
@@ -41,7 +41,7 @@ func (r *auditLogRepository) List(ctx context.Context, params domain.PaginationP
 
 	var total int64
 	countQuery := `SELECT COUNT(*) FROM audit_logs`
-	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery); err != nil {
 		return nil, 0, err
 	}
 
@@ -55,7 +55,7 @@ func (r *auditLogRepository) List(ctx context.Context, params domain.PaginationP
 		LIMIT $1 OFFSET $2`
 
 	var logs []domain.AuditLog
-	err := r.db.SelectContext(ctx, &logs, query, params.PageSize, params.Offset())
+	err := conn(ctx, r.db).SelectContext(ctx, &logs, query, params.PageSize, params.Offset())
 	return logs, total, err
 }
 
@@ -64,7 +64,7 @@ func (r *auditLogRepository) ListByEntity(ctx context.Context, entityType string
 
 	var total int64
 	countQuery := `SELECT COUNT(*) FROM audit_logs WHERE entity_type = $1 AND entity_id = $2`
-	if err := r.db.GetContext(ctx, &total, countQuery, entityType, entityID); err != nil {
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery, entityType, entityID); err != nil {
 		return nil, 0, err
 	}
 
@@ -75,7 +75,7 @@ func (r *auditLogRepository) ListByEntity(ctx context.Context, entityType string
 		LIMIT $3 OFFSET $4`
 
 	var logs []domain.AuditLog
-	err := r.db.SelectContext(ctx, &logs, query, entityType, entityID, params.PageSize, params.Offset())
+	err := conn(ctx, r.db).SelectContext(ctx, &logs, query, entityType, entityID, params.PageSize, params.Offset())
 	return logs, total, err
 }
 
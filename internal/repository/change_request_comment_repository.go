@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type ChangeRequestCommentRepository interface {
+	Create(ctx context.Context, comment *domain.ChangeRequestComment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequestComment, error)
+	ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.ChangeRequestComment, error)
+}
+
+type changeRequestCommentRepository struct {
+	db *sqlx.DB
+}
+
+func NewChangeRequestCommentRepository(db *sqlx.DB) ChangeRequestCommentRepository {
+	return &changeRequestCommentRepository{db: db}
+}
+
+func (r *changeRequestCommentRepository) Create(ctx context.Context, comment *domain.ChangeRequestComment) error {
+	query := `
+		INSERT INTO change_request_comments (comment_id, request_id, user_id, parent_id, content)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		comment.ID, comment.RequestID, comment.UserID, comment.ParentID, comment.Content,
+	).Scan(&comment.CreatedAt)
+}
+
+func (r *changeRequestCommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequestComment, error) {
+	var comment domain.ChangeRequestComment
+	query := `SELECT * FROM change_request_comments WHERE comment_id = $1`
+	err := conn(ctx, r.db).GetContext(ctx, &comment, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListByRequest returns the discussion of a request, oldest first, with
+// each author.
+func (r *changeRequestCommentRepository) ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.ChangeRequestComment, error) {
+	query := `
+		SELECT c.comment_id, c.request_id, c.user_id, c.parent_id, c.content, c.created_at,
+			u.full_name, u.avatar_url
+		FROM change_request_comments c
+		INNER JOIN users u ON c.user_id = u.user_id
+		WHERE c.request_id = $1
+		ORDER BY c.created_at`
+
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []domain.ChangeRequestComment{}
+	for rows.Next() {
+		var c domain.ChangeRequestComment
+		var user domain.CommentUser
+		if err := rows.Scan(
+			&c.ID, &c.RequestID, &c.UserID, &c.ParentID, &c.Content, &c.CreatedAt,
+			&user.FullName, &user.AvatarURL,
+		); err != nil {
+			return nil, err
+		}
+		user.ID = c.UserID
+		c.User = &user
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -15,6 +18,12 @@ type ChangeRequestRepository interface {
 	List(ctx context.Context, status *domain.ChangeRequestStatus, params domain.PaginationParams) ([]domain.ChangeRequest, int64, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ChangeRequestStatus, reviewedBy uuid.UUID, note *string) error
 	CountPending(ctx context.Context) (int64, error)
+	Amend(ctx context.Context, req *domain.ChangeRequest) error
+	Close(ctx context.Context, id uuid.UUID, status domain.ChangeRequestStatus) error
+	Touch(ctx context.Context, id uuid.UUID) error
+	ExpireStale(ctx context.Context, before time.Time) ([]domain.ChangeRequest, error)
+	CreateRevision(ctx context.Context, rev *domain.ChangeRequestRevision) error
+	ListRevisions(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestRevision, error)
 }
 
 type changeRequestRepository struct {
@@ -27,20 +36,27 @@ func NewChangeRequestRepository(db *sqlx.DB) ChangeRequestRepository {
 
 func (r *changeRequestRepository) Create(ctx context.Context, req *domain.ChangeRequest) error {
 	query := `
-		INSERT INTO change_requests (request_id, requested_by, entity_type, entity_id, action, payload, requester_note, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO change_requests (request_id, requested_by, entity_type, entity_id, action, payload, requester_note, status,
+			base_updated_at, base_snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING created_at, updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	var snapshot any
+	if len(req.BaseSnapshot) > 0 {
+		snapshot = req.BaseSnapshot
+	}
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		req.ID, req.RequestedBy, req.EntityType, req.EntityID,
 		req.Action, req.Payload, req.RequesterNote, req.Status,
+		req.BaseUpdatedAt, snapshot,
 	).Scan(&req.CreatedAt, &req.UpdatedAt)
 }
 
 func (r *changeRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
 	var req domain.ChangeRequest
 	query := `SELECT * FROM change_requests WHERE request_id = $1`
-	err := r.db.GetContext(ctx, &req, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &req, query, id)
 	return &req, err
 }
 
@@ -52,7 +68,7 @@ func (r *changeRequestRepository) List(ctx context.Context, status *domain.Chang
 
 	if status != nil {
 		countQuery := `SELECT COUNT(*) FROM change_requests WHERE status = $1`
-		if err := r.db.GetContext(ctx, &total, countQuery, *status); err != nil {
+		if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery, *status); err != nil {
 			return nil, 0, err
 		}
 
@@ -61,12 +77,12 @@ func (r *changeRequestRepository) List(ctx context.Context, status *domain.Chang
 			WHERE status = $1
 			ORDER BY created_at DESC
 			LIMIT $2 OFFSET $3`
-		err := r.db.SelectContext(ctx, &requests, query, *status, params.PageSize, params.Offset())
+		err := conn(ctx, r.db).SelectContext(ctx, &requests, query, *status, params.PageSize, params.Offset())
 		return requests, total, err
 	}
 
 	countQuery := `SELECT COUNT(*) FROM change_requests`
-	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery); err != nil {
 		return nil, 0, err
 	}
 
@@ -74,22 +90,116 @@ func (r *changeRequestRepository) List(ctx context.Context, status *domain.Chang
 		SELECT * FROM change_requests 
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`
-	err := r.db.SelectContext(ctx, &requests, query, params.PageSize, params.Offset())
+	err := conn(ctx, r.db).SelectContext(ctx, &requests, query, params.PageSize, params.Offset())
 	return requests, total, err
 }
 
+// UpdateStatus moves a pending request to status. The conditional update
+// locks the row until the surrounding transaction ends, so of two
+// concurrent reviews the second gets domain.ErrChangeRequestNotPending.
 func (r *changeRequestRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ChangeRequestStatus, reviewedBy uuid.UUID, note *string) error {
 	query := `
 		UPDATE change_requests 
 		SET status = $2, reviewed_by = $3, reviewed_at = NOW(), review_note = $4, updated_at = NOW()
-		WHERE request_id = $1`
-	_, err := r.db.ExecContext(ctx, query, id, status, reviewedBy, note)
-	return err
+		WHERE request_id = $1 AND status = 'PENDING'`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id, status, reviewedBy, note)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrChangeRequestNotPending
+	}
+	return nil
 }
 
 func (r *changeRequestRepository) CountPending(ctx context.Context) (int64, error) {
 	var count int64
 	query := `SELECT COUNT(*) FROM change_requests WHERE status = 'PENDING'`
-	err := r.db.GetContext(ctx, &count, query)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query)
 	return count, err
 }
+
+// Amend stores a new revision of an open request and puts it back up for
+// review. It fails with domain.ErrChangeRequestClosed when the request was
+// closed or amended since req.Revision-1 was read.
+func (r *changeRequestRepository) Amend(ctx context.Context, req *domain.ChangeRequest) error {
+	query := `
+		UPDATE change_requests
+		SET payload = $2, requester_note = $3, base_updated_at = $4, base_snapshot = $5, revision = $6,
+			status = 'PENDING', reviewed_by = NULL, reviewed_at = NULL, review_note = NULL, updated_at = NOW()
+		WHERE request_id = $1 AND revision = $6 - 1 AND status IN ('PENDING', 'CHANGES_REQUESTED')
+		RETURNING updated_at`
+
+	var snapshot any
+	if len(req.BaseSnapshot) > 0 {
+		snapshot = req.BaseSnapshot
+	}
+
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query,
+		req.ID, req.Payload, req.RequesterNote, req.BaseUpdatedAt, snapshot, req.Revision,
+	).Scan(&req.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrChangeRequestClosed
+	}
+	return err
+}
+
+// Close moves an open request to status, e.g. when its requester
+// withdraws it.
+func (r *changeRequestRepository) Close(ctx context.Context, id uuid.UUID, status domain.ChangeRequestStatus) error {
+	query := `
+		UPDATE change_requests SET status = $2, updated_at = NOW()
+		WHERE request_id = $1 AND status IN ('PENDING', 'CHANGES_REQUESTED')`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id, status)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrChangeRequestClosed
+	}
+	return nil
+}
+
+// Touch records activity on a request, which postpones its expiry.
+func (r *changeRequestRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE change_requests SET updated_at = NOW() WHERE request_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// ExpireStale closes the open requests with no activity since before and
+// returns them.
+func (r *changeRequestRepository) ExpireStale(ctx context.Context, before time.Time) ([]domain.ChangeRequest, error) {
+	var requests []domain.ChangeRequest
+	query := `
+		UPDATE change_requests SET status = 'EXPIRED', updated_at = NOW()
+		WHERE status IN ('PENDING', 'CHANGES_REQUESTED') AND updated_at < $1
+		RETURNING *`
+	err := conn(ctx, r.db).SelectContext(ctx, &requests, query, before)
+	return requests, err
+}
+
+func (r *changeRequestRepository) CreateRevision(ctx context.Context, rev *domain.ChangeRequestRevision) error {
+	query := `
+		INSERT INTO change_request_revisions (request_id, revision, payload, requester_note, review_note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING superseded_at`
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		rev.RequestID, rev.Revision, rev.Payload, rev.RequesterNote, rev.ReviewNote,
+	).Scan(&rev.SupersededAt)
+}
+
+func (r *changeRequestRepository) ListRevisions(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestRevision, error) {
+	revisions := []domain.ChangeRequestRevision{}
+	query := `SELECT * FROM change_request_revisions WHERE request_id = $1 ORDER BY revision`
+	err := conn(ctx, r.db).SelectContext(ctx, &revisions, query, id)
+	return revisions, err
+}
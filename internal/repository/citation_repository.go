@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type CitationRepository interface {
+	Create(ctx context.Context, citation *domain.Citation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Citation, error)
+	Update(ctx context.Context, citation *domain.Citation) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByEntity(ctx context.Context, entityType domain.EntityType, entityID uuid.UUID) ([]domain.Citation, error)
+	ListBySource(ctx context.Context, sourceID uuid.UUID) ([]domain.Citation, error)
+	CountBySource(ctx context.Context, sourceID uuid.UUID) (int64, error)
+	GetAll(ctx context.Context) ([]domain.Citation, error)
+}
+
+type citationRepository struct {
+	db *sqlx.DB
+}
+
+func NewCitationRepository(db *sqlx.DB) CitationRepository {
+	return &citationRepository{db: db}
+}
+
+// citationColumns selects a citation with its linked media ids.
+const citationColumns = `
+	c.*, ARRAY(
+		SELECT cm.media_id::text FROM citation_media cm
+		JOIN media m ON m.media_id = cm.media_id AND m.deleted_at IS NULL
+		WHERE cm.citation_id = c.citation_id ORDER BY m.created_at
+	) AS media_ids`
+
+type citationRow struct {
+	domain.Citation
+	RawMediaIDs pq.StringArray `db:"media_ids"`
+}
+
+func (row citationRow) toDomain() domain.Citation {
+	c := row.Citation
+	c.MediaIDs = make([]uuid.UUID, 0, len(row.RawMediaIDs))
+	for _, id := range row.RawMediaIDs {
+		if parsed, err := uuid.Parse(id); err == nil {
+			c.MediaIDs = append(c.MediaIDs, parsed)
+		}
+	}
+	return c
+}
+
+func (r *citationRepository) Create(ctx context.Context, citation *domain.Citation) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO citations (citation_id, source_id, entity_type, entity_id, field, page, confidence, note, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`
+
+	err = tx.QueryRowxContext(ctx, query,
+		citation.ID, citation.SourceID, citation.EntityType, citation.EntityID, citation.Field,
+		citation.Page, citation.Confidence, citation.Note, citation.CreatedBy,
+	).Scan(&citation.CreatedAt, &citation.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceCitationMedia(ctx, tx, citation.ID, citation.MediaIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *citationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Citation, error) {
+	var row citationRow
+	query := `SELECT ` + citationColumns + ` FROM citations c WHERE c.citation_id = $1 AND c.deleted_at IS NULL`
+
+	err := conn(ctx, r.db).GetContext(ctx, &row, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	citation := row.toDomain()
+	return &citation, nil
+}
+
+func (r *citationRepository) Update(ctx context.Context, citation *domain.Citation) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE citations
+		SET field = $2, page = $3, confidence = $4, note = $5, updated_at = NOW()
+		WHERE citation_id = $1 AND deleted_at IS NULL
+		RETURNING updated_at`
+
+	err = tx.QueryRowxContext(ctx, query,
+		citation.ID, citation.Field, citation.Page, citation.Confidence, citation.Note,
+	).Scan(&citation.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceCitationMedia(ctx, tx, citation.ID, citation.MediaIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceCitationMedia(ctx context.Context, tx queryer, citationID uuid.UUID, mediaIDs []uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM citation_media WHERE citation_id = $1`, citationID); err != nil {
+		return err
+	}
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO citation_media (citation_id, media_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING`,
+		citationID, pq.Array(mediaIDs),
+	)
+	return err
+}
+
+func (r *citationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE citations SET deleted_at = NOW() WHERE citation_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// ListByEntity returns the citations of one record with their sources.
+func (r *citationRepository) ListByEntity(ctx context.Context, entityType domain.EntityType, entityID uuid.UUID) ([]domain.Citation, error) {
+	query := `SELECT ` + citationColumns + `
+		FROM citations c
+		WHERE c.entity_type = $1 AND c.entity_id = $2 AND c.deleted_at IS NULL
+		ORDER BY c.field NULLS FIRST, c.created_at`
+
+	citations, err := r.selectCitations(ctx, query, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	return citations, r.attachSources(ctx, citations)
+}
+
+func (r *citationRepository) ListBySource(ctx context.Context, sourceID uuid.UUID) ([]domain.Citation, error) {
+	query := `SELECT ` + citationColumns + `
+		FROM citations c
+		WHERE c.source_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.created_at`
+
+	return r.selectCitations(ctx, query, sourceID)
+}
+
+func (r *citationRepository) CountBySource(ctx context.Context, sourceID uuid.UUID) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM citations WHERE source_id = $1 AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, sourceID)
+	return count, err
+}
+
+// GetAll returns every citation with its source, for export.
+func (r *citationRepository) GetAll(ctx context.Context) ([]domain.Citation, error) {
+	query := `SELECT ` + citationColumns + `
+		FROM citations c
+		WHERE c.deleted_at IS NULL
+		ORDER BY c.created_at`
+
+	citations, err := r.selectCitations(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return citations, r.attachSources(ctx, citations)
+}
+
+func (r *citationRepository) selectCitations(ctx context.Context, query string, args ...any) ([]domain.Citation, error) {
+	var rows []citationRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	citations := make([]domain.Citation, len(rows))
+	for i, row := range rows {
+		citations[i] = row.toDomain()
+	}
+	return citations, nil
+}
+
+func (r *citationRepository) attachSources(ctx context.Context, citations []domain.Citation) error {
+	if len(citations) == 0 {
+		return nil
+	}
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, c := range citations {
+		if !seen[c.SourceID] {
+			seen[c.SourceID] = true
+			ids = append(ids, c.SourceID)
+		}
+	}
+
+	var sources []domain.Source
+	query := `SELECT * FROM sources WHERE source_id = ANY($1)`
+	if err := conn(ctx, r.db).SelectContext(ctx, &sources, query, pq.Array(ids)); err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]*domain.Source, len(sources))
+	for i := range sources {
+		byID[sources[i].ID] = &sources[i]
+	}
+	for i := range citations {
+		citations[i].Source = byID[citations[i].SourceID]
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -13,7 +15,7 @@ type CommentRepository interface {
 	Create(ctx context.Context, comment *domain.Comment) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Comment, error)
 	Update(ctx context.Context, comment *domain.Comment) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
 	ListByPerson(ctx context.Context, personID uuid.UUID, params domain.PaginationParams) ([]domain.Comment, int64, error)
 }
 
@@ -31,16 +33,22 @@ func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment)
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING created_at, updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		comment.ID, comment.PersonID, comment.UserID, comment.ParentID, comment.Content,
 	).Scan(&comment.CreatedAt, &comment.UpdatedAt)
 }
 
 func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Comment, error) {
 	var comment domain.Comment
-	query := `SELECT * FROM comments WHERE id = $1 AND deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &comment, query, id)
-	return &comment, err
+	query := `SELECT * FROM comments WHERE comment_id = $1 AND deleted_at IS NULL`
+	err := conn(ctx, r.db).GetContext(ctx, &comment, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
 }
 
 func (r *commentRepository) Update(ctx context.Context, comment *domain.Comment) error {
@@ -50,14 +58,14 @@ func (r *commentRepository) Update(ctx context.Context, comment *domain.Comment)
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		comment.ID, comment.Content,
 	).Scan(&comment.UpdatedAt)
 }
 
-func (r *commentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE comments SET deleted_at = NOW() WHERE comment_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *commentRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	query := `UPDATE comments SET deleted_at = NOW(), deleted_by = $2 WHERE comment_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, deletedBy)
 	return err
 }
 
@@ -66,7 +74,7 @@ func (r *commentRepository) ListByPerson(ctx context.Context, personID uuid.UUID
 
 	var total int64
 	countQuery := `SELECT COUNT(*) FROM comments WHERE person_id = $1 AND deleted_at IS NULL`
-	if err := r.db.GetContext(ctx, &total, countQuery, personID); err != nil {
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery, personID); err != nil {
 		return nil, 0, err
 	}
 
@@ -80,7 +88,7 @@ func (r *commentRepository) ListByPerson(ctx context.Context, personID uuid.UUID
 		ORDER BY c.created_at DESC
 		LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.QueryxContext(ctx, query, personID, params.PageSize, params.Offset())
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query, personID, params.PageSize, params.Offset())
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type CustomFieldRepository interface {
+	CreateField(ctx context.Context, field *domain.CustomField) error
+	GetFieldByID(ctx context.Context, id uuid.UUID) (*domain.CustomField, error)
+	GetFieldByKey(ctx context.Context, key string) (*domain.CustomField, error)
+	UpdateField(ctx context.Context, field *domain.CustomField) error
+	DeleteField(ctx context.Context, id uuid.UUID) error
+	ListFields(ctx context.Context) ([]domain.CustomField, error)
+
+	SetValue(ctx context.Context, value *domain.PersonCustomValue) error
+	DeleteValue(ctx context.Context, personID, fieldID uuid.UUID) error
+	GetValuesByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonCustomValue, error)
+	GetAllValues(ctx context.Context) ([]domain.PersonCustomValue, error)
+}
+
+type customFieldRepository struct {
+	db *sqlx.DB
+}
+
+func NewCustomFieldRepository(db *sqlx.DB) CustomFieldRepository {
+	return &customFieldRepository{db: db}
+}
+
+func (r *customFieldRepository) CreateField(ctx context.Context, field *domain.CustomField) error {
+	query := `
+		INSERT INTO custom_fields (field_id, key, label, description, type, options, rules, visibility, sort_order, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		field.ID, field.Key, field.Label, field.Description, field.Type, field.Options,
+		field.Rules, field.Visibility, field.SortOrder, field.CreatedBy,
+	).Scan(&field.CreatedAt, &field.UpdatedAt)
+}
+
+func (r *customFieldRepository) GetFieldByID(ctx context.Context, id uuid.UUID) (*domain.CustomField, error) {
+	return r.getField(ctx, `SELECT * FROM custom_fields WHERE field_id = $1 AND deleted_at IS NULL`, id)
+}
+
+func (r *customFieldRepository) GetFieldByKey(ctx context.Context, key string) (*domain.CustomField, error) {
+	return r.getField(ctx, `SELECT * FROM custom_fields WHERE key = $1 AND deleted_at IS NULL`, key)
+}
+
+func (r *customFieldRepository) getField(ctx context.Context, query string, arg any) (*domain.CustomField, error) {
+	var field domain.CustomField
+	err := conn(ctx, r.db).GetContext(ctx, &field, query, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *customFieldRepository) UpdateField(ctx context.Context, field *domain.CustomField) error {
+	query := `
+		UPDATE custom_fields
+		SET label = $2, description = $3, options = $4, rules = $5, visibility = $6,
+			sort_order = $7, updated_at = NOW()
+		WHERE field_id = $1 AND deleted_at IS NULL
+		RETURNING updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		field.ID, field.Label, field.Description, field.Options, field.Rules,
+		field.Visibility, field.SortOrder,
+	).Scan(&field.UpdatedAt)
+}
+
+// DeleteField soft-deletes the definition. Stored values are kept but no
+// longer shown, searched or exported.
+func (r *customFieldRepository) DeleteField(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE custom_fields SET deleted_at = NOW() WHERE field_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *customFieldRepository) ListFields(ctx context.Context) ([]domain.CustomField, error) {
+	fields := []domain.CustomField{}
+	query := `SELECT * FROM custom_fields WHERE deleted_at IS NULL ORDER BY sort_order, label`
+	err := conn(ctx, r.db).SelectContext(ctx, &fields, query)
+	return fields, err
+}
+
+func (r *customFieldRepository) SetValue(ctx context.Context, value *domain.PersonCustomValue) error {
+	query := `
+		INSERT INTO person_custom_values (person_id, field_id, value, value_number, value_date_min, value_date_max, value_person_id, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (person_id, field_id) DO UPDATE
+		SET value = EXCLUDED.value, value_number = EXCLUDED.value_number,
+			value_date_min = EXCLUDED.value_date_min, value_date_max = EXCLUDED.value_date_max,
+			value_person_id = EXCLUDED.value_person_id, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		value.PersonID, value.FieldID, value.Value, value.NumberValue,
+		value.DateMin, value.DateMax, value.PersonRef, value.UpdatedBy,
+	).Scan(&value.UpdatedAt)
+}
+
+func (r *customFieldRepository) DeleteValue(ctx context.Context, personID, fieldID uuid.UUID) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM person_custom_values WHERE person_id = $1 AND field_id = $2`, personID, fieldID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrCustomValueNotFound
+	}
+	return nil
+}
+
+// customValues selects values of live fields joined with their definition.
+// PERSON values display the referenced person's name while that person is
+// not deleted.
+const customValues = `
+	SELECT v.person_id, v.field_id, f.key, f.label, f.type, f.visibility, v.value,
+		COALESCE(CASE WHEN f.type = 'PERSON' THEN (
+			SELECT TRIM(rp.first_name || ' ' || COALESCE(rp.last_name, ''))
+			FROM persons rp WHERE rp.person_id = v.value_person_id AND rp.deleted_at IS NULL
+		) END, v.value) AS display,
+		v.value_number, v.value_date_min, v.value_date_max, v.value_person_id, v.updated_by, v.updated_at
+	FROM person_custom_values v
+	JOIN custom_fields f ON f.field_id = v.field_id AND f.deleted_at IS NULL`
+
+func (r *customFieldRepository) GetValuesByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonCustomValue, error) {
+	values := []domain.PersonCustomValue{}
+	query := customValues + ` WHERE v.person_id = $1 ORDER BY f.sort_order, f.label`
+	err := conn(ctx, r.db).SelectContext(ctx, &values, query, personID)
+	return values, err
+}
+
+func (r *customFieldRepository) GetAllValues(ctx context.Context) ([]domain.PersonCustomValue, error) {
+	values := []domain.PersonCustomValue{}
+	query := customValues + ` ORDER BY v.person_id, f.sort_order, f.label`
+	err := conn(ctx, r.db).SelectContext(ctx, &values, query)
+	return values, err
+}
@@ -15,9 +15,10 @@ type EventRepository interface {
 	Create(ctx context.Context, event *domain.Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error)
 	Update(ctx context.Context, event *domain.Event) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
 	ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Event, error)
 	ListByRelationship(ctx context.Context, relationshipID uuid.UUID) ([]domain.Event, error)
+	GetAll(ctx context.Context) ([]domain.Event, error)
 }
 
 type eventRepository struct {
@@ -29,13 +30,15 @@ func NewEventRepository(db *sqlx.DB) EventRepository {
 }
 
 func (r *eventRepository) Create(ctx context.Context, event *domain.Event) error {
+	event.SyncDateBounds()
+
 	query := `
-		INSERT INTO events (event_id, person_id, relationship_id, type, title, date, place, description, metadata, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO events (event_id, person_id, relationship_id, type, title, date, date_min, date_max, place, description, metadata, created_by, place_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING created_at, updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
-		event.ID, event.PersonID, event.RelationshipID, event.Type, event.Title, event.Date, event.Place, event.Description, event.Metadata, event.CreatedBy,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		event.ID, event.PersonID, event.RelationshipID, event.Type, event.Title, event.Date, event.DateMin, event.DateMax, event.Place, event.Description, event.Metadata, event.CreatedBy, event.PlaceID,
 	).Scan(&event.CreatedAt, &event.UpdatedAt)
 }
 
@@ -43,7 +46,7 @@ func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Ev
 	var event domain.Event
 	query := `SELECT * FROM events WHERE event_id = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &event, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &event, query, id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -54,33 +57,42 @@ func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Ev
 }
 
 func (r *eventRepository) Update(ctx context.Context, event *domain.Event) error {
+	event.SyncDateBounds()
+
 	query := `
 		UPDATE events 
-		SET type = $2, title = $3, date = $4, place = $5, description = $6, metadata = $7, updated_at = NOW()
+		SET type = $2, title = $3, date = $4, date_min = $5, date_max = $6, place = $7, description = $8, metadata = $9, place_id = $10, updated_at = NOW()
 		WHERE event_id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
-		event.ID, event.Type, event.Title, event.Date, event.Place, event.Description, event.Metadata,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		event.ID, event.Type, event.Title, event.Date, event.DateMin, event.DateMax, event.Place, event.Description, event.Metadata, event.PlaceID,
 	).Scan(&event.UpdatedAt)
 }
 
-func (r *eventRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE events SET deleted_at = NOW() WHERE event_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *eventRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	query := `UPDATE events SET deleted_at = NOW(), deleted_by = $2 WHERE event_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, deletedBy)
 	return err
 }
 
 func (r *eventRepository) ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Event, error) {
-	query := `SELECT * FROM events WHERE person_id = $1 AND deleted_at IS NULL ORDER BY date ASC`
+	query := `SELECT * FROM events WHERE person_id = $1 AND deleted_at IS NULL ORDER BY COALESCE(date_min, date_max) ASC`
 	var events []domain.Event
-	err := r.db.SelectContext(ctx, &events, query, personID)
+	err := conn(ctx, r.db).SelectContext(ctx, &events, query, personID)
 	return events, err
 }
 
 func (r *eventRepository) ListByRelationship(ctx context.Context, relationshipID uuid.UUID) ([]domain.Event, error) {
-	query := `SELECT * FROM events WHERE relationship_id = $1 AND deleted_at IS NULL ORDER BY date ASC`
+	query := `SELECT * FROM events WHERE relationship_id = $1 AND deleted_at IS NULL ORDER BY COALESCE(date_min, date_max) ASC`
+	var events []domain.Event
+	err := conn(ctx, r.db).SelectContext(ctx, &events, query, relationshipID)
+	return events, err
+}
+
+func (r *eventRepository) GetAll(ctx context.Context) ([]domain.Event, error) {
+	query := `SELECT * FROM events WHERE deleted_at IS NULL ORDER BY COALESCE(date_min, date_max) ASC`
 	var events []domain.Event
-	err := r.db.SelectContext(ctx, &events, query, relationshipID)
+	err := conn(ctx, r.db).SelectContext(ctx, &events, query)
 	return events, err
 }
@@ -13,8 +13,9 @@ type MediaRepository interface {
 	Create(ctx context.Context, media *domain.Media) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Media, error)
 	Update(ctx context.Context, media *domain.Media) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
 	List(ctx context.Context, personID *uuid.UUID, params domain.PaginationParams) ([]domain.Media, int64, error)
+	ListDatedByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Media, error)
 }
 
 type mediaRepository struct {
@@ -31,7 +32,7 @@ func (r *mediaRepository) Create(ctx context.Context, media *domain.Media) error
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING created_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		media.ID, media.PersonID, media.UploadedBy,
 		media.FileName, media.FileSize, media.MimeType, media.StoragePath,
 		media.Caption, media.Status, media.TakenAt,
@@ -41,22 +42,22 @@ func (r *mediaRepository) Create(ctx context.Context, media *domain.Media) error
 func (r *mediaRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Media, error) {
 	var media domain.Media
 	query := `SELECT * FROM media WHERE media_id = $1 AND deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &media, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &media, query, id)
 	return &media, err
 }
 
 func (r *mediaRepository) Update(ctx context.Context, media *domain.Media) error {
 	query := `
 		UPDATE media 
-		SET status = $1, caption = $2, person_id = $3
-		WHERE media_id = $4 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, media.Status, media.Caption, media.PersonID, media.ID)
+		SET status = $1, caption = $2, person_id = $3, taken_at = $4
+		WHERE media_id = $5 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, media.Status, media.Caption, media.PersonID, media.TakenAt, media.ID)
 	return err
 }
 
-func (r *mediaRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE media SET deleted_at = NOW() WHERE media_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *mediaRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	query := `UPDATE media SET deleted_at = NOW(), deleted_by = $2 WHERE media_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, deletedBy)
 	return err
 }
 
@@ -68,7 +69,7 @@ func (r *mediaRepository) List(ctx context.Context, personID *uuid.UUID, params
 
 	if personID != nil {
 		countQuery := `SELECT COUNT(*) FROM media WHERE person_id = $1 AND status = 'active' AND deleted_at IS NULL`
-		if err := r.db.GetContext(ctx, &total, countQuery, *personID); err != nil {
+		if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery, *personID); err != nil {
 			return nil, 0, err
 		}
 
@@ -77,12 +78,12 @@ func (r *mediaRepository) List(ctx context.Context, personID *uuid.UUID, params
 			WHERE person_id = $1 AND status = 'active' AND deleted_at IS NULL
 			ORDER BY created_at DESC
 			LIMIT $2 OFFSET $3`
-		err := r.db.SelectContext(ctx, &mediaList, query, *personID, params.PageSize, params.Offset())
+		err := conn(ctx, r.db).SelectContext(ctx, &mediaList, query, *personID, params.PageSize, params.Offset())
 		return mediaList, total, err
 	}
 
 	countQuery := `SELECT COUNT(*) FROM media WHERE status = 'active' AND deleted_at IS NULL`
-	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery); err != nil {
 		return nil, 0, err
 	}
 
@@ -91,6 +92,17 @@ func (r *mediaRepository) List(ctx context.Context, personID *uuid.UUID, params
 		WHERE status = 'active' AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`
-	err := r.db.SelectContext(ctx, &mediaList, query, params.PageSize, params.Offset())
+	err := conn(ctx, r.db).SelectContext(ctx, &mediaList, query, params.PageSize, params.Offset())
 	return mediaList, total, err
 }
+
+func (r *mediaRepository) ListDatedByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Media, error) {
+	query := `
+		SELECT * FROM media 
+		WHERE person_id = $1 AND taken_at IS NOT NULL AND status = 'active' AND deleted_at IS NULL
+		ORDER BY taken_at ASC`
+
+	var mediaList []domain.Media
+	err := conn(ctx, r.db).SelectContext(ctx, &mediaList, query, personID)
+	return mediaList, err
+}
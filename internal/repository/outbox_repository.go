@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, msg *domain.OutboxMessage) error
+	// ClaimPending leases up to limit undelivered messages with fewer than
+	// maxAttempts attempts that are due and returns them. Rows leased by a concurrent
+	// dispatcher are skipped until their lease is older than lease, so a
+	// dispatcher that stopped mid-delivery does not keep them.
+	ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error)
+	// MarkDispatched records that a claimed message was delivered.
+	MarkDispatched(ctx context.Context, id uuid.UUID) error
+	// Release returns a claimed message whose delivery failed to the queue,
+	// to be claimed again once retryAfter has passed.
+	Release(ctx context.Context, id uuid.UUID, lastError string, retryAfter time.Duration) error
+}
+
+type outboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, msg *domain.OutboxMessage) error {
+	query := `
+		INSERT INTO notification_outbox (outbox_id, type, entity_id, actor_id, notification)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	var actor, notification any
+	if msg.ActorID != uuid.Nil {
+		actor = msg.ActorID
+	}
+	if len(msg.Notification) > 0 {
+		notification = msg.Notification
+	}
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		msg.ID, msg.Type, msg.EntityID, actor, notification,
+	).Scan(&msg.CreatedAt)
+}
+
+func (r *outboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	query := `
+		UPDATE notification_outbox SET claimed_at = NOW()
+		WHERE outbox_id IN (
+			SELECT outbox_id FROM notification_outbox
+			WHERE dispatched_at IS NULL AND attempts < $2
+			  AND (claimed_at IS NULL OR claimed_at < NOW() - $3 * INTERVAL '1 second')
+			  AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	var msgs []domain.OutboxMessage
+	err := conn(ctx, r.db).SelectContext(ctx, &msgs, query, limit, maxAttempts, lease.Seconds())
+	return msgs, err
+}
+
+func (r *outboxRepository) MarkDispatched(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE notification_outbox
+		SET dispatched_at = NOW(), claimed_at = NULL
+		WHERE outbox_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *outboxRepository) Release(ctx context.Context, id uuid.UUID, lastError string, retryAfter time.Duration) error {
+	query := `
+		UPDATE notification_outbox
+		SET claimed_at = NULL, attempts = attempts + 1, last_error = $2,
+			next_attempt_at = NOW() + $3 * INTERVAL '1 second'
+		WHERE outbox_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, lastError, retryAfter.Seconds())
+	return err
+}
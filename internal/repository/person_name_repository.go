@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type PersonNameRepository interface {
+	Create(ctx context.Context, name *domain.PersonName) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PersonName, error)
+	Update(ctx context.Context, name *domain.PersonName) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonName, error)
+	ListByPersons(ctx context.Context, personIDs []uuid.UUID) ([]domain.PersonName, error)
+	GetAll(ctx context.Context) ([]domain.PersonName, error)
+	BackfillNameKeys(ctx context.Context) (int64, error)
+}
+
+type personNameRepository struct {
+	db *sqlx.DB
+}
+
+func NewPersonNameRepository(db *sqlx.DB) PersonNameRepository {
+	return &personNameRepository{db: db}
+}
+
+func (r *personNameRepository) Create(ctx context.Context, name *domain.PersonName) error {
+	name.SyncNameKeys()
+
+	query := `
+		INSERT INTO person_names (name_id, person_id, type, name, valid_from, valid_to, created_by,
+			name_normalized, name_phonetic)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		name.ID, name.PersonID, name.Type, name.Name, name.ValidFrom, name.ValidTo, name.CreatedBy,
+		name.NameNormalized, name.NamePhonetic,
+	).Scan(&name.CreatedAt, &name.UpdatedAt)
+}
+
+func (r *personNameRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PersonName, error) {
+	var name domain.PersonName
+	query := `SELECT * FROM person_names WHERE name_id = $1 AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).GetContext(ctx, &name, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &name, nil
+}
+
+func (r *personNameRepository) Update(ctx context.Context, name *domain.PersonName) error {
+	name.SyncNameKeys()
+
+	query := `
+		UPDATE person_names
+		SET type = $2, name = $3, valid_from = $4, valid_to = $5,
+			name_normalized = $6, name_phonetic = $7, updated_at = NOW()
+		WHERE name_id = $1 AND deleted_at IS NULL
+		RETURNING updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		name.ID, name.Type, name.Name, name.ValidFrom, name.ValidTo,
+		name.NameNormalized, name.NamePhonetic,
+	).Scan(&name.UpdatedAt)
+}
+
+func (r *personNameRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE person_names SET deleted_at = NOW() WHERE name_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *personNameRepository) ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonName, error) {
+	query := `SELECT * FROM person_names WHERE person_id = $1 AND deleted_at IS NULL ORDER BY type, created_at`
+	var names []domain.PersonName
+	err := conn(ctx, r.db).SelectContext(ctx, &names, query, personID)
+	return names, err
+}
+
+func (r *personNameRepository) ListByPersons(ctx context.Context, personIDs []uuid.UUID) ([]domain.PersonName, error) {
+	if len(personIDs) == 0 {
+		return []domain.PersonName{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM person_names WHERE person_id IN (?) AND deleted_at IS NULL ORDER BY type, created_at`, personIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	var names []domain.PersonName
+	err = conn(ctx, r.db).SelectContext(ctx, &names, query, args...)
+	return names, err
+}
+
+func (r *personNameRepository) GetAll(ctx context.Context) ([]domain.PersonName, error) {
+	query := `SELECT * FROM person_names WHERE deleted_at IS NULL ORDER BY type, created_at`
+	var names []domain.PersonName
+	err := conn(ctx, r.db).SelectContext(ctx, &names, query)
+	return names, err
+}
+
+// BackfillNameKeys fills the search keys of names stored before they
+// existed.
+func (r *personNameRepository) BackfillNameKeys(ctx context.Context) (int64, error) {
+	var names []domain.PersonName
+	if err := conn(ctx, r.db).SelectContext(ctx, &names, `SELECT * FROM person_names WHERE name_normalized IS NULL`); err != nil {
+		return 0, err
+	}
+
+	for i := range names {
+		names[i].SyncNameKeys()
+		_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE person_names SET name_normalized = $2, name_phonetic = $3 WHERE name_id = $1`,
+			names[i].ID, names[i].NameNormalized, names[i].NamePhonetic)
+		if err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(names)), nil
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/namenorm"
 )
 
 type PersonRepository interface {
@@ -17,13 +22,19 @@ type PersonRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Person, error)
 	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Person, error)
 	Update(ctx context.Context, person *domain.Person) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) (uuid.UUID, error)
 	List(ctx context.Context, params domain.PaginationParams) ([]domain.Person, int64, error)
-	Search(ctx context.Context, query string, limit int) ([]domain.Person, error)
+	Search(ctx context.Context, input domain.PersonSearchInput) ([]domain.Person, int64, error)
+	SearchFacets(ctx context.Context, input domain.PersonSearchInput) (*domain.PersonSearchFacets, error)
 	GetAll(ctx context.Context) ([]domain.Person, error)
+	FindSimilar(ctx context.Context, name string, excludeID uuid.UUID, limit int) ([]domain.SimilarPerson, error)
+	FindSimilarPairs(ctx context.Context, limit int) ([]domain.SimilarPair, error)
+	Merge(ctx context.Context, survivor *domain.Person, mergedID, mergedBy uuid.UUID) (*domain.MergeResult, error)
+	BackfillNameKeys(ctx context.Context) (int64, error)
 	CountAll(ctx context.Context) (int64, error)
 	CountLiving(ctx context.Context) (int64, error)
 	CountOrphans(ctx context.Context) (int64, error)
+	CountLinkedUsers(ctx context.Context, personIDs []uuid.UUID) (int64, error)
 	GetLastActivityAt(ctx context.Context) (*time.Time, error)
 }
 
@@ -36,21 +47,28 @@ func NewPersonRepository(db *sqlx.DB) PersonRepository {
 }
 
 func (r *personRepository) Create(ctx context.Context, person *domain.Person) error {
+	person.SyncDateBounds()
+	person.SyncNameKeys()
+
 	query := `
 		INSERT INTO persons (person_id, first_name, last_name, nickname, gender, 
 			birth_date, birth_place, death_date, death_place, bio, avatar_url, 
 			occupation, religion, nationality, education, phone, email, address,
-			is_alive, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			is_alive, created_by, birth_date_min, birth_date_max, death_date_min, death_date_max,
+			name_normalized, name_phonetic, birth_place_id, death_place_id, privacy_level)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)
 		RETURNING created_at, updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		person.ID, person.FirstName, person.LastName, person.Nickname,
 		person.Gender, person.BirthDate, person.BirthPlace, person.DeathDate,
 		person.DeathPlace, person.Bio, person.AvatarURL,
 		person.Occupation, person.Religion, person.Nationality, person.Education,
 		person.Phone, person.Email, person.Address,
 		person.IsAlive, person.CreatedBy,
+		person.BirthDateMin, person.BirthDateMax, person.DeathDateMin, person.DeathDateMax,
+		person.NameNormalized, person.NamePhonetic, person.BirthPlaceID, person.DeathPlaceID,
+		person.PrivacyLevel,
 	).Scan(&person.CreatedAt, &person.UpdatedAt)
 }
 
@@ -58,7 +76,7 @@ func (r *personRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.P
 	var person domain.Person
 	query := `SELECT * FROM persons WHERE person_id = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &person, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &person, query, id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -80,34 +98,89 @@ func (r *personRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]dom
 
 	query = r.db.Rebind(query)
 	var persons []domain.Person
-	err = r.db.SelectContext(ctx, &persons, query, args...)
+	err = conn(ctx, r.db).SelectContext(ctx, &persons, query, args...)
 	return persons, err
 }
 
 func (r *personRepository) Update(ctx context.Context, person *domain.Person) error {
+	return updatePerson(ctx, conn(ctx, r.db), person)
+}
+
+func updatePerson(ctx context.Context, q sqlx.QueryerContext, person *domain.Person) error {
+	person.SyncDateBounds()
+	person.SyncNameKeys()
+
 	query := `
 		UPDATE persons 
 		SET first_name = $2, last_name = $3, nickname = $4, gender = $5,
 			birth_date = $6, birth_place = $7, death_date = $8, death_place = $9,
 			bio = $10, avatar_url = $11, occupation = $12, religion = $13,
 			nationality = $14, education = $15, phone = $16, email = $17,
-			address = $18, is_alive = $19, updated_at = NOW()
+			address = $18, is_alive = $19, birth_date_min = $20, birth_date_max = $21,
+			death_date_min = $22, death_date_max = $23, name_normalized = $24,
+			name_phonetic = $25, birth_place_id = $26, death_place_id = $27, privacy_level = $28,
+			updated_at = NOW()
 		WHERE person_id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return q.QueryRowxContext(ctx, query,
 		person.ID, person.FirstName, person.LastName, person.Nickname, person.Gender,
 		person.BirthDate, person.BirthPlace, person.DeathDate, person.DeathPlace,
 		person.Bio, person.AvatarURL, person.Occupation, person.Religion,
 		person.Nationality, person.Education, person.Phone, person.Email,
 		person.Address, person.IsAlive,
+		person.BirthDateMin, person.BirthDateMax, person.DeathDateMin, person.DeathDateMax,
+		person.NameNormalized, person.NamePhonetic, person.BirthPlaceID, person.DeathPlaceID,
+		person.PrivacyLevel,
 	).Scan(&person.UpdatedAt)
 }
 
-func (r *personRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE persons SET deleted_at = NOW() WHERE person_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+// Delete soft-deletes the person together with their relationships, the
+// events of the person and of those relationships, their comments and their
+// media. Every row gets the same deletion_id so the trash can restore
+// exactly this deletion; it is returned so the caller can record it.
+func (r *personRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) (uuid.UUID, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	deletionID := uuid.New()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE persons SET deleted_at = NOW(), deleted_by = $2, deletion_id = $3
+		WHERE person_id = $1 AND deleted_at IS NULL`, id, deletedBy, deletionID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if n == 0 {
+		return uuid.Nil, domain.ErrPersonNotFound
+	}
+
+	for _, query := range []string{
+		`UPDATE relationships SET deleted_at = NOW(), deleted_by = $2, deletion_id = $3
+			WHERE (person_a = $1 OR person_b = $1) AND deleted_at IS NULL`,
+		`UPDATE events SET deleted_at = NOW(), deleted_by = $2, deletion_id = $3
+			WHERE deleted_at IS NULL AND (person_id = $1
+				OR relationship_id IN (SELECT relationship_id FROM relationships WHERE deletion_id = $3))`,
+		`UPDATE comments SET deleted_at = NOW(), deleted_by = $2, deletion_id = $3
+			WHERE person_id = $1 AND deleted_at IS NULL`,
+		`UPDATE media SET deleted_at = NOW(), deleted_by = $2, deletion_id = $3
+			WHERE person_id = $1 AND deleted_at IS NULL`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, id, deletedBy, deletionID); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return deletionID, nil
 }
 
 func (r *personRepository) List(ctx context.Context, params domain.PaginationParams) ([]domain.Person, int64, error) {
@@ -115,7 +188,7 @@ func (r *personRepository) List(ctx context.Context, params domain.PaginationPar
 
 	var total int64
 	countQuery := `SELECT COUNT(*) FROM persons WHERE deleted_at IS NULL`
-	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery); err != nil {
 		return nil, 0, err
 	}
 
@@ -126,11 +199,245 @@ func (r *personRepository) List(ctx context.Context, params domain.PaginationPar
 		LIMIT $1 OFFSET $2`
 
 	var persons []domain.Person
-	err := r.db.SelectContext(ctx, &persons, query, params.PageSize, params.Offset())
+	err := conn(ctx, r.db).SelectContext(ctx, &persons, query, params.PageSize, params.Offset())
+	return persons, total, err
+}
+
+// personSearchWhere builds the WHERE clause shared by Search and
+// SearchFacets for persons aliased as p.
+func personSearchWhere(input domain.PersonSearchInput) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"p.deleted_at IS NULL"}
+
+	// Filters on facts that Person.Redact strips skip the living persons
+	// hidden from the viewer; name filters skip only PRIVATE ones.
+	notHidden := func(levels []domain.PrivacyLevel) string {
+		if len(levels) == 0 {
+			return "TRUE"
+		}
+		var names []string
+		for _, l := range levels {
+			names = append(names, string(l))
+		}
+		cond := `NOT (p.privacy_level::text = ANY(` + arg(pq.Array(names)) + `)
+			AND p.is_alive AND p.death_date_min IS NULL AND p.death_date_max IS NULL
+			AND (COALESCE(p.birth_date_min, p.birth_date_max) IS NULL
+				OR EXTRACT(YEAR FROM COALESCE(p.birth_date_min, p.birth_date_max)) > EXTRACT(YEAR FROM CURRENT_DATE) - ` + strconv.Itoa(domain.LivingMaxAge) + `)`
+		if input.Viewer.PersonID != nil {
+			cond += ` AND p.person_id <> ` + arg(*input.Viewer.PersonID)
+		}
+		return cond + ")"
+	}
+	hiddenLevels := input.Viewer.HiddenLevels()
+	var privateHidden []domain.PrivacyLevel
+	for _, l := range hiddenLevels {
+		if l == domain.PrivacyPrivate {
+			privateHidden = append(privateHidden, l)
+		}
+	}
+	if input.BirthPlace != "" || len(input.CustomFields) > 0 {
+		conds = append(conds, notHidden(hiddenLevels))
+	}
+	if input.Query != "" || input.Occupation != "" || input.Religion != "" ||
+		input.BirthYearFrom != nil || input.BirthYearTo != nil {
+		conds = append(conds, notHidden(privateHidden))
+	}
+
+	if input.Query != "" {
+		q := arg(input.Query)
+		nq := arg(namenorm.Normalize(input.Query))
+		pq := arg(namenorm.Phonetic(input.Query))
+		conds = append(conds, `(
+			(p.first_name || ' ' || COALESCE(p.last_name, '')) ILIKE '%' || `+q+` || '%'
+			OR p.nickname ILIKE '%' || `+q+` || '%'
+			OR p.name_normalized LIKE '%' || `+nq+` || '%'
+			OR p.name_phonetic LIKE '%' || `+pq+` || '%'
+			OR EXISTS (
+				SELECT 1 FROM person_names n
+				WHERE n.person_id = p.person_id AND n.deleted_at IS NULL
+					AND (
+						n.name ILIKE '%' || `+q+` || '%'
+						OR n.name_normalized LIKE '%' || `+nq+` || '%'
+						OR n.name_phonetic LIKE '%' || `+pq+` || '%'
+					)
+			)
+		)`)
+	}
+	if input.Gender != nil {
+		conds = append(conds, "p.gender = "+arg(*input.Gender))
+	}
+	if input.IsAlive != nil {
+		conds = append(conds, "p.is_alive = "+arg(*input.IsAlive))
+	}
+	if input.BirthYearFrom != nil {
+		conds = append(conds, "p.birth_date_max >= make_date("+arg(*input.BirthYearFrom)+", 1, 1)")
+	}
+	if input.BirthYearTo != nil {
+		conds = append(conds, "p.birth_date_min <= make_date("+arg(*input.BirthYearTo)+", 12, 31)")
+	}
+	if input.DeathYearFrom != nil {
+		conds = append(conds, "p.death_date_max >= make_date("+arg(*input.DeathYearFrom)+", 1, 1)")
+	}
+	if input.DeathYearTo != nil {
+		conds = append(conds, "p.death_date_min <= make_date("+arg(*input.DeathYearTo)+", 12, 31)")
+	}
+	if input.BirthPlace != "" {
+		// Match the free text, or any place (current or historical name)
+		// at or below a matching place, so "Jawa Timur" finds Surabaya.
+		pattern := arg("%" + input.BirthPlace + "%")
+		conds = append(conds, "(p.birth_place ILIKE "+pattern+` OR p.birth_place_id IN (
+			WITH RECURSIVE matched AS (
+				SELECT pl.place_id FROM places pl
+				WHERE pl.deleted_at IS NULL AND (pl.name ILIKE `+pattern+` OR EXISTS (
+					SELECT 1 FROM jsonb_array_elements(pl.historical_names) h WHERE h->>'name' ILIKE `+pattern+`))
+				UNION
+				SELECT c.place_id FROM places c JOIN matched m ON c.parent_id = m.place_id
+				WHERE c.deleted_at IS NULL
+			)
+			SELECT place_id FROM matched))`)
+	}
+	if input.Occupation != "" {
+		conds = append(conds, "p.occupation ILIKE '%' || "+arg(input.Occupation)+" || '%'")
+	}
+	if input.Religion != "" {
+		conds = append(conds, "lower(p.religion) = lower("+arg(input.Religion)+")")
+	}
+	if input.CreatedBy != nil {
+		conds = append(conds, "p.created_by = "+arg(*input.CreatedBy))
+	}
+	if input.NoParents {
+		conds = append(conds, `NOT EXISTS (
+			SELECT 1 FROM relationships r
+			WHERE r.type = 'PARENT' AND r.person_a = p.person_id AND r.deleted_at IS NULL
+		)`)
+	}
+	if input.NoChildren {
+		conds = append(conds, `NOT EXISTS (
+			SELECT 1 FROM relationships r
+			WHERE r.type = 'PARENT' AND r.person_b = p.person_id AND r.deleted_at IS NULL
+		)`)
+	}
+	if len(input.CustomFields) > 0 {
+		var visible []string
+		for _, role := range []domain.UserRole{domain.RoleMember, domain.RoleEditor, domain.RoleDeveloper} {
+			if input.Viewer.Role.AtLeast(role) {
+				visible = append(visible, string(role))
+			}
+		}
+		vis := arg(pq.Array(visible))
+		for _, f := range input.CustomFields {
+			// Number and year are only bound when the filter parses as
+			// one; a NULL argument makes that branch match nothing.
+			var number, year any
+			if n, err := strconv.ParseFloat(f.Value, 64); err == nil {
+				number = n
+			}
+			if y, err := strconv.Atoi(f.Value); err == nil {
+				year = y
+			}
+			q, num, yr := arg(f.Value), arg(number), arg(year)
+			conds = append(conds, `EXISTS (
+				SELECT 1 FROM person_custom_values v
+				JOIN custom_fields f ON f.field_id = v.field_id AND f.deleted_at IS NULL
+				WHERE v.person_id = p.person_id AND f.key = `+arg(f.Key)+` AND f.visibility = ANY(`+vis+`)
+					AND (
+						(f.type = 'TEXT' AND v.value ILIKE '%' || `+q+` || '%')
+						OR (f.type IN ('ENUM', 'PERSON') AND lower(v.value) = lower(`+q+`))
+						OR (f.type = 'NUMBER' AND v.value_number = `+num+`::numeric)
+						OR (f.type = 'DATE' AND v.value_date_max >= make_date(`+yr+`::int, 1, 1)
+							AND v.value_date_min <= make_date(`+yr+`::int, 12, 31))
+					)
+			)`)
+		}
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+var personSortColumns = map[domain.PersonSortField]string{
+	domain.SortByName:      "p.first_name %[1]s, p.last_name %[1]s",
+	domain.SortByBirthDate: "COALESCE(p.birth_date_min, p.birth_date_max) %[1]s NULLS LAST, p.first_name",
+	domain.SortByDeathDate: "COALESCE(p.death_date_min, p.death_date_max) %[1]s NULLS LAST, p.first_name",
+	domain.SortByCreatedAt: "p.created_at %[1]s",
+	domain.SortByUpdatedAt: "p.updated_at %[1]s",
+}
+
+func (r *personRepository) Search(ctx context.Context, input domain.PersonSearchInput) ([]domain.Person, int64, error) {
+	if err := input.Validate(); err != nil {
+		return nil, 0, err
+	}
+	where, args := personSearchWhere(input)
+
+	var total int64
+	if err := conn(ctx, r.db).GetContext(ctx, &total, `SELECT COUNT(*) FROM persons p WHERE `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if input.Descending {
+		direction = "DESC"
+	}
+	orderBy := fmt.Sprintf(personSortColumns[input.Sort], direction)
+
+	query := fmt.Sprintf(`
+		SELECT p.* FROM persons p
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, where, orderBy, len(args)+1, len(args)+2)
+
+	persons := []domain.Person{}
+	err := conn(ctx, r.db).SelectContext(ctx, &persons, query, append(args, input.Limit, input.Offset)...)
 	return persons, total, err
 }
 
-func (r *personRepository) Search(ctx context.Context, query string, limit int) ([]domain.Person, error) {
+// SearchFacets counts the persons matching input by gender, living status,
+// religion and birth decade.
+func (r *personRepository) SearchFacets(ctx context.Context, input domain.PersonSearchInput) (*domain.PersonSearchFacets, error) {
+	where, args := personSearchWhere(input)
+
+	facets := &domain.PersonSearchFacets{}
+	for _, f := range []struct {
+		expr string
+		dest *[]domain.FacetCount
+	}{
+		{"p.gender::text", &facets.Gender},
+		{"CASE WHEN p.is_alive THEN 'LIVING' ELSE 'DECEASED' END", &facets.Living},
+		{"COALESCE(p.religion, '')", &facets.Religion},
+		{"COALESCE((EXTRACT(YEAR FROM COALESCE(p.birth_date_min, p.birth_date_max))::int / 10 * 10)::text, '')", &facets.BirthDecade},
+	} {
+		query := fmt.Sprintf(`
+			SELECT %s AS value, COUNT(*) AS count
+			FROM persons p
+			WHERE %s
+			GROUP BY 1
+			ORDER BY 2 DESC, 1`, f.expr, where)
+
+		*f.dest = []domain.FacetCount{}
+		if err := conn(ctx, r.db).SelectContext(ctx, f.dest, query, args...); err != nil {
+			return nil, err
+		}
+	}
+	return facets, nil
+}
+
+func (r *personRepository) GetAll(ctx context.Context) ([]domain.Person, error) {
+	query := `SELECT * FROM persons WHERE deleted_at IS NULL`
+
+	var persons []domain.Person
+	err := conn(ctx, r.db).SelectContext(ctx, &persons, query)
+	return persons, err
+}
+
+// FindSimilar returns persons whose full name or any alternate name is
+// similar to name, best match first. Names are compared in normalized
+// spelling by trigram similarity (pg_trgm threshold, 0.3 by default, so the
+// index can be used); an equal phonetic key counts as a match too.
+func (r *personRepository) FindSimilar(ctx context.Context, name string, excludeID uuid.UUID, limit int) ([]domain.SimilarPerson, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -138,40 +445,209 @@ func (r *personRepository) Search(ctx context.Context, query string, limit int)
 		limit = 50
 	}
 
-	sqlQuery := `
-		SELECT * FROM persons 
-		WHERE deleted_at IS NULL
+	query := `
+		SELECT p.*, GREATEST(
+			similarity(p.name_normalized, $1),
+			CASE WHEN p.name_phonetic = $2 THEN 0.9 ELSE 0 END,
+			COALESCE((
+				SELECT MAX(GREATEST(
+					similarity(n.name_normalized, $1),
+					CASE WHEN n.name_phonetic = $2 THEN 0.9 ELSE 0 END
+				)) FROM person_names n
+				WHERE n.person_id = p.person_id AND n.deleted_at IS NULL
+			), 0)
+		) AS similarity
+		FROM persons p
+		WHERE p.deleted_at IS NULL AND p.person_id <> $3
 			AND (
-				(first_name || ' ' || COALESCE(last_name, '')) ILIKE '%' || $1 || '%'
-				OR nickname ILIKE '%' || $1 || '%'
+				p.name_normalized % $1
+				OR p.name_phonetic = $2
+				OR EXISTS (
+					SELECT 1 FROM person_names n
+					WHERE n.person_id = p.person_id AND n.deleted_at IS NULL
+						AND (n.name_normalized % $1 OR n.name_phonetic = $2)
+				)
 			)
-		ORDER BY first_name, last_name
-		LIMIT $2`
+		ORDER BY similarity DESC
+		LIMIT $4`
 
-	var persons []domain.Person
-	err := r.db.SelectContext(ctx, &persons, sqlQuery, query, limit)
+	var persons []domain.SimilarPerson
+	err := conn(ctx, r.db).SelectContext(ctx, &persons, query,
+		namenorm.Normalize(name), namenorm.Phonetic(name), excludeID, limit)
 	return persons, err
 }
 
-func (r *personRepository) GetAll(ctx context.Context) ([]domain.Person, error) {
-	query := `SELECT * FROM persons WHERE deleted_at IS NULL`
+// FindSimilarPairs returns pairs of persons with similar full names across
+// the whole tree, most similar first, comparing normalized spellings and
+// phonetic keys like FindSimilar.
+func (r *personRepository) FindSimilarPairs(ctx context.Context, limit int) ([]domain.SimilarPair, error) {
+	if limit <= 0 {
+		limit = 100
+	}
 
+	query := `
+		SELECT a.person_id AS person_a, b.person_id AS person_b,
+			GREATEST(
+				similarity(a.name_normalized, b.name_normalized),
+				CASE WHEN a.name_phonetic = b.name_phonetic THEN 0.9 ELSE 0 END
+			) AS similarity
+		FROM persons a
+		JOIN persons b ON a.person_id < b.person_id
+			AND (a.name_normalized % b.name_normalized OR a.name_phonetic = b.name_phonetic)
+		WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+		ORDER BY similarity DESC
+		LIMIT $1`
+
+	var pairs []domain.SimilarPair
+	err := conn(ctx, r.db).SelectContext(ctx, &pairs, query, limit)
+	return pairs, err
+}
+
+// BackfillNameKeys fills the search keys of persons stored before they
+// existed.
+func (r *personRepository) BackfillNameKeys(ctx context.Context) (int64, error) {
 	var persons []domain.Person
-	err := r.db.SelectContext(ctx, &persons, query)
-	return persons, err
+	if err := conn(ctx, r.db).SelectContext(ctx, &persons, `SELECT * FROM persons WHERE name_normalized IS NULL`); err != nil {
+		return 0, err
+	}
+
+	for i := range persons {
+		persons[i].SyncNameKeys()
+		_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE persons SET name_normalized = $2, name_phonetic = $3 WHERE person_id = $1`,
+			persons[i].ID, persons[i].NameNormalized, persons[i].NamePhonetic)
+		if err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(persons)), nil
+}
+
+// Merge folds mergedID into survivor in one transaction: survivor's fields
+// are saved, everything pointing at the merged person is re-pointed to the
+// survivor, relationships that would become self-relations or duplicates
+// are dropped, and the merged person is soft-deleted. The merged person and
+// the dropped relationships share one deletion_id, and merged_into marks the
+// person so the trash does not offer it for restore.
+func (r *personRepository) Merge(ctx context.Context, survivor *domain.Person, mergedID, mergedBy uuid.UUID) (*domain.MergeResult, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	linked, err := countLinkedUsers(ctx, tx, []uuid.UUID{survivor.ID, mergedID})
+	if err != nil {
+		return nil, err
+	}
+	if linked > 1 {
+		return nil, domain.ErrMergeLinkedUsers
+	}
+
+	if err := updatePerson(ctx, tx, survivor); err != nil {
+		return nil, err
+	}
+
+	var rels []domain.Relationship
+	err = tx.SelectContext(ctx, &rels, `
+		SELECT relationship_id, person_a, person_b, type, metadata, created_by, created_at, updated_at, deleted_at
+		FROM relationships
+		WHERE (person_a IN ($1, $2) OR person_b IN ($1, $2)) AND deleted_at IS NULL`, survivor.ID, mergedID)
+	if err != nil {
+		return nil, err
+	}
+
+	deletionID := uuid.New()
+	plan := domain.PlanRelationshipMerge(mergedID, survivor.ID, rels)
+	result := &domain.MergeResult{
+		Survivor:               survivor,
+		MergedID:               mergedID,
+		RepointedRelationships: len(plan.Repoint),
+		DroppedRelationships:   []uuid.UUID{},
+	}
+
+	for _, d := range plan.Drop {
+		if d.ReplacedBy != nil {
+			_, err = tx.ExecContext(ctx, `UPDATE events SET relationship_id = $2, updated_at = NOW() WHERE relationship_id = $1`, d.ID, *d.ReplacedBy)
+			if err != nil {
+				return nil, err
+			}
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE relationships SET deleted_at = NOW(), deleted_by = $2, deletion_id = $3
+			WHERE relationship_id = $1`, d.ID, mergedBy, deletionID)
+		if err != nil {
+			return nil, err
+		}
+		result.DroppedRelationships = append(result.DroppedRelationships, d.ID)
+	}
+
+	for _, query := range []string{
+		`UPDATE relationships SET person_a = $2, updated_at = NOW() WHERE person_a = $1 AND deleted_at IS NULL`,
+		`UPDATE relationships SET person_b = $2, updated_at = NOW() WHERE person_b = $1 AND deleted_at IS NULL`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, mergedID, survivor.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	moves := []struct {
+		query string
+		count *int64
+	}{
+		{`UPDATE events SET person_id = $2, updated_at = NOW() WHERE person_id = $1 AND deleted_at IS NULL`, &result.Events},
+		{`UPDATE media SET person_id = $2 WHERE person_id = $1 AND deleted_at IS NULL`, &result.Media},
+		{`UPDATE comments SET person_id = $2, updated_at = NOW() WHERE person_id = $1 AND deleted_at IS NULL`, &result.Comments},
+		{`UPDATE person_names SET person_id = $2, updated_at = NOW() WHERE person_id = $1 AND deleted_at IS NULL`, &result.Names},
+		{`UPDATE citations SET entity_id = $2, updated_at = NOW() WHERE entity_type = 'PERSON' AND entity_id = $1 AND deleted_at IS NULL`, &result.Citations},
+		{`UPDATE users SET linked_person_id = $2, updated_at = NOW() WHERE linked_person_id = $1`, &result.LinkedUsers},
+		// Custom values move unless the survivor already has the field;
+		// references to the merged person follow it.
+		{`UPDATE person_custom_values v SET person_id = $2, updated_at = NOW()
+			WHERE v.person_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM person_custom_values s WHERE s.person_id = $2 AND s.field_id = v.field_id)`, &result.CustomValues},
+		{`UPDATE person_custom_values SET value_person_id = $2, value = $2::uuid::text, updated_at = NOW()
+			WHERE value_person_id = $1 AND person_id <> $2`, new(int64)},
+	}
+	for _, m := range moves {
+		res, err := tx.ExecContext(ctx, m.query, mergedID, survivor.ID)
+		if err != nil {
+			return nil, err
+		}
+		if *m.count, err = res.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE persons SET deleted_at = NOW(), deleted_by = $3, deletion_id = $4, merged_into = $2
+		WHERE person_id = $1`, mergedID, survivor.ID, mergedBy, deletionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (r *personRepository) CountAll(ctx context.Context) (int64, error) {
 	var count int64
 	query := `SELECT COUNT(*) FROM persons WHERE deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &count, query)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query)
 	return count, err
 }
 
+// CountLiving counts persons marked alive that their own dates do not
+// contradict: no death date and born less than LivingMaxAge years ago.
 func (r *personRepository) CountLiving(ctx context.Context) (int64, error) {
 	var count int64
-	query := `SELECT COUNT(*) FROM persons WHERE is_alive = true AND deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &count, query)
+	query := `
+		SELECT COUNT(*) FROM persons
+		WHERE is_alive = true AND deleted_at IS NULL
+			AND death_date_min IS NULL AND death_date_max IS NULL
+			AND (birth_date_min IS NULL OR EXTRACT(YEAR FROM birth_date_min) > EXTRACT(YEAR FROM CURRENT_DATE) - $1)`
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, domain.LivingMaxAge)
 	return count, err
 }
 
@@ -182,13 +658,26 @@ func (r *personRepository) CountOrphans(ctx context.Context) (int64, error) {
 		FROM persons p
 		LEFT JOIN relationships r ON p.person_id = r.person_a OR p.person_id = r.person_b
 		WHERE p.deleted_at IS NULL AND r.relationship_id IS NULL`
-	err := r.db.GetContext(ctx, &count, query)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query)
+	return count, err
+}
+
+// CountLinkedUsers counts the active users linked to any of personIDs.
+func (r *personRepository) CountLinkedUsers(ctx context.Context, personIDs []uuid.UUID) (int64, error) {
+	return countLinkedUsers(ctx, conn(ctx, r.db), personIDs)
+}
+
+func countLinkedUsers(ctx context.Context, q queryer, personIDs []uuid.UUID) (int64, error) {
+	var count int64
+	err := q.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM users
+		WHERE linked_person_id = ANY($1) AND deleted_at IS NULL`, pq.Array(personIDs))
 	return count, err
 }
 
 func (r *personRepository) GetLastActivityAt(ctx context.Context) (*time.Time, error) {
 	var t *time.Time
 	query := `SELECT MAX(updated_at) FROM persons`
-	err := r.db.GetContext(ctx, &t, query)
+	err := conn(ctx, r.db).GetContext(ctx, &t, query)
 	return t, err
 }
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/gazetteer"
+)
+
+type PlaceRepository interface {
+	Create(ctx context.Context, place *domain.Place) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Place, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Place, error)
+	Update(ctx context.Context, place *domain.Place) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, parentID *uuid.UUID, level *domain.PlaceLevel) ([]domain.Place, error)
+	Search(ctx context.Context, query string, limit int) ([]domain.Place, error)
+	GetAncestors(ctx context.Context, id uuid.UUID) ([]domain.Place, error)
+	CountReferences(ctx context.Context, id uuid.UUID) (int64, error)
+	ImportGazetteer(ctx context.Context, entries []gazetteer.Entry) (int64, error)
+}
+
+type placeRepository struct {
+	db *sqlx.DB
+}
+
+func NewPlaceRepository(db *sqlx.DB) PlaceRepository {
+	return &placeRepository{db: db}
+}
+
+func (r *placeRepository) Create(ctx context.Context, place *domain.Place) error {
+	query := `
+		INSERT INTO places (place_id, parent_id, level, name, code, latitude, longitude, historical_names, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		place.ID, place.ParentID, place.Level, place.Name, place.Code,
+		place.Latitude, place.Longitude, place.HistoricalNames, place.CreatedBy,
+	).Scan(&place.CreatedAt, &place.UpdatedAt)
+}
+
+func (r *placeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Place, error) {
+	var place domain.Place
+	query := `SELECT * FROM places WHERE place_id = $1 AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).GetContext(ctx, &place, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &place, nil
+}
+
+func (r *placeRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Place, error) {
+	if len(ids) == 0 {
+		return []domain.Place{}, nil
+	}
+
+	var places []domain.Place
+	query := `SELECT * FROM places WHERE place_id = ANY($1) AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &places, query, pq.Array(ids))
+	return places, err
+}
+
+func (r *placeRepository) Update(ctx context.Context, place *domain.Place) error {
+	query := `
+		UPDATE places
+		SET parent_id = $2, level = $3, name = $4, latitude = $5, longitude = $6,
+			historical_names = $7, updated_at = NOW()
+		WHERE place_id = $1 AND deleted_at IS NULL
+		RETURNING updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		place.ID, place.ParentID, place.Level, place.Name,
+		place.Latitude, place.Longitude, place.HistoricalNames,
+	).Scan(&place.UpdatedAt)
+}
+
+func (r *placeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE places SET deleted_at = NOW() WHERE place_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// List returns the children of parentID, or the top-level places when it is
+// nil. A level alone lists every place at that level.
+func (r *placeRepository) List(ctx context.Context, parentID *uuid.UUID, level *domain.PlaceLevel) ([]domain.Place, error) {
+	query := `SELECT * FROM places WHERE deleted_at IS NULL`
+	var args []any
+
+	switch {
+	case parentID != nil:
+		args = append(args, *parentID)
+		query += fmt.Sprintf(" AND parent_id = $%d", len(args))
+	case level == nil:
+		query += " AND parent_id IS NULL"
+	}
+	if level != nil {
+		args = append(args, *level)
+		query += fmt.Sprintf(" AND level = $%d", len(args))
+	}
+	query += " ORDER BY code NULLS LAST, name"
+
+	var places []domain.Place
+	err := conn(ctx, r.db).SelectContext(ctx, &places, query, args...)
+	return places, err
+}
+
+// Search matches current and historical names, so "Batavia" finds Jakarta.
+func (r *placeRepository) Search(ctx context.Context, query string, limit int) ([]domain.Place, error) {
+	var places []domain.Place
+	sqlQuery := `
+		SELECT * FROM places p
+		WHERE p.deleted_at IS NULL
+			AND (p.name ILIKE $1
+				OR EXISTS (
+					SELECT 1 FROM jsonb_array_elements(p.historical_names) h
+					WHERE h->>'name' ILIKE $1
+				))
+		ORDER BY similarity(p.name, $2) DESC, p.level, p.name
+		LIMIT $3`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &places, sqlQuery, "%"+query+"%", query, limit)
+	return places, err
+}
+
+// GetAncestors returns the place followed by its ancestors up to the
+// country.
+func (r *placeRepository) GetAncestors(ctx context.Context, id uuid.UUID) ([]domain.Place, error) {
+	var places []domain.Place
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT p.*, 0 AS depth FROM places p WHERE p.place_id = $1 AND p.deleted_at IS NULL
+			UNION ALL
+			SELECT p.*, c.depth + 1 FROM places p
+			JOIN chain c ON p.place_id = c.parent_id
+			WHERE p.deleted_at IS NULL AND c.depth < 10
+		)
+		SELECT place_id, parent_id, level, name, code, latitude, longitude, historical_names,
+			created_by, created_at, updated_at, deleted_at
+		FROM chain
+		ORDER BY depth`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &places, query, id)
+	return places, err
+}
+
+// CountReferences counts sub-places and the persons, events and marriages
+// that point at the place.
+func (r *placeRepository) CountReferences(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM places WHERE parent_id = $1 AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM persons WHERE (birth_place_id = $1 OR death_place_id = $1) AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM events WHERE place_id = $1 AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM relationships WHERE metadata->>'marriage_place_id' = $1::text AND deleted_at IS NULL)`
+
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, id)
+	return count, err
+}
+
+// ImportGazetteer inserts gazetteer entries that are not yet present,
+// matching on code, and returns how many were added. Existing rows are left
+// alone so local edits survive.
+func (r *placeRepository) ImportGazetteer(ctx context.Context, entries []gazetteer.Entry) (int64, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var inserted int64
+	for _, e := range entries {
+		var parentCode *string
+		if e.ParentCode != "" {
+			parentCode = &e.ParentCode
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO places (place_id, parent_id, level, name, code, latitude, longitude, historical_names)
+			VALUES ($1, (SELECT place_id FROM places WHERE code = $2), $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (code) DO NOTHING`,
+			uuid.New(), parentCode, e.Level, e.Name, e.Code, e.Latitude, e.Longitude, e.HistoricalNames,
+		)
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		inserted += n
+	}
+
+	return inserted, tx.Commit()
+}
@@ -15,8 +15,11 @@ import (
 type RelationshipRepository interface {
 	Create(ctx context.Context, rel *domain.Relationship) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Relationship, error)
+	// FindPair returns the live relationship of relType between personA and
+	// personB, in either order for spouses, or nil when there is none.
+	FindPair(ctx context.Context, personA, personB uuid.UUID, relType domain.RelationshipType) (*domain.Relationship, error)
 	Update(ctx context.Context, rel *domain.Relationship) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
 	List(ctx context.Context, relType *domain.RelationshipType) ([]domain.Relationship, error)
 	ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Relationship, error)
 	GetByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Relationship, error)
@@ -40,7 +43,7 @@ func (r *relationshipRepository) Create(ctx context.Context, rel *domain.Relatio
 		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING created_at, updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		rel.ID, rel.PersonA, rel.PersonB, rel.Type, rel.Metadata, rel.CreatedBy,
 	).Scan(&rel.CreatedAt, &rel.UpdatedAt)
 }
@@ -52,7 +55,26 @@ func (r *relationshipRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 		FROM relationships 
 		WHERE relationship_id = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &rel, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &rel, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+func (r *relationshipRepository) FindPair(ctx context.Context, personA, personB uuid.UUID, relType domain.RelationshipType) (*domain.Relationship, error) {
+	var rel domain.Relationship
+	query := `
+		SELECT relationship_id, person_a, person_b, type, metadata, created_by, created_at, updated_at, deleted_at
+		FROM relationships
+		WHERE type = $3 AND deleted_at IS NULL
+			AND ((person_a = $1 AND person_b = $2) OR (type = 'SPOUSE' AND person_a = $2 AND person_b = $1))
+		LIMIT 1`
+
+	err := conn(ctx, r.db).GetContext(ctx, &rel, query, personA, personB, relType)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -69,14 +91,14 @@ func (r *relationshipRepository) Update(ctx context.Context, rel *domain.Relatio
 		WHERE relationship_id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		rel.ID, rel.Metadata,
 	).Scan(&rel.UpdatedAt)
 }
 
-func (r *relationshipRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE relationships SET deleted_at = NOW() WHERE relationship_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *relationshipRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	query := `UPDATE relationships SET deleted_at = NOW(), deleted_by = $2 WHERE relationship_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, deletedBy)
 	return err
 }
 
@@ -91,9 +113,9 @@ func (r *relationshipRepository) List(ctx context.Context, relType *domain.Relat
 
 	if relType != nil {
 		query += ` AND type = $1`
-		err = r.db.SelectContext(ctx, &relationships, query, *relType)
+		err = conn(ctx, r.db).SelectContext(ctx, &relationships, query, *relType)
 	} else {
-		err = r.db.SelectContext(ctx, &relationships, query)
+		err = conn(ctx, r.db).SelectContext(ctx, &relationships, query)
 	}
 
 	return relationships, err
@@ -106,7 +128,7 @@ func (r *relationshipRepository) ListByPerson(ctx context.Context, personID uuid
 		WHERE (person_a = $1 OR person_b = $1) AND deleted_at IS NULL`
 
 	var relationships []domain.Relationship
-	err := r.db.SelectContext(ctx, &relationships, query, personID)
+	err := conn(ctx, r.db).SelectContext(ctx, &relationships, query, personID)
 	return relationships, err
 }
 
@@ -121,7 +143,7 @@ func (r *relationshipRepository) GetAll(ctx context.Context) ([]domain.Relations
 		WHERE deleted_at IS NULL`
 
 	var relationships []domain.Relationship
-	err := r.db.SelectContext(ctx, &relationships, query)
+	err := conn(ctx, r.db).SelectContext(ctx, &relationships, query)
 	return relationships, err
 }
 
@@ -141,20 +163,20 @@ func (r *relationshipRepository) ListByPeople(ctx context.Context, personIDs []u
 
 	query = r.db.Rebind(query)
 	var rels []domain.Relationship
-	err = r.db.SelectContext(ctx, &rels, query, args...)
+	err = conn(ctx, r.db).SelectContext(ctx, &rels, query, args...)
 	return rels, err
 }
 
 func (r *relationshipRepository) CountAll(ctx context.Context) (int64, error) {
 	var count int64
 	query := `SELECT COUNT(*) FROM relationships WHERE deleted_at IS NULL`
-	err := r.db.GetContext(ctx, &count, query)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query)
 	return count, err
 }
 
 func (r *relationshipRepository) GetLastActivityAt(ctx context.Context) (*time.Time, error) {
 	var t *time.Time
 	query := `SELECT MAX(updated_at) FROM relationships`
-	err := r.db.GetContext(ctx, &t, query)
+	err := conn(ctx, r.db).GetContext(ctx, &t, query)
 	return t, err
 }
@@ -5,27 +5,49 @@ import (
 )
 
 type Repositories struct {
-	User          UserRepository
-	Person        PersonRepository
-	Relationship  RelationshipRepository
-	ChangeRequest ChangeRequestRepository
-	Media         MediaRepository
-	Comment       CommentRepository
-	AuditLog      AuditLogRepository
-	Notification  NotificationRepository
-	Session       SessionRepository
+	User                 UserRepository
+	Person               PersonRepository
+	PersonName           PersonNameRepository
+	Relationship         RelationshipRepository
+	Event                EventRepository
+	ChangeRequest        ChangeRequestRepository
+	Media                MediaRepository
+	Comment              CommentRepository
+	AuditLog             AuditLogRepository
+	Notification         NotificationRepository
+	Session              SessionRepository
+	Place                PlaceRepository
+	Source               SourceRepository
+	Citation             CitationRepository
+	Trash                TrashRepository
+	CustomField          CustomFieldRepository
+	Outbox               OutboxRepository
+	Approval             ApprovalRepository
+	ChangeRequestComment ChangeRequestCommentRepository
+	Transactor           Transactor
 }
 
 func NewRepositories(db *sqlx.DB) *Repositories {
 	return &Repositories{
-		User:          NewUserRepository(db),
-		Person:        NewPersonRepository(db),
-		Relationship:  NewRelationshipRepository(db),
-		ChangeRequest: NewChangeRequestRepository(db),
-		Media:         NewMediaRepository(db),
-		Comment:       NewCommentRepository(db),
-		AuditLog:      NewAuditLogRepository(db),
-		Notification:  NewNotificationRepository(db),
-		Session:       NewSessionRepository(db),
+		User:                 NewUserRepository(db),
+		Person:               NewPersonRepository(db),
+		PersonName:           NewPersonNameRepository(db),
+		Relationship:         NewRelationshipRepository(db),
+		Event:                NewEventRepository(db),
+		ChangeRequest:        NewChangeRequestRepository(db),
+		Media:                NewMediaRepository(db),
+		Comment:              NewCommentRepository(db),
+		AuditLog:             NewAuditLogRepository(db),
+		Notification:         NewNotificationRepository(db),
+		Session:              NewSessionRepository(db),
+		Place:                NewPlaceRepository(db),
+		Source:               NewSourceRepository(db),
+		Citation:             NewCitationRepository(db),
+		Trash:                NewTrashRepository(db),
+		CustomField:          NewCustomFieldRepository(db),
+		Outbox:               NewOutboxRepository(db),
+		Approval:             NewApprovalRepository(db),
+		ChangeRequestComment: NewChangeRequestCommentRepository(db),
+		Transactor:           NewTransactor(db),
 	}
 }
@@ -45,7 +45,7 @@ func (r *sessionRepository) Create(ctx context.Context, session *Session) error
 		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING created_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		session.ID, session.UserID, session.TokenHash, session.UserAgent, session.IPAddress, session.ExpiresAt,
 	).Scan(&session.CreatedAt)
 }
@@ -54,7 +54,7 @@ func (r *sessionRepository) GetByTokenHash(ctx context.Context, tokenHash string
 	var session Session
 	query := `SELECT * FROM sessions WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()`
 
-	err := r.db.GetContext(ctx, &session, query, tokenHash)
+	err := conn(ctx, r.db).GetContext(ctx, &session, query, tokenHash)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -68,7 +68,7 @@ func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 	var sessions []*Session
 	query := `SELECT * FROM sessions WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW() ORDER BY created_at DESC`
 
-	err := r.db.SelectContext(ctx, &sessions, query, userID)
+	err := conn(ctx, r.db).SelectContext(ctx, &sessions, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -77,18 +77,18 @@ func (r *sessionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 func (r *sessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE sessions SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
 
 func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
 	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
 	return err
 }
 
 func (r *sessionRepository) DeleteExpired(ctx context.Context) error {
 	query := `DELETE FROM sessions WHERE expires_at < NOW() OR revoked_at IS NOT NULL`
-	_, err := r.db.ExecContext(ctx, query)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query)
 	return err
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type SourceRepository interface {
+	Create(ctx context.Context, source *domain.Source) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Source, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Source, error)
+	Update(ctx context.Context, source *domain.Source) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, params domain.SourceListParams) ([]domain.Source, int64, error)
+}
+
+type sourceRepository struct {
+	db *sqlx.DB
+}
+
+func NewSourceRepository(db *sqlx.DB) SourceRepository {
+	return &sourceRepository{db: db}
+}
+
+func (r *sourceRepository) Create(ctx context.Context, source *domain.Source) error {
+	query := `
+		INSERT INTO sources (source_id, type, title, author, publication, repository, url, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		source.ID, source.Type, source.Title, source.Author, source.Publication,
+		source.Repository, source.URL, source.Notes, source.CreatedBy,
+	).Scan(&source.CreatedAt, &source.UpdatedAt)
+}
+
+func (r *sourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Source, error) {
+	var source domain.Source
+	query := `SELECT * FROM sources WHERE source_id = $1 AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).GetContext(ctx, &source, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+func (r *sourceRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Source, error) {
+	if len(ids) == 0 {
+		return []domain.Source{}, nil
+	}
+
+	var sources []domain.Source
+	query := `SELECT * FROM sources WHERE source_id = ANY($1) AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &sources, query, pq.Array(ids))
+	return sources, err
+}
+
+func (r *sourceRepository) Update(ctx context.Context, source *domain.Source) error {
+	query := `
+		UPDATE sources
+		SET type = $2, title = $3, author = $4, publication = $5, repository = $6,
+			url = $7, notes = $8, updated_at = NOW()
+		WHERE source_id = $1 AND deleted_at IS NULL
+		RETURNING updated_at`
+
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		source.ID, source.Type, source.Title, source.Author, source.Publication,
+		source.Repository, source.URL, source.Notes,
+	).Scan(&source.UpdatedAt)
+}
+
+func (r *sourceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE sources SET deleted_at = NOW() WHERE source_id = $1 AND deleted_at IS NULL`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *sourceRepository) List(ctx context.Context, params domain.SourceListParams) ([]domain.Source, int64, error) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+	if params.Query != "" {
+		args = append(args, "%"+params.Query+"%")
+		conds = append(conds, fmt.Sprintf("(title ILIKE $%[1]d OR author ILIKE $%[1]d)", len(args)))
+	}
+	if params.Type != nil {
+		args = append(args, *params.Type)
+		conds = append(conds, fmt.Sprintf("type = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	if err := conn(ctx, r.db).GetContext(ctx, &total, "SELECT COUNT(*) FROM sources WHERE "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, params.PageSize, params.Offset())
+	query := fmt.Sprintf(`SELECT * FROM sources WHERE %s ORDER BY title LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	var sources []domain.Source
+	err := conn(ctx, r.db).SelectContext(ctx, &sources, query, args...)
+	return sources, total, err
+}
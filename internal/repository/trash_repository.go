@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+type TrashRepository interface {
+	List(ctx context.Context, params domain.TrashListParams) ([]domain.TrashItem, int64, error)
+	Restore(ctx context.Context, entityType domain.EntityType, id uuid.UUID) (*domain.RestoreResult, error)
+	Purge(ctx context.Context, before time.Time) (*domain.PurgeResult, error)
+}
+
+type trashRepository struct {
+	db *sqlx.DB
+}
+
+func NewTrashRepository(db *sqlx.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// trashItems selects every soft-deleted row as a trash item. Rows deleted
+// as part of a person's deletion are not listed on their own; the person
+// item counts them instead. Persons removed by a merge live on in the
+// survivor and are not listed.
+const trashItems = `
+	SELECT 'PERSON' AS entity_type, p.person_id AS entity_id,
+		TRIM(p.first_name || ' ' || COALESCE(p.last_name, '')) AS label,
+		NULL::uuid AS person_id, p.deleted_at, p.deleted_by,
+		(SELECT COUNT(*) FROM relationships x WHERE x.deletion_id = p.deletion_id)
+			+ (SELECT COUNT(*) FROM events x WHERE x.deletion_id = p.deletion_id)
+			+ (SELECT COUNT(*) FROM media x WHERE x.deletion_id = p.deletion_id)
+			+ (SELECT COUNT(*) FROM comments x WHERE x.deletion_id = p.deletion_id) AS cascaded
+	FROM persons p WHERE p.deleted_at IS NOT NULL AND p.merged_into IS NULL
+	UNION ALL
+	SELECT 'RELATIONSHIP', r.relationship_id, r.type::text || ': ' || pa.first_name || ' - ' || pb.first_name,
+		r.person_a, r.deleted_at, r.deleted_by, 0
+	FROM relationships r
+	JOIN persons pa ON pa.person_id = r.person_a
+	JOIN persons pb ON pb.person_id = r.person_b
+	WHERE r.deleted_at IS NOT NULL AND r.deletion_id IS NULL
+	UNION ALL
+	SELECT 'EVENT', e.event_id, e.title, e.person_id, e.deleted_at, e.deleted_by, 0
+	FROM events e WHERE e.deleted_at IS NOT NULL AND e.deletion_id IS NULL
+	UNION ALL
+	SELECT 'MEDIA', m.media_id, m.file_name, m.person_id, m.deleted_at, m.deleted_by, 0
+	FROM media m WHERE m.deleted_at IS NOT NULL AND m.deletion_id IS NULL
+	UNION ALL
+	SELECT 'COMMENT', c.comment_id, LEFT(c.content, 100), c.person_id, c.deleted_at, c.deleted_by, 0
+	FROM comments c WHERE c.deleted_at IS NOT NULL AND c.deletion_id IS NULL`
+
+func (r *trashRepository) List(ctx context.Context, params domain.TrashListParams) ([]domain.TrashItem, int64, error) {
+	var entityType *string
+	if params.EntityType != nil {
+		t := string(*params.EntityType)
+		entityType = &t
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM (` + trashItems + `) t WHERE ($1::text IS NULL OR t.entity_type = $1)`
+	if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery, entityType); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT t.*, u.full_name AS deleted_by_name
+		FROM (` + trashItems + `) t
+		LEFT JOIN users u ON u.user_id = t.deleted_by
+		WHERE ($1::text IS NULL OR t.entity_type = $1)
+		ORDER BY t.deleted_at DESC
+		LIMIT $2 OFFSET $3`
+
+	var items []domain.TrashItem
+	err := conn(ctx, r.db).SelectContext(ctx, &items, query, entityType, params.PageSize, params.Offset())
+	return items, total, err
+}
+
+// Restore clears the deletion of one item. Items that would point at rows
+// still in the trash are refused with ErrRestoreBlocked.
+func (r *trashRepository) Restore(ctx context.Context, entityType domain.EntityType, id uuid.UUID) (*domain.RestoreResult, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &domain.RestoreResult{EntityType: entityType, EntityID: id, Relationships: []uuid.UUID{}}
+
+	var blocker string
+	switch entityType {
+	case domain.EntityPerson:
+		var deleted struct {
+			DeletionID *uuid.UUID `db:"deletion_id"`
+			MergedInto *uuid.UUID `db:"merged_into"`
+		}
+		err = tx.GetContext(ctx, &deleted, `
+			SELECT deletion_id, merged_into FROM persons WHERE person_id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTrashItemNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if deleted.MergedInto != nil {
+			return nil, fmt.Errorf("%w: the person was merged into %s", domain.ErrRestoreBlocked, *deleted.MergedInto)
+		}
+		deletionID := deleted.DeletionID
+		err = restoreRow(ctx, tx, `UPDATE persons SET deleted_at = NULL, deleted_by = NULL, deletion_id = NULL WHERE person_id = $1`, id)
+		if err != nil {
+			return nil, err
+		}
+		if deletionID != nil {
+			if result.Cascaded, err = restoreDeletion(ctx, tx, *deletionID); err != nil {
+				return nil, err
+			}
+		}
+		// Report every relationship of the person that is visible again,
+		// including those whose other side was already live.
+		err = tx.SelectContext(ctx, &result.Relationships, `
+			SELECT r.relationship_id FROM relationships r
+			JOIN persons pa ON pa.person_id = r.person_a AND pa.deleted_at IS NULL
+			JOIN persons pb ON pb.person_id = r.person_b AND pb.deleted_at IS NULL
+			WHERE (r.person_a = $1 OR r.person_b = $1) AND r.deleted_at IS NULL`, id)
+		if err != nil {
+			return nil, err
+		}
+
+	case domain.EntityRelationship:
+		err = tx.GetContext(ctx, &blocker, `
+			SELECT CASE
+				WHEN EXISTS (
+					SELECT 1 FROM persons p
+					WHERE p.person_id IN (r.person_a, r.person_b) AND p.deleted_at IS NOT NULL
+				) THEN 'a related person is in the trash'
+				WHEN EXISTS (
+					SELECT 1 FROM relationships o
+					WHERE o.relationship_id <> r.relationship_id AND o.deleted_at IS NULL AND o.type = r.type
+						AND ((o.person_a = r.person_a AND o.person_b = r.person_b)
+							OR (r.type = 'SPOUSE' AND o.person_a = r.person_b AND o.person_b = r.person_a))
+				) THEN 'the relationship already exists again'
+				ELSE '' END
+			FROM relationships r WHERE r.relationship_id = $1 AND r.deleted_at IS NOT NULL`, id)
+		if err == nil && blocker == "" {
+			err = restoreRow(ctx, tx, `UPDATE relationships SET deleted_at = NULL, deleted_by = NULL, deletion_id = NULL WHERE relationship_id = $1`, id)
+		}
+
+	case domain.EntityEvent:
+		err = tx.GetContext(ctx, &blocker, `
+			SELECT CASE
+				WHEN EXISTS (SELECT 1 FROM persons p WHERE p.person_id = e.person_id AND p.deleted_at IS NOT NULL)
+					THEN 'the person is in the trash'
+				WHEN EXISTS (SELECT 1 FROM relationships r WHERE r.relationship_id = e.relationship_id AND r.deleted_at IS NOT NULL)
+					THEN 'the relationship is in the trash'
+				ELSE '' END
+			FROM events e WHERE e.event_id = $1 AND e.deleted_at IS NOT NULL`, id)
+		if err == nil && blocker == "" {
+			err = restoreRow(ctx, tx, `UPDATE events SET deleted_at = NULL, deleted_by = NULL, deletion_id = NULL WHERE event_id = $1`, id)
+		}
+
+	case domain.EntityMedia:
+		err = tx.GetContext(ctx, &blocker, `
+			SELECT CASE
+				WHEN EXISTS (SELECT 1 FROM persons p WHERE p.person_id = m.person_id AND p.deleted_at IS NOT NULL)
+					THEN 'the person is in the trash'
+				ELSE '' END
+			FROM media m WHERE m.media_id = $1 AND m.deleted_at IS NOT NULL`, id)
+		if err == nil && blocker == "" {
+			err = restoreRow(ctx, tx, `UPDATE media SET deleted_at = NULL, deleted_by = NULL, deletion_id = NULL WHERE media_id = $1`, id)
+		}
+
+	case domain.EntityComment:
+		err = tx.GetContext(ctx, &blocker, `
+			SELECT CASE
+				WHEN EXISTS (SELECT 1 FROM persons p WHERE p.person_id = c.person_id AND p.deleted_at IS NOT NULL)
+					THEN 'the person is in the trash'
+				ELSE '' END
+			FROM comments c WHERE c.comment_id = $1 AND c.deleted_at IS NOT NULL`, id)
+		if err == nil && blocker == "" {
+			err = restoreRow(ctx, tx, `UPDATE comments SET deleted_at = NULL, deleted_by = NULL, deletion_id = NULL WHERE comment_id = $1`, id)
+		}
+
+	default:
+		return nil, domain.ErrInvalidTrashEntity
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTrashItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if blocker != "" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrRestoreBlocked, blocker)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func restoreRow(ctx context.Context, tx queryer, query string, id uuid.UUID) error {
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// restoreDeletion restores the rows a person deletion cascaded to and
+// returns how many there were.
+func restoreDeletion(ctx context.Context, tx queryer, deletionID uuid.UUID) (int64, error) {
+	var total int64
+	for _, table := range []string{"relationships", "events", "media", "comments"} {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE `+table+` SET deleted_at = NULL, deleted_by = NULL, deletion_id = NULL WHERE deletion_id = $1`, deletionID)
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// Purge hard-deletes everything moved to the trash before the cutoff and
+// returns the storage paths of purged media so the caller can remove the
+// files. Citations left pointing at purged rows are removed as well.
+func (r *trashRepository) Purge(ctx context.Context, before time.Time) (*domain.PurgeResult, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &domain.PurgeResult{Before: before, StoragePaths: []string{}}
+
+	err = tx.SelectContext(ctx, &result.StoragePaths,
+		`DELETE FROM media WHERE deleted_at < $1 RETURNING storage_path`, before)
+	if err != nil {
+		return nil, err
+	}
+	result.Media = int64(len(result.StoragePaths))
+
+	steps := []struct {
+		query string
+		count *int64
+	}{
+		{`DELETE FROM comments WHERE deleted_at < $1`, &result.Comments},
+		{`DELETE FROM events WHERE deleted_at < $1`, &result.Events},
+		{`DELETE FROM relationships WHERE deleted_at < $1`, &result.Relationships},
+		{`DELETE FROM persons WHERE deleted_at < $1`, &result.Persons},
+	}
+	for _, step := range steps {
+		res, err := tx.ExecContext(ctx, step.query, before)
+		if err != nil {
+			return nil, err
+		}
+		if *step.count, err = res.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM citations c
+		WHERE (c.entity_type = 'PERSON' AND NOT EXISTS (SELECT 1 FROM persons WHERE person_id = c.entity_id))
+			OR (c.entity_type = 'RELATIONSHIP' AND NOT EXISTS (SELECT 1 FROM relationships WHERE relationship_id = c.entity_id))
+			OR (c.entity_type = 'EVENT' AND NOT EXISTS (SELECT 1 FROM events WHERE event_id = c.entity_id))`)
+	if err != nil {
+		return nil, err
+	}
+	if result.Citations, err = res.RowsAffected(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
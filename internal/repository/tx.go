@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Transactor runs several repository calls in one database transaction.
+type Transactor interface {
+	// WithinTx calls fn with a context carrying the transaction. Repository
+	// calls made with that context join it; fn's error rolls it back. A
+	// call nested in another WithinTx joins the outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *sqlx.DB
+}
+
+func NewTransactor(db *sqlx.DB) Transactor {
+	return &transactor{db: db}
+}
+
+type txKey struct{}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// InTx reports whether ctx carries a transaction from WithinTx, so work
+// that must wait for the commit can be left to whoever owns it.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	return ok
+}
+
+// queryer is what repositories query through: *sqlx.DB or *sqlx.Tx.
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// conn returns the transaction carried by ctx, or db outside WithinTx.
+func conn(ctx context.Context, db *sqlx.DB) queryer {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// txScope is the transaction a repository method runs its statements in:
+// its own, or the one carried by ctx, whose owner then commits or rolls it
+// back.
+type txScope struct {
+	*sqlx.Tx
+	owned bool
+}
+
+func beginTx(ctx context.Context, db *sqlx.DB) (*txScope, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return &txScope{Tx: tx}, nil
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txScope{Tx: tx, owned: true}, nil
+}
+
+func (t *txScope) Commit() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *txScope) Rollback() error {
+	if !t.owned {
+		return sql.ErrTxDone
+	}
+	return t.Tx.Rollback()
+}
@@ -16,6 +16,8 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	// GetByLinkedPerson returns the user linked to the person, or nil.
+	GetByLinkedPerson(ctx context.Context, personID uuid.UUID) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
@@ -45,7 +47,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING created_at, updated_at`
 
-	return r.db.QueryRowxContext(ctx, query,
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.FullName,
 		user.AvatarURL, user.Bio, user.Role, user.IsActive, user.IsEmailVerified,
 	).Scan(&user.CreatedAt, &user.UpdatedAt)
@@ -55,7 +57,21 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 	var user domain.User
 	query := `SELECT * FROM users WHERE user_id = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetByLinkedPerson(ctx context.Context, personID uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	query := `SELECT * FROM users WHERE linked_person_id = $1 AND deleted_at IS NULL`
+
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, personID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -69,7 +85,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	var user domain.User
 	query := `SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &user, query, email)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, email)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -87,20 +103,20 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 			linked_person_id = :linked_person_id, updated_at = NOW()
 		WHERE user_id = :user_id AND deleted_at IS NULL`
 
-	_, err := r.db.NamedExecContext(ctx, query, user)
+	_, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, user)
 	return err
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE users SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	return err
 }
 
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND deleted_at IS NULL)`
-	err := r.db.GetContext(ctx, &exists, query, email)
+	err := conn(ctx, r.db).GetContext(ctx, &exists, query, email)
 	return exists, err
 }
 
@@ -112,7 +128,7 @@ func (r *userRepository) AssignRole(ctx context.Context, userID uuid.UUID, role
 		RETURNING updated_at`
 
 	var updatedAt time.Time
-	err := r.db.QueryRowxContext(ctx, query, userID, role).Scan(&updatedAt)
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, userID, role).Scan(&updatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("user not found")
 	}
@@ -123,7 +139,7 @@ func (r *userRepository) ListByRole(ctx context.Context, role string) ([]domain.
 	var users []domain.User
 	query := `SELECT * FROM users WHERE role = $1 AND deleted_at IS NULL ORDER BY created_at DESC`
 
-	err := r.db.SelectContext(ctx, &users, query, role)
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, role)
 	return users, err
 }
 
@@ -140,7 +156,7 @@ func (r *userRepository) GetByRoles(ctx context.Context, roles []domain.UserRole
 		roleStrings[i] = string(role)
 	}
 
-	err := r.db.SelectContext(ctx, &users, query, roleStrings)
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, roleStrings)
 	return users, err
 }
 
@@ -148,7 +164,7 @@ func (r *userRepository) GetAllUsers(ctx context.Context) ([]domain.User, error)
 	var users []domain.User
 	query := `SELECT * FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC`
 
-	err := r.db.SelectContext(ctx, &users, query)
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query)
 	return users, err
 }
 
@@ -160,7 +176,7 @@ func (r *userRepository) SetPasswordResetToken(ctx context.Context, userID uuid.
 		RETURNING updated_at`
 
 	var updatedAt time.Time
-	err := r.db.QueryRowxContext(ctx, query, userID, token, expiresAt).Scan(&updatedAt)
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, userID, token, expiresAt).Scan(&updatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("user not found")
 	}
@@ -171,7 +187,7 @@ func (r *userRepository) GetUserByResetToken(ctx context.Context, token string)
 	var user domain.User
 	query := `SELECT * FROM users WHERE password_reset_token = $1 AND password_reset_expires_at > NOW() AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &user, query, token)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, token)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -189,7 +205,7 @@ func (r *userRepository) ClearPasswordResetToken(ctx context.Context, userID uui
 		RETURNING updated_at`
 
 	var updatedAt time.Time
-	err := r.db.QueryRowxContext(ctx, query, userID).Scan(&updatedAt)
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, userID).Scan(&updatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("user not found")
 	}
@@ -204,7 +220,7 @@ func (r *userRepository) SetEmailVerificationToken(ctx context.Context, userID u
 		RETURNING updated_at`
 
 	var updatedAt time.Time
-	err := r.db.QueryRowxContext(ctx, query, userID, token, sentAt).Scan(&updatedAt)
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, userID, token, sentAt).Scan(&updatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("user not found")
 	}
@@ -215,7 +231,7 @@ func (r *userRepository) GetUserByEmailVerificationToken(ctx context.Context, to
 	var user domain.User
 	query := `SELECT * FROM users WHERE email_verification_token = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &user, query, token)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, token)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -233,7 +249,7 @@ func (r *userRepository) VerifyEmail(ctx context.Context, userID uuid.UUID) erro
 		RETURNING updated_at`
 
 	var updatedAt time.Time
-	err := r.db.QueryRowxContext(ctx, query, userID).Scan(&updatedAt)
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, userID).Scan(&updatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("user not found")
 	}
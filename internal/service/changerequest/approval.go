@@ -0,0 +1,319 @@
+package changerequest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+// ErrInsufficientPermissions is returned when someone who is neither a
+// reviewer nor the consenting user reviews a change request.
+var ErrInsufficientPermissions = errors.New("insufficient permissions to review change request")
+
+// SetApprovalRepository makes approvals follow the stored policies: each
+// reviewer's approval is recorded and the request is applied once enough
+// reviewers, and the linked user where asked, have approved. Without it a
+// single reviewer's approval applies the request.
+func (s *service) SetApprovalRepository(approvalRepo repository.ApprovalRepository) {
+	s.approvalRepo = approvalRepo
+}
+
+func (s *service) policyFor(ctx context.Context, cr *domain.ChangeRequest) (domain.ApprovalPolicy, error) {
+	if cr.EntityType == domain.EntityChangeset {
+		policy, _, err := s.changesetPolicy(ctx, cr)
+		return policy, err
+	}
+	return s.storedPolicy(ctx, cr.EntityType, cr.Action)
+}
+
+func (s *service) storedPolicy(ctx context.Context, entityType domain.EntityType, action domain.ChangeAction) (domain.ApprovalPolicy, error) {
+	policy := domain.DefaultApprovalPolicy(entityType, action)
+	if s.approvalRepo == nil {
+		return policy, nil
+	}
+	stored, err := s.approvalRepo.GetPolicy(ctx, entityType, action)
+	if err != nil || stored == nil {
+		return policy, err
+	}
+	return *stored, nil
+}
+
+// changesetPolicy returns the policy of a changeset: its own, made as
+// strict as that of its strictest operation, so wrapping a change in a
+// changeset does not lower the bar. It also returns the existing persons
+// whose operations ask for consent.
+func (s *service) changesetPolicy(ctx context.Context, cr *domain.ChangeRequest) (domain.ApprovalPolicy, []uuid.UUID, error) {
+	policy, err := s.storedPolicy(ctx, cr.EntityType, cr.Action)
+	if err != nil {
+		return policy, nil, err
+	}
+	var cs domain.Changeset
+	if err := json.Unmarshal(cr.Payload, &cs); err != nil {
+		return policy, nil, err
+	}
+
+	var subjects []uuid.UUID
+	for _, op := range cs.Operations {
+		opPolicy, err := s.storedPolicy(ctx, op.EntityType, op.Action)
+		if err != nil {
+			return policy, nil, err
+		}
+		if opPolicy.RequiredApprovals > policy.RequiredApprovals {
+			policy.RequiredApprovals = opPolicy.RequiredApprovals
+		}
+		if opPolicy.RequireConsent && op.EntityType == domain.EntityPerson && op.EntityID != nil {
+			policy.RequireConsent = true
+			subjects = append(subjects, *op.EntityID)
+		}
+	}
+	return policy, subjects, nil
+}
+
+// consentUsers returns the users who must consent to the request: those
+// linked to a person it changes, when the policy asks for consent, except
+// the requester. A merge asks the users linked to both persons, a
+// changeset those linked to each person an operation asking for consent
+// changes.
+func (s *service) consentUsers(ctx context.Context, cr *domain.ChangeRequest, policy domain.ApprovalPolicy) ([]uuid.UUID, error) {
+	if !policy.RequireConsent {
+		return nil, nil
+	}
+
+	var subjects []uuid.UUID
+	switch {
+	case cr.EntityType == domain.EntityChangeset:
+		var err error
+		if _, subjects, err = s.changesetPolicy(ctx, cr); err != nil {
+			return nil, err
+		}
+	case cr.EntityType == domain.EntityPerson && cr.EntityID != nil:
+		subjects = []uuid.UUID{*cr.EntityID}
+		if cr.Action == domain.ActionMerge {
+			var input domain.MergePersonInput
+			if err := json.Unmarshal(cr.Payload, &input); err == nil {
+				subjects = append(subjects, input.TargetID)
+			}
+		}
+	}
+	return s.linkedUsers(ctx, cr.RequestedBy, subjects)
+}
+
+// linkedUsers returns the users linked to the persons, once each, leaving
+// out requester.
+func (s *service) linkedUsers(ctx context.Context, requester uuid.UUID, persons []uuid.UUID) ([]uuid.UUID, error) {
+	var users []uuid.UUID
+	seen := map[uuid.UUID]bool{requester: true}
+	for _, personID := range persons {
+		user, err := s.userRepo.GetByLinkedPerson(ctx, personID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		users = append(users, user.ID)
+	}
+	return users, nil
+}
+
+// isConsentUser reports whether userID is one of the users whose consent
+// the request needs.
+func (s *service) isConsentUser(ctx context.Context, cr *domain.ChangeRequest, userID uuid.UUID) (bool, error) {
+	policy, err := s.policyFor(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	consent, err := s.consentUsers(ctx, cr, policy)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range consent {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *service) progress(ctx context.Context, cr *domain.ChangeRequest) (*domain.ApprovalProgress, error) {
+	policy, err := s.policyFor(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	consent, err := s.consentUsers(ctx, cr, policy)
+	if err != nil {
+		return nil, err
+	}
+	var approvals []domain.ChangeRequestApproval
+	if s.approvalRepo != nil {
+		approvals, err = s.approvalRepo.ListApprovals(ctx, cr.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p := domain.NewApprovalProgress(policy, approvals, consent)
+	return &p, nil
+}
+
+// recordApproval stores the reviewer's approval and reports whether the
+// policy is now met. Approvals of one request are serialized, so two
+// reviewers approving at once both count, and the status is read again
+// under the lock, so a request closed meanwhile is not approved. Each
+// approval counts as activity and postpones the request's expiry.
+func (s *service) recordApproval(ctx context.Context, cr *domain.ChangeRequest, reviewer *domain.User, note *string) (*domain.ApprovalProgress, error) {
+	approval := domain.ChangeRequestApproval{
+		RequestID:    cr.ID,
+		ReviewerID:   reviewer.ID,
+		ReviewerRole: domain.UserRole(reviewer.Role),
+		Note:         note,
+	}
+	if s.approvalRepo == nil {
+		p := domain.NewApprovalProgress(domain.DefaultApprovalPolicy(cr.EntityType, cr.Action), []domain.ChangeRequestApproval{approval}, nil)
+		return &p, nil
+	}
+
+	status, err := s.approvalRepo.LockRequest(ctx, cr.ID)
+	if err != nil {
+		return nil, err
+	}
+	if status != domain.StatusPending {
+		return nil, domain.ErrChangeRequestNotPending
+	}
+	if err := s.approvalRepo.AddApproval(ctx, &approval); err != nil {
+		return nil, err
+	}
+	if err := s.crRepo.Touch(ctx, cr.ID); err != nil {
+		return nil, err
+	}
+	return s.progress(ctx, cr)
+}
+
+// Approvals shows who approved the change request and what its policy
+// still needs.
+func (s *service) Approvals(ctx context.Context, id uuid.UUID) (*domain.ApprovalProgress, error) {
+	cr, err := s.crRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.progress(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	for i := range p.Approvals {
+		if reviewer, err := s.userRepo.GetByID(ctx, p.Approvals[i].ReviewerID); err == nil {
+			p.Approvals[i].Reviewer = reviewer
+		}
+	}
+	return p, nil
+}
+
+func (s *service) ListPolicies(ctx context.Context) ([]domain.ApprovalPolicy, error) {
+	if s.approvalRepo == nil {
+		return []domain.ApprovalPolicy{}, nil
+	}
+	policies, err := s.approvalRepo.ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if policies == nil {
+		policies = []domain.ApprovalPolicy{}
+	}
+	return policies, nil
+}
+
+func (s *service) SetPolicy(ctx context.Context, userID uuid.UUID, entityType domain.EntityType, action domain.ChangeAction, input domain.SetApprovalPolicyInput) (*domain.ApprovalPolicy, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if !validPolicyTarget(entityType, action) {
+		return nil, fmt.Errorf("%w: no change requests are filed as %s %s", domain.ErrInvalidApprovalPolicy, action, entityType)
+	}
+	if s.approvalRepo == nil {
+		return nil, errors.New("approval policies are not enabled")
+	}
+
+	old, err := s.approvalRepo.GetPolicy(ctx, entityType, action)
+	if err != nil {
+		return nil, err
+	}
+	policy := &domain.ApprovalPolicy{
+		EntityType:        entityType,
+		Action:            action,
+		RequiredApprovals: input.RequiredApprovals,
+		RequireConsent:    input.RequireConsent,
+		UpdatedBy:         &userID,
+	}
+	if err := s.approvalRepo.UpsertPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	auditOld := any(nil)
+	if old != nil {
+		auditOld = old
+	}
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "SET_APPROVAL_POLICY",
+		EntityType: "APPROVAL_POLICY",
+		EntityID:   uuid.NewSHA1(uuid.NameSpaceOID, []byte(string(entityType)+"/"+string(action))),
+		OldValue:   auditOld,
+		NewValue:   policy,
+	})
+	return policy, nil
+}
+
+// validPolicyTarget reports whether change requests can be filed as the
+// action on the entity type, as validatePayload allows them.
+func validPolicyTarget(entityType domain.EntityType, action domain.ChangeAction) bool {
+	switch action {
+	case domain.ActionCreate, domain.ActionUpdate, domain.ActionDelete:
+	case domain.ActionMerge:
+		return entityType == domain.EntityPerson
+	default:
+		return false
+	}
+	switch entityType {
+	case domain.EntityPerson, domain.EntityRelationship, domain.EntityMedia, domain.EntityEvent, domain.EntityComment:
+		return true
+	case domain.EntityChangeset:
+		return action == domain.ActionCreate
+	}
+	return false
+}
+
+// notifyConsent asks the users linked to the persons a new request changes
+// to approve it.
+func (s *service) notifyConsent(ctx context.Context, cr *domain.ChangeRequest) {
+	if s.approvalRepo == nil || s.notifRepo == nil {
+		return
+	}
+	policy, err := s.policyFor(ctx, cr)
+	if err != nil {
+		return
+	}
+	consent, err := s.consentUsers(ctx, cr, policy)
+	if err != nil {
+		return
+	}
+	for _, userID := range consent {
+		_ = s.notifRepo.Create(ctx, &domain.Notification{
+			ID:      uuid.New(),
+			UserID:  userID,
+			Type:    domain.NotifChangeRequest,
+			Title:   "Your Consent Is Needed",
+			Message: "A change request concerns the person linked to your account and needs your approval",
+			Data:    json.RawMessage(`{"change_request_id":"` + cr.ID.String() + `"}`),
+		})
+	}
+}
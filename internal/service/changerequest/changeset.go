@@ -0,0 +1,198 @@
+package changerequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+// changesetEntities are the operations a changeset may contain.
+var changesetEntities = map[domain.EntityType]bool{
+	domain.EntityPerson:       true,
+	domain.EntityRelationship: true,
+	domain.EntityEvent:        true,
+}
+
+func operationInput(op domain.ChangeOperation, payload json.RawMessage) domain.CreateChangeRequestInput {
+	return domain.CreateChangeRequestInput{
+		EntityType: op.EntityType,
+		EntityID:   op.EntityID,
+		Action:     op.Action,
+		Payload:    payload,
+	}
+}
+
+func checkOperation(i int, op domain.ChangeOperation) error {
+	if !changesetEntities[op.EntityType] || op.Action == domain.ActionMerge {
+		return fmt.Errorf("%w: operation %d: %s %s cannot be part of a changeset",
+			domain.ErrInvalidChangeset, i+1, op.Action, op.EntityType)
+	}
+	return nil
+}
+
+// validateChangeset checks the changeset as a whole and each operation as
+// if it were filed on its own. Operations that use a ref are only checked
+// for shape, since the entity they point at does not exist yet; approval
+// validates them once it does.
+func (s *service) validateChangeset(ctx context.Context, requester uuid.UUID, input domain.CreateChangeRequestInput) error {
+	var cs domain.Changeset
+	if err := json.Unmarshal(input.Payload, &cs); err != nil {
+		return invalidChange(err)
+	}
+	if err := cs.Validate(); err != nil {
+		return invalidChange(err)
+	}
+
+	declared := cs.DeclaredRefs()
+	for i, op := range cs.Operations {
+		if err := checkOperation(i, op); err != nil {
+			return invalidChange(err)
+		}
+		opInput := operationInput(op, op.Payload)
+		if err := s.validatePayload(opInput); err != nil {
+			return fmt.Errorf("%w: operation %d: %w", ErrInvalidChange, i+1, err)
+		}
+		refs, err := op.Refs(declared)
+		if err != nil {
+			return invalidChange(err)
+		}
+		if len(refs) > 0 {
+			continue
+		}
+		if err := s.validateChange(ctx, requester, opInput); err != nil {
+			return fmt.Errorf("operation %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// executeChangeset applies the operations in order, replacing each ref by
+// the ID of the entity created for it. Approval runs it in one transaction,
+// so a failing operation undoes the ones before it. An UPDATE is merged
+// against the version it was filed against like a request of its own;
+// resolutions for operation n are keyed "n.field".
+func (s *service) executeChangeset(ctx context.Context, cr *domain.ChangeRequest, resolutions map[string]json.RawMessage) error {
+	var cs domain.Changeset
+	if err := json.Unmarshal(cr.Payload, &cs); err != nil {
+		return err
+	}
+	if err := cs.Validate(); err != nil {
+		return err
+	}
+
+	bases := operationBases(cr)
+	ids := map[string]uuid.UUID{}
+	for i, op := range cs.Operations {
+		if err := checkOperation(i, op); err != nil {
+			return err
+		}
+		payload, err := op.ResolveRefs(ids)
+		if err != nil {
+			return err
+		}
+		step := changesetStep(cr, bases, i, op, payload)
+		prefix := fmt.Sprintf("%d.", i+1)
+		id, err := s.executeChange(ctx, step, stepResolutions(resolutions, prefix))
+		var conflict *domain.ChangeConflictError
+		if errors.As(err, &conflict) {
+			fields := make([]string, len(conflict.Fields))
+			for j, f := range conflict.Fields {
+				fields[j] = prefix + f
+			}
+			err = &domain.ChangeConflictError{Fields: fields}
+		}
+		if err != nil {
+			return fmt.Errorf("operation %d: %w", i+1, err)
+		}
+		if op.Ref != "" {
+			ids[op.Ref] = id
+		}
+	}
+	return nil
+}
+
+// stepResolutions picks the resolutions keyed with prefix, without it.
+func stepResolutions(resolutions map[string]json.RawMessage, prefix string) map[string]json.RawMessage {
+	step := map[string]json.RawMessage{}
+	for k, v := range resolutions {
+		if field, ok := strings.CutPrefix(k, prefix); ok {
+			step[field] = v
+		}
+	}
+	return step
+}
+
+// previewLabelsKey carries the names of entities a changeset preview
+// creates, keyed by their placeholder IDs, to personLabel.
+type previewLabelsKey struct{}
+
+// diffChangeset previews each operation. Entities created by the changeset
+// get placeholder IDs so later operations can be previewed against them;
+// fields pointing at one show its ref and name.
+func (s *service) diffChangeset(ctx context.Context, cr *domain.ChangeRequest, d *domain.ChangeRequestDiff, viewer domain.Viewer, checkDuplicates bool) (string, error) {
+	var cs domain.Changeset
+	if err := json.Unmarshal(cr.Payload, &cs); err != nil {
+		return "", err
+	}
+
+	ids := map[string]uuid.UUID{}
+	refs := map[string]string{}
+	labels := map[uuid.UUID]string{}
+	ctx = context.WithValue(ctx, previewLabelsKey{}, labels)
+
+	bases := operationBases(cr)
+	for i, op := range cs.Operations {
+		payload, err := op.ResolveRefs(ids)
+		if err != nil {
+			return "", err
+		}
+		step := changesetStep(cr, bases, i, op, payload)
+		od, err := s.diff(ctx, step, viewer, checkDuplicates)
+		if err != nil {
+			return "", err
+		}
+		for i := range od.Changes {
+			c := &od.Changes[i]
+			if str, ok := c.After.(string); ok && refs[str] != "" {
+				if c.AfterLabel == "" {
+					c.AfterLabel = labels[uuid.MustParse(str)]
+				}
+				c.After = domain.RefToken(refs[str])
+			}
+		}
+		d.EntityMissing = d.EntityMissing || od.EntityMissing
+		d.Stale = d.Stale || od.Stale
+		d.Operations = append(d.Operations, *od)
+
+		if op.Ref != "" {
+			id := uuid.New()
+			ids[op.Ref] = id
+			refs[id.String()] = op.Ref
+			labels[id] = previewLabel(op, payload, viewer)
+		}
+	}
+	return fmt.Sprintf("of %d operations", len(cs.Operations)), nil
+}
+
+// previewLabel names an entity a changeset creates: a person by name as
+// the viewer may see it, anything else by its ref.
+func previewLabel(op domain.ChangeOperation, payload json.RawMessage, viewer domain.Viewer) string {
+	if op.EntityType == domain.EntityPerson {
+		var p domain.Person
+		if err := json.Unmarshal(payload, &p); err == nil {
+			if p.PrivacyLevel == "" {
+				p.PrivacyLevel = domain.PrivacyFamily
+			}
+			p.Redact(viewer, time.Now())
+			return p.FullName()
+		}
+	}
+	return domain.RefToken(op.Ref)
+}
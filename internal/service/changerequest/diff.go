@@ -0,0 +1,449 @@
+package changerequest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/person"
+)
+
+// ErrChangeRequestNotFound is returned when a change request does not exist.
+var ErrChangeRequestNotFound = errors.New("change request not found")
+
+// SetDuplicateDetector lets the diff of a person CREATE list the persons it
+// would likely duplicate.
+func (s *service) SetDuplicateDetector(detector person.DuplicateDetector) {
+	s.duplicates = detector
+}
+
+// Diff previews the change request against the current state of its
+// entity. Persons are shown as the viewer may see them.
+func (s *service) Diff(ctx context.Context, id uuid.UUID, viewer domain.Viewer) (*domain.ChangeRequestDiff, error) {
+	cr, err := s.crRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.diff(ctx, cr, viewer, true)
+}
+
+// diff builds the preview. An UPDATE is shown as its patch applied to the
+// current entity, the way approval applies it.
+func (s *service) diff(ctx context.Context, cr *domain.ChangeRequest, viewer domain.Viewer, checkDuplicates bool) (*domain.ChangeRequestDiff, error) {
+	d := &domain.ChangeRequestDiff{
+		RequestID:  cr.ID,
+		EntityType: cr.EntityType,
+		EntityID:   cr.EntityID,
+		Action:     cr.Action,
+		Changes:    []domain.FieldChange{},
+	}
+
+	var label string
+	var err error
+	switch cr.EntityType {
+	case domain.EntityPerson:
+		label, err = s.diffPerson(ctx, cr, d, viewer, checkDuplicates)
+	case domain.EntityRelationship:
+		label, err = s.diffRelationship(ctx, cr, d, viewer)
+	case domain.EntityEvent:
+		label, err = s.diffEvent(ctx, cr, d)
+	case domain.EntityMedia:
+		label, err = s.diffMedia(ctx, cr, d)
+	case domain.EntityComment:
+		label, err = s.diffComment(ctx, cr, d, viewer)
+	case domain.EntityChangeset:
+		label, err = s.diffChangeset(ctx, cr, d, viewer, checkDuplicates)
+	default:
+		var payload map[string]any
+		if err := json.Unmarshal(cr.Payload, &payload); err != nil {
+			return nil, err
+		}
+		d.Changes, err = domain.DiffFields(nil, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.Summary = domain.SummarizeChange(cr.Action, cr.EntityType, label, d.Changes)
+	return d, nil
+}
+
+func (s *service) diffPerson(ctx context.Context, cr *domain.ChangeRequest, d *domain.ChangeRequestDiff, viewer domain.Viewer, checkDuplicates bool) (string, error) {
+	now := time.Now()
+
+	var current *domain.Person
+	if cr.EntityID != nil {
+		p, err := s.personRepo.GetByID(ctx, *cr.EntityID)
+		if err != nil {
+			return "", err
+		}
+		if p == nil {
+			d.EntityMissing = true
+		} else {
+			current = p
+		}
+	}
+
+	switch cr.Action {
+	case domain.ActionCreate:
+		var proposed domain.Person
+		if err := json.Unmarshal(cr.Payload, &proposed); err != nil {
+			return "", err
+		}
+		if proposed.PrivacyLevel == "" {
+			proposed.PrivacyLevel = domain.PrivacyFamily
+		}
+		proposed.Redact(viewer, now)
+
+		changes, err := domain.DiffFields(nil, &proposed)
+		if err != nil {
+			return "", err
+		}
+		d.Changes = changes
+
+		if checkDuplicates && s.duplicates != nil {
+			candidates, err := s.duplicates.CheckNew(ctx, domain.CreatePersonInput{
+				FirstName:  proposed.FirstName,
+				LastName:   proposed.LastName,
+				Gender:     proposed.Gender,
+				BirthDate:  proposed.BirthDate,
+				BirthPlace: proposed.BirthPlace,
+				DeathDate:  proposed.DeathDate,
+			})
+			if err != nil {
+				return "", err
+			}
+			for i := range candidates {
+				candidates[i].Person.Redact(viewer, now)
+			}
+			d.Duplicates = candidates
+		}
+		return proposed.FullName(), nil
+
+	case domain.ActionUpdate:
+		if current == nil {
+			var proposed domain.Person
+			if err := json.Unmarshal(cr.Payload, &proposed); err != nil {
+				return "", err
+			}
+			return proposed.FullName(), nil
+		}
+		patch, err := domain.PatchFields(cr.BaseSnapshot, cr.Payload, current)
+		if err != nil {
+			return "", err
+		}
+		var proposed domain.Person
+		if err := domain.ApplyPatch(current, patch, &proposed); err != nil {
+			return "", err
+		}
+		d.Stale = isStale(cr, current.UpdatedAt)
+		// Redacting both sides hides fields the viewer may not see while
+		// still showing that they change when the other side is visible.
+		current.Redact(viewer, now)
+		proposed.Redact(viewer, now)
+
+		changes, err := domain.DiffFields(current, &proposed)
+		if err != nil {
+			return "", err
+		}
+		d.Changes = changes
+		return current.FullName(), nil
+
+	case domain.ActionDelete:
+		if current == nil {
+			return "", nil
+		}
+		current.Redact(viewer, now)
+		changes, err := domain.DiffFields(current, nil)
+		if err != nil {
+			return "", err
+		}
+		d.Changes = changes
+		return current.FullName(), nil
+
+	case domain.ActionMerge:
+		var input domain.MergePersonInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return "", err
+		}
+		target := s.personLabel(ctx, &input.TargetID, viewer)
+		d.Changes = []domain.FieldChange{{Field: "target_id", After: input.TargetID, AfterLabel: target}}
+		for _, field := range sortedKeys(input.Fields) {
+			d.Changes = append(d.Changes, domain.FieldChange{Field: "fields." + field, After: input.Fields[field]})
+		}
+		if current == nil {
+			return "into " + target, nil
+		}
+		current.Redact(viewer, now)
+		return fmt.Sprintf("%s into %s", current.FullName(), target), nil
+	}
+	return "", nil
+}
+
+func (s *service) diffRelationship(ctx context.Context, cr *domain.ChangeRequest, d *domain.ChangeRequestDiff, viewer domain.Viewer) (string, error) {
+	var current *domain.Relationship
+	if cr.EntityID != nil {
+		rel, err := s.relRepo.GetByID(ctx, *cr.EntityID)
+		if err != nil {
+			return "", err
+		}
+		if rel == nil {
+			d.EntityMissing = true
+		} else {
+			current = rel
+		}
+	}
+
+	var proposed *domain.Relationship
+	switch {
+	case cr.Action == domain.ActionCreate || (cr.Action == domain.ActionUpdate && current == nil):
+		proposed = &domain.Relationship{}
+		if err := json.Unmarshal(cr.Payload, proposed); err != nil {
+			return "", err
+		}
+		if cr.Action == domain.ActionUpdate {
+			return s.relationshipLabel(ctx, proposed, viewer), nil
+		}
+	case cr.Action == domain.ActionUpdate:
+		patch, err := domain.PatchFields(cr.BaseSnapshot, cr.Payload, current)
+		if err != nil {
+			return "", err
+		}
+		proposed = &domain.Relationship{}
+		if err := domain.ApplyPatch(current, patch, proposed); err != nil {
+			return "", err
+		}
+		d.Stale = isStale(cr, current.UpdatedAt)
+	}
+
+	var before any
+	if cr.Action != domain.ActionCreate && current != nil {
+		before = current
+	}
+	var after any
+	if proposed != nil {
+		after = proposed
+	}
+	changes, err := domain.DiffFields(before, after)
+	if err != nil {
+		return "", err
+	}
+	for i := range changes {
+		if changes[i].Field != "person_a" && changes[i].Field != "person_b" {
+			continue
+		}
+		changes[i].BeforeLabel = s.personLabel(ctx, jsonUUID(changes[i].Before), viewer)
+		changes[i].AfterLabel = s.personLabel(ctx, jsonUUID(changes[i].After), viewer)
+	}
+	d.Changes = changes
+
+	if current != nil {
+		return s.relationshipLabel(ctx, current, viewer), nil
+	}
+	if proposed != nil {
+		return s.relationshipLabel(ctx, proposed, viewer), nil
+	}
+	return "", nil
+}
+
+func (s *service) diffEvent(ctx context.Context, cr *domain.ChangeRequest, d *domain.ChangeRequestDiff) (string, error) {
+	var current *domain.Event
+	if cr.EntityID != nil && s.eventSvc != nil {
+		e, err := s.eventSvc.GetByID(ctx, *cr.EntityID)
+		if errors.Is(err, domain.ErrEventNotFound) {
+			d.EntityMissing = true
+		} else if err != nil {
+			return "", err
+		} else {
+			current = e
+		}
+	}
+
+	var proposed *domain.Event
+	switch {
+	case cr.Action == domain.ActionCreate:
+		proposed = &domain.Event{}
+		if err := json.Unmarshal(cr.Payload, proposed); err != nil {
+			return "", err
+		}
+	case cr.Action == domain.ActionUpdate && current != nil:
+		patch, err := domain.PatchFields(cr.BaseSnapshot, cr.Payload, current)
+		if err != nil {
+			return "", err
+		}
+		proposed = &domain.Event{}
+		if err := domain.ApplyPatch(current, patch, proposed); err != nil {
+			return "", err
+		}
+		d.Stale = isStale(cr, current.UpdatedAt)
+	}
+
+	var before any
+	if cr.Action != domain.ActionCreate && current != nil {
+		before = current
+	}
+	var after any
+	if proposed != nil {
+		after = proposed
+	}
+	changes, err := domain.DiffFields(before, after)
+	if err != nil {
+		return "", err
+	}
+	d.Changes = changes
+
+	if current != nil {
+		return current.Title, nil
+	}
+	if proposed != nil {
+		return proposed.Title, nil
+	}
+	return "", nil
+}
+
+func (s *service) diffMedia(ctx context.Context, cr *domain.ChangeRequest, d *domain.ChangeRequestDiff) (string, error) {
+	if cr.EntityID == nil {
+		return "", nil
+	}
+	m, err := s.mediaRepo.GetByID(ctx, *cr.EntityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		d.EntityMissing = true
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Approving a media CREATE publishes an uploaded file, an UPDATE
+	// edits its caption and taken date, and a DELETE removes it.
+	var changes []domain.FieldChange
+	switch cr.Action {
+	case domain.ActionDelete:
+		changes, err = domain.DiffFields(m, nil)
+	case domain.ActionUpdate:
+		var input domain.UpdateMediaInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return "", err
+		}
+		proposed := *m
+		if input.Caption.Set {
+			proposed.Caption = input.Caption.Value
+		}
+		if input.TakenAt.Set {
+			proposed.TakenAt = input.TakenAt.Value
+		}
+		changes, err = domain.DiffFields(m, &proposed)
+	default:
+		changes, err = domain.DiffFields(nil, m)
+	}
+	if err != nil {
+		return "", err
+	}
+	d.Changes = changes
+	return m.FileName, nil
+}
+
+// diffComment shows the content a comment request posts, edits or
+// removes, labelled with the person the comment is about.
+func (s *service) diffComment(ctx context.Context, cr *domain.ChangeRequest, d *domain.ChangeRequestDiff, viewer domain.Viewer) (string, error) {
+	if cr.Action == domain.ActionCreate {
+		var input domain.ProposeCommentInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return "", err
+		}
+		changes, err := domain.DiffFields(nil, input.CreateCommentInput)
+		if err != nil {
+			return "", err
+		}
+		d.Changes = changes
+		return s.personLabel(ctx, &input.PersonID, viewer), nil
+	}
+
+	if cr.EntityID == nil || s.commentSvc == nil {
+		return "", nil
+	}
+	c, err := s.commentSvc.GetByID(ctx, *cr.EntityID)
+	if err != nil {
+		return "", err
+	}
+	if c == nil {
+		d.EntityMissing = true
+		return "", nil
+	}
+
+	var changes []domain.FieldChange
+	switch cr.Action {
+	case domain.ActionDelete:
+		changes, err = domain.DiffFields(c, nil)
+	default:
+		var input domain.UpdateCommentInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return "", err
+		}
+		proposed := *c
+		proposed.Content = input.Content
+		changes, err = domain.DiffFields(c, &proposed)
+	}
+	if err != nil {
+		return "", err
+	}
+	d.Changes = changes
+	return s.personLabel(ctx, &c.PersonID, viewer), nil
+}
+
+// personLabel names a person for the viewer, or returns "" when the ID is
+// nil or the person is gone. Persons a previewed changeset creates are
+// named from the changeset.
+func (s *service) personLabel(ctx context.Context, id *uuid.UUID, viewer domain.Viewer) string {
+	if id == nil {
+		return ""
+	}
+	if labels, ok := ctx.Value(previewLabelsKey{}).(map[uuid.UUID]string); ok {
+		if label, ok := labels[*id]; ok {
+			return label
+		}
+	}
+	p, err := s.personRepo.GetByID(ctx, *id)
+	if err != nil || p == nil {
+		return ""
+	}
+	p.Redact(viewer, time.Now())
+	return p.FullName()
+}
+
+func (s *service) relationshipLabel(ctx context.Context, rel *domain.Relationship, viewer domain.Viewer) string {
+	a := s.personLabel(ctx, &rel.PersonA, viewer)
+	b := s.personLabel(ctx, &rel.PersonB, viewer)
+	return fmt.Sprintf("%s – %s (%s)", a, b, rel.Type)
+}
+
+func jsonUUID(v any) *uuid.UUID {
+	str, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	id, err := uuid.Parse(str)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,405 @@
+package changerequest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+var (
+	// ErrNotRequester is returned when someone other than the requester
+	// withdraws or amends a change request.
+	ErrNotRequester = errors.New("only the requester can withdraw or amend a change request")
+	// ErrNoteRequired is returned when asking for changes without saying
+	// which.
+	ErrNoteRequired = errors.New("a note is required when asking for changes")
+	// ErrCommentNotFound is returned when replying to a comment that is not
+	// part of the change request's discussion.
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+// SetCommentRepository enables the discussion of change requests.
+func (s *service) SetCommentRepository(commentRepo repository.ChangeRequestCommentRepository) {
+	s.commentRepo = commentRepo
+}
+
+// SetExpiry makes ExpireStale close open requests without activity for
+// age. Zero leaves requests open indefinitely.
+func (s *service) SetExpiry(age time.Duration) {
+	s.expiry = age
+}
+
+func (s *service) load(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error) {
+	cr, err := s.crRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChangeRequestNotFound
+	}
+	return cr, err
+}
+
+// loadOwn loads an open change request filed by userID.
+func (s *service) loadOwn(ctx context.Context, id, userID uuid.UUID) (*domain.ChangeRequest, error) {
+	cr, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cr.RequestedBy != userID {
+		return nil, ErrNotRequester
+	}
+	if !cr.Status.IsOpen() {
+		return nil, domain.ErrChangeRequestClosed
+	}
+	return cr, nil
+}
+
+// Withdraw closes the requester's own open change request.
+func (s *service) Withdraw(ctx context.Context, id, userID uuid.UUID, meta *RequestMeta) error {
+	cr, err := s.loadOwn(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.crRepo.Close(ctx, id, domain.StatusWithdrawn); err != nil {
+			return err
+		}
+		withdrawn := *cr
+		withdrawn.Status = domain.StatusWithdrawn
+
+		if err := s.notifyParticipants(ctx, &withdrawn, userID, domain.NotifChangeWithdrawn,
+			"Change Request Withdrawn", "A change request you are involved in was withdrawn by its requester"); err != nil {
+			return err
+		}
+		return s.logAudit(ctx, userID, "WITHDRAW_CHANGE_REQUEST", &withdrawn, cr.Status, meta)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.dispatchOutbox()
+	return nil
+}
+
+// Amend replaces the payload of the requester's own open change request.
+// The replaced version is kept as a revision, approvals given so far are
+// dropped and the request is up for review again.
+func (s *service) Amend(ctx context.Context, id, userID uuid.UUID, input domain.AmendChangeRequestInput, meta *RequestMeta) (*domain.ChangeRequest, error) {
+	cr, err := s.loadOwn(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filed := domain.CreateChangeRequestInput{
+		EntityType:    cr.EntityType,
+		EntityID:      cr.EntityID,
+		Action:        cr.Action,
+		Payload:       input.Payload,
+		RequesterNote: input.RequesterNote,
+	}
+	if err := s.validatePayload(filed); err != nil {
+		return nil, err
+	}
+
+	amended := *cr
+	amended.Payload = input.Payload
+	amended.RequesterNote = input.RequesterNote
+	amended.Revision = cr.Revision + 1
+	amended.Status = domain.StatusPending
+	amended.ReviewedBy = nil
+	amended.ReviewedAt = nil
+	amended.ReviewNote = nil
+	if err := s.recordBase(ctx, &amended); err != nil {
+		return nil, err
+	}
+	if err := s.validateChange(ctx, cr.RequestedBy, filed); err != nil {
+		return nil, err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		// The conditional update goes first: of two concurrent amendments
+		// the second waits for the first and then finds the revision
+		// moved on, instead of colliding on the revision it would keep.
+		if err := s.crRepo.Amend(ctx, &amended); err != nil {
+			return err
+		}
+		rev := &domain.ChangeRequestRevision{
+			RequestID:     cr.ID,
+			Revision:      cr.Revision,
+			Payload:       cr.Payload,
+			RequesterNote: cr.RequesterNote,
+		}
+		if cr.Status == domain.StatusChangesRequested {
+			rev.ReviewNote = cr.ReviewNote
+		}
+		if err := s.crRepo.CreateRevision(ctx, rev); err != nil {
+			return err
+		}
+		// Notified before the approvals are cleared, so approvers hear of it.
+		if err := s.notifyParticipants(ctx, cr, userID, domain.NotifChangeAmended,
+			"Change Request Amended", "A change request you are involved in was amended and needs review again"); err != nil {
+			return err
+		}
+		if s.approvalRepo != nil {
+			if err := s.approvalRepo.ClearApprovals(ctx, cr.ID); err != nil {
+				return err
+			}
+		}
+		return s.logAudit(ctx, userID, "AMEND_CHANGE_REQUEST", &amended, cr.Status, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutbox()
+	return &amended, nil
+}
+
+// RequestChanges sends a pending change request back to its requester,
+// with a note saying what to change.
+func (s *service) RequestChanges(ctx context.Context, id, reviewerID uuid.UUID, note *string, meta *RequestMeta) error {
+	if note == nil || *note == "" {
+		return ErrNoteRequired
+	}
+	cr, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.validateReview(ctx, cr, reviewerID); err != nil {
+		return err
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.crRepo.UpdateStatus(ctx, id, domain.StatusChangesRequested, reviewerID, note); err != nil {
+			return err
+		}
+		reviewed := *cr
+		reviewed.Status = domain.StatusChangesRequested
+
+		if err := s.notifyUsers(ctx, []uuid.UUID{cr.RequestedBy}, cr, reviewerID, domain.NotifChangesRequested,
+			"Changes Requested", "A reviewer asked for changes to your change request: "+*note); err != nil {
+			return err
+		}
+		return s.logAudit(ctx, reviewerID, "REQUEST_CHANGES", &reviewed, cr.Status, meta)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.dispatchOutbox()
+	return nil
+}
+
+// Revisions lists the earlier versions of an amended change request,
+// oldest first; the change request itself is the latest.
+func (s *service) Revisions(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestRevision, error) {
+	if _, err := s.load(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.crRepo.ListRevisions(ctx, id)
+}
+
+// AddComment adds to the discussion of a change request, which is open to
+// its requester, reviewers and the user whose consent it needs.
+func (s *service) AddComment(ctx context.Context, id, userID uuid.UUID, input domain.CreateChangeRequestCommentInput) (*domain.ChangeRequestComment, error) {
+	if s.commentRepo == nil {
+		return nil, errors.New("change request discussion is not enabled")
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	cr, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDiscussant(ctx, cr, userID); err != nil {
+		return nil, err
+	}
+	if input.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.RequestID != cr.ID {
+			return nil, ErrCommentNotFound
+		}
+	}
+
+	comment := &domain.ChangeRequestComment{
+		ID:        uuid.New(),
+		RequestID: cr.ID,
+		UserID:    userID,
+		ParentID:  input.ParentID,
+		Content:   input.Content,
+	}
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.Create(ctx, comment); err != nil {
+			return err
+		}
+		if cr.Status.IsOpen() {
+			if err := s.crRepo.Touch(ctx, cr.ID); err != nil {
+				return err
+			}
+		}
+		return s.notifyParticipants(ctx, cr, userID, domain.NotifChangeComment,
+			"New Comment on Change Request", "Someone commented on a change request you are involved in")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutbox()
+	return comment, nil
+}
+
+// Comments returns the discussion of a change request as threads.
+func (s *service) Comments(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestComment, error) {
+	if _, err := s.load(ctx, id); err != nil {
+		return nil, err
+	}
+	if s.commentRepo == nil {
+		return []domain.ChangeRequestComment{}, nil
+	}
+	comments, err := s.commentRepo.ListByRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ThreadComments(comments), nil
+}
+
+func (s *service) checkDiscussant(ctx context.Context, cr *domain.ChangeRequest, userID uuid.UUID) error {
+	if cr.RequestedBy == userID {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user != nil && domain.UserRole(user.Role).AtLeast(domain.RoleEditor) {
+		return nil
+	}
+	consenting, err := s.isConsentUser(ctx, cr, userID)
+	if err != nil {
+		return err
+	}
+	if consenting {
+		return nil
+	}
+	return ErrInsufficientPermissions
+}
+
+// ExpireStale closes the open change requests nobody amended, reviewed or
+// discussed within the expiry age and tells those involved. It returns how
+// many expired.
+func (s *service) ExpireStale(ctx context.Context) (int, error) {
+	if s.expiry <= 0 {
+		return 0, nil
+	}
+
+	var expired []domain.ChangeRequest
+	err := s.withinTx(ctx, func(ctx context.Context) error {
+		var err error
+		expired, err = s.crRepo.ExpireStale(ctx, time.Now().Add(-s.expiry))
+		if err != nil {
+			return err
+		}
+		for i := range expired {
+			if err := s.notifyParticipants(ctx, &expired[i], uuid.Nil, domain.NotifChangeExpired,
+				"Change Request Expired", "A change request you are involved in expired without a decision"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.dispatchOutbox()
+	return len(expired), nil
+}
+
+// participants are the users involved in a change request: its requester,
+// whoever last reviewed it, approvers and discussants.
+func (s *service) participants(ctx context.Context, cr *domain.ChangeRequest) ([]uuid.UUID, error) {
+	users := []uuid.UUID{cr.RequestedBy}
+	if cr.ReviewedBy != nil {
+		users = append(users, *cr.ReviewedBy)
+	}
+	if s.approvalRepo != nil {
+		approvals, err := s.approvalRepo.ListApprovals(ctx, cr.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range approvals {
+			users = append(users, a.ReviewerID)
+		}
+	}
+	if s.commentRepo != nil {
+		comments, err := s.commentRepo.ListByRequest(ctx, cr.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			users = append(users, c.UserID)
+		}
+	}
+	return users, nil
+}
+
+// notifyParticipants notifies everyone involved in the change request
+// except actorID.
+func (s *service) notifyParticipants(ctx context.Context, cr *domain.ChangeRequest, actorID uuid.UUID, notifType domain.NotificationType, title, message string) error {
+	if s.notifRepo == nil && s.outbox == nil {
+		return nil
+	}
+	users, err := s.participants(ctx, cr)
+	if err != nil {
+		return err
+	}
+	recipients := make([]uuid.UUID, 0, len(users))
+	seen := map[uuid.UUID]bool{actorID: true}
+	for _, id := range users {
+		if !seen[id] {
+			seen[id] = true
+			recipients = append(recipients, id)
+		}
+	}
+	return s.notifyUsers(ctx, recipients, cr, actorID, notifType, title, message)
+}
+
+// notifyUsers prepares the notifications now, while the recipients are
+// known, and enqueues them in the outbox to be delivered after commit.
+// Without an outbox they are written directly, which inside a transaction
+// also commits them with the change they announce.
+func (s *service) notifyUsers(ctx context.Context, users []uuid.UUID, cr *domain.ChangeRequest, actorID uuid.UUID, notifType domain.NotificationType, title, message string) error {
+	if s.notifRepo == nil && s.outbox == nil {
+		return nil
+	}
+	for _, userID := range users {
+		notif := &domain.Notification{
+			ID:      uuid.New(),
+			UserID:  userID,
+			Type:    notifType,
+			Title:   title,
+			Message: message,
+			Data:    json.RawMessage(`{"change_request_id":"` + cr.ID.String() + `"}`),
+		}
+		var err error
+		if s.outbox != nil {
+			err = s.outbox.EnqueueNotification(ctx, notif, cr.ID, actorID)
+		} else {
+			err = s.notifRepo.Create(ctx, notif)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
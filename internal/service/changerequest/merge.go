@@ -0,0 +1,260 @@
+package changerequest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+var (
+	// ErrEntityNotFound is returned when the entity an UPDATE targets does
+	// not exist or was deleted.
+	ErrEntityNotFound = errors.New("entity not found")
+	// ErrNotMergeable is returned when merging a change request that is not
+	// an UPDATE.
+	ErrNotMergeable = errors.New("only update change requests can be merged")
+)
+
+// recordBase snapshots the entity an UPDATE is filed against, so approval
+// can tell whether it was edited in the meantime.
+func (s *service) recordBase(ctx context.Context, cr *domain.ChangeRequest) error {
+	if cr.EntityType == domain.EntityChangeset {
+		return s.recordChangesetBase(ctx, cr)
+	}
+	if cr.Action != domain.ActionUpdate {
+		return nil
+	}
+	switch cr.EntityType {
+	case domain.EntityPerson, domain.EntityRelationship, domain.EntityEvent:
+	default:
+		return nil
+	}
+
+	entity, updatedAt, err := s.loadEntity(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if entity == nil {
+		return ErrEntityNotFound
+	}
+	snapshot, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	cr.BaseUpdatedAt = &updatedAt
+	cr.BaseSnapshot = snapshot
+	return nil
+}
+
+// recordChangesetBase snapshots the entity of each UPDATE operation of a
+// changeset that targets an existing entity.
+func (s *service) recordChangesetBase(ctx context.Context, cr *domain.ChangeRequest) error {
+	var cs domain.Changeset
+	if err := json.Unmarshal(cr.Payload, &cs); err != nil {
+		return invalidChange(err)
+	}
+
+	bases := make([]*domain.OperationBase, len(cs.Operations))
+	recorded := false
+	for i, op := range cs.Operations {
+		if op.Action != domain.ActionUpdate || op.EntityID == nil {
+			continue
+		}
+		step := &domain.ChangeRequest{EntityType: op.EntityType, EntityID: op.EntityID, Action: op.Action}
+		if err := s.recordBase(ctx, step); err != nil {
+			return fmt.Errorf("operation %d: %w", i+1, err)
+		}
+		if step.BaseUpdatedAt != nil {
+			bases[i] = &domain.OperationBase{UpdatedAt: *step.BaseUpdatedAt, Snapshot: step.BaseSnapshot}
+			recorded = true
+		}
+	}
+	if !recorded {
+		cr.BaseSnapshot = nil
+		return nil
+	}
+	snapshot, err := json.Marshal(bases)
+	if err != nil {
+		return err
+	}
+	cr.BaseSnapshot = snapshot
+	return nil
+}
+
+// operationBases reads back the bases recordChangesetBase kept; requests
+// filed before they were recorded have none.
+func operationBases(cr *domain.ChangeRequest) []*domain.OperationBase {
+	var bases []*domain.OperationBase
+	if len(cr.BaseSnapshot) > 0 {
+		_ = json.Unmarshal(cr.BaseSnapshot, &bases)
+	}
+	return bases
+}
+
+// changesetStep is operation i of a changeset as a change request of its
+// own, with the base it was filed against.
+func changesetStep(cr *domain.ChangeRequest, bases []*domain.OperationBase, i int, op domain.ChangeOperation, payload json.RawMessage) *domain.ChangeRequest {
+	step := &domain.ChangeRequest{
+		ID:          cr.ID,
+		RequestedBy: cr.RequestedBy,
+		Status:      cr.Status,
+		EntityType:  op.EntityType,
+		EntityID:    op.EntityID,
+		Action:      op.Action,
+		Payload:     payload,
+	}
+	if i < len(bases) && bases[i] != nil {
+		updatedAt := bases[i].UpdatedAt
+		step.BaseUpdatedAt = &updatedAt
+		step.BaseSnapshot = bases[i].Snapshot
+	}
+	return step
+}
+
+// loadEntity returns the live person, relationship or event a change
+// request targets, or nil when it is gone.
+func (s *service) loadEntity(ctx context.Context, cr *domain.ChangeRequest) (any, time.Time, error) {
+	if cr.EntityID == nil {
+		return nil, time.Time{}, nil
+	}
+	switch cr.EntityType {
+	case domain.EntityPerson:
+		p, err := s.personRepo.GetByID(ctx, *cr.EntityID)
+		if err != nil || p == nil || p.DeletedAt != nil {
+			return nil, time.Time{}, err
+		}
+		return p, p.UpdatedAt, nil
+	case domain.EntityRelationship:
+		rel, err := s.relRepo.GetByID(ctx, *cr.EntityID)
+		if err != nil || rel == nil || rel.DeletedAt != nil {
+			return nil, time.Time{}, err
+		}
+		return rel, rel.UpdatedAt, nil
+	case domain.EntityEvent:
+		if s.eventSvc == nil {
+			return nil, time.Time{}, nil
+		}
+		e, err := s.eventSvc.GetByID(ctx, *cr.EntityID)
+		if errors.Is(err, domain.ErrEventNotFound) {
+			return nil, time.Time{}, nil
+		}
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return e, e.UpdatedAt, nil
+	}
+	return nil, time.Time{}, nil
+}
+
+func isStale(cr *domain.ChangeRequest, updatedAt time.Time) bool {
+	return cr.BaseUpdatedAt != nil && !cr.BaseUpdatedAt.Equal(updatedAt)
+}
+
+// patchFor returns the fields approving an UPDATE writes onto current. When
+// current was edited since the request was filed, conflicting fields take
+// the reviewer's resolution and any left unresolved fail the approval.
+func (s *service) patchFor(cr *domain.ChangeRequest, current any, updatedAt time.Time, resolutions map[string]json.RawMessage) (map[string]any, error) {
+	patch, err := domain.PatchFields(cr.BaseSnapshot, cr.Payload, current)
+	if err != nil {
+		return nil, err
+	}
+	if !isStale(cr, updatedAt) {
+		return patch, nil
+	}
+
+	fields, err := domain.ThreeWayMerge(cr.BaseSnapshot, current, patch)
+	if err != nil {
+		return nil, err
+	}
+	var unresolved []string
+	for _, f := range fields {
+		if !f.Conflict {
+			continue
+		}
+		raw, ok := resolutions[f.Field]
+		if !ok {
+			unresolved = append(unresolved, f.Field)
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("invalid resolution for %s: %w", f.Field, err)
+		}
+		patch[f.Field] = v
+	}
+	if len(unresolved) > 0 {
+		return nil, &domain.ChangeConflictError{Fields: unresolved}
+	}
+	return patch, nil
+}
+
+// Merge shows an UPDATE change request as a three-way merge of the entity
+// it was filed against, the entity now and the proposed values. Fields of a
+// person the viewer may not see are blanked.
+func (s *service) Merge(ctx context.Context, id uuid.UUID, viewer domain.Viewer) (*domain.ChangeRequestMerge, error) {
+	cr, err := s.crRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cr.Action != domain.ActionUpdate {
+		return nil, ErrNotMergeable
+	}
+
+	entity, updatedAt, err := s.loadEntity(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, ErrEntityNotFound
+	}
+
+	patch, err := domain.PatchFields(cr.BaseSnapshot, cr.Payload, entity)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := domain.ThreeWayMerge(cr.BaseSnapshot, entity, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &domain.ChangeRequestMerge{
+		RequestID:        cr.ID,
+		Stale:            isStale(cr, updatedAt),
+		BaseUpdatedAt:    cr.BaseUpdatedAt,
+		CurrentUpdatedAt: &updatedAt,
+		Fields:           fields,
+		Conflicts:        []string{},
+	}
+
+	hidden := map[string]bool{}
+	if p, ok := entity.(*domain.Person); ok {
+		for _, field := range p.RedactedFields(viewer, time.Now()) {
+			hidden[field] = true
+		}
+	}
+	for i := range m.Fields {
+		f := &m.Fields[i]
+		// Without a newer version there is nothing to conflict with, even
+		// for requests filed before snapshots were recorded.
+		if !m.Stale {
+			f.Conflict = false
+		}
+		if f.Conflict {
+			m.Conflicts = append(m.Conflicts, f.Field)
+		}
+		if hidden[f.Field] {
+			f.Base, f.Current, f.Proposed = nil, nil, nil
+		}
+	}
+	return m, nil
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/repository"
+	"silsilah-keluarga/internal/service/comment"
+	"silsilah-keluarga/internal/service/event"
 	"silsilah-keluarga/internal/service/media"
 	"silsilah-keluarga/internal/service/notification"
+	"silsilah-keluarga/internal/service/outbox"
 	"silsilah-keluarga/internal/service/person"
 	"silsilah-keluarga/internal/service/relationship"
 )
@@ -24,10 +29,29 @@ type RequestMeta struct {
 type Service interface {
 	Create(ctx context.Context, userID uuid.UUID, input domain.CreateChangeRequestInput) (*domain.ChangeRequest, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequest, error)
-	List(ctx context.Context, status *domain.ChangeRequestStatus, params domain.PaginationParams) (domain.PaginatedResponse[domain.ChangeRequest], error)
-	Approve(ctx context.Context, id, reviewerID uuid.UUID, note *string, meta *RequestMeta) error
+	List(ctx context.Context, status *domain.ChangeRequestStatus, params domain.PaginationParams, viewer domain.Viewer) (domain.PaginatedResponse[domain.ChangeRequest], error)
+	Diff(ctx context.Context, id uuid.UUID, viewer domain.Viewer) (*domain.ChangeRequestDiff, error)
+	Merge(ctx context.Context, id uuid.UUID, viewer domain.Viewer) (*domain.ChangeRequestMerge, error)
+	Approve(ctx context.Context, id, reviewerID uuid.UUID, note *string, resolutions map[string]json.RawMessage, meta *RequestMeta) (*domain.ApprovalProgress, error)
 	Reject(ctx context.Context, id, reviewerID uuid.UUID, note *string, meta *RequestMeta) error
 	SetNotificationService(notifSvc notification.Service)
+	SetTransactor(tx repository.Transactor, outboxSvc outbox.Service)
+	SetDuplicateDetector(detector person.DuplicateDetector)
+	SetEventService(eventSvc event.Service)
+	SetCommentService(commentSvc comment.Service)
+	SetApprovalRepository(approvalRepo repository.ApprovalRepository)
+	Approvals(ctx context.Context, id uuid.UUID) (*domain.ApprovalProgress, error)
+	ListPolicies(ctx context.Context) ([]domain.ApprovalPolicy, error)
+	SetPolicy(ctx context.Context, userID uuid.UUID, entityType domain.EntityType, action domain.ChangeAction, input domain.SetApprovalPolicyInput) (*domain.ApprovalPolicy, error)
+	SetCommentRepository(commentRepo repository.ChangeRequestCommentRepository)
+	SetExpiry(age time.Duration)
+	Withdraw(ctx context.Context, id, userID uuid.UUID, meta *RequestMeta) error
+	Amend(ctx context.Context, id, userID uuid.UUID, input domain.AmendChangeRequestInput, meta *RequestMeta) (*domain.ChangeRequest, error)
+	RequestChanges(ctx context.Context, id, reviewerID uuid.UUID, note *string, meta *RequestMeta) error
+	Revisions(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestRevision, error)
+	AddComment(ctx context.Context, id, userID uuid.UUID, input domain.CreateChangeRequestCommentInput) (*domain.ChangeRequestComment, error)
+	Comments(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestComment, error)
+	ExpireStale(ctx context.Context) (int, error)
 }
 
 type service struct {
@@ -41,7 +65,16 @@ type service struct {
 	personSvc  person.Service
 	relSvc     relationship.Service
 	mediaSvc   media.Service
+	eventSvc   event.Service
+	commentSvc comment.Service
 	notifSvc   notification.Service
+	duplicates person.DuplicateDetector
+	tx         repository.Transactor
+	outbox     outbox.Service
+
+	approvalRepo repository.ApprovalRepository
+	commentRepo  repository.ChangeRequestCommentRepository
+	expiry       time.Duration
 }
 
 func NewService(
@@ -74,6 +107,65 @@ func (s *service) SetNotificationService(notifSvc notification.Service) {
 	s.notifSvc = notifSvc
 }
 
+// SetEventService lets change requests, and changesets in particular,
+// create and edit events.
+func (s *service) SetEventService(eventSvc event.Service) {
+	s.eventSvc = eventSvc
+}
+
+// SetCommentService lets change requests post, edit and delete comments
+// on persons.
+func (s *service) SetCommentService(commentSvc comment.Service) {
+	s.commentSvc = commentSvc
+}
+
+// SetTransactor makes reviews run in one transaction, with notifications
+// written to the outbox and delivered after commit. Without it each step
+// runs on its own.
+func (s *service) SetTransactor(tx repository.Transactor, outboxSvc outbox.Service) {
+	s.tx = tx
+	s.outbox = outboxSvc
+}
+
+func (s *service) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
+
+// notify sends a notification about entityID. Inside a review it goes to
+// the outbox, so it is only delivered if the review commits.
+func (s *service) notify(ctx context.Context, notifType domain.NotificationType, entityID, actorID uuid.UUID) error {
+	if s.outbox != nil {
+		return s.outbox.Enqueue(ctx, notifType, entityID, actorID)
+	}
+	if s.notifSvc == nil {
+		return nil
+	}
+	go func() {
+		bg := context.Background()
+		switch notifType {
+		case domain.NotifChangeApproved:
+			_ = s.notifSvc.NotifyChangeApproved(bg, entityID, actorID)
+		case domain.NotifChangeRejected:
+			_ = s.notifSvc.NotifyChangeRejected(bg, entityID, actorID)
+		}
+	}()
+	return nil
+}
+
+// dispatchOutbox delivers what a committed review enqueued. Messages it
+// misses are picked up by the periodic dispatcher.
+func (s *service) dispatchOutbox() {
+	if s.outbox == nil {
+		return
+	}
+	go func() {
+		_, _ = s.outbox.Dispatch(context.Background())
+	}()
+}
+
 func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.CreateChangeRequestInput) (*domain.ChangeRequest, error) {
 	if err := s.validatePayload(input); err != nil {
 		return nil, err
@@ -88,6 +180,15 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.Cre
 		Payload:       input.Payload,
 		RequesterNote: input.RequesterNote,
 		Status:        domain.StatusPending,
+		Revision:      1,
+	}
+
+	if err := s.recordBase(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateChange(ctx, userID, input); err != nil {
+		return nil, err
 	}
 
 	if err := s.crRepo.Create(ctx, cr); err != nil {
@@ -95,6 +196,7 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.Cre
 	}
 
 	s.notifyReviewers(ctx, cr)
+	s.notifyConsent(ctx, cr)
 
 	return cr, nil
 }
@@ -104,13 +206,36 @@ func (s *service) validatePayload(input domain.CreateChangeRequestInput) error {
 		return errors.New("invalid JSON payload")
 	}
 
-	if input.Action == domain.ActionUpdate || input.Action == domain.ActionDelete {
+	if input.Action == domain.ActionUpdate || input.Action == domain.ActionDelete || input.Action == domain.ActionMerge {
 		if input.EntityID == nil {
-			return errors.New("entity_id required for update/delete actions")
+			return errors.New("entity_id required for update/delete/merge actions")
+		}
+	}
+
+	if input.Action == domain.ActionMerge {
+		if input.EntityType != domain.EntityPerson {
+			return errors.New("only persons can be merged")
+		}
+		var merge domain.MergePersonInput
+		if err := json.Unmarshal(input.Payload, &merge); err != nil || merge.TargetID == uuid.Nil {
+			return errors.New("merge payload requires target_id")
 		}
 	}
 
-	if input.Action == domain.ActionCreate && input.EntityID != nil {
+	if input.EntityType == domain.EntityChangeset && input.Action != domain.ActionCreate {
+		return errors.New("changesets are filed as create actions")
+	}
+
+	// A media CREATE publishes a file that was already uploaded, so it
+	// names the file; other creates make a new entity.
+	if input.EntityType == domain.EntityMedia {
+		if input.EntityID == nil {
+			return errors.New("entity_id required for media actions")
+		}
+		if input.Action != domain.ActionCreate && input.Action != domain.ActionUpdate && input.Action != domain.ActionDelete {
+			return errors.New("unsupported action for media")
+		}
+	} else if input.Action == domain.ActionCreate && input.EntityID != nil {
 		return errors.New("entity_id must be null for create actions")
 	}
 
@@ -152,7 +277,10 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequ
 	return s.crRepo.GetByID(ctx, id)
 }
 
-func (s *service) List(ctx context.Context, status *domain.ChangeRequestStatus, params domain.PaginationParams) (domain.PaginatedResponse[domain.ChangeRequest], error) {
+// List returns change requests with a summary of each. Pending requests
+// are summarized against the current entity, so the summary names the
+// fields approval would change.
+func (s *service) List(ctx context.Context, status *domain.ChangeRequestStatus, params domain.PaginationParams, viewer domain.Viewer) (domain.PaginatedResponse[domain.ChangeRequest], error) {
 	requests, total, err := s.crRepo.List(ctx, status, params)
 	if err != nil {
 		return domain.PaginatedResponse[domain.ChangeRequest]{}, err
@@ -168,33 +296,68 @@ func (s *service) List(ctx context.Context, status *domain.ChangeRequestStatus,
 				requests[i].Reviewer = reviewer
 			}
 		}
+
+		requests[i].Summary = domain.SummarizeChange(requests[i].Action, requests[i].EntityType, "", nil)
+		if requests[i].Status == domain.StatusPending {
+			if d, err := s.diff(ctx, &requests[i], viewer, false); err == nil {
+				requests[i].Summary = d.Summary
+			}
+		}
 	}
 
 	return domain.NewPaginatedResponse(requests, params.Page, params.PageSize, total), nil
 }
 
-func (s *service) Approve(ctx context.Context, id, reviewerID uuid.UUID, note *string, meta *RequestMeta) error {
+// Approve records the reviewer's approval and, once the approval policy
+// for the request's entity type and action is met, applies the change. An
+// UPDATE filed against an older version of its entity is merged field by
+// field; conflicting fields need a value in resolutions.
+//
+// The approval, the status transition, the change itself, the audit log
+// and the notifications commit together. The transition only succeeds on a
+// pending request, so a concurrent approval waits for this one and then
+// fails instead of applying the change twice.
+func (s *service) Approve(ctx context.Context, id, reviewerID uuid.UUID, note *string, resolutions map[string]json.RawMessage, meta *RequestMeta) (*domain.ApprovalProgress, error) {
 	cr, err := s.crRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := s.validateReview(ctx, cr, reviewerID); err != nil {
-		return err
+	reviewer, err := s.validateReview(ctx, cr, reviewerID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.executeChange(ctx, cr); err != nil {
-		return err
-	}
+	var progress *domain.ApprovalProgress
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		progress, err = s.recordApproval(ctx, cr, reviewer, note)
+		if err != nil {
+			return err
+		}
+		if !progress.Met {
+			return s.logAudit(ctx, reviewerID, "RECORD_APPROVAL", cr, cr.Status, meta)
+		}
 
-	if err := s.crRepo.UpdateStatus(ctx, id, domain.StatusApproved, reviewerID, note); err != nil {
-		return err
-	}
+		if err := s.crRepo.UpdateStatus(ctx, id, domain.StatusApproved, reviewerID, note); err != nil {
+			return err
+		}
+		reviewed := *cr
+		reviewed.Status = domain.StatusApproved
 
-	s.notifyRequester(ctx, cr, domain.StatusApproved, reviewerID, note)
-	s.logAudit(ctx, reviewerID, "APPROVE_CHANGE_REQUEST", cr, meta)
+		if _, err := s.executeChange(ctx, cr, resolutions); err != nil {
+			return invalidChange(err)
+		}
+		if err := s.notifyRequester(ctx, &reviewed, domain.StatusApproved, reviewerID, note); err != nil {
+			return err
+		}
+		return s.logAudit(ctx, reviewerID, "APPROVE_CHANGE_REQUEST", &reviewed, cr.Status, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	s.dispatchOutbox()
+	return progress, nil
 }
 
 func (s *service) Reject(ctx context.Context, id, reviewerID uuid.UUID, note *string, meta *RequestMeta) error {
@@ -203,165 +366,345 @@ func (s *service) Reject(ctx context.Context, id, reviewerID uuid.UUID, note *st
 		return err
 	}
 
-	if err := s.validateReview(ctx, cr, reviewerID); err != nil {
+	if _, err := s.validateReview(ctx, cr, reviewerID); err != nil {
 		return err
 	}
 
-	if err := s.crRepo.UpdateStatus(ctx, id, domain.StatusRejected, reviewerID, note); err != nil {
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		if err := s.crRepo.UpdateStatus(ctx, id, domain.StatusRejected, reviewerID, note); err != nil {
+			return err
+		}
+		reviewed := *cr
+		reviewed.Status = domain.StatusRejected
+
+		if err := s.notifyRequester(ctx, &reviewed, domain.StatusRejected, reviewerID, note); err != nil {
+			return err
+		}
+		return s.logAudit(ctx, reviewerID, "REJECT_CHANGE_REQUEST", &reviewed, cr.Status, meta)
+	})
+	if err != nil {
 		return err
 	}
 
-	s.notifyRequester(ctx, cr, domain.StatusRejected, reviewerID, note)
-	s.logAudit(ctx, reviewerID, "REJECT_CHANGE_REQUEST", cr, meta)
-
+	s.dispatchOutbox()
 	return nil
 }
 
-func (s *service) validateReview(ctx context.Context, cr *domain.ChangeRequest, reviewerID uuid.UUID) error {
+// validateReview checks that reviewerID may review the request: an editor
+// or developer, or the user whose consent its policy asks for.
+func (s *service) validateReview(ctx context.Context, cr *domain.ChangeRequest, reviewerID uuid.UUID) (*domain.User, error) {
 	if cr.Status != domain.StatusPending {
-		return errors.New("change request is not pending")
+		return nil, domain.ErrChangeRequestNotPending
 	}
 
 	if cr.RequestedBy == reviewerID {
-		return errors.New("cannot review own change request")
+		return nil, errors.New("cannot review own change request")
 	}
 
 	reviewer, err := s.userRepo.GetByID(ctx, reviewerID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	if reviewer.Role != string(domain.RoleEditor) && reviewer.Role != string(domain.RoleDeveloper) {
-		return errors.New("insufficient permissions to review change request")
+	if reviewer == nil {
+		return nil, errors.New("reviewer not found")
 	}
 
 	if reviewer.DeletedAt != nil {
-		return errors.New("reviewer account is inactive")
+		return nil, errors.New("reviewer account is inactive")
 	}
 
-	return nil
+	if reviewer.Role == string(domain.RoleEditor) || reviewer.Role == string(domain.RoleDeveloper) {
+		return reviewer, nil
+	}
+	if s.approvalRepo != nil {
+		consenting, err := s.isConsentUser(ctx, cr, reviewerID)
+		if err != nil {
+			return nil, err
+		}
+		if consenting {
+			return reviewer, nil
+		}
+	}
+	return nil, ErrInsufficientPermissions
 }
 
-func (s *service) executeChange(ctx context.Context, cr *domain.ChangeRequest) error {
+// executeChange applies the change and returns the ID of the entity it
+// created, or of the entity it changed.
+func (s *service) executeChange(ctx context.Context, cr *domain.ChangeRequest, resolutions map[string]json.RawMessage) (uuid.UUID, error) {
 	switch cr.EntityType {
 	case domain.EntityPerson:
-		return s.executePersonChange(ctx, cr)
+		return s.executePersonChange(ctx, cr, resolutions)
 	case domain.EntityRelationship:
-		return s.executeRelationshipChange(ctx, cr)
+		return s.executeRelationshipChange(ctx, cr, resolutions)
+	case domain.EntityEvent:
+		return s.executeEventChange(ctx, cr, resolutions)
 	case domain.EntityMedia:
-		return s.executeMediaChange(ctx, cr)
+		return entityID(cr), s.executeMediaChange(ctx, cr)
+	case domain.EntityComment:
+		return s.executeCommentChange(ctx, cr)
+	case domain.EntityChangeset:
+		return uuid.Nil, s.executeChangeset(ctx, cr, resolutions)
 	default:
-		return errors.New("unknown entity type")
+		return uuid.Nil, errors.New("unknown entity type")
+	}
+}
+
+func entityID(cr *domain.ChangeRequest) uuid.UUID {
+	if cr.EntityID == nil {
+		return uuid.Nil
 	}
+	return *cr.EntityID
 }
 
-func (s *service) executePersonChange(ctx context.Context, cr *domain.ChangeRequest) error {
+func (s *service) executePersonChange(ctx context.Context, cr *domain.ChangeRequest, resolutions map[string]json.RawMessage) (uuid.UUID, error) {
 	switch cr.Action {
 	case domain.ActionCreate:
-		var person domain.Person
-		if err := json.Unmarshal(cr.Payload, &person); err != nil {
-			return err
-		}
-		person.ID = uuid.New()
-		person.CreatedBy = cr.RequestedBy
-		if err := s.personRepo.Create(ctx, &person); err != nil {
-			return err
+		var input domain.CreatePersonInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return uuid.Nil, err
 		}
-
-		if s.notifSvc != nil {
-			go func() {
-				_ = s.notifSvc.NotifyPersonAdded(context.Background(), person.ID, cr.RequestedBy)
-			}()
+		// Possible duplicates were shown to the reviewer in the diff.
+		input.IgnoreDuplicates = true
+		p, err := s.personSvc.Create(ctx, cr.RequestedBy, input)
+		if err != nil {
+			return uuid.Nil, err
 		}
-		return nil
+		return p.ID, nil
 
 	case domain.ActionUpdate:
 		if cr.EntityID == nil {
-			return errors.New("entity_id required for update")
-		}
-		var updates domain.Person
-		if err := json.Unmarshal(cr.Payload, &updates); err != nil {
-			return err
+			return uuid.Nil, errors.New("entity_id required for update")
 		}
 		existing, err := s.personRepo.GetByID(ctx, *cr.EntityID)
 		if err != nil {
-			return err
+			return uuid.Nil, err
 		}
-		if existing.DeletedAt != nil {
-			return errors.New("cannot update deleted person")
+		if existing == nil || existing.DeletedAt != nil {
+			return uuid.Nil, errors.New("cannot update deleted person")
 		}
-		updates.ID = *cr.EntityID
-		return s.personRepo.Update(ctx, &updates)
+		patch, err := s.patchFor(cr, existing, existing.UpdatedAt, resolutions)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		input, err := personUpdateInput(patch)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		_, err = s.personSvc.Update(ctx, *cr.EntityID, cr.RequestedBy, input)
+		return *cr.EntityID, err
 
 	case domain.ActionDelete:
 		if cr.EntityID == nil {
-			return errors.New("entity_id required for delete")
+			return uuid.Nil, errors.New("entity_id required for delete")
+		}
+		return *cr.EntityID, s.personSvc.Delete(ctx, cr.RequestedBy, *cr.EntityID)
+
+	case domain.ActionMerge:
+		if cr.EntityID == nil {
+			return uuid.Nil, errors.New("entity_id required for merge")
+		}
+		var input domain.MergePersonInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return uuid.Nil, err
 		}
-		return s.personRepo.Delete(ctx, *cr.EntityID)
+		_, err := s.personSvc.Merge(ctx, cr.RequestedBy, *cr.EntityID, input)
+		return input.TargetID, err
 
 	default:
-		return errors.New("unknown action")
+		return uuid.Nil, errors.New("unknown action")
 	}
 }
 
-func (s *service) executeRelationshipChange(ctx context.Context, cr *domain.ChangeRequest) error {
+func (s *service) executeRelationshipChange(ctx context.Context, cr *domain.ChangeRequest, resolutions map[string]json.RawMessage) (uuid.UUID, error) {
 	switch cr.Action {
 	case domain.ActionCreate:
-		var input struct {
-			PersonA  uuid.UUID               `json:"person_a"`
-			PersonB  uuid.UUID               `json:"person_b"`
-			Type     domain.RelationshipType `json:"type"`
-			Metadata json.RawMessage         `json:"metadata,omitempty"`
-		}
+		var input domain.CreateRelationshipInput
 		if err := json.Unmarshal(cr.Payload, &input); err != nil {
-			return err
+			return uuid.Nil, err
 		}
-		rel := &domain.Relationship{
-			ID:        uuid.New(),
-			PersonA:   input.PersonA,
-			PersonB:   input.PersonB,
-			Type:      input.Type,
-			Metadata:  input.Metadata,
-			CreatedBy: cr.RequestedBy,
+		rel, err := s.relSvc.Create(ctx, cr.RequestedBy, input)
+		if err != nil {
+			return uuid.Nil, err
 		}
-		if err := s.relRepo.Create(ctx, rel); err != nil {
-			return err
+		return rel.ID, nil
+
+	case domain.ActionUpdate:
+		if cr.EntityID == nil {
+			return uuid.Nil, errors.New("entity_id required for update")
 		}
+		existing, err := s.relRepo.GetByID(ctx, *cr.EntityID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if existing == nil || existing.DeletedAt != nil {
+			return uuid.Nil, errors.New("cannot update deleted relationship")
+		}
+		patch, err := s.patchFor(cr, existing, existing.UpdatedAt, resolutions)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		var updates domain.Relationship
+		if err := domain.ApplyPatch(existing, patch, &updates); err != nil {
+			return uuid.Nil, err
+		}
+		// Only the metadata of a relationship can be edited.
+		_, err = s.relSvc.Update(ctx, cr.RequestedBy, *cr.EntityID, domain.UpdateRelationshipInput{Metadata: updates.Metadata})
+		return *cr.EntityID, err
 
-		if s.notifSvc != nil {
-			go func() {
-				_ = s.notifSvc.NotifyRelationshipAdded(context.Background(), rel.ID, cr.RequestedBy)
-			}()
+	case domain.ActionDelete:
+		if cr.EntityID == nil {
+			return uuid.Nil, errors.New("entity_id required for delete")
 		}
-		return nil
+		return *cr.EntityID, s.relSvc.Delete(ctx, cr.RequestedBy, *cr.EntityID)
+
+	default:
+		return uuid.Nil, errors.New("unknown action")
+	}
+}
+
+func (s *service) executeEventChange(ctx context.Context, cr *domain.ChangeRequest, resolutions map[string]json.RawMessage) (uuid.UUID, error) {
+	if s.eventSvc == nil {
+		return uuid.Nil, errors.New("event changes are not supported")
+	}
+	switch cr.Action {
+	case domain.ActionCreate:
+		var input domain.CreateEventInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return uuid.Nil, err
+		}
+		e, err := s.eventSvc.Create(ctx, cr.RequestedBy, input)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return e.ID, nil
 
 	case domain.ActionUpdate:
 		if cr.EntityID == nil {
-			return errors.New("entity_id required for update")
+			return uuid.Nil, errors.New("entity_id required for update")
 		}
-		var updates domain.Relationship
-		if err := json.Unmarshal(cr.Payload, &updates); err != nil {
-			return err
+		existing, err := s.eventSvc.GetByID(ctx, *cr.EntityID)
+		if errors.Is(err, domain.ErrEventNotFound) {
+			return uuid.Nil, errors.New("cannot update deleted event")
 		}
-		existing, err := s.relRepo.GetByID(ctx, *cr.EntityID)
 		if err != nil {
-			return err
+			return uuid.Nil, err
+		}
+		patch, err := s.patchFor(cr, existing, existing.UpdatedAt, resolutions)
+		if err != nil {
+			return uuid.Nil, err
 		}
-		if existing.DeletedAt != nil {
-			return errors.New("cannot update deleted relationship")
+		input, err := eventUpdateInput(existing, patch)
+		if err != nil {
+			return uuid.Nil, err
 		}
-		updates.ID = *cr.EntityID
-		return s.relRepo.Update(ctx, &updates)
+		_, err = s.eventSvc.Update(ctx, cr.RequestedBy, *cr.EntityID, input)
+		return *cr.EntityID, err
 
 	case domain.ActionDelete:
 		if cr.EntityID == nil {
-			return errors.New("entity_id required for delete")
+			return uuid.Nil, errors.New("entity_id required for delete")
 		}
-		return s.relRepo.Delete(ctx, *cr.EntityID)
+		return *cr.EntityID, s.eventSvc.Delete(ctx, cr.RequestedBy, *cr.EntityID)
 
 	default:
-		return errors.New("unknown action")
+		return uuid.Nil, errors.New("unknown action")
+	}
+}
+
+// requiredPersonFields are the person fields an update may change but not
+// clear. UpdatePersonInput reads null on them as "unchanged", so a null
+// there is refused rather than dropped.
+var requiredPersonFields = []string{"first_name", "is_alive", "privacy_level"}
+
+// personUpdateInput reads a person patch as an update input; person fields
+// are flat, so the patch maps onto it field by field.
+func personUpdateInput(patch map[string]any) (domain.UpdatePersonInput, error) {
+	var input domain.UpdatePersonInput
+	for _, field := range requiredPersonFields {
+		if v, ok := patch[field]; ok && v == nil {
+			return input, fmt.Errorf("%w: %s cannot be cleared", ErrInvalidChange, field)
+		}
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return input, err
 	}
+	err = json.Unmarshal(data, &input)
+	return input, err
+}
+
+// executeCommentChange applies a comment change as the requester, who must
+// be the author of a comment they edit or delete.
+func (s *service) executeCommentChange(ctx context.Context, cr *domain.ChangeRequest) (uuid.UUID, error) {
+	if s.commentSvc == nil {
+		return uuid.Nil, errors.New("comment changes are not supported")
+	}
+	switch cr.Action {
+	case domain.ActionCreate:
+		var input domain.ProposeCommentInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return uuid.Nil, err
+		}
+		c, err := s.commentSvc.Create(ctx, input.PersonID, cr.RequestedBy, input.CreateCommentInput)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return c.ID, nil
+
+	case domain.ActionUpdate:
+		if cr.EntityID == nil {
+			return uuid.Nil, errors.New("entity_id required for update")
+		}
+		var input domain.UpdateCommentInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return uuid.Nil, err
+		}
+		_, err := s.commentSvc.Update(ctx, cr.RequestedBy, *cr.EntityID, input)
+		return *cr.EntityID, err
+
+	case domain.ActionDelete:
+		if cr.EntityID == nil {
+			return uuid.Nil, errors.New("entity_id required for delete")
+		}
+		return *cr.EntityID, s.commentSvc.Delete(ctx, cr.RequestedBy, *cr.EntityID)
+
+	default:
+		return uuid.Nil, errors.New("unknown action")
+	}
+}
+
+// eventUpdateInput turns a patch of current into an update input. The
+// patch may touch single metadata keys, so the input carries each top-level
+// field the patch touches, as it is on the patched event.
+func eventUpdateInput(current *domain.Event, patch map[string]any) (domain.UpdateEventInput, error) {
+	var input domain.UpdateEventInput
+	var updated domain.Event
+	if err := domain.ApplyPatch(current, patch, &updated); err != nil {
+		return input, err
+	}
+	data, err := json.Marshal(updated)
+	if err != nil {
+		return input, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return input, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	for k := range patch {
+		field, _, _ := strings.Cut(k, ".")
+		fields[field] = all[field]
+	}
+	if _, ok := fields["metadata"]; ok && len(updated.Metadata) == 0 {
+		fields["metadata"] = json.RawMessage(`{}`)
+	}
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return input, err
+	}
+	err = json.Unmarshal(data, &input)
+	return input, err
 }
 
 func (s *service) executeMediaChange(ctx context.Context, cr *domain.ChangeRequest) error {
@@ -372,27 +715,35 @@ func (s *service) executeMediaChange(ctx context.Context, cr *domain.ChangeReque
 		}
 		return s.mediaSvc.Approve(ctx, *cr.EntityID)
 
+	case domain.ActionUpdate:
+		if cr.EntityID == nil {
+			return errors.New("entity_id required for update")
+		}
+		var input domain.UpdateMediaInput
+		if err := json.Unmarshal(cr.Payload, &input); err != nil {
+			return err
+		}
+		_, err := s.mediaSvc.Update(ctx, *cr.EntityID, input)
+		return err
+
 	case domain.ActionDelete:
 		if cr.EntityID == nil {
 			return errors.New("entity_id required for delete")
 		}
-		return s.mediaRepo.Delete(ctx, *cr.EntityID)
+		return s.mediaRepo.Delete(ctx, *cr.EntityID, cr.RequestedBy)
 
 	default:
 		return errors.New("unsupported action for media")
 	}
 }
 
-func (s *service) notifyRequester(ctx context.Context, cr *domain.ChangeRequest, status domain.ChangeRequestStatus, reviewerID uuid.UUID, note *string) {
-	if s.notifSvc != nil {
-		go func() {
-			if status == domain.StatusApproved {
-				_ = s.notifSvc.NotifyChangeApproved(context.Background(), cr.ID, reviewerID)
-			} else {
-				_ = s.notifSvc.NotifyChangeRejected(context.Background(), cr.ID, reviewerID)
-			}
-		}()
-		return
+func (s *service) notifyRequester(ctx context.Context, cr *domain.ChangeRequest, status domain.ChangeRequestStatus, reviewerID uuid.UUID, note *string) error {
+	if s.notifSvc != nil || s.outbox != nil {
+		notifType := domain.NotifChangeApproved
+		if status == domain.StatusRejected {
+			notifType = domain.NotifChangeRejected
+		}
+		return s.notify(ctx, notifType, cr.ID, reviewerID)
 	}
 
 	var title, message string
@@ -421,10 +772,12 @@ func (s *service) notifyRequester(ctx context.Context, cr *domain.ChangeRequest,
 		notif.Type = domain.NotifChangeRejected
 	}
 
-	_ = s.notifRepo.Create(ctx, notif)
+	return s.notifRepo.Create(ctx, notif)
 }
 
-func (s *service) logAudit(ctx context.Context, reviewerID uuid.UUID, action string, cr *domain.ChangeRequest, meta *RequestMeta) {
+// logAudit records that userID moved the change request from status from
+// to its current status.
+func (s *service) logAudit(ctx context.Context, userID uuid.UUID, action string, cr *domain.ChangeRequest, from domain.ChangeRequestStatus, meta *RequestMeta) error {
 	var entityID uuid.UUID
 	if cr.EntityID != nil {
 		entityID = *cr.EntityID
@@ -434,11 +787,11 @@ func (s *service) logAudit(ctx context.Context, reviewerID uuid.UUID, action str
 
 	audit := &domain.AuditLog{
 		ID:         uuid.New(),
-		UserID:     reviewerID,
+		UserID:     userID,
 		Action:     action,
 		EntityType: string(cr.EntityType),
 		EntityID:   entityID,
-		OldValue:   json.RawMessage(`{"status":"PENDING"}`),
+		OldValue:   json.RawMessage(`{"status":"` + string(from) + `"}`),
 		NewValue:   json.RawMessage(`{"status":"` + string(cr.Status) + `"}`),
 		CreatedAt:  time.Now(),
 	}
@@ -452,5 +805,5 @@ func (s *service) logAudit(ctx context.Context, reviewerID uuid.UUID, action str
 		}
 	}
 
-	_ = s.auditRepo.Create(ctx, audit)
+	return s.auditRepo.Create(ctx, audit)
 }
@@ -0,0 +1,202 @@
+package changerequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/event"
+	"silsilah-keluarga/internal/service/relationship"
+)
+
+// ErrInvalidChange wraps the reason a change request breaks a rule a
+// direct edit would be held to, at submission or at approval.
+var ErrInvalidChange = errors.New("invalid change")
+
+// validationErrors are the errors the entity services return for input
+// that can never be applied as it stands.
+var validationErrors = []error{
+	domain.ErrInvalidLifeDates,
+	domain.ErrInvalidPrivacyLevel,
+	domain.ErrInvalidGenDate,
+	domain.ErrPlaceNotFound,
+	domain.ErrPersonNotFound,
+	domain.ErrMergeSamePerson,
+	domain.ErrInvalidMergeField,
+	domain.ErrMergeLinkedUsers,
+	relationship.ErrSelfRelation,
+	relationship.ErrInvalidRelationType,
+	relationship.ErrRelationshipCycle,
+	relationship.ErrParentYounger,
+	relationship.ErrDuplicateRelationship,
+	relationship.ErrRelationshipNotFound,
+	domain.ErrInvalidEventType,
+	domain.ErrInvalidEventMetadata,
+	event.ErrMissingSubject,
+	event.ErrPersonRequired,
+	event.ErrRelationshipRequired,
+	event.ErrRelationshipNotFound,
+	domain.ErrEventNotFound,
+	domain.ErrMediaNotFound,
+	domain.ErrInvalidMediaEdit,
+	domain.ErrInvalidChangeset,
+	domain.ErrInvalidComment,
+	domain.ErrCommentNotFound,
+	domain.ErrNotCommentAuthor,
+}
+
+// invalidChange wraps validation errors in ErrInvalidChange and returns
+// others unchanged.
+func invalidChange(err error) error {
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return fmt.Errorf("%w: %w", ErrInvalidChange, err)
+	}
+	for _, target := range validationErrors {
+		if errors.Is(err, target) {
+			return fmt.Errorf("%w: %w", ErrInvalidChange, err)
+		}
+	}
+	return err
+}
+
+// validateChange runs the checks the entity services apply to a direct
+// edit against the proposed change, so a request that could never be
+// approved is refused when it is filed.
+func (s *service) validateChange(ctx context.Context, requester uuid.UUID, input domain.CreateChangeRequestInput) error {
+	switch input.EntityType {
+	case domain.EntityPerson:
+		if s.personSvc == nil {
+			return nil
+		}
+		switch input.Action {
+		case domain.ActionCreate:
+			var create domain.CreatePersonInput
+			if err := json.Unmarshal(input.Payload, &create); err != nil {
+				return invalidChange(err)
+			}
+			return invalidChange(s.personSvc.ValidateCreate(ctx, create))
+		case domain.ActionUpdate:
+			var patch map[string]any
+			if err := json.Unmarshal(input.Payload, &patch); err != nil {
+				return invalidChange(err)
+			}
+			update, err := personUpdateInput(patch)
+			if err != nil {
+				return invalidChange(err)
+			}
+			return invalidChange(s.personSvc.ValidateUpdate(ctx, *input.EntityID, update))
+		case domain.ActionMerge:
+			var merge domain.MergePersonInput
+			if err := json.Unmarshal(input.Payload, &merge); err != nil {
+				return invalidChange(err)
+			}
+			return invalidChange(s.personSvc.ValidateMerge(ctx, *input.EntityID, merge))
+		}
+
+	case domain.EntityRelationship:
+		if s.relSvc == nil {
+			return nil
+		}
+		switch input.Action {
+		case domain.ActionCreate:
+			var create domain.CreateRelationshipInput
+			if err := json.Unmarshal(input.Payload, &create); err != nil {
+				return invalidChange(err)
+			}
+			return invalidChange(s.relSvc.ValidateCreate(ctx, create))
+		case domain.ActionUpdate:
+			var update domain.UpdateRelationshipInput
+			if err := json.Unmarshal(input.Payload, &update); err != nil {
+				return invalidChange(err)
+			}
+			return invalidChange(s.relSvc.ValidateUpdate(ctx, *input.EntityID, update))
+		}
+
+	case domain.EntityEvent:
+		if s.eventSvc == nil {
+			return nil
+		}
+		switch input.Action {
+		case domain.ActionCreate:
+			var create domain.CreateEventInput
+			if err := decodePayload(input.Payload, &create); err != nil {
+				return err
+			}
+			return invalidChange(s.eventSvc.ValidateCreate(ctx, create))
+		case domain.ActionUpdate:
+			var update domain.UpdateEventInput
+			if err := decodePayload(input.Payload, &update); err != nil {
+				return err
+			}
+			return invalidChange(s.eventSvc.ValidateUpdate(ctx, *input.EntityID, update))
+		case domain.ActionDelete:
+			_, err := s.eventSvc.GetByID(ctx, *input.EntityID)
+			return invalidChange(err)
+		}
+
+	case domain.EntityMedia:
+		if s.mediaSvc == nil || input.Action != domain.ActionUpdate {
+			return nil
+		}
+		var update domain.UpdateMediaInput
+		if err := decodePayload(input.Payload, &update); err != nil {
+			return err
+		}
+		return invalidChange(s.mediaSvc.ValidateUpdate(ctx, *input.EntityID, update))
+
+	case domain.EntityComment:
+		if s.commentSvc == nil {
+			return nil
+		}
+		switch input.Action {
+		case domain.ActionCreate:
+			var create domain.ProposeCommentInput
+			if err := decodePayload(input.Payload, &create); err != nil {
+				return err
+			}
+			person, err := s.personRepo.GetByID(ctx, create.PersonID)
+			if err != nil {
+				return err
+			}
+			if person == nil {
+				return invalidChange(domain.ErrPersonNotFound)
+			}
+			return invalidChange(s.commentSvc.ValidateCreate(ctx, create.PersonID, create.CreateCommentInput))
+		case domain.ActionUpdate:
+			var update domain.UpdateCommentInput
+			if err := decodePayload(input.Payload, &update); err != nil {
+				return err
+			}
+			return invalidChange(s.commentSvc.ValidateUpdate(ctx, requester, *input.EntityID, update))
+		case domain.ActionDelete:
+			return invalidChange(s.commentSvc.ValidateDelete(ctx, requester, *input.EntityID))
+		}
+
+	case domain.EntityChangeset:
+		return s.validateChangeset(ctx, requester, input)
+	}
+	return nil
+}
+
+// decodePayload decodes the payload of an event, media or comment request,
+// refusing fields the input does not have, so a misspelt field is
+// reported when the request is filed rather than dropped when it is
+// approved.
+func decodePayload(payload json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidChange, err)
+	}
+	return nil
+}
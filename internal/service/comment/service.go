@@ -3,7 +3,6 @@ package comment
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"time"
 
@@ -22,6 +21,12 @@ type Service interface {
 	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCommentInput) (*domain.Comment, error)
 	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 	ListByPerson(ctx context.Context, personID uuid.UUID, params domain.PaginationParams) (domain.PaginatedResponse[domain.Comment], error)
+	// ValidateCreate, ValidateUpdate and ValidateDelete run the checks of
+	// Create, Update and Delete without saving, for changes that are
+	// applied later.
+	ValidateCreate(ctx context.Context, personID uuid.UUID, input domain.CreateCommentInput) error
+	ValidateUpdate(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCommentInput) error
+	ValidateDelete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 }
 
 type service struct {
@@ -42,10 +47,15 @@ func (s *service) SetNotificationService(notifSvc notification.Service) {
 }
 
 func (s *service) Create(ctx context.Context, personID, userID uuid.UUID, input domain.CreateCommentInput) (*domain.Comment, error) {
+	if err := s.ValidateCreate(ctx, personID, input); err != nil {
+		return nil, err
+	}
+
 	comment := &domain.Comment{
 		ID:       uuid.New(),
 		PersonID: personID,
 		UserID:   userID,
+		ParentID: input.ParentID,
 		Content:  input.Content,
 	}
 
@@ -75,16 +85,12 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Comment, e
 }
 
 func (s *service) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCommentInput) (*domain.Comment, error) {
-	comment, err := s.commentRepo.GetByID(ctx, id)
-	if err != nil {
+	if err := input.Validate(); err != nil {
 		return nil, err
 	}
-	if comment == nil {
-		return nil, errors.New("comment not found")
-	}
-
-	if comment.UserID != userID {
-		return nil, errors.New("insufficient permissions to edit this comment")
+	comment, err := s.ownComment(ctx, userID, id)
+	if err != nil {
+		return nil, err
 	}
 
 	comment.Content = input.Content
@@ -105,17 +111,10 @@ func (s *service) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, in
 }
 
 func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
-	comment, err := s.commentRepo.GetByID(ctx, id)
+	comment, err := s.ownComment(ctx, userID, id)
 	if err != nil {
 		return err
 	}
-	if comment == nil {
-		return errors.New("comment not found")
-	}
-
-	if comment.UserID != userID {
-		return errors.New("insufficient permissions to delete this comment")
-	}
 	if s.redis != nil {
 		cachePattern := fmt.Sprintf("comments:%s:*", comment.PersonID)
 		keys, _ := s.redis.Keys(ctx, cachePattern).Result()
@@ -124,7 +123,52 @@ func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) er
 		}
 	}
 
-	return s.commentRepo.Delete(ctx, id)
+	return s.commentRepo.Delete(ctx, id, userID)
+}
+
+func (s *service) ValidateCreate(ctx context.Context, personID uuid.UUID, input domain.CreateCommentInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	if input.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *input.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent == nil || parent.PersonID != personID {
+			return domain.ErrCommentNotFound
+		}
+	}
+	return nil
+}
+
+func (s *service) ValidateUpdate(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCommentInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	_, err := s.ownComment(ctx, userID, id)
+	return err
+}
+
+func (s *service) ValidateDelete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	_, err := s.ownComment(ctx, userID, id)
+	return err
+}
+
+// ownComment loads a comment that userID wrote; only the author may edit
+// or delete it.
+func (s *service) ownComment(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, domain.ErrCommentNotFound
+	}
+	if comment.UserID != userID {
+		return nil, domain.ErrNotCommentAuthor
+	}
+	return comment, nil
 }
 
 func (s *service) ListByPerson(ctx context.Context, personID uuid.UUID, params domain.PaginationParams) (domain.PaginatedResponse[domain.Comment], error) {
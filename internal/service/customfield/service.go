@@ -0,0 +1,300 @@
+package customfield
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+type Service interface {
+	CreateField(ctx context.Context, userID uuid.UUID, input domain.CreateCustomFieldInput) (*domain.CustomField, error)
+	GetField(ctx context.Context, id uuid.UUID) (*domain.CustomField, error)
+	UpdateField(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCustomFieldInput) (*domain.CustomField, error)
+	DeleteField(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	ListFields(ctx context.Context, role domain.UserRole) ([]domain.CustomField, error)
+
+	GetPersonValues(ctx context.Context, personID uuid.UUID, viewer domain.Viewer) ([]domain.PersonCustomValue, error)
+	SetPersonValue(ctx context.Context, userID uuid.UUID, personID, fieldID uuid.UUID, input domain.SetCustomValueInput) (*domain.PersonCustomValue, error)
+	DeletePersonValue(ctx context.Context, userID uuid.UUID, personID, fieldID uuid.UUID) error
+}
+
+type service struct {
+	customFieldRepo repository.CustomFieldRepository
+	personRepo      repository.PersonRepository
+	auditRepo       repository.AuditLogRepository
+}
+
+func NewService(customFieldRepo repository.CustomFieldRepository, personRepo repository.PersonRepository, auditRepo repository.AuditLogRepository) Service {
+	return &service{
+		customFieldRepo: customFieldRepo,
+		personRepo:      personRepo,
+		auditRepo:       auditRepo,
+	}
+}
+
+func (s *service) CreateField(ctx context.Context, userID uuid.UUID, input domain.CreateCustomFieldInput) (*domain.CustomField, error) {
+	field := &domain.CustomField{
+		ID:          uuid.New(),
+		Key:         strings.TrimSpace(input.Key),
+		Label:       strings.TrimSpace(input.Label),
+		Description: input.Description,
+		Type:        input.Type,
+		Options:     input.Options,
+		Rules:       input.Rules,
+		Visibility:  input.Visibility,
+		SortOrder:   input.SortOrder,
+		CreatedBy:   userID,
+	}
+	if field.Visibility == "" {
+		field.Visibility = domain.RoleMember
+	}
+	if field.Options == nil {
+		field.Options = []string{}
+	}
+	if err := field.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.customFieldRepo.GetFieldByKey(ctx, field.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrCustomFieldKeyTaken
+	}
+
+	if err := s.customFieldRepo.CreateField(ctx, field); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "CREATE",
+		EntityType: "CUSTOM_FIELD",
+		EntityID:   field.ID,
+		NewValue:   field,
+	})
+
+	return field, nil
+}
+
+func (s *service) GetField(ctx context.Context, id uuid.UUID) (*domain.CustomField, error) {
+	field, err := s.customFieldRepo.GetFieldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if field == nil {
+		return nil, domain.ErrCustomFieldNotFound
+	}
+	return field, nil
+}
+
+func (s *service) UpdateField(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCustomFieldInput) (*domain.CustomField, error) {
+	field, err := s.GetField(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	old := *field
+
+	if input.Label != nil {
+		field.Label = strings.TrimSpace(*input.Label)
+	}
+	if input.Description != nil {
+		field.Description = input.Description
+	}
+	if input.Options != nil {
+		field.Options = input.Options
+	}
+	if input.Rules != nil {
+		field.Rules = *input.Rules
+	}
+	if input.Visibility != nil {
+		field.Visibility = *input.Visibility
+	}
+	if input.SortOrder != nil {
+		field.SortOrder = *input.SortOrder
+	}
+	// Stored values are not re-validated: tightening a rule or dropping an
+	// option only applies to values set from now on.
+	if err := field.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.customFieldRepo.UpdateField(ctx, field); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "CUSTOM_FIELD",
+		EntityID:   field.ID,
+		OldValue:   old,
+		NewValue:   field,
+	})
+
+	return field, nil
+}
+
+func (s *service) DeleteField(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	field, err := s.GetField(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.customFieldRepo.DeleteField(ctx, id); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "DELETE",
+		EntityType: "CUSTOM_FIELD",
+		EntityID:   id,
+		OldValue:   field,
+	})
+
+	return nil
+}
+
+// ListFields returns the field definitions whose values the role may see.
+func (s *service) ListFields(ctx context.Context, role domain.UserRole) ([]domain.CustomField, error) {
+	fields, err := s.customFieldRepo.ListFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []domain.CustomField{}
+	for _, f := range fields {
+		if f.VisibleTo(role) {
+			visible = append(visible, f)
+		}
+	}
+	return visible, nil
+}
+
+// GetPersonValues returns the values the viewer may see. A living person
+// hidden from the viewer shows none, as with Person.Redact.
+func (s *service) GetPersonValues(ctx context.Context, personID uuid.UUID, viewer domain.Viewer) ([]domain.PersonCustomValue, error) {
+	person, err := s.getPerson(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person.ProbablyLiving(time.Now()) && !viewer.CanSee(person.ID, person.PrivacyLevel) {
+		return []domain.PersonCustomValue{}, nil
+	}
+
+	values, err := s.customFieldRepo.GetValuesByPerson(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.VisibleCustomValues(values, viewer.Role), nil
+}
+
+func (s *service) SetPersonValue(ctx context.Context, userID uuid.UUID, personID, fieldID uuid.UUID, input domain.SetCustomValueInput) (*domain.PersonCustomValue, error) {
+	if _, err := s.getPerson(ctx, personID); err != nil {
+		return nil, err
+	}
+	field, err := s.GetField(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := field.ParseValue(input.Value)
+	if err != nil {
+		return nil, err
+	}
+	if value.PersonRef != nil {
+		if *value.PersonRef == personID {
+			return nil, domain.ErrCustomValueSelfRef
+		}
+		ref, err := s.personRepo.GetByID(ctx, *value.PersonRef)
+		if err != nil {
+			return nil, err
+		}
+		if ref == nil {
+			return nil, fmt.Errorf("%w: referenced person not found", domain.ErrInvalidCustomValue)
+		}
+		value.Display = ref.FirstName
+		if ref.LastName != nil {
+			value.Display += " " + *ref.LastName
+		}
+	}
+	value.PersonID = personID
+	value.UpdatedBy = userID
+
+	old, err := s.findValue(ctx, personID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.customFieldRepo.SetValue(ctx, value); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: string(domain.EntityPerson),
+		EntityID:   personID,
+		OldValue:   old,
+		NewValue:   value,
+	})
+
+	return value, nil
+}
+
+func (s *service) DeletePersonValue(ctx context.Context, userID uuid.UUID, personID, fieldID uuid.UUID) error {
+	old, err := s.findValue(ctx, personID, fieldID)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return domain.ErrCustomValueNotFound
+	}
+
+	if err := s.customFieldRepo.DeleteValue(ctx, personID, fieldID); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: string(domain.EntityPerson),
+		EntityID:   personID,
+		OldValue:   old,
+	})
+
+	return nil
+}
+
+func (s *service) getPerson(ctx context.Context, personID uuid.UUID) (*domain.Person, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, domain.ErrPersonNotFound
+	}
+	return person, nil
+}
+
+// findValue returns the stored value of the field for the person, or nil.
+func (s *service) findValue(ctx context.Context, personID, fieldID uuid.UUID) (*domain.PersonCustomValue, error) {
+	values, err := s.customFieldRepo.GetValuesByPerson(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range values {
+		if values[i].FieldID == fieldID {
+			return &values[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,201 @@
+package duplicate
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+// DefaultMinScore is the report cut-off when the caller gives none.
+const DefaultMinScore = 0.5
+
+type Service interface {
+	FindForPerson(ctx context.Context, personID uuid.UUID, limit int) ([]domain.DuplicateCandidate, error)
+	Report(ctx context.Context, minScore float64, limit int) ([]domain.DuplicatePair, error)
+	CheckNew(ctx context.Context, input domain.CreatePersonInput) ([]domain.DuplicateCandidate, error)
+}
+
+type service struct {
+	personRepo repository.PersonRepository
+	relRepo    repository.RelationshipRepository
+}
+
+func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository) Service {
+	return &service{
+		personRepo: personRepo,
+		relRepo:    relRepo,
+	}
+}
+
+// FindForPerson scores the persons whose names resemble the given person's.
+// Persons directly related to them are never reported: a son named after
+// his father is not a duplicate.
+func (s *service) FindForPerson(ctx context.Context, personID uuid.UUID, limit int) ([]domain.DuplicateCandidate, error) {
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, domain.ErrPersonNotFound
+	}
+
+	similar, err := s.personRepo.FindSimilar(ctx, person.FullName(), personID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uuid.UUID{personID}
+	for _, sp := range similar {
+		ids = append(ids, sp.ID)
+	}
+	relatives, err := s.relatives(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []domain.DuplicateCandidate{}
+	for _, sp := range similar {
+		if relatives[personID][sp.ID] {
+			continue
+		}
+		score, signals := domain.ScoreDuplicate(person, &sp.Person, sp.Similarity, sharedCount(relatives[personID], relatives[sp.ID]))
+		candidates = append(candidates, domain.DuplicateCandidate{Person: sp.Person, Score: score, Signals: signals})
+	}
+
+	sortCandidates(candidates)
+	return candidates, nil
+}
+
+// Report lists likely duplicate pairs across the tree scoring at least
+// minScore, best first.
+func (s *service) Report(ctx context.Context, minScore float64, limit int) ([]domain.DuplicatePair, error) {
+	if minScore <= 0 {
+		minScore = DefaultMinScore
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	// Scoring can only demote pairs, so fetch a wider pool than requested.
+	similar, err := s.personRepo.FindSimilarPairs(ctx, limit*4)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, sp := range similar {
+		for _, id := range []uuid.UUID{sp.PersonA, sp.PersonB} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	persons, err := s.personRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.Person, len(persons))
+	for i := range persons {
+		byID[persons[i].ID] = &persons[i]
+	}
+
+	relatives, err := s.relatives(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []domain.DuplicatePair{}
+	for _, sp := range similar {
+		a, b := byID[sp.PersonA], byID[sp.PersonB]
+		if a == nil || b == nil || relatives[a.ID][b.ID] {
+			continue
+		}
+		score, signals := domain.ScoreDuplicate(a, b, sp.Similarity, sharedCount(relatives[a.ID], relatives[b.ID]))
+		if score < minScore {
+			continue
+		}
+		pairs = append(pairs, domain.DuplicatePair{PersonA: *a, PersonB: *b, Score: score, Signals: signals})
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Score > pairs[j].Score
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs, nil
+}
+
+// CheckNew returns existing persons that a person about to be created would
+// likely duplicate, i.e. those scoring at least DuplicateWarningThreshold.
+func (s *service) CheckNew(ctx context.Context, input domain.CreatePersonInput) ([]domain.DuplicateCandidate, error) {
+	person := &domain.Person{
+		FirstName:  input.FirstName,
+		LastName:   input.LastName,
+		Gender:     input.Gender,
+		BirthDate:  input.BirthDate,
+		BirthPlace: input.BirthPlace,
+		DeathDate:  input.DeathDate,
+	}
+
+	similar, err := s.personRepo.FindSimilar(ctx, person.FullName(), uuid.Nil, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []domain.DuplicateCandidate
+	for _, sp := range similar {
+		score, signals := domain.ScoreDuplicate(person, &sp.Person, sp.Similarity, 0)
+		if score >= domain.DuplicateWarningThreshold {
+			candidates = append(candidates, domain.DuplicateCandidate{Person: sp.Person, Score: score, Signals: signals})
+		}
+	}
+
+	sortCandidates(candidates)
+	return candidates, nil
+}
+
+// relatives maps each of the given persons to the set of persons they have
+// a parent, child or spouse relationship with.
+func (s *service) relatives(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]map[uuid.UUID]bool, error) {
+	rels, err := s.relRepo.ListByPeople(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]map[uuid.UUID]bool, len(ids))
+	link := func(from, to uuid.UUID) {
+		if result[from] == nil {
+			result[from] = make(map[uuid.UUID]bool)
+		}
+		result[from][to] = true
+	}
+	for _, r := range rels {
+		link(r.PersonA, r.PersonB)
+		link(r.PersonB, r.PersonA)
+	}
+	return result, nil
+}
+
+func sharedCount(a, b map[uuid.UUID]bool) int {
+	n := 0
+	for id := range a {
+		if b[id] {
+			n++
+		}
+	}
+	return n
+}
+
+func sortCandidates(candidates []domain.DuplicateCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
@@ -0,0 +1,322 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/i18n"
+	"silsilah-keluarga/internal/repository"
+	"silsilah-keluarga/internal/service/helpers"
+)
+
+var (
+	ErrMissingSubject       = errors.New("event must belong to a person or a relationship")
+	ErrRelationshipRequired = errors.New("event type must be attached to a spouse relationship")
+	ErrPersonRequired       = errors.New("event type must be attached to a person")
+	ErrRelationshipNotFound = errors.New("relationship not found")
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, input domain.CreateEventInput) (*domain.Event, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error)
+	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateEventInput) (*domain.Event, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Event, error)
+	ListByRelationship(ctx context.Context, relationshipID uuid.UUID) ([]domain.Event, error)
+	ListTypes(locale string) []domain.EventTypeDefinition
+	// ValidateCreate and ValidateUpdate run the checks of Create and Update
+	// without saving, for changes that are applied later.
+	ValidateCreate(ctx context.Context, input domain.CreateEventInput) error
+	ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateEventInput) error
+}
+
+type service struct {
+	eventRepo  repository.EventRepository
+	personRepo repository.PersonRepository
+	relRepo    repository.RelationshipRepository
+	placeRepo  repository.PlaceRepository
+	auditRepo  repository.AuditLogRepository
+}
+
+func NewService(eventRepo repository.EventRepository, personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, placeRepo repository.PlaceRepository, auditRepo repository.AuditLogRepository) Service {
+	return &service{
+		eventRepo:  eventRepo,
+		personRepo: personRepo,
+		relRepo:    relRepo,
+		placeRepo:  placeRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.CreateEventInput) (*domain.Event, error) {
+	event, err := s.buildNew(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "CREATE",
+		EntityType: "EVENT",
+		EntityID:   event.ID,
+		NewValue:   event,
+	})
+
+	return event, nil
+}
+
+func (s *service) ValidateCreate(ctx context.Context, input domain.CreateEventInput) error {
+	_, err := s.buildNew(ctx, uuid.Nil, input)
+	return err
+}
+
+// buildNew turns the input into an event and checks it.
+func (s *service) buildNew(ctx context.Context, userID uuid.UUID, input domain.CreateEventInput) (*domain.Event, error) {
+	def, ok := input.Type.Definition()
+	if !ok {
+		return nil, domain.ErrInvalidEventType
+	}
+
+	if err := s.validateSubject(ctx, def, input.PersonID, input.RelationshipID); err != nil {
+		return nil, err
+	}
+
+	if err := def.ValidateMetadata(input.Metadata); err != nil {
+		return nil, err
+	}
+
+	event := &domain.Event{
+		ID:             uuid.New(),
+		PersonID:       input.PersonID,
+		RelationshipID: input.RelationshipID,
+		Type:           input.Type,
+		Title:          input.Title,
+		Date:           input.Date,
+		Place:          input.Place,
+		PlaceID:        input.PlaceID,
+		Description:    input.Description,
+		Metadata:       normalizeMetadata(input.Metadata),
+		CreatedBy:      userID,
+	}
+	if err := s.resolvePlace(ctx, event, false); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// resolvePlace checks the referenced place and fills the free-text place
+// from its path when empty, or always when overwrite is set.
+func (s *service) resolvePlace(ctx context.Context, event *domain.Event, overwrite bool) error {
+	if event.PlaceID == nil {
+		return nil
+	}
+	name, err := helpers.PlaceFullName(ctx, s.placeRepo, *event.PlaceID)
+	if err != nil {
+		return err
+	}
+	if overwrite || event.Place == nil || *event.Place == "" {
+		event.Place = &name
+	}
+	return nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, domain.ErrEventNotFound
+	}
+	return event, nil
+}
+
+func (s *service) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateEventInput) (*domain.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, domain.ErrEventNotFound
+	}
+
+	oldEvent := *event
+	if err := s.applyUpdate(ctx, event, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Update(ctx, event); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "EVENT",
+		EntityID:   event.ID,
+		OldValue:   oldEvent,
+		NewValue:   *event,
+	})
+
+	return event, nil
+}
+
+func (s *service) ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateEventInput) error {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return domain.ErrEventNotFound
+	}
+	return s.applyUpdate(ctx, event, input)
+}
+
+// applyUpdate writes the fields set in input onto event and checks the
+// result.
+func (s *service) applyUpdate(ctx context.Context, event *domain.Event, input domain.UpdateEventInput) error {
+	oldType := event.Type
+	if input.Type != nil {
+		event.Type = *input.Type
+	}
+	if input.Title != nil {
+		event.Title = *input.Title
+	}
+	if input.Date.Set {
+		event.Date = input.Date.Value
+	}
+	if input.Place.Set {
+		event.Place = input.Place.Value
+	}
+	if input.PlaceID.Set {
+		event.PlaceID = input.PlaceID.Value
+		if err := s.resolvePlace(ctx, event, !input.Place.Set); err != nil {
+			return err
+		}
+	}
+	if input.Description.Set {
+		event.Description = input.Description.Value
+	}
+	if input.Metadata != nil {
+		event.Metadata = normalizeMetadata(input.Metadata)
+	}
+
+	def, ok := event.Type.Definition()
+	if !ok {
+		return domain.ErrInvalidEventType
+	}
+	if event.Type != oldType {
+		if err := s.validateSubject(ctx, def, event.PersonID, event.RelationshipID); err != nil {
+			return err
+		}
+	}
+	return def.ValidateMetadata(event.Metadata)
+}
+
+// normalizeMetadata stores absent or null metadata as an empty object, which
+// is how ValidateMetadata already reads it.
+func normalizeMetadata(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 || string(raw) == "null" {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return domain.ErrEventNotFound
+	}
+
+	if err := s.eventRepo.Delete(ctx, id, userID); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "DELETE",
+		EntityType: "EVENT",
+		EntityID:   id,
+		OldValue:   event,
+	})
+
+	return nil
+}
+
+func (s *service) ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Event, error) {
+	return s.eventRepo.ListByPerson(ctx, personID)
+}
+
+func (s *service) ListByRelationship(ctx context.Context, relationshipID uuid.UUID) ([]domain.Event, error) {
+	return s.eventRepo.ListByRelationship(ctx, relationshipID)
+}
+
+func (s *service) ListTypes(locale string) []domain.EventTypeDefinition {
+	defs := domain.EventTypeDefinitions()
+	for i := range defs {
+		key := defs[i].LabelKey()
+		defs[i].Label = i18n.Translate(locale, key)
+		if defs[i].Label != key {
+			continue
+		}
+		if label, ok := defs[i].Labels[locale]; ok {
+			defs[i].Label = label
+		} else if label, ok := defs[i].Labels["en"]; ok {
+			defs[i].Label = label
+		}
+	}
+	return defs
+}
+
+func (s *service) validateSubject(ctx context.Context, def domain.EventTypeDefinition, personID, relationshipID *uuid.UUID) error {
+	if personID == nil && relationshipID == nil {
+		return ErrMissingSubject
+	}
+
+	switch def.Scope {
+	case domain.EventScopePerson:
+		if personID == nil {
+			return ErrPersonRequired
+		}
+	case domain.EventScopeRelationship:
+		if relationshipID == nil {
+			return ErrRelationshipRequired
+		}
+	}
+
+	if personID != nil {
+		person, err := s.personRepo.GetByID(ctx, *personID)
+		if err != nil {
+			return err
+		}
+		if person == nil {
+			return domain.ErrPersonNotFound
+		}
+	}
+
+	if relationshipID != nil {
+		rel, err := s.relRepo.GetByID(ctx, *relationshipID)
+		if err != nil {
+			return err
+		}
+		if rel == nil {
+			return ErrRelationshipNotFound
+		}
+		if def.Scope == domain.EventScopeRelationship && rel.Type != domain.RelTypeSpouse {
+			return ErrRelationshipRequired
+		}
+	}
+
+	return nil
+}
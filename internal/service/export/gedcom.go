@@ -0,0 +1,557 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+const gedcomMaxLineValue = 200
+
+type gedcomFamily struct {
+	xref           string
+	husband        *uuid.UUID
+	wife           *uuid.UUID
+	children       []uuid.UUID
+	relationshipID *uuid.UUID
+	spouseMeta     *domain.SpouseMetadata
+}
+
+type gedcomBuilder struct {
+	sb            strings.Builder
+	persons       map[uuid.UUID]*domain.Person
+	order         []uuid.UUID
+	personXref    map[uuid.UUID]string
+	families      []*gedcomFamily
+	familyByKey   map[string]*gedcomFamily
+	famsByPerson  map[uuid.UUID][]string
+	famcByPerson  map[uuid.UUID][]string
+	personEvents  map[uuid.UUID][]domain.Event
+	relEvents     map[uuid.UUID][]domain.Event
+	familyByRelID map[uuid.UUID]*gedcomFamily
+	childRels     map[uuid.UUID][]uuid.UUID
+	citations     map[uuid.UUID][]domain.Citation
+	written       map[uuid.UUID]bool
+	sources       []*domain.Source
+	sourceXref    map[uuid.UUID]string
+}
+
+// buildGEDCOM renders the connected component around rootID as a GEDCOM 5.5.1
+// lineage-linked file. Catalog event types without a dedicated GEDCOM tag are
+// written as EVEN/TYPE pairs so other programs still import them, and custom
+// fields as FACT/TYPE pairs. Citations become SOUR citations under the fact
+// they support and every cited source gets its own SOUR record.
+func buildGEDCOM(rootID uuid.UUID, persons []domain.Person, rels []domain.Relationship, events []domain.Event, citations []domain.Citation, now time.Time) string {
+	b := &gedcomBuilder{
+		persons:       make(map[uuid.UUID]*domain.Person),
+		personXref:    make(map[uuid.UUID]string),
+		familyByKey:   make(map[string]*gedcomFamily),
+		famsByPerson:  make(map[uuid.UUID][]string),
+		famcByPerson:  make(map[uuid.UUID][]string),
+		personEvents:  make(map[uuid.UUID][]domain.Event),
+		relEvents:     make(map[uuid.UUID][]domain.Event),
+		familyByRelID: make(map[uuid.UUID]*gedcomFamily),
+		childRels:     make(map[uuid.UUID][]uuid.UUID),
+		citations:     make(map[uuid.UUID][]domain.Citation),
+		written:       make(map[uuid.UUID]bool),
+		sourceXref:    make(map[uuid.UUID]string),
+	}
+	for i := range persons {
+		b.persons[persons[i].ID] = &persons[i]
+	}
+
+	b.collectComponent(rootID, rels)
+	b.buildFamilies(rels)
+
+	for _, ev := range events {
+		if ev.PersonID != nil {
+			b.personEvents[*ev.PersonID] = append(b.personEvents[*ev.PersonID], ev)
+		} else if ev.RelationshipID != nil {
+			b.relEvents[*ev.RelationshipID] = append(b.relEvents[*ev.RelationshipID], ev)
+		}
+	}
+
+	for _, c := range citations {
+		if c.Source != nil {
+			b.citations[c.EntityID] = append(b.citations[c.EntityID], c)
+		}
+	}
+
+	b.writeHeader(now)
+	for _, id := range b.order {
+		b.writeIndividual(b.persons[id])
+	}
+	for _, fam := range b.families {
+		b.writeFamily(fam)
+	}
+	for _, src := range b.sources {
+		b.writeSource(src)
+	}
+	b.line(0, "TRLR", "")
+
+	return b.sb.String()
+}
+
+func (b *gedcomBuilder) collectComponent(rootID uuid.UUID, rels []domain.Relationship) {
+	adjacency := make(map[uuid.UUID][]uuid.UUID)
+	for _, r := range rels {
+		adjacency[r.PersonA] = append(adjacency[r.PersonA], r.PersonB)
+		adjacency[r.PersonB] = append(adjacency[r.PersonB], r.PersonA)
+	}
+
+	visited := map[uuid.UUID]bool{rootID: true}
+	queue := []uuid.UUID{rootID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := b.persons[current]; !ok {
+			continue
+		}
+		b.order = append(b.order, current)
+		b.personXref[current] = fmt.Sprintf("@I%d@", len(b.order))
+
+		for _, next := range adjacency[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+func (b *gedcomBuilder) buildFamilies(rels []domain.Relationship) {
+	parentsByChild := make(map[uuid.UUID][]uuid.UUID)
+
+	for i := range rels {
+		r := rels[i]
+		if b.personXref[r.PersonA] == "" || b.personXref[r.PersonB] == "" {
+			continue
+		}
+		switch r.Type {
+		case domain.RelTypeSpouse:
+			fam := b.family([]uuid.UUID{r.PersonA, r.PersonB})
+			relID := r.ID
+			fam.relationshipID = &relID
+			if len(r.Metadata) > 0 {
+				var meta domain.SpouseMetadata
+				if json.Unmarshal(r.Metadata, &meta) == nil {
+					fam.spouseMeta = &meta
+				}
+			}
+			b.familyByRelID[r.ID] = fam
+		case domain.RelTypeParent:
+			parentsByChild[r.PersonA] = append(parentsByChild[r.PersonA], r.PersonB)
+			b.childRels[r.PersonA] = append(b.childRels[r.PersonA], r.ID)
+		}
+	}
+
+	for _, childID := range b.order {
+		parents := parentsByChild[childID]
+		if len(parents) == 0 {
+			continue
+		}
+		if len(parents) > 2 {
+			parents = parents[:2]
+		}
+		fam := b.family(parents)
+		fam.children = append(fam.children, childID)
+		b.famcByPerson[childID] = append(b.famcByPerson[childID], fam.xref)
+	}
+}
+
+func (b *gedcomBuilder) family(members []uuid.UUID) *gedcomFamily {
+	ids := append([]uuid.UUID(nil), members...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	keyParts := make([]string, len(ids))
+	for i, id := range ids {
+		keyParts[i] = id.String()
+	}
+	key := strings.Join(keyParts, ":")
+
+	if fam, ok := b.familyByKey[key]; ok {
+		return fam
+	}
+
+	fam := &gedcomFamily{xref: fmt.Sprintf("@F%d@", len(b.families)+1)}
+	for _, id := range ids {
+		id := id
+		p := b.persons[id]
+		switch {
+		case p != nil && p.Gender == domain.GenderFemale && fam.wife == nil:
+			fam.wife = &id
+		case fam.husband == nil:
+			fam.husband = &id
+		default:
+			fam.wife = &id
+		}
+		b.famsByPerson[id] = append(b.famsByPerson[id], fam.xref)
+	}
+
+	b.families = append(b.families, fam)
+	b.familyByKey[key] = fam
+	return fam
+}
+
+func (b *gedcomBuilder) writeHeader(now time.Time) {
+	b.line(0, "HEAD", "")
+	b.line(1, "SOUR", "SILSILAH_KELUARGA")
+	b.line(2, "NAME", "Silsilah Keluarga")
+	b.line(1, "DATE", gedcomDate(now))
+	b.line(1, "GEDC", "")
+	b.line(2, "VERS", "5.5.1")
+	b.line(2, "FORM", "LINEAGE-LINKED")
+	b.line(1, "CHAR", "UTF-8")
+}
+
+func (b *gedcomBuilder) writeIndividual(p *domain.Person) {
+	b.line(0, b.personXref[p.ID]+" INDI", "")
+
+	surname := ""
+	if p.LastName != nil {
+		surname = *p.LastName
+	}
+	b.line(1, "NAME", strings.TrimSpace(p.FirstName+" /"+surname+"/"))
+	b.line(2, "GIVN", p.FirstName)
+	if surname != "" {
+		b.line(2, "SURN", surname)
+	}
+	if p.Nickname != nil {
+		b.line(2, "NICK", *p.Nickname)
+	}
+	b.writeFieldCitations(2, p.ID, "first_name", "last_name", "nickname")
+	for _, n := range p.Names {
+		b.writeAlternateName(n)
+	}
+
+	b.line(1, "SEX", gedcomSex(p.Gender))
+	b.writeFieldCitations(2, p.ID, "gender")
+
+	events := b.personEvents[p.ID]
+	if !hasEventType(events, domain.EventTypeBirth) && (p.BirthDate != nil || p.BirthPlace != nil) {
+		b.line(1, "BIRT", "")
+		b.writeDatePlace(2, p.BirthDate, p.BirthPlace)
+		b.writeFieldCitations(2, p.ID, "birth_date", "birth_place")
+	}
+	if !hasEventType(events, domain.EventTypeDeath) && (p.DeathDate != nil || p.DeathPlace != nil || !p.IsAlive) {
+		if p.DeathDate == nil && p.DeathPlace == nil {
+			b.line(1, "DEAT", "Y")
+		} else {
+			b.line(1, "DEAT", "")
+			b.writeDatePlace(2, p.DeathDate, p.DeathPlace)
+		}
+		b.writeFieldCitations(2, p.ID, "death_date", "death_place")
+	}
+	for _, ev := range events {
+		b.writeEvent(1, ev)
+	}
+
+	for _, attr := range []struct {
+		tag, field string
+		value      *string
+	}{
+		{"OCCU", "occupation", p.Occupation},
+		{"EDUC", "education", p.Education},
+		{"RELI", "religion", p.Religion},
+		{"NATI", "nationality", p.Nationality},
+	} {
+		if b.optional(1, attr.tag, attr.value) {
+			b.writeFieldCitations(2, p.ID, attr.field)
+		}
+	}
+	b.writeCustomFields(p.CustomFields)
+	if p.Bio != nil {
+		b.text(1, "NOTE", *p.Bio)
+	}
+
+	b.writeRemainingCitations(1, p.ID)
+	// Parent-child relationships have no record of their own, so their
+	// citations go on the child.
+	for _, relID := range b.childRels[p.ID] {
+		b.writeRemainingCitations(1, relID)
+	}
+
+	for _, xref := range b.famcByPerson[p.ID] {
+		b.line(1, "FAMC", xref)
+	}
+	for _, xref := range b.famsByPerson[p.ID] {
+		b.line(1, "FAMS", xref)
+	}
+}
+
+// writeCustomFields writes custom facts as FACT records typed by the field
+// label. A PERSON value naming someone in the export becomes an ASSO link.
+func (b *gedcomBuilder) writeCustomFields(values []domain.PersonCustomValue) {
+	for _, v := range values {
+		if v.PersonRef != nil {
+			if xref, ok := b.personXref[*v.PersonRef]; ok {
+				b.line(1, "ASSO", xref)
+				b.line(2, "RELA", v.Label)
+				continue
+			}
+		}
+		b.line(1, "FACT", v.Display)
+		b.line(2, "TYPE", v.Label)
+	}
+}
+
+func (b *gedcomBuilder) writeFamily(fam *gedcomFamily) {
+	b.line(0, fam.xref+" FAM", "")
+	if fam.husband != nil {
+		b.line(1, "HUSB", b.personXref[*fam.husband])
+	}
+	if fam.wife != nil {
+		b.line(1, "WIFE", b.personXref[*fam.wife])
+	}
+	for _, child := range fam.children {
+		b.line(1, "CHIL", b.personXref[child])
+	}
+
+	var events []domain.Event
+	if fam.relationshipID != nil {
+		events = b.relEvents[*fam.relationshipID]
+	}
+
+	if fam.spouseMeta != nil {
+		meta := fam.spouseMeta
+		if !hasEventType(events, domain.EventTypeMarriage, domain.EventTypeAkadNikah) && (meta.MarriageDate != nil || meta.MarriagePlace != nil) {
+			b.line(1, "MARR", "")
+			b.writeDatePlace(2, meta.MarriageDate, meta.MarriagePlace)
+			b.writeFieldCitations(2, *fam.relationshipID, "marriage_date", "marriage_place")
+		}
+		if !hasEventType(events, domain.EventTypeDivorce) && meta.DivorceDate != nil {
+			b.line(1, "DIV", "")
+			b.writeDatePlace(2, meta.DivorceDate, nil)
+			b.writeFieldCitations(2, *fam.relationshipID, "divorce_date")
+		}
+	}
+
+	for _, ev := range events {
+		b.writeEvent(1, ev)
+	}
+
+	if fam.relationshipID != nil {
+		b.writeRemainingCitations(1, *fam.relationshipID)
+	}
+}
+
+func (b *gedcomBuilder) writeEvent(level int, ev domain.Event) {
+	tag, eventType := gedcomEventTag(ev)
+	b.line(level, tag, "")
+	if eventType != "" {
+		b.line(level+1, "TYPE", eventType)
+	}
+	b.writeDatePlace(level+1, ev.Date, ev.Place)
+	if ev.Description != nil {
+		b.text(level+1, "NOTE", *ev.Description)
+	}
+	b.writeRemainingCitations(level+1, ev.ID)
+}
+
+// writeFieldCitations writes the citations of an entity that single out one
+// of fields, under the fact just written for them.
+func (b *gedcomBuilder) writeFieldCitations(level int, entityID uuid.UUID, fields ...string) {
+	for _, c := range b.citations[entityID] {
+		if b.written[c.ID] || c.Field == nil {
+			continue
+		}
+		for _, f := range fields {
+			if *c.Field == f {
+				b.writeCitation(level, c, false)
+				break
+			}
+		}
+	}
+}
+
+// writeRemainingCitations writes the citations of an entity not yet placed
+// under a fact. Field citations whose fact was not exported keep the field
+// name in a NOTE so the claim they support is not lost.
+func (b *gedcomBuilder) writeRemainingCitations(level int, entityID uuid.UUID) {
+	for _, c := range b.citations[entityID] {
+		if !b.written[c.ID] {
+			b.writeCitation(level, c, c.Field != nil)
+		}
+	}
+}
+
+func (b *gedcomBuilder) writeCitation(level int, c domain.Citation, noteField bool) {
+	b.written[c.ID] = true
+
+	xref, ok := b.sourceXref[c.SourceID]
+	if !ok {
+		xref = fmt.Sprintf("@S%d@", len(b.sources)+1)
+		b.sourceXref[c.SourceID] = xref
+		b.sources = append(b.sources, c.Source)
+	}
+
+	b.line(level, "SOUR", xref)
+	b.optional(level+1, "PAGE", c.Page)
+	b.line(level+1, "QUAY", fmt.Sprint(c.Confidence.Quay()))
+	if noteField {
+		b.line(level+1, "NOTE", "Field: "+*c.Field)
+	}
+	if c.Note != nil {
+		b.text(level+1, "NOTE", *c.Note)
+	}
+}
+
+// writeSource writes a SOUR record. GEDCOM 5.5.1 has no tags for the
+// repository name or a URL, so those go into notes.
+func (b *gedcomBuilder) writeSource(src *domain.Source) {
+	b.line(0, b.sourceXref[src.ID]+" SOUR", "")
+	b.text(1, "TITL", src.Title)
+	b.optional(1, "AUTH", src.Author)
+	b.optional(1, "PUBL", src.Publication)
+	b.line(1, "_TYPE", string(src.Type))
+	if src.Repository != nil && *src.Repository != "" {
+		b.text(1, "NOTE", "Repository: "+*src.Repository)
+	}
+	if src.URL != nil && *src.URL != "" {
+		b.line(1, "NOTE", "URL: "+*src.URL)
+	}
+	if src.Notes != nil {
+		b.text(1, "NOTE", *src.Notes)
+	}
+}
+
+// writeAlternateName writes a person_names entry. Gelar become TITL
+// attributes; every other type is an additional NAME with its TYPE.
+func (b *gedcomBuilder) writeAlternateName(n domain.PersonName) {
+	if n.Type == domain.NameTypeTitle {
+		b.line(1, "TITL", n.Name)
+		if n.ValidFrom != nil || n.ValidTo != nil {
+			b.line(2, "DATE", gedcomPeriod(n.ValidFrom, n.ValidTo))
+		}
+		return
+	}
+
+	b.line(1, "NAME", n.Name)
+	b.line(2, "TYPE", gedcomNameType(n.Type))
+	if n.ValidFrom != nil || n.ValidTo != nil {
+		b.line(2, "NOTE", "Valid "+gedcomPeriod(n.ValidFrom, n.ValidTo))
+	}
+}
+
+func gedcomNameType(t domain.NameType) string {
+	switch t {
+	case domain.NameTypeBirth:
+		return "birth"
+	case domain.NameTypeMarried:
+		return "married"
+	case domain.NameTypeAlias:
+		return "aka"
+	}
+	return strings.ToLower(string(t))
+}
+
+// gedcomPeriod renders a FROM/TO date period; open ends are omitted.
+func gedcomPeriod(from, to *domain.GenDate) string {
+	var parts []string
+	if from != nil {
+		parts = append(parts, "FROM "+from.GEDCOM())
+	}
+	if to != nil {
+		parts = append(parts, "TO "+to.GEDCOM())
+	}
+	return strings.Join(parts, " ")
+}
+
+func (b *gedcomBuilder) writeDatePlace(level int, date *domain.GenDate, place *string) {
+	if date != nil {
+		b.line(level, "DATE", date.GEDCOM())
+	}
+	b.optional(level, "PLAC", place)
+}
+
+// optional writes tag when value is set and reports whether it did.
+func (b *gedcomBuilder) optional(level int, tag string, value *string) bool {
+	if value != nil && *value != "" {
+		b.line(level, tag, *value)
+		return true
+	}
+	return false
+}
+
+// text writes a possibly multi-line value using CONT for line breaks and
+// CONC for lines longer than the GEDCOM line limit.
+func (b *gedcomBuilder) text(level int, tag, value string) {
+	for i, para := range strings.Split(value, "\n") {
+		currentTag := tag
+		currentLevel := level
+		if i > 0 {
+			currentTag = "CONT"
+			currentLevel = level + 1
+		}
+		runes := []rune(para)
+		first := true
+		for first || len(runes) > 0 {
+			n := len(runes)
+			if n > gedcomMaxLineValue {
+				n = gedcomMaxLineValue
+			}
+			if first {
+				b.line(currentLevel, currentTag, string(runes[:n]))
+				first = false
+			} else {
+				b.line(level+1, "CONC", string(runes[:n]))
+			}
+			runes = runes[n:]
+		}
+	}
+}
+
+func (b *gedcomBuilder) line(level int, tag, value string) {
+	b.sb.WriteString(fmt.Sprintf("%d %s", level, tag))
+	if value != "" {
+		if !strings.HasPrefix(value, "@") || !strings.HasSuffix(value, "@") || strings.Contains(value, " ") {
+			value = strings.ReplaceAll(value, "@", "@@")
+		}
+		b.sb.WriteString(" " + value)
+	}
+	b.sb.WriteString("\r\n")
+}
+
+// gedcomEventTag maps a catalog event to the closest standard GEDCOM tag and
+// an optional TYPE descriptor.
+func gedcomEventTag(ev domain.Event) (string, string) {
+	def, ok := ev.Type.Definition()
+	if !ok {
+		return "EVEN", ev.Title
+	}
+	if def.GedcomTag == "EVEN" && def.GedcomType == "" {
+		return "EVEN", ev.Title
+	}
+	return def.GedcomTag, def.GedcomType
+}
+
+func hasEventType(events []domain.Event, types ...domain.EventType) bool {
+	for _, ev := range events {
+		for _, t := range types {
+			if ev.Type == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func gedcomSex(g domain.Gender) string {
+	switch g {
+	case domain.GenderMale:
+		return "M"
+	case domain.GenderFemale:
+		return "F"
+	}
+	return "U"
+}
+
+func gedcomDate(t time.Time) string {
+	return strings.ToUpper(t.Format("2 Jan 2006"))
+}
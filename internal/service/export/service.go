@@ -2,43 +2,128 @@ package export
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
+	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/repository"
 	"silsilah-keluarga/internal/service/graph"
 )
 
 type Service interface {
-	ExportJSON(ctx context.Context, userID, personID uuid.UUID) (any, error)
-	ExportGEDCOM(ctx context.Context, userID, rootID uuid.UUID) (string, error)
+	ExportJSON(ctx context.Context, viewer domain.Viewer, personID uuid.UUID) (any, error)
+	ExportGEDCOM(ctx context.Context, viewer domain.Viewer, rootID uuid.UUID) (string, error)
 }
 
 type service struct {
-	personRepo repository.PersonRepository
-	relRepo    repository.RelationshipRepository
-	auditRepo  repository.AuditLogRepository
-	graphSvc   graph.Service
+	personRepo      repository.PersonRepository
+	relRepo         repository.RelationshipRepository
+	nameRepo        repository.PersonNameRepository
+	eventRepo       repository.EventRepository
+	citationRepo    repository.CitationRepository
+	customFieldRepo repository.CustomFieldRepository
+	auditRepo       repository.AuditLogRepository
+	graphSvc        graph.Service
 }
 
-func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, auditRepo repository.AuditLogRepository, graphSvc graph.Service) Service {
+func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, nameRepo repository.PersonNameRepository, eventRepo repository.EventRepository, citationRepo repository.CitationRepository, customFieldRepo repository.CustomFieldRepository, auditRepo repository.AuditLogRepository, graphSvc graph.Service) Service {
 	return &service{
-		personRepo: personRepo,
-		relRepo:    relRepo,
-		auditRepo:  auditRepo,
-		graphSvc:   graphSvc,
+		personRepo:      personRepo,
+		relRepo:         relRepo,
+		nameRepo:        nameRepo,
+		eventRepo:       eventRepo,
+		citationRepo:    citationRepo,
+		customFieldRepo: customFieldRepo,
+		auditRepo:       auditRepo,
+		graphSvc:        graphSvc,
 	}
 }
 
-func (s *service) ExportJSON(ctx context.Context, userID, personID uuid.UUID) (any, error) {
+func (s *service) ExportJSON(ctx context.Context, viewer domain.Viewer, personID uuid.UUID) (any, error) {
 	graphData, err := s.graphSvc.GetFullGraph(ctx)
 	if err != nil {
 		return nil, err
 	}
+	domain.RedactNodes(graphData.Nodes, viewer, time.Now())
 	return graphData, nil
 }
 
-func (s *service) ExportGEDCOM(ctx context.Context, userID, rootID uuid.UUID) (string, error) {
-	return "", errors.New("GEDCOM export not implemented yet")
+// ExportGEDCOM exports the family of the root person as the viewer may see
+// it. Living persons hidden from the viewer are exported redacted, without
+// their own events and citations.
+func (s *service) ExportGEDCOM(ctx context.Context, viewer domain.Viewer, rootID uuid.UUID) (string, error) {
+	now := time.Now()
+	root, err := s.personRepo.GetByID(ctx, rootID)
+	if err != nil {
+		return "", err
+	}
+	if root == nil {
+		return "", domain.ErrPersonNotFound
+	}
+
+	persons, err := s.personRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	names, err := s.nameRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	namesByPerson := make(map[uuid.UUID][]domain.PersonName)
+	for _, n := range names {
+		namesByPerson[n.PersonID] = append(namesByPerson[n.PersonID], n)
+	}
+	// Exports leave the site, so only custom fields every member may see
+	// are included.
+	values, err := s.customFieldRepo.GetAllValues(ctx)
+	if err != nil {
+		return "", err
+	}
+	valuesByPerson := make(map[uuid.UUID][]domain.PersonCustomValue)
+	for _, v := range domain.VisibleCustomValues(values, domain.RoleMember) {
+		valuesByPerson[v.PersonID] = append(valuesByPerson[v.PersonID], v)
+	}
+
+	for i := range persons {
+		persons[i].Names = namesByPerson[persons[i].ID]
+		persons[i].CustomFields = valuesByPerson[persons[i].ID]
+	}
+	domain.RedactPersons(persons, viewer, now)
+	redacted := make(map[uuid.UUID]bool)
+	for _, p := range persons {
+		if p.Redacted {
+			redacted[p.ID] = true
+		}
+	}
+
+	rels, err := s.relRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	allEvents, err := s.eventRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	var events []domain.Event
+	for _, ev := range allEvents {
+		if ev.PersonID == nil || !redacted[*ev.PersonID] {
+			events = append(events, ev)
+		}
+	}
+
+	allCitations, err := s.citationRepo.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	var citations []domain.Citation
+	for _, c := range allCitations {
+		if !redacted[c.EntityID] {
+			citations = append(citations, c)
+		}
+	}
+
+	return buildGEDCOM(rootID, persons, rels, events, citations, now), nil
 }
@@ -25,6 +25,8 @@ func personToGraphNode(p *domain.Person, generation *int) domain.GraphNode {
 		BirthYear: birthYear,
 		DeathYear: deathYear,
 		IsAlive:   p.IsAlive,
+
+		PrivacyLevel: p.PrivacyLevel,
 	}
 	if generation != nil {
 		node.Generation = generation
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+)
+
+// GetMigrationMap returns the birth and death places of a person's
+// ancestors or descendants as GeoJSON, with arcs between the birth places of
+// parents and children. Birth places of living persons hidden from the
+// viewer are left off the map.
+func (s *service) GetMigrationMap(ctx context.Context, personID uuid.UUID, direction domain.MigrationDirection, maxDepth int, viewer domain.Viewer) (*domain.MigrationMap, error) {
+	if !direction.IsValid() {
+		return nil, domain.ErrInvalidMigrationDirection
+	}
+	maxDepth = clampDepth(maxDepth)
+
+	root, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, domain.ErrPersonNotFound
+	}
+
+	var nodes []domain.GraphNode
+	if direction == domain.MigrationAncestors {
+		nodes, err = BFSAncestors(ctx, s.relRepo, s.personRepo, personID, maxDepth)
+		invertGenerations(nodes)
+	} else {
+		nodes, err = BFSDescendants(ctx, s.relRepo, s.personRepo, personID, maxDepth)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	generations := map[uuid.UUID]int{personID: 0}
+	ids := []uuid.UUID{personID}
+	for _, n := range nodes {
+		if n.Generation != nil {
+			generations[n.ID] = *n.Generation
+		}
+		ids = append(ids, n.ID)
+	}
+
+	persons, err := s.personRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	domain.RedactPersons(persons, viewer, time.Now())
+
+	rels, err := s.relRepo.ListByPeople(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var links []domain.ParentChildLink
+	for _, r := range rels {
+		_, childIn := generations[r.PersonA]
+		_, parentIn := generations[r.PersonB]
+		if r.Type == domain.RelTypeParent && childIn && parentIn {
+			links = append(links, domain.ParentChildLink{Parent: r.PersonB, Child: r.PersonA})
+		}
+	}
+
+	places, err := s.locatePlaces(ctx, persons)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildMigrationMap(domain.MigrationMapInput{
+		RootPerson:  personID,
+		Direction:   direction,
+		Persons:     persons,
+		Generations: generations,
+		Links:       links,
+		Places:      places,
+	}), nil
+}
+
+// locatePlaces loads the birth and death places of persons. A place without
+// coordinates of its own borrows those of the nearest enclosing region, so
+// a desa added without a location still lands in its kabupaten.
+func (s *service) locatePlaces(ctx context.Context, persons []domain.Person) (map[uuid.UUID]*domain.Place, error) {
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, p := range persons {
+		for _, id := range []*uuid.UUID{p.BirthPlaceID, p.DeathPlaceID} {
+			if id != nil && !seen[*id] {
+				seen[*id] = true
+				ids = append(ids, *id)
+			}
+		}
+	}
+
+	list, err := s.placeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	places := make(map[uuid.UUID]*domain.Place, len(list))
+	for i := range list {
+		p := &list[i]
+		if !p.HasCoordinates() {
+			path, err := s.placeRepo.GetAncestors(ctx, p.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range path {
+				if a.HasCoordinates() {
+					p.Latitude, p.Longitude = a.Latitude, a.Longitude
+					break
+				}
+			}
+		}
+		places[p.ID] = p
+	}
+	return places, nil
+}
@@ -20,20 +20,25 @@ type Service interface {
 	GetSplitAncestors(ctx context.Context, personID uuid.UUID, maxDepth int) (*domain.SplitAncestorTree, error)
 	GetDescendants(ctx context.Context, personID uuid.UUID, maxDepth int) (*domain.DescendantTree, error)
 	FindRelationshipPath(ctx context.Context, fromPersonID, toPersonID uuid.UUID, maxDepth int, locale string) (*domain.RelationshipPath, error)
+	GetMigrationMap(ctx context.Context, personID uuid.UUID, direction domain.MigrationDirection, maxDepth int, viewer domain.Viewer) (*domain.MigrationMap, error)
 	InvalidateCache(ctx context.Context) error
 }
 
 type service struct {
 	personRepo repository.PersonRepository
 	relRepo    repository.RelationshipRepository
+	nameRepo   repository.PersonNameRepository
+	placeRepo  repository.PlaceRepository
 	redis      *redis.Client
 	narrative  narrative.Service
 }
 
-func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, redis *redis.Client, narrative narrative.Service) Service {
+func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, nameRepo repository.PersonNameRepository, placeRepo repository.PlaceRepository, redis *redis.Client, narrative narrative.Service) Service {
 	return &service{
 		personRepo: personRepo,
 		relRepo:    relRepo,
+		nameRepo:   nameRepo,
+		placeRepo:  placeRepo,
 		redis:      redis,
 		narrative:  narrative,
 	}
@@ -83,6 +88,10 @@ func (s *service) GetFullGraph(ctx context.Context) (*domain.FamilyGraph, error)
 		}
 	}
 
+	if err := s.attachNames(ctx, nodes); err != nil {
+		return nil, err
+	}
+
 	personIdSet := make(map[uuid.UUID]bool)
 	for _, p := range connectedPersons {
 		personIdSet[p.ID] = true
@@ -195,6 +204,10 @@ func (s *service) getAncestorTree(ctx context.Context, personID uuid.UUID, maxDe
 		return nil, err
 	}
 
+	if err := s.attachNames(ctx, allNodes); err != nil {
+		return nil, err
+	}
+
 	return &domain.AncestorTree{
 		RootPerson: personID,
 		Ancestors:  allNodes,
@@ -246,6 +259,10 @@ func (s *service) getAncestorTreeForSide(ctx context.Context, personID, parentID
 		return nil, err
 	}
 
+	if err := s.attachNames(ctx, allNodes); err != nil {
+		return nil, err
+	}
+
 	return &domain.AncestorTree{
 		RootPerson: personID,
 		Ancestors:  allNodes,
@@ -281,6 +298,10 @@ func (s *service) GetDescendants(ctx context.Context, personID uuid.UUID, maxDep
 		return nil, err
 	}
 
+	if err := s.attachNames(ctx, allNodes); err != nil {
+		return nil, err
+	}
+
 	return &domain.DescendantTree{
 		RootPerson:  personID,
 		Descendants: allNodes,
@@ -337,6 +358,40 @@ func (s *service) fetchAndBuildEdges(ctx context.Context, personIds []uuid.UUID,
 	return buildEdgesForNodes(rels, personIdSet), nil
 }
 
+// attachNames fills in the current gelar and the alternate names of nodes
+// from the person_names table.
+func (s *service) attachNames(ctx context.Context, nodes []domain.GraphNode) error {
+	if s.nameRepo == nil || len(nodes) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(nodes))
+	for i := range nodes {
+		ids[i] = nodes[i].ID
+	}
+	names, err := s.nameRepo.ListByPersons(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byPerson := make(map[uuid.UUID][]domain.PersonName)
+	for _, n := range names {
+		byPerson[n.PersonID] = append(byPerson[n.PersonID], n)
+	}
+
+	now := time.Now()
+	for i := range nodes {
+		personNames := byPerson[nodes[i].ID]
+		nodes[i].Title = domain.CurrentTitle(personNames, now)
+		for _, n := range personNames {
+			if n.Type != domain.NameTypeTitle {
+				nodes[i].AlternateNames = append(nodes[i].AlternateNames, n.Name)
+			}
+		}
+	}
+	return nil
+}
+
 func clampDepth(d int) int {
 	if d <= 0 {
 		return 5
@@ -24,6 +24,7 @@ func BuildPersonWithRelationships(
 		Children:      []domain.Person{},
 		Siblings:      []domain.SiblingInfo{},
 		Relationships: []domain.RelationshipInfo{},
+		Citations:     []domain.Citation{},
 	}
 
 	relationships, err := relRepo.GetByPerson(ctx, person.ID)
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+// PlaceFullName checks that a place exists and returns its display path,
+// e.g. "Kota Surabaya, Jawa Timur, Indonesia", for use as the free-text
+// fallback on records that reference it.
+func PlaceFullName(ctx context.Context, placeRepo repository.PlaceRepository, id uuid.UUID) (string, error) {
+	path, err := placeRepo.GetAncestors(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if len(path) == 0 {
+		return "", domain.ErrPlaceNotFound
+	}
+	return domain.PlacePath(path), nil
+}
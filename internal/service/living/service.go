@@ -0,0 +1,129 @@
+package living
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+type Service interface {
+	Infer(ctx context.Context, personID uuid.UUID) (*domain.LivingEstimate, error)
+	Report(ctx context.Context) ([]domain.LivingEstimate, error)
+	Correct(ctx context.Context, userID uuid.UUID, dryRun bool) (*domain.LivingCorrectionResult, error)
+}
+
+type service struct {
+	personRepo repository.PersonRepository
+	relRepo    repository.RelationshipRepository
+	eventRepo  repository.EventRepository
+	auditRepo  repository.AuditLogRepository
+	redis      *redis.Client
+}
+
+func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, eventRepo repository.EventRepository, auditRepo repository.AuditLogRepository, redis *redis.Client) Service {
+	return &service{
+		personRepo: personRepo,
+		relRepo:    relRepo,
+		eventRepo:  eventRepo,
+		auditRepo:  auditRepo,
+		redis:      redis,
+	}
+}
+
+// infer loads the whole tree, since an estimate may rest on relatives
+// several generations away.
+func (s *service) infer(ctx context.Context) ([]domain.Person, []domain.LivingEstimate, error) {
+	persons, err := s.personRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	rels, err := s.relRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := s.eventRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return persons, domain.InferLiving(persons, rels, events, time.Now()), nil
+}
+
+func (s *service) Infer(ctx context.Context, personID uuid.UUID) (*domain.LivingEstimate, error) {
+	_, estimates, err := s.infer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range estimates {
+		if estimates[i].PersonID == personID {
+			return &estimates[i], nil
+		}
+	}
+	return nil, domain.ErrPersonNotFound
+}
+
+// Report lists the persons whose stored status needs correcting or whose
+// dates are inconsistent.
+func (s *service) Report(ctx context.Context) ([]domain.LivingEstimate, error) {
+	_, estimates, err := s.infer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := []domain.LivingEstimate{}
+	for _, e := range estimates {
+		if e.NeedsCorrection() || len(e.Issues) > 0 {
+			report = append(report, e)
+		}
+	}
+	return report, nil
+}
+
+// Correct marks deceased every person stored as alive but inferred to be
+// deceased, with an audit entry per person. A dry run only lists them.
+func (s *service) Correct(ctx context.Context, userID uuid.UUID, dryRun bool) (*domain.LivingCorrectionResult, error) {
+	persons, estimates, err := s.infer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.LivingCorrectionResult{DryRun: dryRun, Persons: []domain.LivingEstimate{}}
+	for i, e := range estimates {
+		if !e.NeedsCorrection() {
+			continue
+		}
+		result.Persons = append(result.Persons, e)
+		if dryRun {
+			continue
+		}
+
+		person := &persons[i]
+		person.IsAlive = false
+		if err := s.personRepo.Update(ctx, person); err != nil {
+			return nil, err
+		}
+		result.Corrected++
+
+		_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+			UserID:     userID,
+			Action:     "UPDATE",
+			EntityType: string(domain.EntityPerson),
+			EntityID:   person.ID,
+			OldValue:   map[string]any{"is_alive": true},
+			NewValue: map[string]any{
+				"is_alive":             false,
+				"basis":                e.Basis,
+				"estimated_birth_year": e.EstimatedBirthYear,
+			},
+		})
+	}
+
+	if result.Corrected > 0 && s.redis != nil {
+		_ = s.redis.Del(ctx, "family:graph", "dashboard:stats").Err()
+	}
+
+	return result, nil
+}
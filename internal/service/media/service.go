@@ -2,6 +2,8 @@ package media
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -18,9 +20,15 @@ import (
 type Service interface {
 	Upload(ctx context.Context, userID uuid.UUID, personID *uuid.UUID, caption *string, fileName string, fileSize int64, mimeType string, reader io.Reader, status string) (*domain.Media, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Media, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 	List(ctx context.Context, personID *uuid.UUID, params domain.PaginationParams) (domain.PaginatedResponse[domain.Media], error)
+	ListDatedByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Media, error)
 	Approve(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) (*domain.Media, error)
+	// ValidateUpdate runs the checks of Update without saving, for edits
+	// that are applied later.
+	ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) error
+	RemoveObjects(ctx context.Context, storagePaths []string) error
 }
 
 type service struct {
@@ -78,18 +86,26 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Media, err
 	return media, nil
 }
 
-func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
-	media, err := s.mediaRepo.GetByID(ctx, id)
-	if err != nil {
+// Delete moves the media to the trash. The stored object is kept so the
+// media can be restored; it is removed when the trash is purged.
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if _, err := s.mediaRepo.GetByID(ctx, id); err != nil {
 		return err
 	}
+	return s.mediaRepo.Delete(ctx, id, userID)
+}
 
-	if err := s.mediaRepo.Delete(ctx, id); err != nil {
-		return err
+// RemoveObjects deletes stored files of purged media. It tries every path
+// and returns the first failure.
+func (s *service) RemoveObjects(ctx context.Context, storagePaths []string) error {
+	var firstErr error
+	for _, path := range storagePaths {
+		err := s.minioClient.RemoveObject(ctx, s.cfg.MinIOBucket, path, minio.RemoveObjectOptions{})
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-
-	_ = s.minioClient.RemoveObject(ctx, s.cfg.MinIOBucket, media.StoragePath, minio.RemoveObjectOptions{})
-	return nil
+	return firstErr
 }
 
 func (s *service) List(ctx context.Context, personID *uuid.UUID, params domain.PaginationParams) (domain.PaginatedResponse[domain.Media], error) {
@@ -105,6 +121,19 @@ func (s *service) List(ctx context.Context, personID *uuid.UUID, params domain.P
 	return domain.NewPaginatedResponse(mediaList, params.Page, params.PageSize, total), nil
 }
 
+func (s *service) ListDatedByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Media, error) {
+	mediaList, err := s.mediaRepo.ListDatedByPerson(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range mediaList {
+		mediaList[i].URL = s.getPublicURL(mediaList[i].StoragePath)
+	}
+
+	return mediaList, nil
+}
+
 func (s *service) getPublicURL(storagePath string) string {
 	scheme := "http"
 	if s.cfg.MinIOPublicUseSSL {
@@ -126,3 +155,42 @@ func (s *service) Approve(ctx context.Context, id uuid.UUID) error {
 	media.Status = "active"
 	return s.mediaRepo.Update(ctx, media)
 }
+
+// Update edits the caption and taken date of a media file.
+func (s *service) Update(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) (*domain.Media, error) {
+	media, err := s.loadForUpdate(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepo.Update(ctx, media); err != nil {
+		return nil, err
+	}
+	media.URL = s.getPublicURL(media.StoragePath)
+	return media, nil
+}
+
+func (s *service) ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) error {
+	_, err := s.loadForUpdate(ctx, id, input)
+	return err
+}
+
+// loadForUpdate checks the input and returns the media with it applied.
+func (s *service) loadForUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) (*domain.Media, error) {
+	if err := input.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if input.Caption.Set {
+		media.Caption = input.Caption.Value
+	}
+	if input.TakenAt.Set {
+		media.TakenAt = input.TakenAt.Value
+	}
+	return media, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -35,13 +36,13 @@ func (s *service) DescribeRelationship(ctx context.Context, path *domain.Relatio
 
 	var nameA, nameB string
 	if pA, err := s.personRepo.GetByID(ctx, path.FromPerson); err == nil && pA != nil {
-		nameA = pA.FirstName
+		nameA = pA.SharedName(time.Now())
 	} else {
 		nameA = "Unknown"
 	}
 
 	if pB, err := s.personRepo.GetByID(ctx, path.ToPerson); err == nil && pB != nil {
-		nameB = pB.FirstName
+		nameB = pB.SharedName(time.Now())
 	} else {
 		nameB = "Unknown"
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -248,6 +249,9 @@ func (s *service) NotifyNewComment(ctx context.Context, commentID uuid.UUID, aut
 	if err != nil {
 		return fmt.Errorf("failed to get comment: %w", err)
 	}
+	if comment == nil {
+		return domain.ErrCommentNotFound
+	}
 
 	author, err := s.userRepo.GetByID(ctx, authorID)
 	if err != nil {
@@ -280,7 +284,7 @@ func (s *service) NotifyNewComment(ctx context.Context, commentID uuid.UUID, aut
 			UserID:  user.ID,
 			Type:    domain.NotifNewComment,
 			Title:   "Komentar Baru",
-			Message: fmt.Sprintf("%s mengomentari profil %s", author.FullName, person.FirstName),
+			Message: fmt.Sprintf("%s mengomentari profil %s", author.FullName, person.SharedName(time.Now())),
 			Data:    json.RawMessage(data),
 		}
 
@@ -321,7 +325,7 @@ func (s *service) NotifyPersonAdded(ctx context.Context, personID uuid.UUID, add
 			UserID:  user.ID,
 			Type:    domain.NotifPersonAdded,
 			Title:   "Anggota Keluarga Baru",
-			Message: fmt.Sprintf("%s menambahkan anggota baru: %s", adder.FullName, person.FirstName),
+			Message: fmt.Sprintf("%s menambahkan anggota baru: %s", adder.FullName, person.SharedName(time.Now())),
 			Data:    json.RawMessage(data),
 		}
 
@@ -362,7 +366,7 @@ func (s *service) NotifyRelationshipAdded(ctx context.Context, relationshipID uu
 
 		msg := fmt.Sprintf("%s menambahkan hubungan baru", adder.FullName)
 		if personA != nil && personB != nil {
-			msg = fmt.Sprintf("%s menghubungkan %s dan %s (%s)", adder.FullName, personA.FirstName, personB.FirstName, rel.Type)
+			msg = fmt.Sprintf("%s menghubungkan %s dan %s (%s)", adder.FullName, personA.SharedName(time.Now()), personB.SharedName(time.Now()), rel.Type)
 		}
 
 		notif := &domain.Notification{
@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+	"silsilah-keluarga/internal/service/notification"
+)
+
+const (
+	batchSize = 50
+	// MaxAttempts is how often delivery of a message is tried before it is
+	// left in the outbox for inspection.
+	MaxAttempts = 5
+	// ClaimLease is how long a claimed message is left to its dispatcher
+	// before another may take it over. Delivery is at least once: a
+	// dispatcher that stops after delivering but before marking the message
+	// dispatched has it delivered again.
+	ClaimLease = 5 * time.Minute
+	// RetryBackoff is how long a message whose delivery failed waits before
+	// it is tried again; the wait doubles with each further attempt.
+	RetryBackoff = 30 * time.Second
+)
+
+type Service interface {
+	// Enqueue records a notification; with a context from
+	// repository.Transactor it commits or rolls back with the change.
+	Enqueue(ctx context.Context, notifType domain.NotificationType, entityID, actorID uuid.UUID) error
+	// EnqueueNotification records a notification prepared now, for when
+	// its recipients or wording depend on state the commit changes.
+	// actorID may be uuid.Nil.
+	EnqueueNotification(ctx context.Context, notif *domain.Notification, entityID, actorID uuid.UUID) error
+	// Dispatch delivers pending notifications and returns how many were
+	// delivered.
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type service struct {
+	outboxRepo repository.OutboxRepository
+	notifSvc   notification.Service
+}
+
+func NewService(outboxRepo repository.OutboxRepository, notifSvc notification.Service) Service {
+	return &service{
+		outboxRepo: outboxRepo,
+		notifSvc:   notifSvc,
+	}
+}
+
+func (s *service) Enqueue(ctx context.Context, notifType domain.NotificationType, entityID, actorID uuid.UUID) error {
+	return s.outboxRepo.Create(ctx, &domain.OutboxMessage{
+		ID:       uuid.New(),
+		Type:     notifType,
+		EntityID: entityID,
+		ActorID:  actorID,
+	})
+}
+
+func (s *service) EnqueueNotification(ctx context.Context, notif *domain.Notification, entityID, actorID uuid.UUID) error {
+	prepared, err := json.Marshal(notif)
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.Create(ctx, &domain.OutboxMessage{
+		ID:           uuid.New(),
+		Type:         notif.Type,
+		EntityID:     entityID,
+		ActorID:      actorID,
+		Notification: prepared,
+	})
+}
+
+func (s *service) Dispatch(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		msgs, err := s.outboxRepo.ClaimPending(ctx, batchSize, MaxAttempts, ClaimLease)
+		if err != nil {
+			return delivered, err
+		}
+		if len(msgs) == 0 {
+			return delivered, nil
+		}
+
+		for _, msg := range msgs {
+			if err := s.deliver(ctx, msg); err != nil {
+				if err := s.outboxRepo.Release(ctx, msg.ID, err.Error(), Backoff(msg.Attempts)); err != nil {
+					return delivered, err
+				}
+				continue
+			}
+			if err := s.outboxRepo.MarkDispatched(ctx, msg.ID); err != nil {
+				return delivered, err
+			}
+			delivered++
+		}
+		if len(msgs) < batchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// Backoff is how long a message is held back after its delivery failed
+// with attempts earlier attempts.
+func Backoff(attempts int) time.Duration {
+	return RetryBackoff << attempts
+}
+
+// deliver hands a message to the notification service. A prepared
+// notification keeps its ID, so one already saved before a lost
+// MarkDispatched counts as delivered instead of notifying twice.
+func (s *service) deliver(ctx context.Context, msg domain.OutboxMessage) error {
+	if len(msg.Notification) > 0 {
+		var notif domain.Notification
+		if err := json.Unmarshal(msg.Notification, &notif); err != nil {
+			return err
+		}
+		err := s.notifSvc.Create(ctx, &notif)
+		if errors.Is(err, domain.ErrNotificationExists) {
+			return nil
+		}
+		return err
+	}
+
+	switch msg.Type {
+	case domain.NotifChangeRequest:
+		return s.notifSvc.NotifyChangeRequest(ctx, msg.EntityID, msg.ActorID)
+	case domain.NotifChangeApproved:
+		return s.notifSvc.NotifyChangeApproved(ctx, msg.EntityID, msg.ActorID)
+	case domain.NotifChangeRejected:
+		return s.notifSvc.NotifyChangeRejected(ctx, msg.EntityID, msg.ActorID)
+	case domain.NotifNewComment:
+		return s.notifSvc.NotifyNewComment(ctx, msg.EntityID, msg.ActorID)
+	case domain.NotifPersonAdded:
+		return s.notifSvc.NotifyPersonAdded(ctx, msg.EntityID, msg.ActorID)
+	case domain.NotifRelationshipAdded:
+		return s.notifSvc.NotifyRelationshipAdded(ctx, msg.EntityID, msg.ActorID)
+	default:
+		return fmt.Errorf("unknown notification type %s", msg.Type)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/repository"
 	"silsilah-keluarga/internal/service/graph"
+	"silsilah-keluarga/internal/service/helpers"
 	"silsilah-keluarga/internal/service/notification"
+	"silsilah-keluarga/internal/service/outbox"
 )
 
 type Service interface {
@@ -18,25 +20,50 @@ type Service interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Person, error)
 	GetByIDWithRelationships(ctx context.Context, personID uuid.UUID) (*domain.PersonWithRelationships, error)
 	Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, input domain.UpdatePersonInput) (*domain.Person, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 	List(ctx context.Context, params domain.PaginationParams) (domain.PaginatedResponse[domain.Person], error)
-	Search(ctx context.Context, query string, limit int) ([]domain.Person, error)
+	Search(ctx context.Context, input domain.PersonSearchInput) (*domain.PersonSearchResult, error)
 	GetAncestors(ctx context.Context, personID uuid.UUID) ([]domain.Person, error)
+	Merge(ctx context.Context, userID, sourceID uuid.UUID, input domain.MergePersonInput) (*domain.MergeResult, error)
+	// ValidateCreate, ValidateUpdate and ValidateMerge run the checks of
+	// Create, Update and Merge without saving, for changes that are applied
+	// later.
+	ValidateCreate(ctx context.Context, input domain.CreatePersonInput) error
+	ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdatePersonInput) error
+	ValidateMerge(ctx context.Context, sourceID uuid.UUID, input domain.MergePersonInput) error
 	SetNotificationService(notifSvc notification.Service)
+	SetOutbox(outboxSvc outbox.Service)
+	SetDuplicateDetector(detector DuplicateDetector)
+	SetCitationRepository(citationRepo repository.CitationRepository)
+	SetCustomFieldRepository(customFieldRepo repository.CustomFieldRepository)
+}
+
+// DuplicateDetector finds existing persons that a new person would likely
+// duplicate.
+type DuplicateDetector interface {
+	CheckNew(ctx context.Context, input domain.CreatePersonInput) ([]domain.DuplicateCandidate, error)
 }
 
 type service struct {
 	personRepo       repository.PersonRepository
 	relationshipRepo repository.RelationshipRepository
+	nameRepo         repository.PersonNameRepository
+	placeRepo        repository.PlaceRepository
+	citationRepo     repository.CitationRepository
+	customFieldRepo  repository.CustomFieldRepository
 	auditRepo        repository.AuditLogRepository
 	redis            *redis.Client
 	notifSvc         notification.Service
+	outbox           outbox.Service
+	duplicates       DuplicateDetector
 }
 
-func NewService(personRepo repository.PersonRepository, relationshipRepo repository.RelationshipRepository, auditRepo repository.AuditLogRepository, redis *redis.Client) Service {
+func NewService(personRepo repository.PersonRepository, relationshipRepo repository.RelationshipRepository, nameRepo repository.PersonNameRepository, placeRepo repository.PlaceRepository, auditRepo repository.AuditLogRepository, redis *redis.Client) Service {
 	return &service{
 		personRepo:       personRepo,
 		relationshipRepo: relationshipRepo,
+		nameRepo:         nameRepo,
+		placeRepo:        placeRepo,
 		auditRepo:        auditRepo,
 		redis:            redis,
 	}
@@ -46,32 +73,38 @@ func (s *service) SetNotificationService(notifSvc notification.Service) {
 	s.notifSvc = notifSvc
 }
 
+// SetOutbox sends notifications through the outbox, so a person created
+// inside a transaction is only announced once it commits.
+func (s *service) SetOutbox(outboxSvc outbox.Service) {
+	s.outbox = outboxSvc
+}
+
+func (s *service) SetDuplicateDetector(detector DuplicateDetector) {
+	s.duplicates = detector
+}
+
+func (s *service) SetCitationRepository(citationRepo repository.CitationRepository) {
+	s.citationRepo = citationRepo
+}
+
+func (s *service) SetCustomFieldRepository(customFieldRepo repository.CustomFieldRepository) {
+	s.customFieldRepo = customFieldRepo
+}
+
 func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.CreatePersonInput) (*domain.Person, error) {
-	isAlive := true
-	if input.IsAlive != nil {
-		isAlive = *input.IsAlive
+	person, err := s.buildNew(ctx, userID, input)
+	if err != nil {
+		return nil, err
 	}
 
-	person := &domain.Person{
-		ID:          uuid.New(),
-		FirstName:   input.FirstName,
-		LastName:    input.LastName,
-		Nickname:    input.Nickname,
-		Gender:      input.Gender,
-		BirthDate:   input.BirthDate,
-		BirthPlace:  input.BirthPlace,
-		DeathDate:   input.DeathDate,
-		DeathPlace:  input.DeathPlace,
-		Bio:         input.Bio,
-		Occupation:  input.Occupation,
-		Religion:    input.Religion,
-		Nationality: input.Nationality,
-		Education:   input.Education,
-		Phone:       input.Phone,
-		Email:       input.Email,
-		Address:     input.Address,
-		IsAlive:     isAlive,
-		CreatedBy:   userID,
+	if s.duplicates != nil && !input.IgnoreDuplicates {
+		candidates, err := s.duplicates.CheckNew(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			return nil, &domain.DuplicateWarningError{Candidates: candidates}
+		}
 	}
 
 	if err := s.personRepo.Create(ctx, person); err != nil {
@@ -90,13 +123,99 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.Cre
 		_ = s.redis.Del(ctx, "family:graph").Err()
 	}
 
+	s.notifyPersonAdded(ctx, person.ID, userID)
+
+	return person, nil
+}
+
+func (s *service) ValidateCreate(ctx context.Context, input domain.CreatePersonInput) error {
+	_, err := s.buildNew(ctx, uuid.Nil, input)
+	return err
+}
+
+// buildNew turns the input into a person and checks it.
+func (s *service) buildNew(ctx context.Context, userID uuid.UUID, input domain.CreatePersonInput) (*domain.Person, error) {
+	isAlive := true
+	if input.IsAlive != nil {
+		isAlive = *input.IsAlive
+	}
+
+	person := &domain.Person{
+		ID:           uuid.New(),
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		Nickname:     input.Nickname,
+		Gender:       input.Gender,
+		BirthDate:    input.BirthDate,
+		BirthPlace:   input.BirthPlace,
+		BirthPlaceID: input.BirthPlaceID,
+		DeathDate:    input.DeathDate,
+		DeathPlace:   input.DeathPlace,
+		DeathPlaceID: input.DeathPlaceID,
+		Bio:          input.Bio,
+		Occupation:   input.Occupation,
+		Religion:     input.Religion,
+		Nationality:  input.Nationality,
+		Education:    input.Education,
+		Phone:        input.Phone,
+		Email:        input.Email,
+		Address:      input.Address,
+		IsAlive:      isAlive,
+		PrivacyLevel: domain.PrivacyFamily,
+		CreatedBy:    userID,
+	}
+	if input.PrivacyLevel != nil {
+		if !input.PrivacyLevel.IsValid() {
+			return nil, domain.ErrInvalidPrivacyLevel
+		}
+		person.PrivacyLevel = *input.PrivacyLevel
+	}
+
+	if err := person.ValidateLifeDates(); err != nil {
+		return nil, err
+	}
+	if err := s.resolvePlace(ctx, person.BirthPlaceID, &person.BirthPlace, false); err != nil {
+		return nil, err
+	}
+	if err := s.resolvePlace(ctx, person.DeathPlaceID, &person.DeathPlace, false); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+func (s *service) notifyPersonAdded(ctx context.Context, personID, userID uuid.UUID) {
+	if s.outbox != nil {
+		// Inside a transaction the message is not visible until the
+		// commit; the owner of the transaction dispatches it then.
+		if err := s.outbox.Enqueue(ctx, domain.NotifPersonAdded, personID, userID); err == nil && !repository.InTx(ctx) {
+			go func() {
+				_, _ = s.outbox.Dispatch(context.Background())
+			}()
+		}
+		return
+	}
 	if s.notifSvc != nil {
 		go func() {
-			_ = s.notifSvc.NotifyPersonAdded(context.Background(), person.ID, userID)
+			_ = s.notifSvc.NotifyPersonAdded(context.Background(), personID, userID)
 		}()
 	}
+}
 
-	return person, nil
+// resolvePlace checks a referenced place and keeps the free-text fallback
+// in step with it: the text is filled from the place's path when empty, or
+// always when overwrite is set.
+func (s *service) resolvePlace(ctx context.Context, placeID *uuid.UUID, text **string, overwrite bool) error {
+	if placeID == nil {
+		return nil
+	}
+	name, err := helpers.PlaceFullName(ctx, s.placeRepo, *placeID)
+	if err != nil {
+		return err
+	}
+	if overwrite || *text == nil || **text == "" {
+		*text = &name
+	}
+	return nil
 }
 
 func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Person, error) {
@@ -120,7 +239,44 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, in
 	}
 
 	oldPerson := *person
+	if err := s.applyUpdate(ctx, person, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.personRepo.Update(ctx, person); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "PERSON",
+		EntityID:   person.ID,
+		OldValue:   oldPerson,
+		NewValue:   *person,
+	})
+
+	if s.redis != nil {
+		_ = s.redis.Del(ctx, "family:graph").Err()
+	}
 
+	return person, nil
+}
+
+func (s *service) ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdatePersonInput) error {
+	person, err := s.personRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if person == nil {
+		return domain.ErrPersonNotFound
+	}
+	return s.applyUpdate(ctx, person, input)
+}
+
+// applyUpdate writes the fields set in input onto person and checks the
+// result.
+func (s *service) applyUpdate(ctx context.Context, person *domain.Person, input domain.UpdatePersonInput) error {
 	if input.FirstName != nil {
 		person.FirstName = *input.FirstName
 	}
@@ -149,6 +305,18 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, in
 	if input.DeathPlace.Set {
 		person.DeathPlace = input.DeathPlace.Value
 	}
+	if input.BirthPlaceID.Set {
+		person.BirthPlaceID = input.BirthPlaceID.Value
+		if err := s.resolvePlace(ctx, person.BirthPlaceID, &person.BirthPlace, !input.BirthPlace.Set); err != nil {
+			return err
+		}
+	}
+	if input.DeathPlaceID.Set {
+		person.DeathPlaceID = input.DeathPlaceID.Value
+		if err := s.resolvePlace(ctx, person.DeathPlaceID, &person.DeathPlace, !input.DeathPlace.Set); err != nil {
+			return err
+		}
+	}
 	if input.Bio.Set {
 		person.Bio = input.Bio.Value
 	}
@@ -179,37 +347,125 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, in
 	if input.IsAlive != nil {
 		person.IsAlive = *input.IsAlive
 	}
+	if input.PrivacyLevel != nil {
+		if !input.PrivacyLevel.IsValid() {
+			return domain.ErrInvalidPrivacyLevel
+		}
+		person.PrivacyLevel = *input.PrivacyLevel
+	}
 
-	if err := s.personRepo.Update(ctx, person); err != nil {
-		return nil, err
+	return person.ValidateLifeDates()
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	person, err := s.personRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if person == nil {
+		return domain.ErrPersonNotFound
+	}
+
+	deletionID, err := s.personRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return err
 	}
 
 	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
 		UserID:     userID,
-		Action:     "UPDATE",
+		Action:     "DELETE",
 		EntityType: "PERSON",
-		EntityID:   person.ID,
-		OldValue:   oldPerson,
-		NewValue:   *person,
+		EntityID:   id,
+		OldValue:   person,
+		NewValue:   map[string]any{"deletion_id": deletionID},
 	})
 
 	if s.redis != nil {
 		_ = s.redis.Del(ctx, "family:graph").Err()
 	}
 
-	return person, nil
+	return nil
 }
 
-func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := s.personRepo.Delete(ctx, id); err != nil {
-		return err
+// Merge folds the source person into input.TargetID, which survives with
+// the chosen field values and inherits everything linked to the source.
+func (s *service) Merge(ctx context.Context, userID, sourceID uuid.UUID, input domain.MergePersonInput) (*domain.MergeResult, error) {
+	source, oldTarget, target, err := s.prepareMerge(ctx, sourceID, input)
+	if err != nil {
+		return nil, err
 	}
 
+	result, err := s.personRepo.Merge(ctx, target, source.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "MERGE",
+		EntityType: "PERSON",
+		EntityID:   target.ID,
+		OldValue: map[string]any{
+			"survivor": oldTarget,
+			"merged":   source,
+		},
+		NewValue: map[string]any{
+			"fields": input.Fields,
+			"result": result,
+		},
+	})
+
 	if s.redis != nil {
 		_ = s.redis.Del(ctx, "family:graph").Err()
 	}
 
-	return nil
+	return result, nil
+}
+
+func (s *service) ValidateMerge(ctx context.Context, sourceID uuid.UUID, input domain.MergePersonInput) error {
+	_, _, _, err := s.prepareMerge(ctx, sourceID, input)
+	return err
+}
+
+// prepareMerge loads both persons, checks the merge can go ahead and returns
+// the source, the target as stored and the target with the chosen field
+// values applied.
+func (s *service) prepareMerge(ctx context.Context, sourceID uuid.UUID, input domain.MergePersonInput) (source, target, merged *domain.Person, err error) {
+	if sourceID == input.TargetID {
+		return nil, nil, nil, domain.ErrMergeSamePerson
+	}
+
+	source, err = s.personRepo.GetByID(ctx, sourceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	target, err = s.personRepo.GetByID(ctx, input.TargetID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if source == nil || target == nil {
+		return nil, nil, nil, domain.ErrPersonNotFound
+	}
+
+	// Each user links to at most one person, so the survivor can only
+	// take over the link when at most one of the two has one.
+	linked, err := s.personRepo.CountLinkedUsers(ctx, []uuid.UUID{source.ID, target.ID})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if linked > 1 {
+		return nil, nil, nil, domain.ErrMergeLinkedUsers
+	}
+
+	merged = new(domain.Person)
+	*merged = *target
+	if err := domain.ApplyMergeChoices(merged, source, input.Fields); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := merged.ValidateLifeDates(); err != nil {
+		return nil, nil, nil, err
+	}
+	return source, target, merged, nil
 }
 
 func (s *service) List(ctx context.Context, params domain.PaginationParams) (domain.PaginatedResponse[domain.Person], error) {
@@ -221,8 +477,28 @@ func (s *service) List(ctx context.Context, params domain.PaginationParams) (dom
 	return domain.NewPaginatedResponse(persons, params.Page, params.PageSize, total), nil
 }
 
-func (s *service) Search(ctx context.Context, query string, limit int) ([]domain.Person, error) {
-	return s.personRepo.Search(ctx, query, limit)
+func (s *service) Search(ctx context.Context, input domain.PersonSearchInput) (*domain.PersonSearchResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	persons, total, err := s.personRepo.Search(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := s.personRepo.SearchFacets(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PersonSearchResult{
+		Data:       persons,
+		TotalItems: total,
+		Limit:      input.Limit,
+		Offset:     input.Offset,
+		Facets:     facets,
+	}, nil
 }
 
 func (s *service) GetByIDWithRelationships(ctx context.Context, personID uuid.UUID) (*domain.PersonWithRelationships, error) {
@@ -234,6 +510,10 @@ func (s *service) GetByIDWithRelationships(ctx context.Context, personID uuid.UU
 		return nil, domain.ErrPersonNotFound
 	}
 
+	if names, err := s.nameRepo.ListByPerson(ctx, personID); err == nil {
+		person.Names = names
+	}
+
 	result := &domain.PersonWithRelationships{
 		Person:        *person,
 		Parents:       []domain.ParentInfo{},
@@ -241,6 +521,21 @@ func (s *service) GetByIDWithRelationships(ctx context.Context, personID uuid.UU
 		Children:      []domain.Person{},
 		Siblings:      []domain.SiblingInfo{},
 		Relationships: []domain.RelationshipInfo{},
+		Citations:     []domain.Citation{},
+	}
+
+	if s.citationRepo != nil {
+		if citations, err := s.citationRepo.ListByEntity(ctx, domain.EntityPerson, personID); err == nil {
+			result.Citations = citations
+		}
+	}
+
+	// All custom values are loaded; callers drop the ones the viewer may
+	// not see.
+	if s.customFieldRepo != nil {
+		if values, err := s.customFieldRepo.GetValuesByPerson(ctx, personID); err == nil {
+			result.CustomFields = values
+		}
 	}
 
 	relationships, err := s.relationshipRepo.GetByPerson(ctx, personID)
@@ -265,6 +560,9 @@ func (s *service) GetByIDWithRelationships(ctx context.Context, personID uuid.UU
 						Person: *p,
 						Role:   role,
 					})
+					if role == string(domain.ParentRoleFather) {
+						result.Patronymic = domain.Patronymic(person, p)
+					}
 					result.Relationships = append(result.Relationships, domain.RelationshipInfo{
 						ID:            rel.ID,
 						Type:          "PARENT",
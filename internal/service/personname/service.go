@@ -0,0 +1,173 @@
+package personname
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, personID uuid.UUID, input domain.CreatePersonNameInput) (*domain.PersonName, error)
+	Update(ctx context.Context, userID uuid.UUID, personID uuid.UUID, id uuid.UUID, input domain.UpdatePersonNameInput) (*domain.PersonName, error)
+	Delete(ctx context.Context, userID uuid.UUID, personID uuid.UUID, id uuid.UUID) error
+	ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonName, error)
+}
+
+type service struct {
+	nameRepo   repository.PersonNameRepository
+	personRepo repository.PersonRepository
+	auditRepo  repository.AuditLogRepository
+	redis      *redis.Client
+}
+
+func NewService(nameRepo repository.PersonNameRepository, personRepo repository.PersonRepository, auditRepo repository.AuditLogRepository, redis *redis.Client) Service {
+	return &service{
+		nameRepo:   nameRepo,
+		personRepo: personRepo,
+		auditRepo:  auditRepo,
+		redis:      redis,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, personID uuid.UUID, input domain.CreatePersonNameInput) (*domain.PersonName, error) {
+	if !input.Type.IsValid() {
+		return nil, domain.ErrInvalidNameType
+	}
+
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, domain.ErrPersonNotFound
+	}
+
+	name := &domain.PersonName{
+		ID:        uuid.New(),
+		PersonID:  personID,
+		Type:      input.Type,
+		Name:      input.Name,
+		ValidFrom: input.ValidFrom,
+		ValidTo:   input.ValidTo,
+		CreatedBy: userID,
+	}
+	if err := name.ValidateValidity(); err != nil {
+		return nil, err
+	}
+
+	if err := s.nameRepo.Create(ctx, name); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "CREATE",
+		EntityType: "PERSON_NAME",
+		EntityID:   name.ID,
+		NewValue:   name,
+	})
+
+	s.invalidateGraph(ctx)
+
+	return name, nil
+}
+
+func (s *service) Update(ctx context.Context, userID uuid.UUID, personID uuid.UUID, id uuid.UUID, input domain.UpdatePersonNameInput) (*domain.PersonName, error) {
+	name, err := s.getForPerson(ctx, personID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := *name
+
+	if input.Type != nil {
+		if !input.Type.IsValid() {
+			return nil, domain.ErrInvalidNameType
+		}
+		name.Type = *input.Type
+	}
+	if input.Name != nil {
+		name.Name = *input.Name
+	}
+	if input.ValidFrom.Set {
+		name.ValidFrom = input.ValidFrom.Value
+	}
+	if input.ValidTo.Set {
+		name.ValidTo = input.ValidTo.Value
+	}
+	if err := name.ValidateValidity(); err != nil {
+		return nil, err
+	}
+
+	if err := s.nameRepo.Update(ctx, name); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "PERSON_NAME",
+		EntityID:   name.ID,
+		OldValue:   oldName,
+		NewValue:   *name,
+	})
+
+	s.invalidateGraph(ctx)
+
+	return name, nil
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, personID uuid.UUID, id uuid.UUID) error {
+	name, err := s.getForPerson(ctx, personID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.nameRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "DELETE",
+		EntityType: "PERSON_NAME",
+		EntityID:   id,
+		OldValue:   name,
+	})
+
+	s.invalidateGraph(ctx)
+
+	return nil
+}
+
+func (s *service) ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonName, error) {
+	names, err := s.nameRepo.ListByPerson(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if names == nil {
+		names = []domain.PersonName{}
+	}
+	return names, nil
+}
+
+func (s *service) getForPerson(ctx context.Context, personID uuid.UUID, id uuid.UUID) (*domain.PersonName, error) {
+	name, err := s.nameRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if name == nil || name.PersonID != personID {
+		return nil, domain.ErrPersonNameNotFound
+	}
+	return name, nil
+}
+
+func (s *service) invalidateGraph(ctx context.Context) {
+	if s.redis != nil {
+		_ = s.redis.Del(ctx, "family:graph").Err()
+	}
+}
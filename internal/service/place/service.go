@@ -0,0 +1,262 @@
+package place
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/gazetteer"
+	"silsilah-keluarga/internal/repository"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, input domain.CreatePlaceInput) (*domain.Place, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.Place, error)
+	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdatePlaceInput) (*domain.Place, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	List(ctx context.Context, parentID *uuid.UUID, level *domain.PlaceLevel) ([]domain.Place, error)
+	Search(ctx context.Context, query string, limit int) ([]domain.Place, error)
+	ImportGazetteer(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	placeRepo repository.PlaceRepository
+	auditRepo repository.AuditLogRepository
+}
+
+func NewService(placeRepo repository.PlaceRepository, auditRepo repository.AuditLogRepository) Service {
+	return &service{
+		placeRepo: placeRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.CreatePlaceInput) (*domain.Place, error) {
+	parent, err := s.getParent(ctx, input.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidatePlaceParent(input.Level, parent); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateCoordinates(input.Latitude, input.Longitude); err != nil {
+		return nil, err
+	}
+
+	place := &domain.Place{
+		ID:              uuid.New(),
+		ParentID:        input.ParentID,
+		Level:           input.Level,
+		Name:            input.Name,
+		Latitude:        input.Latitude,
+		Longitude:       input.Longitude,
+		HistoricalNames: input.HistoricalNames,
+		CreatedBy:       &userID,
+	}
+	if place.HistoricalNames == nil {
+		place.HistoricalNames = domain.HistoricalNames{}
+	}
+
+	if err := s.placeRepo.Create(ctx, place); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "CREATE",
+		EntityType: "PLACE",
+		EntityID:   place.ID,
+		NewValue:   place,
+	})
+
+	return s.withFullName(ctx, place)
+}
+
+func (s *service) Get(ctx context.Context, id uuid.UUID) (*domain.Place, error) {
+	place, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if place == nil {
+		return nil, domain.ErrPlaceNotFound
+	}
+	return s.withFullName(ctx, place)
+}
+
+func (s *service) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdatePlaceInput) (*domain.Place, error) {
+	place, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if place == nil {
+		return nil, domain.ErrPlaceNotFound
+	}
+
+	oldPlace := *place
+
+	if input.ParentID.Set {
+		if input.ParentID.Value != nil && *input.ParentID.Value == id {
+			return nil, domain.ErrInvalidPlaceHierarchy
+		}
+		place.ParentID = input.ParentID.Value
+	}
+	if input.Level != nil {
+		place.Level = *input.Level
+	}
+	if input.Name != nil {
+		place.Name = *input.Name
+	}
+	if input.Latitude != nil || input.Longitude != nil {
+		place.Latitude, place.Longitude = input.Latitude, input.Longitude
+	}
+	if input.HistoricalNames != nil {
+		place.HistoricalNames = *input.HistoricalNames
+	}
+
+	parent, err := s.getParent(ctx, place.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidatePlaceParent(place.Level, parent); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateCoordinates(place.Latitude, place.Longitude); err != nil {
+		return nil, err
+	}
+	if input.Level != nil && *input.Level != oldPlace.Level {
+		if err := s.validateChildren(ctx, place); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.placeRepo.Update(ctx, place); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "PLACE",
+		EntityID:   place.ID,
+		OldValue:   oldPlace,
+		NewValue:   *place,
+	})
+
+	return s.withFullName(ctx, place)
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	place, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if place == nil {
+		return domain.ErrPlaceNotFound
+	}
+
+	refs, err := s.placeRepo.CountReferences(ctx, id)
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		return fmt.Errorf("%w: %d references", domain.ErrPlaceInUse, refs)
+	}
+
+	if err := s.placeRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "DELETE",
+		EntityType: "PLACE",
+		EntityID:   id,
+		OldValue:   place,
+	})
+
+	return nil
+}
+
+func (s *service) List(ctx context.Context, parentID *uuid.UUID, level *domain.PlaceLevel) ([]domain.Place, error) {
+	if level != nil && !level.IsValid() {
+		return nil, domain.ErrInvalidPlaceLevel
+	}
+	return s.placeRepo.List(ctx, parentID, level)
+}
+
+func (s *service) Search(ctx context.Context, query string, limit int) ([]domain.Place, error) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	places, err := s.placeRepo.Search(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range places {
+		if path, err := s.placeRepo.GetAncestors(ctx, places[i].ID); err == nil && len(path) > 0 {
+			places[i].FullName = domain.PlacePath(path)
+		}
+	}
+	return places, nil
+}
+
+// ImportGazetteer loads the bundled Indonesian gazetteer, adding regions
+// that are not in the database yet.
+func (s *service) ImportGazetteer(ctx context.Context) (int64, error) {
+	entries, err := gazetteer.Load()
+	if err != nil {
+		return 0, err
+	}
+	return s.placeRepo.ImportGazetteer(ctx, entries)
+}
+
+func (s *service) getParent(ctx context.Context, parentID *uuid.UUID) (*domain.Place, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+	parent, err := s.placeRepo.GetByID(ctx, *parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("%w: parent", domain.ErrPlaceNotFound)
+	}
+	return parent, nil
+}
+
+// validateChildren keeps existing sub-places below a place whose level
+// changes.
+func (s *service) validateChildren(ctx context.Context, place *domain.Place) error {
+	children, err := s.placeRepo.List(ctx, &place.ID, nil)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := domain.ValidatePlaceParent(child.Level, place); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) withFullName(ctx context.Context, place *domain.Place) (*domain.Place, error) {
+	path, err := s.placeRepo.GetAncestors(ctx, place.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) > 0 {
+		place.FullName = domain.PlacePath(path)
+	}
+	return place, nil
+}
@@ -12,7 +12,9 @@ import (
 	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/repository"
 	"silsilah-keluarga/internal/service/graph"
+	"silsilah-keluarga/internal/service/helpers"
 	"silsilah-keluarga/internal/service/notification"
+	"silsilah-keluarga/internal/service/outbox"
 )
 
 var (
@@ -21,16 +23,24 @@ var (
 	ErrInvalidRelationType   = errors.New("invalid relationship type")
 	ErrDuplicateRelationship = errors.New("relationship already exists")
 	ErrDuplicateParentRole   = errors.New("person already has a parent with this role")
+	ErrRelationshipCycle     = errors.New("cycle detected: cannot make a descendant a parent")
+	ErrParentYounger         = errors.New("invalid relationship: parent cannot be younger than child")
 )
 
 type Service interface {
 	Create(ctx context.Context, userID uuid.UUID, input domain.CreateRelationshipInput) (*domain.Relationship, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Relationship, error)
 	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateRelationshipInput) (*domain.Relationship, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 	List(ctx context.Context, relType *domain.RelationshipType) ([]domain.Relationship, error)
 	ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Relationship, error)
+	// ValidateCreate and ValidateUpdate run the checks of Create and Update
+	// without saving, for changes that are applied later.
+	ValidateCreate(ctx context.Context, input domain.CreateRelationshipInput) error
+	ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateRelationshipInput) error
 	SetNotificationService(notifSvc notification.Service)
+	SetOutbox(outboxSvc outbox.Service)
+	SetPlaceRepository(placeRepo repository.PlaceRepository)
 }
 
 type service struct {
@@ -39,6 +49,8 @@ type service struct {
 	auditRepo  repository.AuditLogRepository
 	redis      *redis.Client
 	notifSvc   notification.Service
+	outbox     outbox.Service
+	placeRepo  repository.PlaceRepository
 }
 
 func NewService(relRepo repository.RelationshipRepository, personRepo repository.PersonRepository, auditRepo repository.AuditLogRepository, redis *redis.Client) Service {
@@ -54,24 +66,46 @@ func (s *service) SetNotificationService(notifSvc notification.Service) {
 	s.notifSvc = notifSvc
 }
 
-func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.CreateRelationshipInput) (*domain.Relationship, error) {
-	personA, err := s.personRepo.GetByID(ctx, input.PersonA)
-	if err != nil {
-		return nil, err
+// SetOutbox sends notifications through the outbox, so a relationship
+// created inside a transaction is only announced once it commits.
+func (s *service) SetOutbox(outboxSvc outbox.Service) {
+	s.outbox = outboxSvc
+}
+
+func (s *service) SetPlaceRepository(placeRepo repository.PlaceRepository) {
+	s.placeRepo = placeRepo
+}
+
+// resolveMarriagePlace checks the marriage place of spouse metadata and
+// fills the free-text marriage place from it when empty.
+func (s *service) resolveMarriagePlace(ctx context.Context, relType domain.RelationshipType, metadata json.RawMessage) (json.RawMessage, error) {
+	if relType != domain.RelTypeSpouse || s.placeRepo == nil || len(metadata) == 0 {
+		return metadata, nil
 	}
-	if personA == nil {
-		return nil, domain.ErrPersonNotFound
+	var meta domain.SpouseMetadata
+	if err := json.Unmarshal(metadata, &meta); err != nil || meta.MarriagePlaceID == nil {
+		return metadata, nil
 	}
-
-	personB, err := s.personRepo.GetByID(ctx, input.PersonB)
+	name, err := helpers.PlaceFullName(ctx, s.placeRepo, *meta.MarriagePlaceID)
 	if err != nil {
 		return nil, err
 	}
-	if personB == nil {
-		return nil, domain.ErrPersonNotFound
+	if meta.MarriagePlace != nil && *meta.MarriagePlace != "" {
+		return metadata, nil
 	}
 
-	if err := s.validateRelationship(ctx, personA, personB, input.Type); err != nil {
+	// Keep keys SpouseMetadata does not know about.
+	var raw map[string]any
+	if err := json.Unmarshal(metadata, &raw); err != nil {
+		return metadata, nil
+	}
+	raw["marriage_place"] = name
+	return json.Marshal(raw)
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.CreateRelationshipInput) (*domain.Relationship, error) {
+	personA, personB, err := s.loadPair(ctx, input)
+	if err != nil {
 		return nil, err
 	}
 
@@ -94,6 +128,11 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.Cre
 		}
 	}
 
+	input.Metadata, err = s.resolveMarriagePlace(ctx, input.Type, input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	rel := &domain.Relationship{
 		ID:        uuid.New(),
 		PersonA:   input.PersonA,
@@ -128,13 +167,72 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, input domain.Cre
 		},
 	})
 
+	s.notifyRelationshipAdded(ctx, rel.ID, userID)
+
+	return rel, nil
+}
+
+func (s *service) ValidateCreate(ctx context.Context, input domain.CreateRelationshipInput) error {
+	_, _, err := s.loadPair(ctx, input)
+	if err != nil {
+		return err
+	}
+	_, err = s.resolveMarriagePlace(ctx, input.Type, input.Metadata)
+	return err
+}
+
+// loadPair fetches both persons of a new relationship and validates it.
+func (s *service) loadPair(ctx context.Context, input domain.CreateRelationshipInput) (*domain.Person, *domain.Person, error) {
+	if !input.Type.IsValid() {
+		return nil, nil, ErrInvalidRelationType
+	}
+
+	personA, err := s.personRepo.GetByID(ctx, input.PersonA)
+	if err != nil {
+		return nil, nil, err
+	}
+	if personA == nil {
+		return nil, nil, domain.ErrPersonNotFound
+	}
+
+	personB, err := s.personRepo.GetByID(ctx, input.PersonB)
+	if err != nil {
+		return nil, nil, err
+	}
+	if personB == nil {
+		return nil, nil, domain.ErrPersonNotFound
+	}
+
+	if err := s.validateRelationship(ctx, personA, personB, input.Type); err != nil {
+		return nil, nil, err
+	}
+
+	existing, err := s.relRepo.FindPair(ctx, personA.ID, personB.ID, input.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		return nil, nil, ErrDuplicateRelationship
+	}
+	return personA, personB, nil
+}
+
+func (s *service) notifyRelationshipAdded(ctx context.Context, relID, userID uuid.UUID) {
+	if s.outbox != nil {
+		// Inside a transaction the message is not visible until the
+		// commit; the owner of the transaction dispatches it then.
+		if err := s.outbox.Enqueue(ctx, domain.NotifRelationshipAdded, relID, userID); err == nil && !repository.InTx(ctx) {
+			go func() {
+				_, _ = s.outbox.Dispatch(context.Background())
+			}()
+		}
+		return
+	}
 	if s.notifSvc != nil {
 		go func() {
-			_ = s.notifSvc.NotifyRelationshipAdded(context.Background(), rel.ID, userID)
+			_ = s.notifSvc.NotifyRelationshipAdded(context.Background(), relID, userID)
 		}()
 	}
-
-	return rel, nil
 }
 
 func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Relationship, error) {
@@ -160,7 +258,10 @@ func (s *service) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, in
 	oldValue := *rel
 
 	if input.Metadata != nil {
-		rel.Metadata = input.Metadata
+		rel.Metadata, err = s.resolveMarriagePlace(ctx, rel.Type, input.Metadata)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.relRepo.Update(ctx, rel); err != nil {
@@ -183,11 +284,26 @@ func (s *service) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, in
 	return rel, nil
 }
 
-func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+func (s *service) ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateRelationshipInput) error {
+	rel, err := s.relRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rel == nil {
+		return ErrRelationshipNotFound
+	}
+	if input.Metadata == nil {
+		return nil
+	}
+	_, err = s.resolveMarriagePlace(ctx, rel.Type, input.Metadata)
+	return err
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
 	if s.redis != nil {
 		_ = s.redis.Del(ctx, "family:graph").Err()
 	}
-	return s.relRepo.Delete(ctx, id)
+	return s.relRepo.Delete(ctx, id, userID)
 }
 
 func (s *service) List(ctx context.Context, relType *domain.RelationshipType) ([]domain.Relationship, error) {
@@ -207,7 +323,7 @@ func stringPtrValue(s *string) string {
 
 func (s *service) validateRelationship(ctx context.Context, personA, personB *domain.Person, relType domain.RelationshipType) error {
 	if personA.ID == personB.ID {
-		return errors.New("cannot create relationship with self")
+		return ErrSelfRelation
 	}
 
 	if relType == domain.RelTypeParent {
@@ -215,14 +331,14 @@ func (s *service) validateRelationship(ctx context.Context, personA, personB *do
 		if err == nil {
 			for _, d := range descendants {
 				if d.ID == personB.ID {
-					return errors.New("cycle detected: cannot make a descendant a parent")
+					return ErrRelationshipCycle
 				}
 			}
 		}
 
 		if personA.BirthDate != nil && personB.BirthDate != nil {
-			if personB.BirthDate.After(*personA.BirthDate) {
-				return errors.New("invalid relationship: parent cannot be younger than child")
+			if personB.BirthDate.After(personA.BirthDate) {
+				return ErrParentYounger
 			}
 		}
 	}
@@ -10,15 +10,25 @@ import (
 	"silsilah-keluarga/internal/service/auth"
 	"silsilah-keluarga/internal/service/changerequest"
 	"silsilah-keluarga/internal/service/comment"
+	"silsilah-keluarga/internal/service/customfield"
 	"silsilah-keluarga/internal/service/dashboard"
+	"silsilah-keluarga/internal/service/duplicate"
 	"silsilah-keluarga/internal/service/email"
+	"silsilah-keluarga/internal/service/event"
 	"silsilah-keluarga/internal/service/export"
 	"silsilah-keluarga/internal/service/graph"
+	"silsilah-keluarga/internal/service/living"
 	"silsilah-keluarga/internal/service/media"
 	"silsilah-keluarga/internal/service/narrative"
 	"silsilah-keluarga/internal/service/notification"
+	"silsilah-keluarga/internal/service/outbox"
 	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/internal/service/personname"
+	"silsilah-keluarga/internal/service/place"
 	"silsilah-keluarga/internal/service/relationship"
+	"silsilah-keluarga/internal/service/source"
+	"silsilah-keluarga/internal/service/timeline"
+	"silsilah-keluarga/internal/service/trash"
 	"silsilah-keluarga/internal/service/user"
 )
 
@@ -26,7 +36,11 @@ type Services struct {
 	Auth          auth.Service
 	User          user.Service
 	Person        person.Service
+	PersonName    personname.Service
+	Duplicate     duplicate.Service
 	Relationship  relationship.Service
+	Event         event.Service
+	Timeline      timeline.Service
 	Graph         graph.Service
 	ChangeRequest changerequest.Service
 	Media         media.Service
@@ -37,22 +51,36 @@ type Services struct {
 	Dashboard     dashboard.Service
 	Narrative     narrative.Service
 	Export        export.Service
+	Place         place.Service
+	Source        source.Service
+	Trash         trash.Service
+	CustomField   customfield.Service
+	Living        living.Service
+	Outbox        outbox.Service
 }
 
 func NewServices(repos *repository.Repositories, redis *redis.Client, minioClient *minio.Client, cfg *config.Config) *Services {
 	emailService := email.NewService(cfg)
 	authService := auth.NewService(repos.User, repos.Session, emailService, cfg)
-	personService := person.NewService(repos.Person, repos.Relationship, repos.AuditLog, redis)
+	personService := person.NewService(repos.Person, repos.Relationship, repos.PersonName, repos.Place, repos.AuditLog, redis)
+	duplicateService := duplicate.NewService(repos.Person, repos.Relationship)
+	personService.SetDuplicateDetector(duplicateService)
+	personService.SetCitationRepository(repos.Citation)
+	personService.SetCustomFieldRepository(repos.CustomField)
+	personNameService := personname.NewService(repos.PersonName, repos.Person, repos.AuditLog, redis)
 	auditService := audit.NewService(repos.AuditLog)
 	relationshipService := relationship.NewService(repos.Relationship, repos.Person, repos.AuditLog, redis)
+	eventService := event.NewService(repos.Event, repos.Person, repos.Relationship, repos.Place, repos.AuditLog)
 	mediaService := media.NewService(repos.Media, minioClient, cfg)
+	timelineService := timeline.NewService(repos.Person, repos.Relationship, repos.Event, mediaService)
 	narrativeService := narrative.NewService(repos.Person, repos.Relationship)
-	graphService := graph.NewService(repos.Person, repos.Relationship, redis, narrativeService)
+	graphService := graph.NewService(repos.Person, repos.Relationship, repos.PersonName, repos.Place, redis, narrativeService)
 	commentService := comment.NewService(repos.Comment, redis)
 	notificationService := notification.NewService(repos.Notification, repos.User, repos.ChangeRequest, repos.Comment, repos.Person, repos.Relationship, emailService)
 	commentService.SetNotificationService(notificationService)
 	personService.SetNotificationService(notificationService)
 	relationshipService.SetNotificationService(notificationService)
+	relationshipService.SetPlaceRepository(repos.Place)
 
 	changeRequestService := changerequest.NewService(
 		repos.ChangeRequest,
@@ -67,16 +95,35 @@ func NewServices(repos *repository.Repositories, redis *redis.Client, minioClien
 		mediaService,
 	)
 	changeRequestService.SetNotificationService(notificationService)
+	changeRequestService.SetDuplicateDetector(duplicateService)
+	changeRequestService.SetEventService(eventService)
+	changeRequestService.SetCommentService(commentService)
+	changeRequestService.SetApprovalRepository(repos.Approval)
+	changeRequestService.SetCommentRepository(repos.ChangeRequestComment)
+	changeRequestService.SetExpiry(cfg.ChangeRequestExpiry)
+	outboxService := outbox.NewService(repos.Outbox, notificationService)
+	personService.SetOutbox(outboxService)
+	relationshipService.SetOutbox(outboxService)
+	changeRequestService.SetTransactor(repos.Transactor, outboxService)
 
 	dashboardService := dashboard.NewService(repos.Person, repos.Relationship, repos.ChangeRequest, redis)
-	exportService := export.NewService(repos.Person, repos.Relationship, repos.AuditLog, graphService)
+	exportService := export.NewService(repos.Person, repos.Relationship, repos.PersonName, repos.Event, repos.Citation, repos.CustomField, repos.AuditLog, graphService)
 	userService := user.NewService(repos.User)
+	placeService := place.NewService(repos.Place, repos.AuditLog)
+	trashService := trash.NewService(repos.Trash, mediaService, repos.AuditLog, redis, cfg.TrashRetention)
+	sourceService := source.NewService(repos.Source, repos.Citation, repos.Person, repos.Relationship, repos.Event, repos.Media, repos.AuditLog)
+	customFieldService := customfield.NewService(repos.CustomField, repos.Person, repos.AuditLog)
+	livingService := living.NewService(repos.Person, repos.Relationship, repos.Event, repos.AuditLog, redis)
 
 	return &Services{
 		Auth:          authService,
 		User:          userService,
 		Person:        personService,
+		PersonName:    personNameService,
+		Duplicate:     duplicateService,
 		Relationship:  relationshipService,
+		Event:         eventService,
+		Timeline:      timelineService,
 		Graph:         graphService,
 		ChangeRequest: changeRequestService,
 		Media:         mediaService,
@@ -87,5 +134,11 @@ func NewServices(repos *repository.Repositories, redis *redis.Client, minioClien
 		Dashboard:     dashboardService,
 		Narrative:     narrativeService,
 		Export:        exportService,
+		Place:         placeService,
+		Source:        sourceService,
+		Trash:         trashService,
+		CustomField:   customFieldService,
+		Living:        livingService,
+		Outbox:        outboxService,
 	}
 }
@@ -0,0 +1,383 @@
+package source
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+)
+
+type Service interface {
+	CreateSource(ctx context.Context, userID uuid.UUID, input domain.CreateSourceInput) (*domain.Source, error)
+	GetSource(ctx context.Context, id uuid.UUID) (*domain.Source, error)
+	UpdateSource(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateSourceInput) (*domain.Source, error)
+	DeleteSource(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	ListSources(ctx context.Context, params domain.SourceListParams) (domain.PaginatedResponse[domain.Source], error)
+	ListSourceCitations(ctx context.Context, sourceID uuid.UUID) ([]domain.Citation, error)
+
+	CreateCitation(ctx context.Context, userID uuid.UUID, input domain.CreateCitationInput) (*domain.Citation, error)
+	UpdateCitation(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCitationInput) (*domain.Citation, error)
+	DeleteCitation(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	ListCitations(ctx context.Context, entityType domain.EntityType, entityID uuid.UUID) ([]domain.Citation, error)
+}
+
+type service struct {
+	sourceRepo   repository.SourceRepository
+	citationRepo repository.CitationRepository
+	personRepo   repository.PersonRepository
+	relRepo      repository.RelationshipRepository
+	eventRepo    repository.EventRepository
+	mediaRepo    repository.MediaRepository
+	auditRepo    repository.AuditLogRepository
+}
+
+func NewService(
+	sourceRepo repository.SourceRepository,
+	citationRepo repository.CitationRepository,
+	personRepo repository.PersonRepository,
+	relRepo repository.RelationshipRepository,
+	eventRepo repository.EventRepository,
+	mediaRepo repository.MediaRepository,
+	auditRepo repository.AuditLogRepository,
+) Service {
+	return &service{
+		sourceRepo:   sourceRepo,
+		citationRepo: citationRepo,
+		personRepo:   personRepo,
+		relRepo:      relRepo,
+		eventRepo:    eventRepo,
+		mediaRepo:    mediaRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+func (s *service) CreateSource(ctx context.Context, userID uuid.UUID, input domain.CreateSourceInput) (*domain.Source, error) {
+	if !input.Type.IsValid() {
+		return nil, domain.ErrInvalidSourceType
+	}
+
+	source := &domain.Source{
+		ID:          uuid.New(),
+		Type:        input.Type,
+		Title:       input.Title,
+		Author:      input.Author,
+		Publication: input.Publication,
+		Repository:  input.Repository,
+		URL:         input.URL,
+		Notes:       input.Notes,
+		CreatedBy:   userID,
+	}
+
+	if err := s.sourceRepo.Create(ctx, source); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "CREATE",
+		EntityType: "SOURCE",
+		EntityID:   source.ID,
+		NewValue:   source,
+	})
+
+	return source, nil
+}
+
+func (s *service) GetSource(ctx context.Context, id uuid.UUID) (*domain.Source, error) {
+	source, err := s.sourceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, domain.ErrSourceNotFound
+	}
+	return source, nil
+}
+
+func (s *service) UpdateSource(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateSourceInput) (*domain.Source, error) {
+	source, err := s.GetSource(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldSource := *source
+
+	if input.Type != nil {
+		if !input.Type.IsValid() {
+			return nil, domain.ErrInvalidSourceType
+		}
+		source.Type = *input.Type
+	}
+	if input.Title != nil {
+		source.Title = *input.Title
+	}
+	if input.Author.Set {
+		source.Author = input.Author.Value
+	}
+	if input.Publication.Set {
+		source.Publication = input.Publication.Value
+	}
+	if input.Repository.Set {
+		source.Repository = input.Repository.Value
+	}
+	if input.URL.Set {
+		source.URL = input.URL.Value
+	}
+	if input.Notes.Set {
+		source.Notes = input.Notes.Value
+	}
+
+	if err := s.sourceRepo.Update(ctx, source); err != nil {
+		return nil, err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "SOURCE",
+		EntityID:   source.ID,
+		OldValue:   oldSource,
+		NewValue:   *source,
+	})
+
+	return source, nil
+}
+
+func (s *service) DeleteSource(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	source, err := s.GetSource(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.citationRepo.CountBySource(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d citations", domain.ErrSourceInUse, count)
+	}
+
+	if err := s.sourceRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "DELETE",
+		EntityType: "SOURCE",
+		EntityID:   id,
+		OldValue:   source,
+	})
+
+	return nil
+}
+
+func (s *service) ListSources(ctx context.Context, params domain.SourceListParams) (domain.PaginatedResponse[domain.Source], error) {
+	if params.Type != nil && !params.Type.IsValid() {
+		return domain.PaginatedResponse[domain.Source]{}, domain.ErrInvalidSourceType
+	}
+	params.Validate()
+
+	sources, total, err := s.sourceRepo.List(ctx, params)
+	if err != nil {
+		return domain.PaginatedResponse[domain.Source]{}, err
+	}
+
+	return domain.NewPaginatedResponse(sources, params.Page, params.PageSize, total), nil
+}
+
+func (s *service) ListSourceCitations(ctx context.Context, sourceID uuid.UUID) ([]domain.Citation, error) {
+	if _, err := s.GetSource(ctx, sourceID); err != nil {
+		return nil, err
+	}
+	return s.citationRepo.ListBySource(ctx, sourceID)
+}
+
+func (s *service) CreateCitation(ctx context.Context, userID uuid.UUID, input domain.CreateCitationInput) (*domain.Citation, error) {
+	if input.Confidence == "" {
+		input.Confidence = domain.ConfidenceSecondary
+	}
+	if !input.Confidence.IsValid() {
+		return nil, domain.ErrInvalidConfidence
+	}
+	if err := domain.ValidateCitationTarget(input.EntityType, input.Field); err != nil {
+		return nil, err
+	}
+
+	source, err := s.GetSource(ctx, input.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateEntity(ctx, input.EntityType, input.EntityID); err != nil {
+		return nil, err
+	}
+	if err := s.validateMedia(ctx, input.MediaIDs); err != nil {
+		return nil, err
+	}
+
+	citation := &domain.Citation{
+		ID:         uuid.New(),
+		SourceID:   input.SourceID,
+		EntityType: input.EntityType,
+		EntityID:   input.EntityID,
+		Field:      input.Field,
+		Page:       input.Page,
+		Confidence: input.Confidence,
+		Note:       input.Note,
+		CreatedBy:  userID,
+		MediaIDs:   input.MediaIDs,
+	}
+	if citation.MediaIDs == nil {
+		citation.MediaIDs = []uuid.UUID{}
+	}
+
+	if err := s.citationRepo.Create(ctx, citation); err != nil {
+		return nil, err
+	}
+	citation.Source = source
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "CREATE",
+		EntityType: "CITATION",
+		EntityID:   citation.ID,
+		NewValue:   citation,
+	})
+
+	return citation, nil
+}
+
+func (s *service) UpdateCitation(ctx context.Context, userID uuid.UUID, id uuid.UUID, input domain.UpdateCitationInput) (*domain.Citation, error) {
+	citation, err := s.citationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if citation == nil {
+		return nil, domain.ErrCitationNotFound
+	}
+
+	oldCitation := *citation
+
+	if input.Field.Set {
+		if err := domain.ValidateCitationTarget(citation.EntityType, input.Field.Value); err != nil {
+			return nil, err
+		}
+		citation.Field = input.Field.Value
+	}
+	if input.Page.Set {
+		citation.Page = input.Page.Value
+	}
+	if input.Confidence != nil {
+		if !input.Confidence.IsValid() {
+			return nil, domain.ErrInvalidConfidence
+		}
+		citation.Confidence = *input.Confidence
+	}
+	if input.Note.Set {
+		citation.Note = input.Note.Value
+	}
+	if input.MediaIDs != nil {
+		if err := s.validateMedia(ctx, *input.MediaIDs); err != nil {
+			return nil, err
+		}
+		citation.MediaIDs = *input.MediaIDs
+	}
+
+	if err := s.citationRepo.Update(ctx, citation); err != nil {
+		return nil, err
+	}
+	if source, err := s.sourceRepo.GetByID(ctx, citation.SourceID); err == nil {
+		citation.Source = source
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "UPDATE",
+		EntityType: "CITATION",
+		EntityID:   citation.ID,
+		OldValue:   oldCitation,
+		NewValue:   *citation,
+	})
+
+	return citation, nil
+}
+
+func (s *service) DeleteCitation(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	citation, err := s.citationRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if citation == nil {
+		return domain.ErrCitationNotFound
+	}
+
+	if err := s.citationRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "DELETE",
+		EntityType: "CITATION",
+		EntityID:   id,
+		OldValue:   citation,
+	})
+
+	return nil
+}
+
+func (s *service) ListCitations(ctx context.Context, entityType domain.EntityType, entityID uuid.UUID) ([]domain.Citation, error) {
+	if err := domain.ValidateCitationTarget(entityType, nil); err != nil {
+		return nil, err
+	}
+	return s.citationRepo.ListByEntity(ctx, entityType, entityID)
+}
+
+// validateEntity checks that the cited person, relationship or event exists.
+func (s *service) validateEntity(ctx context.Context, entityType domain.EntityType, entityID uuid.UUID) error {
+	var found bool
+	switch entityType {
+	case domain.EntityPerson:
+		person, err := s.personRepo.GetByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		found = person != nil
+	case domain.EntityRelationship:
+		rel, err := s.relRepo.GetByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		found = rel != nil
+	case domain.EntityEvent:
+		event, err := s.eventRepo.GetByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		found = event != nil
+	default:
+		return domain.ErrInvalidCitationEntity
+	}
+	if !found {
+		return domain.ErrCitedEntityNotFound
+	}
+	return nil
+}
+
+func (s *service) validateMedia(ctx context.Context, mediaIDs []uuid.UUID) error {
+	for _, id := range mediaIDs {
+		media, err := s.mediaRepo.GetByID(ctx, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err != nil || media == nil {
+			return fmt.Errorf("%w: %s", domain.ErrCitationMediaNotFound, id)
+		}
+	}
+	return nil
+}
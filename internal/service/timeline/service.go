@@ -0,0 +1,279 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+	"silsilah-keluarga/internal/service/media"
+)
+
+type Service interface {
+	GetPersonTimeline(ctx context.Context, personID uuid.UUID, viewer domain.Viewer) (*domain.PersonTimeline, error)
+}
+
+type service struct {
+	personRepo repository.PersonRepository
+	relRepo    repository.RelationshipRepository
+	eventRepo  repository.EventRepository
+	mediaSvc   media.Service
+}
+
+func NewService(personRepo repository.PersonRepository, relRepo repository.RelationshipRepository, eventRepo repository.EventRepository, mediaSvc media.Service) Service {
+	return &service{
+		personRepo: personRepo,
+		relRepo:    relRepo,
+		eventRepo:  eventRepo,
+		mediaSvc:   mediaSvc,
+	}
+}
+
+// GetPersonTimeline builds the timeline as the viewer may see it. A living
+// person hidden from the viewer only shows what their redacted record and
+// relationships reveal; their own events and media are left out.
+func (s *service) GetPersonTimeline(ctx context.Context, personID uuid.UUID, viewer domain.Viewer) (*domain.PersonTimeline, error) {
+	now := time.Now()
+	person, err := s.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, domain.ErrPersonNotFound
+	}
+	person.Redact(viewer, now)
+
+	var events []domain.Event
+	if !person.Redacted {
+		events, err = s.eventRepo.ListByPerson(ctx, personID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rels, err := s.relRepo.ListByPerson(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedIDs := make([]uuid.UUID, 0, len(rels))
+	for _, r := range rels {
+		if r.PersonA == personID {
+			relatedIDs = append(relatedIDs, r.PersonB)
+		} else {
+			relatedIDs = append(relatedIDs, r.PersonA)
+		}
+	}
+	related, err := s.personRepo.GetByIDs(ctx, relatedIDs)
+	if err != nil {
+		return nil, err
+	}
+	domain.RedactPersons(related, viewer, now)
+	relatedByID := make(map[uuid.UUID]*domain.Person, len(related))
+	for i := range related {
+		relatedByID[related[i].ID] = &related[i]
+	}
+
+	var items []domain.TimelineItem
+	for i := range events {
+		items = append(items, eventItem(events[i]))
+	}
+
+	if !hasEvent(events, domain.EventTypeBirth) && person.BirthDate != nil {
+		items = append(items, domain.TimelineItem{
+			Source: domain.TimelineSourceDerived,
+			Type:   domain.TimelineBirth,
+			Date:   person.BirthDate,
+			Place:  person.BirthPlace,
+		})
+	}
+	if !hasEvent(events, domain.EventTypeDeath) && person.DeathDate != nil {
+		items = append(items, domain.TimelineItem{
+			Source: domain.TimelineSourceDerived,
+			Type:   domain.TimelineDeath,
+			Date:   person.DeathDate,
+			Place:  person.DeathPlace,
+		})
+	}
+
+	for _, r := range rels {
+		relID := r.ID
+		switch {
+		case r.Type == domain.RelTypeParent && r.PersonB == personID:
+			child := relatedByID[r.PersonA]
+			if child != nil && child.BirthDate != nil {
+				items = append(items, domain.TimelineItem{
+					Source:         domain.TimelineSourceDerived,
+					Type:           domain.TimelineChildBirth,
+					Date:           child.BirthDate,
+					Place:          child.BirthPlace,
+					RelationshipID: &relID,
+					RelatedPerson:  child,
+				})
+			}
+		case r.Type == domain.RelTypeParent && r.PersonA == personID:
+			parent := relatedByID[r.PersonB]
+			if parent != nil && parent.DeathDate != nil {
+				items = append(items, domain.TimelineItem{
+					Source:         domain.TimelineSourceDerived,
+					Type:           domain.TimelineParentDeath,
+					Date:           parent.DeathDate,
+					Place:          parent.DeathPlace,
+					RelationshipID: &relID,
+					RelatedPerson:  parent,
+				})
+			}
+		case r.Type == domain.RelTypeSpouse:
+			otherID := r.PersonA
+			if r.PersonA == personID {
+				otherID = r.PersonB
+			}
+			spouse := relatedByID[otherID]
+			if spouse == nil {
+				continue
+			}
+
+			relEvents, err := s.eventRepo.ListByRelationship(ctx, r.ID)
+			if err != nil {
+				return nil, err
+			}
+			for i := range relEvents {
+				item := eventItem(relEvents[i])
+				item.RelatedPerson = spouse
+				items = append(items, item)
+			}
+
+			var meta domain.SpouseMetadata
+			if len(r.Metadata) > 0 {
+				_ = json.Unmarshal(r.Metadata, &meta)
+			}
+			if meta.MarriageDate != nil && !hasEvent(relEvents, domain.EventTypeMarriage, domain.EventTypeAkadNikah) {
+				items = append(items, domain.TimelineItem{
+					Source:         domain.TimelineSourceDerived,
+					Type:           domain.TimelineMarriage,
+					Date:           meta.MarriageDate,
+					Place:          meta.MarriagePlace,
+					RelationshipID: &relID,
+					RelatedPerson:  spouse,
+				})
+			}
+			if meta.DivorceDate != nil && !hasEvent(relEvents, domain.EventTypeDivorce) {
+				items = append(items, domain.TimelineItem{
+					Source:         domain.TimelineSourceDerived,
+					Type:           domain.TimelineDivorce,
+					Date:           meta.DivorceDate,
+					RelationshipID: &relID,
+					RelatedPerson:  spouse,
+				})
+			}
+			if spouse.DeathDate != nil {
+				items = append(items, domain.TimelineItem{
+					Source:         domain.TimelineSourceDerived,
+					Type:           domain.TimelineSpouseDeath,
+					Date:           spouse.DeathDate,
+					Place:          spouse.DeathPlace,
+					RelationshipID: &relID,
+					RelatedPerson:  spouse,
+				})
+			}
+		}
+	}
+
+	if s.mediaSvc != nil && !person.Redacted {
+		mediaList, err := s.mediaSvc.ListDatedByPerson(ctx, personID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range mediaList {
+			m := mediaList[i]
+			if m.TakenAt == nil {
+				continue
+			}
+			title := m.FileName
+			if m.Caption != nil && *m.Caption != "" {
+				title = *m.Caption
+			}
+			items = append(items, domain.TimelineItem{
+				Source: domain.TimelineSourceMedia,
+				Type:   domain.TimelineMediaTaken,
+				Title:  title,
+				Date:   domain.NewExactDate(*m.TakenAt),
+				Media:  &m,
+			})
+		}
+	}
+
+	for i := range items {
+		items[i].Age = domain.AgeAt(person.BirthDate, items[i].Date)
+	}
+
+	sortTimeline(items)
+
+	if items == nil {
+		items = []domain.TimelineItem{}
+	}
+
+	return &domain.PersonTimeline{
+		PersonID: personID,
+		Items:    items,
+	}, nil
+}
+
+func eventItem(ev domain.Event) domain.TimelineItem {
+	evType := ev.Type
+	evID := ev.ID
+	return domain.TimelineItem{
+		Source:         domain.TimelineSourceEvent,
+		Type:           timelineTypeForEvent(ev.Type),
+		EventType:      &evType,
+		Title:          ev.Title,
+		Date:           ev.Date,
+		Place:          ev.Place,
+		EventID:        &evID,
+		RelationshipID: ev.RelationshipID,
+	}
+}
+
+func timelineTypeForEvent(t domain.EventType) domain.TimelineItemType {
+	switch t {
+	case domain.EventTypeBirth:
+		return domain.TimelineBirth
+	case domain.EventTypeDeath:
+		return domain.TimelineDeath
+	case domain.EventTypeMarriage, domain.EventTypeAkadNikah:
+		return domain.TimelineMarriage
+	case domain.EventTypeDivorce:
+		return domain.TimelineDivorce
+	}
+	return domain.TimelineItemType(t)
+}
+
+func hasEvent(events []domain.Event, types ...domain.EventType) bool {
+	for _, ev := range events {
+		for _, t := range types {
+			if ev.Type == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortTimeline orders items chronologically by their sort date; undated
+// items keep their relative order and are placed after every dated item.
+func sortTimeline(items []domain.TimelineItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Date.SortDate(), items[j].Date.SortDate()
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.Before(*b)
+	})
+}
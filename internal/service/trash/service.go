@@ -0,0 +1,104 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/repository"
+	"silsilah-keluarga/internal/service/media"
+)
+
+type Service interface {
+	List(ctx context.Context, params domain.TrashListParams) (domain.PaginatedResponse[domain.TrashItem], error)
+	Restore(ctx context.Context, userID uuid.UUID, entityType domain.EntityType, id uuid.UUID) (*domain.RestoreResult, error)
+	Purge(ctx context.Context, userID uuid.UUID) (*domain.PurgeResult, error)
+}
+
+type service struct {
+	trashRepo repository.TrashRepository
+	mediaSvc  media.Service
+	auditRepo repository.AuditLogRepository
+	redis     *redis.Client
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewService(trashRepo repository.TrashRepository, mediaSvc media.Service, auditRepo repository.AuditLogRepository, redis *redis.Client, retention time.Duration) Service {
+	return &service{
+		trashRepo: trashRepo,
+		mediaSvc:  mediaSvc,
+		auditRepo: auditRepo,
+		redis:     redis,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+func (s *service) List(ctx context.Context, params domain.TrashListParams) (domain.PaginatedResponse[domain.TrashItem], error) {
+	if params.EntityType != nil && !params.EntityType.IsTrashable() {
+		return domain.PaginatedResponse[domain.TrashItem]{}, domain.ErrInvalidTrashEntity
+	}
+	params.Validate()
+
+	items, total, err := s.trashRepo.List(ctx, params)
+	if err != nil {
+		return domain.PaginatedResponse[domain.TrashItem]{}, err
+	}
+	for i := range items {
+		items[i].PurgeAfter = items[i].DeletedAt.Add(s.retention)
+	}
+
+	return domain.NewPaginatedResponse(items, params.Page, params.PageSize, total), nil
+}
+
+func (s *service) Restore(ctx context.Context, userID uuid.UUID, entityType domain.EntityType, id uuid.UUID) (*domain.RestoreResult, error) {
+	if !entityType.IsTrashable() {
+		return nil, domain.ErrInvalidTrashEntity
+	}
+
+	result, err := s.trashRepo.Restore(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.redis != nil && entityType != domain.EntityComment {
+		_ = s.redis.Del(ctx, "family:graph").Err()
+	}
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "RESTORE",
+		EntityType: string(entityType),
+		EntityID:   id,
+		NewValue:   result,
+	})
+
+	return result, nil
+}
+
+// Purge permanently deletes everything that has been in the trash longer
+// than the retention period, including the stored files of purged media.
+func (s *service) Purge(ctx context.Context, userID uuid.UUID) (*domain.PurgeResult, error) {
+	result, err := s.trashRepo.Purge(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		return nil, err
+	}
+
+	// The rows are gone already; a file that fails to be removed is only
+	// orphaned storage and must not fail the purge.
+	_ = s.mediaSvc.RemoveObjects(ctx, result.StoragePaths)
+
+	_ = repository.CreateAuditLog(s.auditRepo, ctx, domain.CreateAuditLogInput{
+		UserID:     userID,
+		Action:     "PURGE",
+		EntityType: "TRASH",
+		EntityID:   uuid.Nil,
+		NewValue:   result,
+	})
+
+	return result, nil
+}
@@ -0,0 +1,9 @@
+-- 000002_extended_event_types.down.sql
+-- PostgreSQL cannot drop enum values, so the type is rebuilt with the original set
+
+UPDATE events SET type = 'OTHER' WHERE type::text NOT IN ('BIRTH', 'DEATH', 'MARRIAGE', 'DIVORCE', 'OTHER');
+
+ALTER TYPE event_type RENAME TO event_type_old;
+CREATE TYPE event_type AS ENUM ('BIRTH', 'DEATH', 'MARRIAGE', 'DIVORCE', 'OTHER');
+ALTER TABLE events ALTER COLUMN type TYPE event_type USING type::text::event_type;
+DROP TYPE event_type_old;
@@ -0,0 +1,13 @@
+-- 000002_extended_event_types.up.sql
+-- Adds Indonesian life-ritual event types to the event catalog
+
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'AQIQAH';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'KHITAN';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'AKAD_NIKAH';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'RESEPSI';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'GRADUATION';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'HAJJ';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'MIGRATION';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'BURIAL';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'HAUL';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'MILITARY_SERVICE';
@@ -0,0 +1,31 @@
+-- 000003_genealogical_dates.down.sql
+-- Reverting collapses every genealogical date to a single day (its earliest
+-- bound, or its latest for BEF dates); phrases and qualifiers are lost.
+
+DROP INDEX IF EXISTS idx_events_date;
+
+ALTER TABLE events ALTER COLUMN date TYPE DATE USING COALESCE(date_min, date_max);
+
+ALTER TABLE events
+    DROP COLUMN date_min,
+    DROP COLUMN date_max;
+
+CREATE INDEX idx_events_date ON events(date);
+
+DROP INDEX IF EXISTS idx_persons_birth_sort;
+
+ALTER TABLE persons DROP CONSTRAINT chk_dates;
+
+ALTER TABLE persons
+    ALTER COLUMN birth_date TYPE DATE USING COALESCE(birth_date_min, birth_date_max),
+    ALTER COLUMN death_date TYPE DATE USING COALESCE(death_date_min, death_date_max);
+
+UPDATE persons SET death_date = birth_date WHERE death_date < birth_date;
+
+ALTER TABLE persons
+    DROP COLUMN birth_date_min,
+    DROP COLUMN birth_date_max,
+    DROP COLUMN death_date_min,
+    DROP COLUMN death_date_max;
+
+ALTER TABLE persons ADD CONSTRAINT chk_dates CHECK (death_date IS NULL OR birth_date IS NULL OR death_date >= birth_date);
@@ -0,0 +1,52 @@
+-- 000003_genealogical_dates.up.sql
+-- Genealogical dates: birth, death and event dates become GEDCOM-style text
+-- ("ABT 1920", "BET 1900 AND 1910", "MAR 1920", "(sebelum merdeka)") backed
+-- by the earliest/latest day they can denote, which drive sorting and the
+-- death-after-birth check.
+
+ALTER TABLE persons DROP CONSTRAINT chk_dates;
+
+ALTER TABLE persons
+    ADD COLUMN birth_date_min DATE,
+    ADD COLUMN birth_date_max DATE,
+    ADD COLUMN death_date_min DATE,
+    ADD COLUMN death_date_max DATE;
+
+UPDATE persons SET
+    birth_date_min = birth_date,
+    birth_date_max = birth_date,
+    death_date_min = death_date,
+    death_date_max = death_date;
+
+ALTER TABLE persons
+    ALTER COLUMN birth_date TYPE TEXT USING to_char(birth_date, 'FMDD MON YYYY'),
+    ALTER COLUMN death_date TYPE TEXT USING to_char(death_date, 'FMDD MON YYYY');
+
+ALTER TABLE persons ADD CONSTRAINT chk_dates CHECK (
+    death_date_max IS NULL OR birth_date_min IS NULL OR death_date_max >= birth_date_min
+);
+
+COMMENT ON COLUMN persons.birth_date IS 'Genealogical birth date in GEDCOM form, optionally followed by the original phrase';
+COMMENT ON COLUMN persons.birth_date_min IS 'Earliest day the birth date can denote (NULL when open, e.g. BEF)';
+COMMENT ON COLUMN persons.birth_date_max IS 'Latest day the birth date can denote (NULL when open, e.g. AFT)';
+COMMENT ON COLUMN persons.death_date IS 'Genealogical death date in GEDCOM form, optionally followed by the original phrase';
+COMMENT ON COLUMN persons.death_date_min IS 'Earliest day the death date can denote';
+COMMENT ON COLUMN persons.death_date_max IS 'Latest day the death date can denote';
+
+CREATE INDEX idx_persons_birth_sort ON persons ((COALESCE(birth_date_min, birth_date_max))) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_events_date;
+
+ALTER TABLE events
+    ADD COLUMN date_min DATE,
+    ADD COLUMN date_max DATE;
+
+UPDATE events SET date_min = date, date_max = date;
+
+ALTER TABLE events ALTER COLUMN date TYPE TEXT USING to_char(date, 'FMDD MON YYYY');
+
+COMMENT ON COLUMN events.date IS 'Genealogical event date in GEDCOM form, optionally followed by the original phrase';
+COMMENT ON COLUMN events.date_min IS 'Earliest day the event date can denote';
+COMMENT ON COLUMN events.date_max IS 'Latest day the event date can denote';
+
+CREATE INDEX idx_events_date ON events ((COALESCE(date_min, date_max)));
@@ -0,0 +1,4 @@
+-- 000004_person_names.down.sql
+
+DROP TABLE IF EXISTS person_names;
+DROP TYPE IF EXISTS name_type;
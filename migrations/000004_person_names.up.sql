@@ -0,0 +1,28 @@
+-- 000004_person_names.up.sql
+-- Alternate names (birth, married, religious, Arabic-script, gelar, alias)
+-- with optional validity periods
+
+CREATE TYPE name_type AS ENUM ('BIRTH', 'MARRIED', 'RELIGIOUS', 'ARABIC', 'TITLE', 'ALIAS');
+
+CREATE TABLE person_names (
+    name_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    person_id UUID NOT NULL REFERENCES persons(person_id) ON DELETE CASCADE,
+    type name_type NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    valid_from TEXT,
+    valid_to TEXT,
+    created_by UUID NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE person_names IS 'Alternate names a person is or was known by';
+COMMENT ON COLUMN person_names.type IS 'BIRTH, MARRIED, RELIGIOUS, ARABIC (Arabic script), TITLE (gelar) or ALIAS';
+COMMENT ON COLUMN person_names.valid_from IS 'Genealogical date the name came into use';
+COMMENT ON COLUMN person_names.valid_to IS 'Genealogical date the name stopped being used';
+
+CREATE INDEX idx_person_names_person ON person_names(person_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_person_names_name ON person_names(lower(name)) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_person_names_updated_at BEFORE UPDATE ON person_names FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
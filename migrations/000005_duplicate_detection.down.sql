@@ -0,0 +1,3 @@
+-- 000005_duplicate_detection.down.sql
+
+DROP INDEX IF EXISTS idx_person_names_trgm;
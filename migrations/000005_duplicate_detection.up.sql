@@ -0,0 +1,4 @@
+-- 000005_duplicate_detection.up.sql
+-- Trigram index so duplicate detection can match alternate names.
+
+CREATE INDEX idx_person_names_trgm ON person_names USING gist(name gist_trgm_ops) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+-- 000006_person_merge.down.sql
+-- PostgreSQL cannot drop enum values, so the type is rebuilt without MERGE
+
+DELETE FROM change_requests WHERE action = 'MERGE';
+
+ALTER TYPE change_action RENAME TO change_action_old;
+CREATE TYPE change_action AS ENUM ('CREATE', 'UPDATE', 'DELETE');
+ALTER TABLE change_requests ALTER COLUMN action TYPE change_action USING action::text::change_action;
+DROP TYPE change_action_old;
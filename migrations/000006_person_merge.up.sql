@@ -0,0 +1,4 @@
+-- 000006_person_merge.up.sql
+-- Lets members propose person merges as change requests
+
+ALTER TYPE change_action ADD VALUE IF NOT EXISTS 'MERGE';
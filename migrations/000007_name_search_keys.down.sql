@@ -0,0 +1,11 @@
+-- 000007_name_search_keys.down.sql
+
+DROP INDEX IF EXISTS idx_person_names_phonetic;
+DROP INDEX IF EXISTS idx_person_names_normalized;
+DROP INDEX IF EXISTS idx_persons_name_phonetic;
+DROP INDEX IF EXISTS idx_persons_name_normalized;
+
+ALTER TABLE person_names DROP COLUMN IF EXISTS name_phonetic;
+ALTER TABLE person_names DROP COLUMN IF EXISTS name_normalized;
+ALTER TABLE persons DROP COLUMN IF EXISTS name_phonetic;
+ALTER TABLE persons DROP COLUMN IF EXISTS name_normalized;
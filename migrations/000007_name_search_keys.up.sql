@@ -0,0 +1,17 @@
+-- 000007_name_search_keys.up.sql
+-- Normalized spelling and phonetic keys for spelling-tolerant name search.
+-- Keys are computed by the application; rows stored before this migration
+-- are filled in when the API starts.
+
+ALTER TABLE persons ADD COLUMN name_normalized TEXT;
+ALTER TABLE persons ADD COLUMN name_phonetic TEXT;
+ALTER TABLE person_names ADD COLUMN name_normalized TEXT;
+ALTER TABLE person_names ADD COLUMN name_phonetic TEXT;
+
+COMMENT ON COLUMN persons.name_normalized IS 'Full name in EYD spelling with Arabic-name variants unified';
+COMMENT ON COLUMN persons.name_phonetic IS 'Sound-alike key of the full name';
+
+CREATE INDEX idx_persons_name_normalized ON persons USING gist(name_normalized gist_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_persons_name_phonetic ON persons(name_phonetic) WHERE deleted_at IS NULL;
+CREATE INDEX idx_person_names_normalized ON person_names USING gist(name_normalized gist_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_person_names_phonetic ON person_names(name_phonetic) WHERE deleted_at IS NULL;
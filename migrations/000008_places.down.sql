@@ -0,0 +1,8 @@
+-- 000008_places.down.sql
+
+ALTER TABLE events DROP COLUMN IF EXISTS place_id;
+ALTER TABLE persons DROP COLUMN IF EXISTS death_place_id;
+ALTER TABLE persons DROP COLUMN IF EXISTS birth_place_id;
+
+DROP TABLE IF EXISTS places;
+DROP TYPE IF EXISTS place_level;
@@ -0,0 +1,45 @@
+-- 000008_places.up.sql
+-- Places with the Indonesian administrative hierarchy
+-- (desa/kelurahan -> kecamatan -> kabupaten/kota -> provinsi -> country).
+-- Persons and events reference places; their free-text place columns stay
+-- as fallbacks.
+
+CREATE TYPE place_level AS ENUM ('COUNTRY', 'PROVINSI', 'KABUPATEN', 'KECAMATAN', 'DESA');
+
+CREATE TABLE places (
+    place_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES places(place_id) ON DELETE RESTRICT,
+    level place_level NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    code VARCHAR(20) UNIQUE,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    historical_names JSONB NOT NULL DEFAULT '[]',
+    created_by UUID REFERENCES users(user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_place_root CHECK (parent_id IS NOT NULL OR level = 'COUNTRY'),
+    CONSTRAINT chk_place_latitude CHECK (latitude IS NULL OR latitude BETWEEN -90 AND 90),
+    CONSTRAINT chk_place_longitude CHECK (longitude IS NULL OR longitude BETWEEN -180 AND 180)
+);
+
+COMMENT ON TABLE places IS 'Places in an administrative hierarchy';
+COMMENT ON COLUMN places.level IS 'COUNTRY, PROVINSI, KABUPATEN (kabupaten/kota), KECAMATAN or DESA (desa/kelurahan)';
+COMMENT ON COLUMN places.code IS 'Kemendagri region code for gazetteer places, e.g. 35.78; NULL for user-added places';
+COMMENT ON COLUMN places.created_by IS 'NULL for places imported from the bundled gazetteer';
+COMMENT ON COLUMN places.historical_names IS 'Earlier names with optional year ranges, e.g. Batavia until 1942';
+
+CREATE INDEX idx_places_parent ON places(parent_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_places_name ON places USING gist(name gist_trgm_ops) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_places_updated_at BEFORE UPDATE ON places FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE persons ADD COLUMN birth_place_id UUID REFERENCES places(place_id) ON DELETE SET NULL;
+ALTER TABLE persons ADD COLUMN death_place_id UUID REFERENCES places(place_id) ON DELETE SET NULL;
+ALTER TABLE events ADD COLUMN place_id UUID REFERENCES places(place_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_persons_birth_place ON persons(birth_place_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_persons_death_place ON persons(death_place_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_events_place ON events(place_id) WHERE deleted_at IS NULL;
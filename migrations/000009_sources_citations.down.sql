@@ -0,0 +1,7 @@
+-- 000009_sources_citations.down.sql
+
+DROP TABLE IF EXISTS citation_media;
+DROP TABLE IF EXISTS citations;
+DROP TABLE IF EXISTS sources;
+DROP TYPE IF EXISTS citation_confidence;
+DROP TYPE IF EXISTS source_type;
@@ -0,0 +1,65 @@
+-- 000009_sources_citations.up.sql
+-- Sources (books, certificates, interviews, gravestones, websites) and the
+-- citations that attach them to persons, single person fields,
+-- relationships and events
+
+CREATE TYPE source_type AS ENUM ('BOOK', 'CERTIFICATE', 'INTERVIEW', 'GRAVESTONE', 'WEBSITE', 'DOCUMENT', 'OTHER');
+CREATE TYPE citation_confidence AS ENUM ('UNRELIABLE', 'QUESTIONABLE', 'SECONDARY', 'PRIMARY');
+
+CREATE TABLE sources (
+    source_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type source_type NOT NULL,
+    title VARCHAR(300) NOT NULL,
+    author VARCHAR(200),
+    publication TEXT,
+    repository VARCHAR(300),
+    url TEXT,
+    notes TEXT,
+    created_by UUID NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE sources IS 'Where facts come from';
+COMMENT ON COLUMN sources.publication IS 'Publisher, place and date of publication, or the interview date and setting';
+COMMENT ON COLUMN sources.repository IS 'Where the original is kept, e.g. a family archive or Arsip Nasional';
+
+CREATE INDEX idx_sources_title ON sources USING gist(title gist_trgm_ops) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_sources_updated_at BEFORE UPDATE ON sources FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE citations (
+    citation_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source_id UUID NOT NULL REFERENCES sources(source_id) ON DELETE RESTRICT,
+    entity_type entity_type NOT NULL,
+    entity_id UUID NOT NULL,
+    field VARCHAR(50),
+    page TEXT,
+    confidence citation_confidence NOT NULL DEFAULT 'SECONDARY',
+    note TEXT,
+    created_by UUID NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_citation_entity CHECK (entity_type IN ('PERSON', 'RELATIONSHIP', 'EVENT'))
+);
+
+COMMENT ON TABLE citations IS 'A source backing a person, relationship or event, or one field of it';
+COMMENT ON COLUMN citations.field IS 'Cited field such as birth_date; NULL cites the whole record';
+COMMENT ON COLUMN citations.page IS 'Page, entry number or other detail locating the fact in the source';
+COMMENT ON COLUMN citations.confidence IS 'Evidence quality, mapped to GEDCOM QUAY 0-3';
+
+CREATE INDEX idx_citations_entity ON citations(entity_type, entity_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_citations_source ON citations(source_id) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_citations_updated_at BEFORE UPDATE ON citations FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE citation_media (
+    citation_id UUID NOT NULL REFERENCES citations(citation_id) ON DELETE CASCADE,
+    media_id UUID NOT NULL REFERENCES media(media_id) ON DELETE CASCADE,
+    PRIMARY KEY (citation_id, media_id)
+);
+
+COMMENT ON TABLE citation_media IS 'Scans of the cited source, e.g. a photo of the certificate';
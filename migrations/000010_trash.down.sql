@@ -0,0 +1,13 @@
+-- 000010_trash.down.sql
+
+DROP INDEX IF EXISTS idx_comments_trash;
+DROP INDEX IF EXISTS idx_media_trash;
+DROP INDEX IF EXISTS idx_events_trash;
+DROP INDEX IF EXISTS idx_relationships_trash;
+DROP INDEX IF EXISTS idx_persons_trash;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE media DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE relationships DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE persons DROP COLUMN IF EXISTS deleted_by;
@@ -0,0 +1,21 @@
+-- 000010_trash.up.sql
+-- Records who moved a row to the trash and indexes deleted rows so the
+-- trash bin can list and purge them
+
+ALTER TABLE persons ADD COLUMN deleted_by UUID REFERENCES users(user_id) ON DELETE SET NULL;
+ALTER TABLE relationships ADD COLUMN deleted_by UUID REFERENCES users(user_id) ON DELETE SET NULL;
+ALTER TABLE events ADD COLUMN deleted_by UUID REFERENCES users(user_id) ON DELETE SET NULL;
+ALTER TABLE media ADD COLUMN deleted_by UUID REFERENCES users(user_id) ON DELETE SET NULL;
+ALTER TABLE comments ADD COLUMN deleted_by UUID REFERENCES users(user_id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN persons.deleted_by IS 'User who moved the person to the trash';
+COMMENT ON COLUMN relationships.deleted_by IS 'User who moved the relationship to the trash';
+COMMENT ON COLUMN events.deleted_by IS 'User who moved the event to the trash';
+COMMENT ON COLUMN media.deleted_by IS 'User who moved the media to the trash';
+COMMENT ON COLUMN comments.deleted_by IS 'User who moved the comment to the trash';
+
+CREATE INDEX idx_persons_trash ON persons(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_relationships_trash ON relationships(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_events_trash ON events(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_media_trash ON media(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_comments_trash ON comments(deleted_at) WHERE deleted_at IS NOT NULL;
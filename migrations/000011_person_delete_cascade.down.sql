@@ -0,0 +1,14 @@
+-- 000011_person_delete_cascade.down.sql
+-- Rows cascaded by person deletions stay deleted; only the grouping is lost.
+
+DROP INDEX IF EXISTS idx_comments_deletion;
+DROP INDEX IF EXISTS idx_media_deletion;
+DROP INDEX IF EXISTS idx_events_deletion;
+DROP INDEX IF EXISTS idx_relationships_deletion;
+DROP INDEX IF EXISTS idx_persons_deletion;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deletion_id;
+ALTER TABLE media DROP COLUMN IF EXISTS deletion_id;
+ALTER TABLE events DROP COLUMN IF EXISTS deletion_id;
+ALTER TABLE relationships DROP COLUMN IF EXISTS deletion_id;
+ALTER TABLE persons DROP COLUMN IF EXISTS deletion_id;
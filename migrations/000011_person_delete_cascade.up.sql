@@ -0,0 +1,51 @@
+-- 000011_person_delete_cascade.up.sql
+-- Groups the rows soft-deleted together with a person under one deletion_id
+-- so a restore can undo exactly that deletion
+
+ALTER TABLE persons ADD COLUMN deletion_id UUID;
+ALTER TABLE relationships ADD COLUMN deletion_id UUID;
+ALTER TABLE events ADD COLUMN deletion_id UUID;
+ALTER TABLE media ADD COLUMN deletion_id UUID;
+ALTER TABLE comments ADD COLUMN deletion_id UUID;
+
+COMMENT ON COLUMN persons.deletion_id IS 'Deletion operation this person was trashed in';
+COMMENT ON COLUMN relationships.deletion_id IS 'Person deletion that cascaded to this relationship; NULL when deleted on its own';
+COMMENT ON COLUMN events.deletion_id IS 'Person deletion that cascaded to this event; NULL when deleted on its own';
+COMMENT ON COLUMN media.deletion_id IS 'Person deletion that cascaded to this media; NULL when deleted on its own';
+COMMENT ON COLUMN comments.deletion_id IS 'Person deletion that cascaded to this comment; NULL when deleted on its own';
+
+CREATE INDEX idx_persons_deletion ON persons(deletion_id) WHERE deletion_id IS NOT NULL;
+CREATE INDEX idx_relationships_deletion ON relationships(deletion_id) WHERE deletion_id IS NOT NULL;
+CREATE INDEX idx_events_deletion ON events(deletion_id) WHERE deletion_id IS NOT NULL;
+CREATE INDEX idx_media_deletion ON media(deletion_id) WHERE deletion_id IS NOT NULL;
+CREATE INDEX idx_comments_deletion ON comments(deletion_id) WHERE deletion_id IS NOT NULL;
+
+-- Cascade the deletions made before this migration, which left the
+-- person's rows active.
+UPDATE persons SET deletion_id = uuid_generate_v4() WHERE deleted_at IS NOT NULL;
+
+UPDATE relationships r
+SET deleted_at = p.deleted_at, deleted_by = p.deleted_by, deletion_id = p.deletion_id
+FROM persons p
+WHERE p.deleted_at IS NOT NULL AND r.deleted_at IS NULL
+    AND (r.person_a = p.person_id OR r.person_b = p.person_id);
+
+UPDATE events e
+SET deleted_at = p.deleted_at, deleted_by = p.deleted_by, deletion_id = p.deletion_id
+FROM persons p
+WHERE p.deleted_at IS NOT NULL AND e.deleted_at IS NULL AND e.person_id = p.person_id;
+
+UPDATE events e
+SET deleted_at = r.deleted_at, deleted_by = r.deleted_by, deletion_id = r.deletion_id
+FROM relationships r
+WHERE r.deletion_id IS NOT NULL AND e.deleted_at IS NULL AND e.relationship_id = r.relationship_id;
+
+UPDATE media m
+SET deleted_at = p.deleted_at, deleted_by = p.deleted_by, deletion_id = p.deletion_id
+FROM persons p
+WHERE p.deleted_at IS NOT NULL AND m.deleted_at IS NULL AND m.person_id = p.person_id;
+
+UPDATE comments c
+SET deleted_at = p.deleted_at, deleted_by = p.deleted_by, deletion_id = p.deletion_id
+FROM persons p
+WHERE p.deleted_at IS NOT NULL AND c.deleted_at IS NULL AND c.person_id = p.person_id;
@@ -0,0 +1,5 @@
+-- 000012_custom_fields.down.sql
+
+DROP TABLE IF EXISTS person_custom_values;
+DROP TABLE IF EXISTS custom_fields;
+DROP TYPE IF EXISTS custom_field_type;
@@ -0,0 +1,62 @@
+-- 000012_custom_fields.up.sql
+-- Admin-defined custom facts on persons (marga, blood type, military rank,
+-- pesantren, ...) with typed, validated and searchable values
+
+CREATE TYPE custom_field_type AS ENUM ('TEXT', 'NUMBER', 'DATE', 'ENUM', 'PERSON');
+
+CREATE TABLE custom_fields (
+    field_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key VARCHAR(50) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    description TEXT,
+    type custom_field_type NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    rules JSONB NOT NULL DEFAULT '{}',
+    visibility VARCHAR(20) NOT NULL DEFAULT 'member',
+    sort_order INT NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_custom_field_key CHECK (key ~ '^[a-z][a-z0-9_]*$'),
+    CONSTRAINT chk_custom_field_visibility CHECK (visibility IN ('member', 'editor', 'developer'))
+);
+
+COMMENT ON TABLE custom_fields IS 'Definitions of extra person facts not covered by the persons columns';
+COMMENT ON COLUMN custom_fields.key IS 'Stable identifier used in search filters, e.g. marga';
+COMMENT ON COLUMN custom_fields.options IS 'Allowed values of an ENUM field';
+COMMENT ON COLUMN custom_fields.rules IS 'Validation: min_length, max_length and pattern for TEXT, min and max for NUMBER';
+COMMENT ON COLUMN custom_fields.visibility IS 'Lowest user role that may see values of the field';
+
+CREATE UNIQUE INDEX idx_custom_fields_key ON custom_fields(key) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER trg_custom_fields_updated_at BEFORE UPDATE ON custom_fields FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE person_custom_values (
+    person_id UUID NOT NULL REFERENCES persons(person_id) ON DELETE CASCADE,
+    field_id UUID NOT NULL REFERENCES custom_fields(field_id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    value_number NUMERIC,
+    value_date_min DATE,
+    value_date_max DATE,
+    value_person_id UUID REFERENCES persons(person_id) ON DELETE CASCADE,
+    updated_by UUID NOT NULL REFERENCES users(user_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (person_id, field_id)
+);
+
+COMMENT ON TABLE person_custom_values IS 'Value of a custom field for one person';
+COMMENT ON COLUMN person_custom_values.value IS 'Canonical text of the value: the number, genealogical date, option or person ID';
+COMMENT ON COLUMN person_custom_values.value_number IS 'Parsed value of a NUMBER field for range search';
+COMMENT ON COLUMN person_custom_values.value_date_min IS 'Earliest day a DATE value can denote';
+COMMENT ON COLUMN person_custom_values.value_person_id IS 'Referenced person of a PERSON field';
+
+CREATE INDEX idx_person_custom_values_field ON person_custom_values(field_id);
+CREATE INDEX idx_person_custom_values_value ON person_custom_values USING gist(value gist_trgm_ops);
+CREATE INDEX idx_person_custom_values_number ON person_custom_values(field_id, value_number) WHERE value_number IS NOT NULL;
+CREATE INDEX idx_person_custom_values_person_ref ON person_custom_values(value_person_id) WHERE value_person_id IS NOT NULL;
+
+CREATE TRIGGER trg_person_custom_values_updated_at BEFORE UPDATE ON person_custom_values FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- 000013_person_privacy.down.sql
+
+DROP INDEX IF EXISTS idx_persons_private;
+ALTER TABLE persons DROP COLUMN IF EXISTS privacy_level;
+DROP TYPE IF EXISTS privacy_level;
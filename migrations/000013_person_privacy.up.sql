@@ -0,0 +1,11 @@
+-- 000013_person_privacy.up.sql
+-- Per-person privacy levels deciding who sees the full record of a living
+-- person
+
+CREATE TYPE privacy_level AS ENUM ('PUBLIC', 'FAMILY', 'EDITORS', 'PRIVATE');
+
+ALTER TABLE persons ADD COLUMN privacy_level privacy_level NOT NULL DEFAULT 'FAMILY';
+
+COMMENT ON COLUMN persons.privacy_level IS 'Who sees contact details, birth date and place of a living person: PUBLIC everyone including exports, FAMILY members, EDITORS editors and developers, PRIVATE developers and the linked user only';
+
+CREATE INDEX idx_persons_private ON persons(privacy_level) WHERE privacy_level = 'PRIVATE' AND deleted_at IS NULL;
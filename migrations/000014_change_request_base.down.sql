@@ -0,0 +1,5 @@
+-- 000014_change_request_base.down.sql
+
+ALTER TABLE change_requests
+    DROP COLUMN IF EXISTS base_snapshot,
+    DROP COLUMN IF EXISTS base_updated_at;
@@ -0,0 +1,10 @@
+-- 000014_change_request_base.up.sql
+-- Record the version of the entity an UPDATE change request was filed
+-- against, so approval can detect and merge later edits
+
+ALTER TABLE change_requests
+    ADD COLUMN base_updated_at TIMESTAMPTZ,
+    ADD COLUMN base_snapshot JSONB;
+
+COMMENT ON COLUMN change_requests.base_updated_at IS 'updated_at of the entity when an UPDATE request was filed; NULL for other actions and older requests';
+COMMENT ON COLUMN change_requests.base_snapshot IS 'The entity as it was when an UPDATE request was filed, the base of a three-way merge';
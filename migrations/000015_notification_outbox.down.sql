@@ -0,0 +1,3 @@
+-- 000015_notification_outbox.down.sql
+
+DROP TABLE IF EXISTS notification_outbox;
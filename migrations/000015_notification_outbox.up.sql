@@ -0,0 +1,18 @@
+-- 000015_notification_outbox.up.sql
+-- Notifications written in the same transaction as the change they
+-- announce, delivered once it commits
+
+CREATE TABLE notification_outbox (
+    outbox_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type notification_type NOT NULL,
+    entity_id UUID NOT NULL,
+    actor_id UUID NOT NULL REFERENCES users(user_id),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE notification_outbox IS 'Transactional outbox for notifications; a row is claimed by setting dispatched_at and released on failure';
+
+CREATE INDEX idx_notification_outbox_pending ON notification_outbox(created_at) WHERE dispatched_at IS NULL;
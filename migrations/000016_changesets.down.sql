@@ -0,0 +1,10 @@
+-- 000016_changesets.down.sql
+-- PostgreSQL cannot drop enum values, so the type is rebuilt without CHANGESET
+
+DELETE FROM change_requests WHERE entity_type = 'CHANGESET';
+
+ALTER TYPE entity_type RENAME TO entity_type_old;
+CREATE TYPE entity_type AS ENUM ('PERSON', 'RELATIONSHIP', 'MEDIA', 'EVENT');
+ALTER TABLE change_requests ALTER COLUMN entity_type TYPE entity_type USING entity_type::text::entity_type;
+ALTER TABLE citations ALTER COLUMN entity_type TYPE entity_type USING entity_type::text::entity_type;
+DROP TYPE entity_type_old;
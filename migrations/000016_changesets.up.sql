@@ -0,0 +1,4 @@
+-- 000016_changesets.up.sql
+-- Lets a change request carry several operations applied as one unit
+
+ALTER TYPE entity_type ADD VALUE IF NOT EXISTS 'CHANGESET';
@@ -0,0 +1,4 @@
+-- 000017_approval_policies.down.sql
+
+DROP TABLE IF EXISTS change_request_approvals;
+DROP TABLE IF EXISTS approval_policies;
@@ -0,0 +1,35 @@
+-- 000017_approval_policies.up.sql
+-- Per entity type and action approval quorums, and approvals recorded per
+-- reviewer
+
+CREATE TABLE approval_policies (
+    entity_type entity_type NOT NULL,
+    action change_action NOT NULL,
+    required_approvals INT NOT NULL DEFAULT 1,
+    require_consent BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by UUID REFERENCES users(user_id),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (entity_type, action),
+    CONSTRAINT chk_required_approvals CHECK (required_approvals BETWEEN 1 AND 10)
+);
+
+COMMENT ON TABLE approval_policies IS 'How many reviewers must approve a change request; without a row one approval suffices';
+COMMENT ON COLUMN approval_policies.require_consent IS 'The user linked to the person the request touches must approve as well';
+
+INSERT INTO approval_policies (entity_type, action, required_approvals, require_consent) VALUES
+    ('PERSON', 'UPDATE', 1, TRUE),
+    ('PERSON', 'DELETE', 2, TRUE),
+    ('PERSON', 'MERGE', 2, TRUE);
+
+CREATE TABLE change_request_approvals (
+    request_id UUID NOT NULL REFERENCES change_requests(request_id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(user_id),
+    reviewer_role VARCHAR(20) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (request_id, reviewer_id)
+);
+
+COMMENT ON TABLE change_request_approvals IS 'One row per reviewer who approved a change request, with their role at the time';
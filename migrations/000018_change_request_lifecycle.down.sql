@@ -0,0 +1,38 @@
+-- 000018_change_request_lifecycle.down.sql
+-- PostgreSQL cannot drop enum values, so both types are rebuilt; requests
+-- in the new states are closed as rejected
+
+DROP TABLE IF EXISTS change_request_comments;
+DROP TABLE IF EXISTS change_request_revisions;
+DROP INDEX IF EXISTS idx_change_requests_activity;
+ALTER TABLE change_requests DROP COLUMN IF EXISTS revision;
+
+DELETE FROM notifications
+WHERE type IN ('CHANGES_REQUESTED', 'CHANGE_WITHDRAWN', 'CHANGE_AMENDED', 'CHANGE_EXPIRED', 'CHANGE_REQUEST_COMMENT');
+DELETE FROM notification_outbox
+WHERE type IN ('CHANGES_REQUESTED', 'CHANGE_WITHDRAWN', 'CHANGE_AMENDED', 'CHANGE_EXPIRED', 'CHANGE_REQUEST_COMMENT');
+
+ALTER TYPE notification_type RENAME TO notification_type_old;
+CREATE TYPE notification_type AS ENUM (
+    'CHANGE_REQUEST',
+    'CHANGE_APPROVED',
+    'CHANGE_REJECTED',
+    'NEW_COMMENT',
+    'PERSON_ADDED',
+    'RELATIONSHIP_ADDED'
+);
+ALTER TABLE notifications ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+ALTER TABLE notification_outbox ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+DROP TYPE notification_type_old;
+
+UPDATE change_requests SET status = 'REJECTED'
+WHERE status IN ('CHANGES_REQUESTED', 'WITHDRAWN', 'EXPIRED');
+
+DROP INDEX IF EXISTS idx_change_requests_status;
+ALTER TABLE change_requests ALTER COLUMN status DROP DEFAULT;
+ALTER TYPE request_status RENAME TO request_status_old;
+CREATE TYPE request_status AS ENUM ('PENDING', 'APPROVED', 'REJECTED');
+ALTER TABLE change_requests ALTER COLUMN status TYPE request_status USING status::text::request_status;
+ALTER TABLE change_requests ALTER COLUMN status SET DEFAULT 'PENDING';
+DROP TYPE request_status_old;
+CREATE INDEX idx_change_requests_status ON change_requests(status) WHERE status = 'PENDING';
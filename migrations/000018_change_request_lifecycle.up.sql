@@ -0,0 +1,48 @@
+-- 000018_change_request_lifecycle.up.sql
+-- Requesters withdraw and amend their requests, reviewers ask for changes,
+-- both discuss a request, and untouched requests expire
+
+ALTER TYPE request_status ADD VALUE 'CHANGES_REQUESTED';
+ALTER TYPE request_status ADD VALUE 'WITHDRAWN';
+ALTER TYPE request_status ADD VALUE 'EXPIRED';
+
+ALTER TYPE notification_type ADD VALUE 'CHANGES_REQUESTED';
+ALTER TYPE notification_type ADD VALUE 'CHANGE_WITHDRAWN';
+ALTER TYPE notification_type ADD VALUE 'CHANGE_AMENDED';
+ALTER TYPE notification_type ADD VALUE 'CHANGE_EXPIRED';
+ALTER TYPE notification_type ADD VALUE 'CHANGE_REQUEST_COMMENT';
+
+ALTER TABLE change_requests ADD COLUMN revision INT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN change_requests.revision IS 'Incremented each time the requester amends the request';
+
+-- The new statuses cannot be used in this transaction, so the index used
+-- to find stale requests is not partial
+CREATE INDEX idx_change_requests_activity ON change_requests(status, updated_at);
+
+CREATE TABLE change_request_revisions (
+    request_id UUID NOT NULL REFERENCES change_requests(request_id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    payload JSONB NOT NULL,
+    requester_note TEXT,
+    review_note TEXT,
+    superseded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (request_id, revision)
+);
+
+COMMENT ON TABLE change_request_revisions IS 'Earlier versions of an amended change request';
+COMMENT ON COLUMN change_request_revisions.review_note IS 'The note of the reviewer who asked for the changes, if any';
+
+CREATE TABLE change_request_comments (
+    comment_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES change_requests(request_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(user_id),
+    parent_id UUID REFERENCES change_request_comments(comment_id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE change_request_comments IS 'Discussion between the requester and reviewers of a change request';
+
+CREATE INDEX idx_change_request_comments_request ON change_request_comments(request_id, created_at);
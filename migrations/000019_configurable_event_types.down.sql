@@ -0,0 +1,17 @@
+-- 000019_configurable_event_types.down.sql
+-- Events of types the built-in catalog does not know become OTHER
+
+CREATE TYPE event_type AS ENUM (
+    'BIRTH', 'DEATH', 'MARRIAGE', 'DIVORCE', 'OTHER',
+    'AQIQAH', 'KHITAN', 'AKAD_NIKAH', 'RESEPSI', 'GRADUATION',
+    'HAJJ', 'MIGRATION', 'BURIAL', 'HAUL', 'MILITARY_SERVICE'
+);
+
+UPDATE events SET type = 'OTHER'
+WHERE type NOT IN (
+    'BIRTH', 'DEATH', 'MARRIAGE', 'DIVORCE', 'OTHER',
+    'AQIQAH', 'KHITAN', 'AKAD_NIKAH', 'RESEPSI', 'GRADUATION',
+    'HAJJ', 'MIGRATION', 'BURIAL', 'HAUL', 'MILITARY_SERVICE'
+);
+
+ALTER TABLE events ALTER COLUMN type TYPE event_type USING type::event_type;
@@ -0,0 +1,6 @@
+-- 000019_configurable_event_types.up.sql
+-- Event types come from the catalog, which can be extended by config, so
+-- the column no longer restricts them to an enum
+
+ALTER TABLE events ALTER COLUMN type TYPE TEXT USING type::TEXT;
+DROP TYPE IF EXISTS event_type;
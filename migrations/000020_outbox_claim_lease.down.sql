@@ -0,0 +1,5 @@
+-- 000020_outbox_claim_lease.down.sql
+
+ALTER TABLE notification_outbox DROP COLUMN IF EXISTS claimed_at;
+
+COMMENT ON TABLE notification_outbox IS 'Transactional outbox for notifications; a row is claimed by setting dispatched_at and released on failure';
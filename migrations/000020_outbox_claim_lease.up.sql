@@ -0,0 +1,9 @@
+-- 000020_outbox_claim_lease.up.sql
+-- Outbox rows are leased while they are delivered and only marked
+-- dispatched afterwards, so a dispatcher that dies mid-delivery does not
+-- lose them
+
+ALTER TABLE notification_outbox ADD COLUMN claimed_at TIMESTAMPTZ;
+
+COMMENT ON TABLE notification_outbox IS 'Transactional outbox for notifications; a row is leased by setting claimed_at, marked dispatched_at once delivered and released on failure';
+COMMENT ON COLUMN notification_outbox.claimed_at IS 'When a dispatcher claimed the row; a stale claim is taken over';
@@ -0,0 +1,5 @@
+-- 000021_outbox_prepared_notifications.down.sql
+
+DELETE FROM notification_outbox WHERE notification IS NOT NULL OR actor_id IS NULL;
+ALTER TABLE notification_outbox ALTER COLUMN actor_id SET NOT NULL;
+ALTER TABLE notification_outbox DROP COLUMN IF EXISTS notification;
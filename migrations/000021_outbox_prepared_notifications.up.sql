@@ -0,0 +1,9 @@
+-- 000021_outbox_prepared_notifications.up.sql
+-- Change request lifecycle notifications go through the outbox too. Their
+-- recipients are chosen when the change commits, so the notification is
+-- prepared then and stored with the row; expiry has no actor
+
+ALTER TABLE notification_outbox ADD COLUMN notification JSONB;
+ALTER TABLE notification_outbox ALTER COLUMN actor_id DROP NOT NULL;
+
+COMMENT ON COLUMN notification_outbox.notification IS 'A notification prepared at enqueue time and delivered as is; NULL when it is built on delivery';
@@ -0,0 +1,3 @@
+-- 000022_person_merged_into.down.sql
+
+ALTER TABLE persons DROP COLUMN IF EXISTS merged_into;
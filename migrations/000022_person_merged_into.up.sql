@@ -0,0 +1,7 @@
+-- 000022_person_merged_into.up.sql
+-- Marks a person soft-deleted by a merge with the survivor, so the trash
+-- can tell a merge from a deletion and refuse to restore it
+
+ALTER TABLE persons ADD COLUMN merged_into UUID REFERENCES persons(person_id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN persons.merged_into IS 'Person this one was merged into; NULL unless it was removed by a merge';
@@ -0,0 +1,13 @@
+-- 000023_comment_change_requests.down.sql
+-- PostgreSQL cannot drop enum values, so the type is rebuilt without COMMENT
+
+DELETE FROM change_requests WHERE entity_type = 'COMMENT';
+DELETE FROM approval_policies WHERE entity_type = 'COMMENT';
+DELETE FROM citations WHERE entity_type = 'COMMENT';
+
+ALTER TYPE entity_type RENAME TO entity_type_old;
+CREATE TYPE entity_type AS ENUM ('PERSON', 'RELATIONSHIP', 'MEDIA', 'EVENT', 'CHANGESET');
+ALTER TABLE change_requests ALTER COLUMN entity_type TYPE entity_type USING entity_type::text::entity_type;
+ALTER TABLE citations ALTER COLUMN entity_type TYPE entity_type USING entity_type::text::entity_type;
+ALTER TABLE approval_policies ALTER COLUMN entity_type TYPE entity_type USING entity_type::text::entity_type;
+DROP TYPE entity_type_old;
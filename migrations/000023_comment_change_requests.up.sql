@@ -0,0 +1,4 @@
+-- 000023_comment_change_requests.up.sql
+-- Lets change requests post, edit and delete comments on persons
+
+ALTER TYPE entity_type ADD VALUE IF NOT EXISTS 'COMMENT';
@@ -0,0 +1,3 @@
+-- 000024_outbox_retry_backoff.down.sql
+
+ALTER TABLE notification_outbox DROP COLUMN IF EXISTS next_attempt_at;
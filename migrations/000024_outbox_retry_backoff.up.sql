@@ -0,0 +1,7 @@
+-- 000024_outbox_retry_backoff.up.sql
+-- A message whose delivery failed waits before it is claimed again, so a
+-- dispatcher does not retry it straight away in the same run
+
+ALTER TABLE notification_outbox ADD COLUMN next_attempt_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN notification_outbox.next_attempt_at IS 'Earliest time a released row may be claimed again; NULL when it is due now';
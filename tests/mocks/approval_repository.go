@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type ApprovalRepository struct {
+	mock.Mock
+}
+
+func (m *ApprovalRepository) GetPolicy(ctx context.Context, entityType domain.EntityType, action domain.ChangeAction) (*domain.ApprovalPolicy, error) {
+	args := m.Called(ctx, entityType, action)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ApprovalPolicy), args.Error(1)
+}
+
+func (m *ApprovalRepository) ListPolicies(ctx context.Context) ([]domain.ApprovalPolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ApprovalPolicy), args.Error(1)
+}
+
+func (m *ApprovalRepository) UpsertPolicy(ctx context.Context, policy *domain.ApprovalPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *ApprovalRepository) LockRequest(ctx context.Context, requestID uuid.UUID) (domain.ChangeRequestStatus, error) {
+	args := m.Called(ctx, requestID)
+	return args.Get(0).(domain.ChangeRequestStatus), args.Error(1)
+}
+
+func (m *ApprovalRepository) AddApproval(ctx context.Context, approval *domain.ChangeRequestApproval) error {
+	args := m.Called(ctx, approval)
+	return args.Error(0)
+}
+
+func (m *ApprovalRepository) ListApprovals(ctx context.Context, requestID uuid.UUID) ([]domain.ChangeRequestApproval, error) {
+	args := m.Called(ctx, requestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ChangeRequestApproval), args.Error(1)
+}
+
+func (m *ApprovalRepository) ClearApprovals(ctx context.Context, requestID uuid.UUID) error {
+	args := m.Called(ctx, requestID)
+	return args.Error(0)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type ChangeRequestCommentRepository struct {
+	mock.Mock
+}
+
+func (m *ChangeRequestCommentRepository) Create(ctx context.Context, comment *domain.ChangeRequestComment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *ChangeRequestCommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChangeRequestComment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ChangeRequestComment), args.Error(1)
+}
+
+func (m *ChangeRequestCommentRepository) ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.ChangeRequestComment, error) {
+	args := m.Called(ctx, requestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ChangeRequestComment), args.Error(1)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"silsilah-keluarga/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -39,3 +40,39 @@ func (m *ChangeRequestRepository) CountPending(ctx context.Context) (int64, erro
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
+
+func (m *ChangeRequestRepository) Amend(ctx context.Context, req *domain.ChangeRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *ChangeRequestRepository) Close(ctx context.Context, id uuid.UUID, status domain.ChangeRequestStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *ChangeRequestRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ChangeRequestRepository) ExpireStale(ctx context.Context, before time.Time) ([]domain.ChangeRequest, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ChangeRequest), args.Error(1)
+}
+
+func (m *ChangeRequestRepository) CreateRevision(ctx context.Context, rev *domain.ChangeRequestRevision) error {
+	args := m.Called(ctx, rev)
+	return args.Error(0)
+}
+
+func (m *ChangeRequestRepository) ListRevisions(ctx context.Context, id uuid.UUID) ([]domain.ChangeRequestRevision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ChangeRequestRevision), args.Error(1)
+}
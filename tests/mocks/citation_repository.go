@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type CitationRepository struct {
+	mock.Mock
+}
+
+func (m *CitationRepository) Create(ctx context.Context, citation *domain.Citation) error {
+	args := m.Called(ctx, citation)
+	return args.Error(0)
+}
+
+func (m *CitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Citation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Citation), args.Error(1)
+}
+
+func (m *CitationRepository) Update(ctx context.Context, citation *domain.Citation) error {
+	args := m.Called(ctx, citation)
+	return args.Error(0)
+}
+
+func (m *CitationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *CitationRepository) ListByEntity(ctx context.Context, entityType domain.EntityType, entityID uuid.UUID) ([]domain.Citation, error) {
+	args := m.Called(ctx, entityType, entityID)
+	return args.Get(0).([]domain.Citation), args.Error(1)
+}
+
+func (m *CitationRepository) ListBySource(ctx context.Context, sourceID uuid.UUID) ([]domain.Citation, error) {
+	args := m.Called(ctx, sourceID)
+	return args.Get(0).([]domain.Citation), args.Error(1)
+}
+
+func (m *CitationRepository) CountBySource(ctx context.Context, sourceID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, sourceID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *CitationRepository) GetAll(ctx context.Context) ([]domain.Citation, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Citation), args.Error(1)
+}
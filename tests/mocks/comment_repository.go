@@ -30,8 +30,8 @@ func (m *CommentRepository) Update(ctx context.Context, comment *domain.Comment)
 	return args.Error(0)
 }
 
-func (m *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *CommentRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	args := m.Called(ctx, id, deletedBy)
 	return args.Error(0)
 }
 
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type CustomFieldRepository struct {
+	mock.Mock
+}
+
+func (m *CustomFieldRepository) CreateField(ctx context.Context, field *domain.CustomField) error {
+	args := m.Called(ctx, field)
+	return args.Error(0)
+}
+
+func (m *CustomFieldRepository) GetFieldByID(ctx context.Context, id uuid.UUID) (*domain.CustomField, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepository) GetFieldByKey(ctx context.Context, key string) (*domain.CustomField, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepository) UpdateField(ctx context.Context, field *domain.CustomField) error {
+	args := m.Called(ctx, field)
+	return args.Error(0)
+}
+
+func (m *CustomFieldRepository) DeleteField(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *CustomFieldRepository) ListFields(ctx context.Context) ([]domain.CustomField, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepository) SetValue(ctx context.Context, value *domain.PersonCustomValue) error {
+	args := m.Called(ctx, value)
+	return args.Error(0)
+}
+
+func (m *CustomFieldRepository) DeleteValue(ctx context.Context, personID, fieldID uuid.UUID) error {
+	args := m.Called(ctx, personID, fieldID)
+	return args.Error(0)
+}
+
+func (m *CustomFieldRepository) GetValuesByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonCustomValue, error) {
+	args := m.Called(ctx, personID)
+	return args.Get(0).([]domain.PersonCustomValue), args.Error(1)
+}
+
+func (m *CustomFieldRepository) GetAllValues(ctx context.Context) ([]domain.PersonCustomValue, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.PersonCustomValue), args.Error(1)
+}
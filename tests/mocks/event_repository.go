@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type EventRepository struct {
+	mock.Mock
+}
+
+func (m *EventRepository) Create(ctx context.Context, event *domain.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *EventRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Event), args.Error(1)
+}
+
+func (m *EventRepository) Update(ctx context.Context, event *domain.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *EventRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *EventRepository) ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Event, error) {
+	args := m.Called(ctx, personID)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *EventRepository) ListByRelationship(ctx context.Context, relationshipID uuid.UUID) ([]domain.Event, error) {
+	args := m.Called(ctx, relationshipID)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *EventRepository) GetAll(ctx context.Context) ([]domain.Event, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
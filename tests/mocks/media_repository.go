@@ -30,8 +30,8 @@ func (m *MediaRepository) Update(ctx context.Context, media *domain.Media) error
 	return args.Error(0)
 }
 
-func (m *MediaRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MediaRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	args := m.Called(ctx, id, deletedBy)
 	return args.Error(0)
 }
 
@@ -39,3 +39,8 @@ func (m *MediaRepository) List(ctx context.Context, personID *uuid.UUID, params
 	args := m.Called(ctx, personID, params)
 	return args.Get(0).([]domain.Media), args.Get(1).(int64), args.Error(2)
 }
+
+func (m *MediaRepository) ListDatedByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Media, error) {
+	args := m.Called(ctx, personID)
+	return args.Get(0).([]domain.Media), args.Error(1)
+}
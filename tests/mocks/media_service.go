@@ -29,8 +29,8 @@ func (m *MediaService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Media
 	return args.Get(0).(*domain.Media), args.Error(1)
 }
 
-func (m *MediaService) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *MediaService) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
 	return args.Error(0)
 }
 
@@ -43,3 +43,26 @@ func (m *MediaService) Approve(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MediaService) RemoveObjects(ctx context.Context, storagePaths []string) error {
+	args := m.Called(ctx, storagePaths)
+	return args.Error(0)
+}
+
+func (m *MediaService) ListDatedByPerson(ctx context.Context, personID uuid.UUID) ([]domain.Media, error) {
+	args := m.Called(ctx, personID)
+	return args.Get(0).([]domain.Media), args.Error(1)
+}
+
+func (m *MediaService) Update(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) (*domain.Media, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Media), args.Error(1)
+}
+
+func (m *MediaService) ValidateUpdate(ctx context.Context, id uuid.UUID, input domain.UpdateMediaInput) error {
+	args := m.Called(ctx, id, input)
+	return args.Error(0)
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepository struct {
+	mock.Mock
+}
+
+func (m *OutboxRepository) Create(ctx context.Context, msg *domain.OutboxMessage) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	args := m.Called(ctx, limit, maxAttempts, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OutboxMessage), args.Error(1)
+}
+
+func (m *OutboxRepository) MarkDispatched(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) Release(ctx context.Context, id uuid.UUID, lastError string, retryAfter time.Duration) error {
+	args := m.Called(ctx, id, lastError, retryAfter)
+	return args.Error(0)
+}
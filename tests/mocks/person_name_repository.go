@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type PersonNameRepository struct {
+	mock.Mock
+}
+
+func (m *PersonNameRepository) Create(ctx context.Context, name *domain.PersonName) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *PersonNameRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PersonName, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PersonName), args.Error(1)
+}
+
+func (m *PersonNameRepository) Update(ctx context.Context, name *domain.PersonName) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *PersonNameRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *PersonNameRepository) ListByPerson(ctx context.Context, personID uuid.UUID) ([]domain.PersonName, error) {
+	args := m.Called(ctx, personID)
+	return args.Get(0).([]domain.PersonName), args.Error(1)
+}
+
+func (m *PersonNameRepository) ListByPersons(ctx context.Context, personIDs []uuid.UUID) ([]domain.PersonName, error) {
+	args := m.Called(ctx, personIDs)
+	return args.Get(0).([]domain.PersonName), args.Error(1)
+}
+
+func (m *PersonNameRepository) GetAll(ctx context.Context) ([]domain.PersonName, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.PersonName), args.Error(1)
+}
+
+func (m *PersonNameRepository) BackfillNameKeys(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -31,9 +31,9 @@ func (m *PersonRepository) Update(ctx context.Context, person *domain.Person) er
 	return args.Error(0)
 }
 
-func (m *PersonRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
-	return args.Error(0)
+func (m *PersonRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
 func (m *PersonRepository) List(ctx context.Context, params domain.PaginationParams) ([]domain.Person, int64, error) {
@@ -41,9 +41,17 @@ func (m *PersonRepository) List(ctx context.Context, params domain.PaginationPar
 	return args.Get(0).([]domain.Person), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *PersonRepository) Search(ctx context.Context, query string, limit int) ([]domain.Person, error) {
-	args := m.Called(ctx, query, limit)
-	return args.Get(0).([]domain.Person), args.Error(1)
+func (m *PersonRepository) Search(ctx context.Context, input domain.PersonSearchInput) ([]domain.Person, int64, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).([]domain.Person), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *PersonRepository) SearchFacets(ctx context.Context, input domain.PersonSearchInput) (*domain.PersonSearchFacets, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PersonSearchFacets), args.Error(1)
 }
 
 func (m *PersonRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Person, error) {
@@ -56,6 +64,24 @@ func (m *PersonRepository) GetAll(ctx context.Context) ([]domain.Person, error)
 	return args.Get(0).([]domain.Person), args.Error(1)
 }
 
+func (m *PersonRepository) FindSimilar(ctx context.Context, name string, excludeID uuid.UUID, limit int) ([]domain.SimilarPerson, error) {
+	args := m.Called(ctx, name, excludeID, limit)
+	return args.Get(0).([]domain.SimilarPerson), args.Error(1)
+}
+
+func (m *PersonRepository) FindSimilarPairs(ctx context.Context, limit int) ([]domain.SimilarPair, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.SimilarPair), args.Error(1)
+}
+
+func (m *PersonRepository) Merge(ctx context.Context, survivor *domain.Person, mergedID, mergedBy uuid.UUID) (*domain.MergeResult, error) {
+	args := m.Called(ctx, survivor, mergedID, mergedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MergeResult), args.Error(1)
+}
+
 func (m *PersonRepository) CountAll(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
@@ -71,6 +97,11 @@ func (m *PersonRepository) CountOrphans(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *PersonRepository) CountLinkedUsers(ctx context.Context, personIDs []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, personIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *PersonRepository) GetLastActivityAt(ctx context.Context) (*time.Time, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -78,3 +109,8 @@ func (m *PersonRepository) GetLastActivityAt(ctx context.Context) (*time.Time, e
 	}
 	return args.Get(0).(*time.Time), args.Error(1)
 }
+
+func (m *PersonRepository) BackfillNameKeys(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
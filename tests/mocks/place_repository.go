@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/gazetteer"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type PlaceRepository struct {
+	mock.Mock
+}
+
+func (m *PlaceRepository) Create(ctx context.Context, place *domain.Place) error {
+	args := m.Called(ctx, place)
+	return args.Error(0)
+}
+
+func (m *PlaceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Place, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Place), args.Error(1)
+}
+
+func (m *PlaceRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Place, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Place), args.Error(1)
+}
+
+func (m *PlaceRepository) Update(ctx context.Context, place *domain.Place) error {
+	args := m.Called(ctx, place)
+	return args.Error(0)
+}
+
+func (m *PlaceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *PlaceRepository) List(ctx context.Context, parentID *uuid.UUID, level *domain.PlaceLevel) ([]domain.Place, error) {
+	args := m.Called(ctx, parentID, level)
+	return args.Get(0).([]domain.Place), args.Error(1)
+}
+
+func (m *PlaceRepository) Search(ctx context.Context, query string, limit int) ([]domain.Place, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).([]domain.Place), args.Error(1)
+}
+
+func (m *PlaceRepository) GetAncestors(ctx context.Context, id uuid.UUID) ([]domain.Place, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.Place), args.Error(1)
+}
+
+func (m *PlaceRepository) CountReferences(ctx context.Context, id uuid.UUID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PlaceRepository) ImportGazetteer(ctx context.Context, entries []gazetteer.Entry) (int64, error) {
+	args := m.Called(ctx, entries)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -26,13 +26,21 @@ func (m *RelationshipRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 	return args.Get(0).(*domain.Relationship), args.Error(1)
 }
 
+func (m *RelationshipRepository) FindPair(ctx context.Context, personA, personB uuid.UUID, relType domain.RelationshipType) (*domain.Relationship, error) {
+	args := m.Called(ctx, personA, personB, relType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Relationship), args.Error(1)
+}
+
 func (m *RelationshipRepository) Update(ctx context.Context, rel *domain.Relationship) error {
 	args := m.Called(ctx, rel)
 	return args.Error(0)
 }
 
-func (m *RelationshipRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
+func (m *RelationshipRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	args := m.Called(ctx, id, deletedBy)
 	return args.Error(0)
 }
 
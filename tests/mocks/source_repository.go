@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type SourceRepository struct {
+	mock.Mock
+}
+
+func (m *SourceRepository) Create(ctx context.Context, source *domain.Source) error {
+	args := m.Called(ctx, source)
+	return args.Error(0)
+}
+
+func (m *SourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Source, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Source), args.Error(1)
+}
+
+func (m *SourceRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Source, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.Source), args.Error(1)
+}
+
+func (m *SourceRepository) Update(ctx context.Context, source *domain.Source) error {
+	args := m.Called(ctx, source)
+	return args.Error(0)
+}
+
+func (m *SourceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *SourceRepository) List(ctx context.Context, params domain.SourceListParams) ([]domain.Source, int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]domain.Source), args.Get(1).(int64), args.Error(2)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// Transactor runs fn directly and records each transaction. A returned
+// error stands in for a failed commit.
+type Transactor struct {
+	mock.Mock
+}
+
+func (m *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return args.Error(0)
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+	"silsilah-keluarga/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type TrashRepository struct {
+	mock.Mock
+}
+
+func (m *TrashRepository) List(ctx context.Context, params domain.TrashListParams) ([]domain.TrashItem, int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]domain.TrashItem), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *TrashRepository) Restore(ctx context.Context, entityType domain.EntityType, id uuid.UUID) (*domain.RestoreResult, error) {
+	args := m.Called(ctx, entityType, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestoreResult), args.Error(1)
+}
+
+func (m *TrashRepository) Purge(ctx context.Context, before time.Time) (*domain.PurgeResult, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PurgeResult), args.Error(1)
+}
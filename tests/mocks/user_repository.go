@@ -34,6 +34,14 @@ func (m *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *UserRepository) GetByLinkedPerson(ctx context.Context, personID uuid.UUID) (*domain.User, error) {
+	args := m.Called(ctx, personID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
 func (m *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
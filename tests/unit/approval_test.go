@@ -0,0 +1,242 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewApprovalProgress(t *testing.T) {
+	consent := uuid.New()
+	policy := domain.ApprovalPolicy{RequiredApprovals: 2, RequireConsent: true}
+	editor := domain.ChangeRequestApproval{ReviewerID: uuid.New(), ReviewerRole: domain.RoleEditor}
+	developer := domain.ChangeRequestApproval{ReviewerID: uuid.New(), ReviewerRole: domain.RoleDeveloper}
+	subject := domain.ChangeRequestApproval{ReviewerID: consent, ReviewerRole: domain.RoleMember}
+
+	other := uuid.New()
+
+	tests := []struct {
+		name      string
+		approvals []domain.ChangeRequestApproval
+		consent   []uuid.UUID
+		reviews   int
+		met       bool
+	}{
+		{"One reviewer", []domain.ChangeRequestApproval{editor}, nil, 1, false},
+		{"Two reviewers, no consent needed", []domain.ChangeRequestApproval{editor, developer}, nil, 2, true},
+		{"Two reviewers, consent missing", []domain.ChangeRequestApproval{editor, developer}, []uuid.UUID{consent}, 2, false},
+		{"Consent does not count as a review", []domain.ChangeRequestApproval{editor, subject}, []uuid.UUID{consent}, 1, false},
+		{"Reviewers and consent", []domain.ChangeRequestApproval{editor, subject, developer}, []uuid.UUID{consent}, 2, true},
+		{"One of two consents", []domain.ChangeRequestApproval{editor, subject, developer}, []uuid.UUID{consent, other}, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := domain.NewApprovalProgress(policy, tt.approvals, tt.consent)
+			assert.Equal(t, tt.reviews, p.Reviews)
+			assert.Equal(t, tt.met, p.Met)
+		})
+	}
+}
+
+func TestChangeRequestService_ApproveQuorum(t *testing.T) {
+	ctx := context.Background()
+	personID := uuid.New()
+	subject := &domain.User{ID: uuid.New(), Role: string(domain.RoleMember)}
+	first := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	second := &domain.User{ID: uuid.New(), Role: string(domain.RoleDeveloper)}
+	outsider := &domain.User{ID: uuid.New(), Role: string(domain.RoleMember)}
+	policy := &domain.ApprovalPolicy{EntityType: domain.EntityPerson, Action: domain.ActionDelete, RequiredApprovals: 2, RequireConsent: true}
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	mockNotifRepo := new(mocks.NotificationRepository)
+	mockApprovalRepo := new(mocks.ApprovalRepository)
+	personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	svc := changerequest.NewService(
+		mockCRRepo, mockNotifRepo, mockUserRepo, mockPersonRepo, nil, nil, mockAuditRepo,
+		personSvc, nil, nil,
+	)
+	svc.SetApprovalRepository(mockApprovalRepo)
+
+	cr := &domain.ChangeRequest{
+		ID:          uuid.New(),
+		RequestedBy: uuid.New(),
+		Status:      domain.StatusPending,
+		EntityType:  domain.EntityPerson,
+		EntityID:    &personID,
+		Action:      domain.ActionDelete,
+	}
+	approval := func(u *domain.User) domain.ChangeRequestApproval {
+		return domain.ChangeRequestApproval{RequestID: cr.ID, ReviewerID: u.ID, ReviewerRole: domain.UserRole(u.Role)}
+	}
+
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	for _, u := range []*domain.User{subject, first, second, outsider} {
+		mockUserRepo.On("GetByID", ctx, u.ID).Return(u, nil)
+	}
+	mockUserRepo.On("GetByLinkedPerson", ctx, personID).Return(subject, nil)
+	mockApprovalRepo.On("GetPolicy", ctx, domain.EntityPerson, domain.ActionDelete).Return(policy, nil)
+	mockApprovalRepo.On("LockRequest", ctx, cr.ID).Return(domain.StatusPending, nil)
+	mockCRRepo.On("Touch", ctx, cr.ID).Return(nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	t.Run("Outsider cannot approve", func(t *testing.T) {
+		_, err := svc.Approve(ctx, cr.ID, outsider.ID, nil, nil, nil)
+
+		assert.ErrorIs(t, err, changerequest.ErrInsufficientPermissions)
+	})
+
+	t.Run("First reviewer keeps the request pending", func(t *testing.T) {
+		mockApprovalRepo.On("AddApproval", ctx, mock.Anything).Return(nil).Once()
+		mockApprovalRepo.On("ListApprovals", ctx, cr.ID).Return([]domain.ChangeRequestApproval{approval(first)}, nil).Once()
+
+		progress, err := svc.Approve(ctx, cr.ID, first.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		assert.False(t, progress.Met)
+		assert.Equal(t, 1, progress.Reviews)
+		mockCRRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reviewer cannot approve twice", func(t *testing.T) {
+		mockApprovalRepo.On("AddApproval", ctx, mock.Anything).Return(domain.ErrAlreadyApproved).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, first.ID, nil, nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyApproved)
+	})
+
+	t.Run("Second reviewer still needs consent", func(t *testing.T) {
+		mockApprovalRepo.On("AddApproval", ctx, mock.Anything).Return(nil).Once()
+		mockApprovalRepo.On("ListApprovals", ctx, cr.ID).Return([]domain.ChangeRequestApproval{approval(first), approval(second)}, nil).Once()
+
+		progress, err := svc.Approve(ctx, cr.ID, second.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, progress.Reviews)
+		assert.False(t, progress.ConsentGiven)
+		assert.False(t, progress.Met)
+	})
+
+	t.Run("Consent applies the request", func(t *testing.T) {
+		mockApprovalRepo.On("AddApproval", ctx, mock.MatchedBy(func(a *domain.ChangeRequestApproval) bool {
+			return a.ReviewerID == subject.ID && a.ReviewerRole == domain.RoleMember
+		})).Return(nil).Once()
+		mockApprovalRepo.On("ListApprovals", ctx, cr.ID).Return([]domain.ChangeRequestApproval{approval(first), approval(second), approval(subject)}, nil).Once()
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, subject.ID, (*string)(nil)).Return(nil).Once()
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+		mockPersonRepo.On("Delete", ctx, personID, cr.RequestedBy).Return(uuid.New(), nil).Once()
+		mockNotifRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+		progress, err := svc.Approve(ctx, cr.ID, subject.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		assert.True(t, progress.Met)
+		mockCRRepo.AssertExpectations(t)
+		mockPersonRepo.AssertExpectations(t)
+	})
+}
+
+func TestChangeRequestService_ApproveClosedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	personID := uuid.New()
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockApprovalRepo := new(mocks.ApprovalRepository)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, mockUserRepo, nil, nil, nil, nil,
+		nil, nil, nil,
+	)
+	svc.SetApprovalRepository(mockApprovalRepo)
+
+	// The request was read as pending, but another reviewer rejected it
+	// before this approval took the lock.
+	cr := &domain.ChangeRequest{
+		ID:          uuid.New(),
+		RequestedBy: uuid.New(),
+		Status:      domain.StatusPending,
+		EntityType:  domain.EntityPerson,
+		EntityID:    &personID,
+		Action:      domain.ActionDelete,
+	}
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	mockApprovalRepo.On("LockRequest", ctx, cr.ID).Return(domain.StatusRejected, nil)
+
+	_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+	assert.ErrorIs(t, err, domain.ErrChangeRequestNotPending)
+	mockApprovalRepo.AssertNotCalled(t, "AddApproval", mock.Anything, mock.Anything)
+	mockCRRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestService_MergeConsent(t *testing.T) {
+	ctx := context.Background()
+	sourceID, targetID := uuid.New(), uuid.New()
+	sourceUser := &domain.User{ID: uuid.New(), Role: string(domain.RoleMember)}
+	targetUser := &domain.User{ID: uuid.New(), Role: string(domain.RoleMember)}
+	policy := &domain.ApprovalPolicy{EntityType: domain.EntityPerson, Action: domain.ActionMerge, RequiredApprovals: 2, RequireConsent: true}
+
+	setup := func(requester uuid.UUID) (changerequest.Service, *domain.ChangeRequest) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockApprovalRepo := new(mocks.ApprovalRepository)
+		svc := changerequest.NewService(
+			mockCRRepo, nil, mockUserRepo, nil, nil, nil, nil,
+			nil, nil, nil,
+		)
+		svc.SetApprovalRepository(mockApprovalRepo)
+
+		cr := &domain.ChangeRequest{
+			ID:          uuid.New(),
+			RequestedBy: requester,
+			Status:      domain.StatusPending,
+			EntityType:  domain.EntityPerson,
+			EntityID:    &sourceID,
+			Action:      domain.ActionMerge,
+			Payload:     []byte(`{"target_id":"` + targetID.String() + `"}`),
+		}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		mockUserRepo.On("GetByLinkedPerson", ctx, sourceID).Return(sourceUser, nil)
+		mockUserRepo.On("GetByLinkedPerson", ctx, targetID).Return(targetUser, nil)
+		mockUserRepo.On("GetByID", ctx, sourceUser.ID).Return(sourceUser, nil)
+		mockApprovalRepo.On("GetPolicy", ctx, domain.EntityPerson, domain.ActionMerge).Return(policy, nil)
+		mockApprovalRepo.On("ListApprovals", ctx, cr.ID).Return([]domain.ChangeRequestApproval{
+			{RequestID: cr.ID, ReviewerID: sourceUser.ID, ReviewerRole: domain.RoleMember},
+		}, nil)
+		return svc, cr
+	}
+
+	t.Run("Requester linked to the merged person", func(t *testing.T) {
+		svc, cr := setup(sourceUser.ID)
+
+		p, err := svc.Approvals(ctx, cr.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{targetUser.ID}, p.ConsentUserIDs)
+		assert.False(t, p.ConsentGiven)
+	})
+
+	t.Run("Both linked users are asked", func(t *testing.T) {
+		svc, cr := setup(uuid.New())
+
+		p, err := svc.Approvals(ctx, cr.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{sourceUser.ID, targetUser.ID}, p.ConsentUserIDs)
+		assert.False(t, p.ConsentGiven, "only the merged person's user approved")
+	})
+}
@@ -0,0 +1,87 @@
+package unit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/calendar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_JavaneseAnchors(t *testing.T) {
+	cases := []struct {
+		year int
+		want time.Time
+	}{
+		{1555, gdDay(1633, time.July, 8)},
+		{1867, gdDay(1936, time.March, 24)},
+		{1958, gdDay(2024, time.July, 8)},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, calendar.Javanese.ToGregorian(c.year, 1, 1), "1 Sura %d", c.year)
+	}
+
+	assert.Equal(t, "Alip", calendar.WinduYear(1867))
+	assert.Equal(t, "Je", calendar.WinduYear(1958))
+	// 1866 AJ closes a kurup, so its Besar loses the Jimakir leap day.
+	assert.Equal(t, 29, calendar.Javanese.DaysInMonth(1866, 12))
+	assert.Equal(t, 30, calendar.Javanese.DaysInMonth(1858, 12))
+	assert.Error(t, calendar.Javanese.Validate(1500, 1, 1))
+}
+
+func TestCalendar_HijriConversion(t *testing.T) {
+	assert.Equal(t, gdDay(1946, time.August, 10), calendar.Hijri.ToGregorian(1365, 9, 12))
+	assert.Equal(t, gdDay(1633, time.July, 8), calendar.Hijri.ToGregorian(1043, 1, 1))
+	assert.Equal(t, 30, calendar.Hijri.DaysInMonth(1445, 12))
+	assert.Equal(t, 29, calendar.Hijri.DaysInMonth(1446, 12))
+}
+
+func TestCalendar_RoundTrip(t *testing.T) {
+	for _, cal := range []calendar.Calendar{calendar.Hijri, calendar.Javanese} {
+		for day := gdDay(1700, time.January, 1); day.Year() < 2100; day = day.AddDate(0, 0, 97) {
+			y, m, d, err := cal.FromGregorian(day)
+			require.NoError(t, err)
+			require.NoError(t, cal.Validate(y, m, d))
+			assert.Equal(t, day, cal.ToGregorian(y, m, d), "%s %d-%d-%d", cal, y, m, d)
+		}
+	}
+
+	_, _, _, err := calendar.Javanese.FromGregorian(gdDay(1600, time.January, 1))
+	assert.ErrorIs(t, err, calendar.ErrInvalidDate)
+}
+
+func TestGenDate_CalendarDates(t *testing.T) {
+	d := mustDate(t, "@#DHIJRI@ 12 RAMAD 1365")
+	assert.Equal(t, calendar.Hijri, d.Calendar)
+	assert.Equal(t, "@#DHIJRI@ 12 RAMAD 1365", d.String())
+	assert.Equal(t, gdDay(1946, time.August, 10), *d.SortDate())
+	assert.Equal(t, "10 AUG 1946", d.Gregorian().String())
+	assert.Equal(t, "INT 10 AUG 1946 (12 Ramadan 1365 H)", d.GEDCOM())
+	assert.Equal(t, 1946, d.Year())
+
+	d = mustDate(t, "@#DJAVANESE@ SURA 1867")
+	assert.Equal(t, gdDay(1936, time.March, 24), *d.Earliest())
+	assert.Equal(t, "BET 24 MAR 1936 AND 22 APR 1936", d.Gregorian().String())
+
+	d = mustDate(t, "BET @#DHIJRI@ 1365 AND @#DHIJRI@ 1366")
+	assert.Equal(t, calendar.Hijri, d.Calendar)
+
+	_, err := domain.ParseGenDate("BET @#DHIJRI@ 1365 AND 1950")
+	assert.ErrorIs(t, err, domain.ErrInvalidGenDate)
+	_, err = domain.ParseGenDate("@#DJAVANESE@ 1500")
+	assert.ErrorIs(t, err, domain.ErrInvalidGenDate)
+
+	var input struct {
+		Date *domain.GenDate `json:"date"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"date":{"calendar":"javanese","date":"1867-01-01"}}`), &input))
+	out, err := json.Marshal(input.Date)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"value":"@#DJAVANESE@ 1 SURA 1867"`)
+	assert.Contains(t, string(out), `"gregorian":"24 MAR 1936"`)
+	assert.Contains(t, string(out), `"display":"1 Sura 1867 J"`)
+}
@@ -0,0 +1,124 @@
+package unit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findChange(changes []domain.FieldChange, field string) *domain.FieldChange {
+	for i := range changes {
+		if changes[i].Field == field {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestDiffFields(t *testing.T) {
+	before := &domain.Person{ID: uuid.New(), FirstName: "Ahmad", Occupation: stringPtr("Petani"), IsAlive: true}
+	after := &domain.Person{ID: uuid.New(), FirstName: "Ahmad", Occupation: stringPtr("Guru"), Religion: stringPtr("Islam"), IsAlive: true}
+
+	changes, err := domain.DiffFields(before, after)
+
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "occupation", changes[0].Field)
+	assert.Equal(t, "Petani", changes[0].Before)
+	assert.Equal(t, "Guru", changes[0].After)
+	assert.Equal(t, "religion", changes[1].Field)
+	assert.Nil(t, changes[1].Before)
+}
+
+func TestSummarizeChange(t *testing.T) {
+	changes := []domain.FieldChange{{Field: "a"}, {Field: "b"}, {Field: "c"}, {Field: "d"}, {Field: "e"}, {Field: "f"}, {Field: "g"}}
+
+	assert.Equal(t, "Update person Ahmad: a, b, c, d, e, +2 more", domain.SummarizeChange(domain.ActionUpdate, domain.EntityPerson, "Ahmad", changes))
+	assert.Equal(t, "Create person Siti", domain.SummarizeChange(domain.ActionCreate, domain.EntityPerson, "Siti", changes))
+	assert.Equal(t, "Delete relationship", domain.SummarizeChange(domain.ActionDelete, domain.EntityRelationship, "", nil))
+}
+
+func TestChangeRequestService_Diff(t *testing.T) {
+	ctx := context.Background()
+	viewer := domain.Viewer{UserID: uuid.New(), Role: domain.RoleEditor}
+
+	setup := func() (*mocks.ChangeRequestRepository, *mocks.PersonRepository, *mocks.RelationshipRepository, changerequest.Service) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockPersonRepo := new(mocks.PersonRepository)
+		mockRelRepo := new(mocks.RelationshipRepository)
+		svc := changerequest.NewService(
+			mockCRRepo, nil, nil, mockPersonRepo, mockRelRepo, nil, nil,
+			nil, nil, nil,
+		)
+		return mockCRRepo, mockPersonRepo, mockRelRepo, svc
+	}
+
+	t.Run("Person update", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, _, svc := setup()
+		current := &domain.Person{ID: uuid.New(), FirstName: "Ahmad", Occupation: stringPtr("Petani"), IsAlive: false}
+		payload, _ := json.Marshal(domain.Person{FirstName: "Ahmad", Occupation: stringPtr("Guru"), IsAlive: false})
+		cr := &domain.ChangeRequest{ID: uuid.New(), EntityType: domain.EntityPerson, EntityID: &current.ID, Action: domain.ActionUpdate, Payload: payload}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil).Once()
+		mockPersonRepo.On("GetByID", ctx, current.ID).Return(current, nil).Once()
+
+		d, err := svc.Diff(ctx, cr.ID, viewer)
+
+		require.NoError(t, err)
+		require.Len(t, d.Changes, 1)
+		assert.Equal(t, "occupation", d.Changes[0].Field)
+		assert.Equal(t, "Petani", d.Changes[0].Before)
+		assert.Equal(t, "Update person Ahmad: occupation", d.Summary)
+	})
+
+	t.Run("Missing person", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, _, svc := setup()
+		id := uuid.New()
+		cr := &domain.ChangeRequest{ID: uuid.New(), EntityType: domain.EntityPerson, EntityID: &id, Action: domain.ActionDelete, Payload: []byte(`{}`)}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil).Once()
+		mockPersonRepo.On("GetByID", ctx, id).Return(nil, nil).Once()
+
+		d, err := svc.Diff(ctx, cr.ID, viewer)
+
+		require.NoError(t, err)
+		assert.True(t, d.EntityMissing)
+		assert.Empty(t, d.Changes)
+	})
+
+	t.Run("Relationship names persons", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, _, svc := setup()
+		a := &domain.Person{ID: uuid.New(), FirstName: "Budi", IsAlive: false}
+		b := &domain.Person{ID: uuid.New(), FirstName: "Sari", IsAlive: false}
+		payload, _ := json.Marshal(domain.Relationship{PersonA: a.ID, PersonB: b.ID, Type: domain.RelTypeSpouse})
+		cr := &domain.ChangeRequest{ID: uuid.New(), EntityType: domain.EntityRelationship, Action: domain.ActionCreate, Payload: payload}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil).Once()
+		mockPersonRepo.On("GetByID", ctx, a.ID).Return(a, nil)
+		mockPersonRepo.On("GetByID", ctx, b.ID).Return(b, nil)
+
+		d, err := svc.Diff(ctx, cr.ID, viewer)
+
+		require.NoError(t, err)
+		change := findChange(d.Changes, "person_a")
+		require.NotNil(t, change)
+		assert.Equal(t, "Budi", change.AfterLabel)
+		assert.Equal(t, "Create relationship Budi – Sari (SPOUSE)", d.Summary)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockCRRepo, _, _, svc := setup()
+		id := uuid.New()
+		mockCRRepo.On("GetByID", ctx, id).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := svc.Diff(ctx, id, viewer)
+
+		assert.ErrorIs(t, err, changerequest.ErrChangeRequestNotFound)
+	})
+}
@@ -0,0 +1,236 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/comment"
+	"silsilah-keluarga/internal/service/event"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeRequestService_EventPayloads(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	personID := uuid.New()
+
+	setup := func() (*mocks.ChangeRequestRepository, *mocks.PersonRepository, changerequest.Service) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockPersonRepo := new(mocks.PersonRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByRoles", mock.Anything, mock.Anything).Return([]domain.User{}, nil)
+		eventSvc := event.NewService(new(mocks.EventRepository), mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PlaceRepository), new(mocks.AuditLogRepository))
+		svc := changerequest.NewService(
+			mockCRRepo, nil, mockUserRepo, mockPersonRepo, nil, nil, new(mocks.AuditLogRepository),
+			nil, nil, nil,
+		)
+		svc.SetEventService(eventSvc)
+		return mockCRRepo, mockPersonRepo, svc
+	}
+
+	t.Run("Valid event", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, svc := setup()
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil)
+		mockCRRepo.On("Create", ctx, mock.AnythingOfType("*domain.ChangeRequest")).Return(nil).Once()
+
+		cr, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityEvent,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"person_id":"` + personID.String() + `","type":"GRADUATION","title":"Wisuda S1","metadata":{"institution":"Universitas Gadjah Mada"}}`),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.EntityEvent, cr.EntityType)
+		mockCRRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown event type", func(t *testing.T) {
+		mockCRRepo, _, svc := setup()
+
+		_, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityEvent,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"person_id":"` + personID.String() + `","type":"CORONATION","title":"Penobatan"}`),
+		})
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, domain.ErrInvalidEventType)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		mockCRRepo, _, svc := setup()
+
+		_, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityEvent,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"person_id":"` + personID.String() + `","type":"GRADUATION","title":"Wisuda S1","tanggal":"2001"}`),
+		})
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestChangeRequestService_MediaChanges(t *testing.T) {
+	ctx := context.Background()
+	member := uuid.New()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	mediaID := uuid.New()
+	caption := "Lebaran 1985"
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockMediaSvc := new(mocks.MediaService)
+	mockNotifRepo := new(mocks.NotificationRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := changerequest.NewService(
+		mockCRRepo, mockNotifRepo, mockUserRepo, nil, nil, new(mocks.MediaRepository), mockAuditRepo,
+		nil, nil, mockMediaSvc,
+	)
+	mockUserRepo.On("GetByRoles", mock.Anything, mock.Anything).Return([]domain.User{}, nil)
+	mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	mockNotifRepo.On("Create", ctx, mock.Anything).Return(nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	t.Run("Uploads name the file they publish", func(t *testing.T) {
+		mockCRRepo.On("Create", ctx, mock.AnythingOfType("*domain.ChangeRequest")).Return(nil).Once()
+
+		_, err := svc.Create(ctx, member, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityMedia,
+			EntityID:   &mediaID,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{}`),
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Edit is validated when filed", func(t *testing.T) {
+		mockMediaSvc.On("ValidateUpdate", ctx, mediaID, mock.Anything).Return(domain.ErrInvalidMediaEdit).Once()
+
+		_, err := svc.Create(ctx, member, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityMedia,
+			EntityID:   &mediaID,
+			Action:     domain.ActionUpdate,
+			Payload:    json.RawMessage(`{"taken_at":"2999-01-01T00:00:00Z"}`),
+		})
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+	})
+
+	t.Run("Approving an edit applies it", func(t *testing.T) {
+		cr := &domain.ChangeRequest{
+			ID:          uuid.New(),
+			RequestedBy: member,
+			Status:      domain.StatusPending,
+			EntityType:  domain.EntityMedia,
+			EntityID:    &mediaID,
+			Action:      domain.ActionUpdate,
+			Payload:     json.RawMessage(`{"caption":"` + caption + `"}`),
+		}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Once()
+		mockMediaSvc.On("Update", ctx, mediaID, mock.MatchedBy(func(in domain.UpdateMediaInput) bool {
+			return in.Caption.Set && *in.Caption.Value == caption && !in.TakenAt.Set
+		})).Return(&domain.Media{ID: mediaID, Caption: &caption}, nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		mockMediaSvc.AssertExpectations(t)
+	})
+}
+
+func TestChangeRequestService_CommentChanges(t *testing.T) {
+	ctx := context.Background()
+	member := uuid.New()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	personID := uuid.New()
+	existing := &domain.Comment{ID: uuid.New(), PersonID: personID, UserID: member, Content: "Lahir di Solo"}
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockCommentRepo := new(mocks.CommentRepository)
+	mockNotifRepo := new(mocks.NotificationRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := changerequest.NewService(
+		mockCRRepo, mockNotifRepo, mockUserRepo, mockPersonRepo, nil, nil, mockAuditRepo,
+		nil, nil, nil,
+	)
+	svc.SetCommentService(comment.NewService(mockCommentRepo, nil))
+	mockUserRepo.On("GetByRoles", mock.Anything, mock.Anything).Return([]domain.User{}, nil)
+	mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	mockNotifRepo.On("Create", ctx, mock.Anything).Return(nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+	mockCommentRepo.On("GetByID", ctx, existing.ID).Return(existing, nil)
+
+	t.Run("Comment on a missing person", func(t *testing.T) {
+		missing := uuid.New()
+		mockPersonRepo.On("GetByID", ctx, missing).Return(nil, nil).Once()
+
+		_, err := svc.Create(ctx, member, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityComment,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"person_id":"` + missing.String() + `","content":"Lahir di Solo"}`),
+		})
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, domain.ErrPersonNotFound)
+	})
+
+	t.Run("Empty comment", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+
+		_, err := svc.Create(ctx, member, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityComment,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"person_id":"` + personID.String() + `","content":"  "}`),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidComment)
+	})
+
+	t.Run("Only the author can edit", func(t *testing.T) {
+		_, err := svc.Create(ctx, uuid.New(), domain.CreateChangeRequestInput{
+			EntityType: domain.EntityComment,
+			EntityID:   &existing.ID,
+			Action:     domain.ActionUpdate,
+			Payload:    json.RawMessage(`{"content":"Lahir di Surakarta"}`),
+		})
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, domain.ErrNotCommentAuthor)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Approving a comment posts it as the requester", func(t *testing.T) {
+		cr := &domain.ChangeRequest{
+			ID:          uuid.New(),
+			RequestedBy: member,
+			Status:      domain.StatusPending,
+			EntityType:  domain.EntityComment,
+			Action:      domain.ActionCreate,
+			Payload:     json.RawMessage(`{"person_id":"` + personID.String() + `","content":"Lahir di Solo"}`),
+		}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Once()
+		mockCommentRepo.On("Create", ctx, mock.MatchedBy(func(c *domain.Comment) bool {
+			return c.PersonID == personID && c.UserID == member && c.Content == "Lahir di Solo"
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		mockCommentRepo.AssertExpectations(t)
+	})
+}
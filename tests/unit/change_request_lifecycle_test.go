@@ -0,0 +1,286 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/outbox"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycleMocks struct {
+	cr       *mocks.ChangeRequestRepository
+	user     *mocks.UserRepository
+	notif    *mocks.NotificationRepository
+	audit    *mocks.AuditLogRepository
+	comments *mocks.ChangeRequestCommentRepository
+}
+
+func setupLifecycle() (lifecycleMocks, changerequest.Service) {
+	m := lifecycleMocks{
+		cr:       new(mocks.ChangeRequestRepository),
+		user:     new(mocks.UserRepository),
+		notif:    new(mocks.NotificationRepository),
+		audit:    new(mocks.AuditLogRepository),
+		comments: new(mocks.ChangeRequestCommentRepository),
+	}
+	personSvc := person.NewService(new(mocks.PersonRepository), new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), m.audit, nil)
+	svc := changerequest.NewService(
+		m.cr, m.notif, m.user, new(mocks.PersonRepository), nil, nil, m.audit,
+		personSvc, nil, nil,
+	)
+	svc.SetCommentRepository(m.comments)
+	return m, svc
+}
+
+func pendingPersonCreate(requester uuid.UUID) *domain.ChangeRequest {
+	return &domain.ChangeRequest{
+		ID:          uuid.New(),
+		RequestedBy: requester,
+		Status:      domain.StatusPending,
+		EntityType:  domain.EntityPerson,
+		Action:      domain.ActionCreate,
+		Payload:     json.RawMessage(`{"first_name":"Rahmat"}`),
+		Revision:    1,
+	}
+}
+
+func TestChangeRequestService_Withdraw(t *testing.T) {
+	ctx := context.Background()
+	requester := uuid.New()
+
+	t.Run("Only the requester", func(t *testing.T) {
+		m, svc := setupLifecycle()
+		cr := pendingPersonCreate(requester)
+		m.cr.On("GetByID", ctx, cr.ID).Return(cr, nil)
+
+		err := svc.Withdraw(ctx, cr.ID, uuid.New(), nil)
+
+		assert.ErrorIs(t, err, changerequest.ErrNotRequester)
+		m.cr.AssertNotCalled(t, "Close", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Closed request", func(t *testing.T) {
+		m, svc := setupLifecycle()
+		cr := pendingPersonCreate(requester)
+		cr.Status = domain.StatusApproved
+		m.cr.On("GetByID", ctx, cr.ID).Return(cr, nil)
+
+		err := svc.Withdraw(ctx, cr.ID, requester, nil)
+
+		assert.ErrorIs(t, err, domain.ErrChangeRequestClosed)
+	})
+
+	t.Run("Reviewers who commented are told", func(t *testing.T) {
+		m, svc := setupLifecycle()
+		cr := pendingPersonCreate(requester)
+		reviewer := uuid.New()
+		m.cr.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		m.cr.On("Close", ctx, cr.ID, domain.StatusWithdrawn).Return(nil).Once()
+		m.comments.On("ListByRequest", ctx, cr.ID).Return([]domain.ChangeRequestComment{
+			{UserID: requester}, {UserID: reviewer}, {UserID: reviewer},
+		}, nil)
+		m.notif.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == reviewer && n.Type == domain.NotifChangeWithdrawn
+		})).Return(nil).Once()
+		m.audit.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		err := svc.Withdraw(ctx, cr.ID, requester, nil)
+
+		require.NoError(t, err)
+		m.cr.AssertExpectations(t)
+		m.notif.AssertExpectations(t)
+	})
+
+	t.Run("Notifications go through the outbox", func(t *testing.T) {
+		m, svc := setupLifecycle()
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTx := new(mocks.Transactor)
+		svc.SetTransactor(mockTx, outbox.NewService(mockOutboxRepo, new(mocks.NotificationService)))
+		cr := pendingPersonCreate(requester)
+		reviewer := uuid.New()
+		m.cr.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		m.cr.On("Close", ctx, cr.ID, domain.StatusWithdrawn).Return(nil).Once()
+		m.comments.On("ListByRequest", ctx, cr.ID).Return([]domain.ChangeRequestComment{{UserID: reviewer}}, nil)
+		m.audit.On("Create", ctx, mock.Anything).Return(nil).Once()
+		mockTx.On("WithinTx", ctx).Return(nil).Once()
+		mockOutboxRepo.On("Create", ctx, mock.MatchedBy(func(msg *domain.OutboxMessage) bool {
+			var n domain.Notification
+			return msg.Type == domain.NotifChangeWithdrawn && msg.EntityID == cr.ID && msg.ActorID == requester &&
+				json.Unmarshal(msg.Notification, &n) == nil && n.UserID == reviewer
+		})).Return(nil).Once()
+		// Delivery after commit runs in the background.
+		mockOutboxRepo.On("ClaimPending", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+		err := svc.Withdraw(ctx, cr.ID, requester, nil)
+
+		require.NoError(t, err)
+		mockOutboxRepo.AssertExpectations(t)
+		m.notif.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestChangeRequestService_RequestChangesAndAmend(t *testing.T) {
+	ctx := context.Background()
+	requester := uuid.New()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	note := "Please add the gender"
+
+	m, svc := setupLifecycle()
+	cr := pendingPersonCreate(requester)
+	m.cr.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	m.user.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	m.comments.On("ListByRequest", ctx, cr.ID).Return([]domain.ChangeRequestComment{}, nil)
+	m.audit.On("Create", ctx, mock.Anything).Return(nil)
+
+	t.Run("Note is required", func(t *testing.T) {
+		err := svc.RequestChanges(ctx, cr.ID, reviewer.ID, nil, nil)
+
+		assert.ErrorIs(t, err, changerequest.ErrNoteRequired)
+	})
+
+	t.Run("Request changes", func(t *testing.T) {
+		m.cr.On("UpdateStatus", ctx, cr.ID, domain.StatusChangesRequested, reviewer.ID, &note).Return(nil).Once()
+		m.notif.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == requester && n.Type == domain.NotifChangesRequested
+		})).Return(nil).Once()
+
+		err := svc.RequestChanges(ctx, cr.ID, reviewer.ID, &note, nil)
+
+		require.NoError(t, err)
+		m.notif.AssertExpectations(t)
+	})
+
+	t.Run("Amend keeps the revision", func(t *testing.T) {
+		cr.Status = domain.StatusChangesRequested
+		cr.ReviewedBy = &reviewer.ID
+		cr.ReviewNote = &note
+		m.cr.On("CreateRevision", ctx, mock.MatchedBy(func(rev *domain.ChangeRequestRevision) bool {
+			return rev.Revision == 1 && string(rev.Payload) == `{"first_name":"Rahmat"}` && rev.ReviewNote == &note
+		})).Return(nil).Once()
+		m.cr.On("Amend", ctx, mock.MatchedBy(func(a *domain.ChangeRequest) bool {
+			return a.Revision == 2 && a.Status == domain.StatusPending && a.ReviewedBy == nil
+		})).Return(nil).Once()
+		m.notif.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == reviewer.ID && n.Type == domain.NotifChangeAmended
+		})).Return(nil).Once()
+
+		amended, err := svc.Amend(ctx, cr.ID, requester, domain.AmendChangeRequestInput{
+			Payload: json.RawMessage(`{"first_name":"Rahmat","gender":"MALE"}`),
+		}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, amended.Revision)
+		assert.Equal(t, domain.StatusPending, amended.Status)
+		m.cr.AssertExpectations(t)
+		m.notif.AssertExpectations(t)
+	})
+
+	t.Run("Amendment is validated", func(t *testing.T) {
+		_, err := svc.Amend(ctx, cr.ID, requester, domain.AmendChangeRequestInput{
+			Payload: json.RawMessage(`{"first_name":"Rahmat","birth_date":"1990-01-01","death_date":"1980-01-01"}`),
+		}, nil)
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+	})
+
+	t.Run("Concurrent amendment keeps no revision", func(t *testing.T) {
+		m.cr.On("Amend", ctx, mock.Anything).Return(domain.ErrChangeRequestClosed).Once()
+
+		_, err := svc.Amend(ctx, cr.ID, requester, domain.AmendChangeRequestInput{
+			Payload: json.RawMessage(`{"first_name":"Rahmat","gender":"FEMALE"}`),
+		}, nil)
+
+		assert.ErrorIs(t, err, domain.ErrChangeRequestClosed)
+		m.cr.AssertNumberOfCalls(t, "CreateRevision", 1)
+	})
+}
+
+func TestChangeRequestService_Comments(t *testing.T) {
+	ctx := context.Background()
+	requester := uuid.New()
+	member := &domain.User{ID: uuid.New(), Role: string(domain.RoleMember)}
+
+	m, svc := setupLifecycle()
+	cr := pendingPersonCreate(requester)
+	m.cr.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	m.user.On("GetByID", ctx, member.ID).Return(member, nil)
+
+	t.Run("Outsiders cannot comment", func(t *testing.T) {
+		_, err := svc.AddComment(ctx, cr.ID, member.ID, domain.CreateChangeRequestCommentInput{Content: "Hmm"})
+
+		assert.ErrorIs(t, err, changerequest.ErrInsufficientPermissions)
+	})
+
+	t.Run("Reply to another request's comment", func(t *testing.T) {
+		other := &domain.ChangeRequestComment{ID: uuid.New(), RequestID: uuid.New()}
+		m.comments.On("GetByID", ctx, other.ID).Return(other, nil)
+
+		_, err := svc.AddComment(ctx, cr.ID, requester, domain.CreateChangeRequestCommentInput{ParentID: &other.ID, Content: "Yes"})
+
+		assert.ErrorIs(t, err, changerequest.ErrCommentNotFound)
+	})
+
+	t.Run("Comment postpones expiry", func(t *testing.T) {
+		m.comments.On("Create", ctx, mock.AnythingOfType("*domain.ChangeRequestComment")).Return(nil).Once()
+		m.cr.On("Touch", ctx, cr.ID).Return(nil).Once()
+		m.comments.On("ListByRequest", ctx, cr.ID).Return([]domain.ChangeRequestComment{}, nil).Once()
+
+		comment, err := svc.AddComment(ctx, cr.ID, requester, domain.CreateChangeRequestCommentInput{Content: "Source: family bible"})
+
+		require.NoError(t, err)
+		assert.Equal(t, cr.ID, comment.RequestID)
+		m.cr.AssertExpectations(t)
+	})
+}
+
+func TestThreadComments(t *testing.T) {
+	root := domain.ChangeRequestComment{ID: uuid.New()}
+	reply := domain.ChangeRequestComment{ID: uuid.New(), ParentID: &root.ID}
+	nested := domain.ChangeRequestComment{ID: uuid.New(), ParentID: &reply.ID}
+	second := domain.ChangeRequestComment{ID: uuid.New()}
+
+	threads := domain.ThreadComments([]domain.ChangeRequestComment{root, reply, second, nested})
+
+	require.Len(t, threads, 2)
+	assert.Equal(t, root.ID, threads[0].ID)
+	require.Len(t, threads[0].Replies, 1)
+	require.Len(t, threads[0].Replies[0].Replies, 1)
+	assert.Equal(t, nested.ID, threads[0].Replies[0].Replies[0].ID)
+	assert.Empty(t, threads[1].Replies)
+}
+
+func TestChangeRequestService_ExpireStale(t *testing.T) {
+	ctx := context.Background()
+	m, svc := setupLifecycle()
+
+	n, err := svc.ExpireStale(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "expiry is off until configured")
+
+	svc.SetExpiry(30 * 24 * time.Hour)
+	stale := *pendingPersonCreate(uuid.New())
+	m.cr.On("ExpireStale", ctx, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) > 29*24*time.Hour
+	})).Return([]domain.ChangeRequest{stale}, nil).Once()
+	m.comments.On("ListByRequest", ctx, stale.ID).Return([]domain.ChangeRequestComment{}, nil)
+	m.notif.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == stale.RequestedBy && n.Type == domain.NotifChangeExpired
+	})).Return(nil).Once()
+
+	n, err = svc.ExpireStale(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	m.notif.AssertExpectations(t)
+}
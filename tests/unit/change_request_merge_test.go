@@ -0,0 +1,308 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/event"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePersonInput_MarshalsPatch(t *testing.T) {
+	var input domain.UpdatePersonInput
+	require.NoError(t, json.Unmarshal([]byte(`{"occupation":"Guru","nickname":null}`), &input))
+
+	data, err := json.Marshal(input)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"occupation":"Guru","nickname":null}`, string(data))
+}
+
+func TestPatchFields(t *testing.T) {
+	base, _ := json.Marshal(domain.Person{FirstName: "Ahmad", Occupation: stringPtr("Petani"), Nickname: stringPtr("Mad")})
+
+	patch, err := domain.PatchFields(base, []byte(`{"first_name":"Ahmad","occupation":"Guru","nickname":null}`), &domain.Person{})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"occupation": "Guru", "nickname": nil}, patch)
+}
+
+func TestPatchFields_UnchangedDates(t *testing.T) {
+	birth, err := domain.ParseGenDate("ABT 1920")
+	require.NoError(t, err)
+
+	t.Run("Person date sent as text", func(t *testing.T) {
+		base, _ := json.Marshal(domain.Person{FirstName: "Ahmad", BirthDate: birth})
+
+		patch, err := domain.PatchFields(base, []byte(`{"birth_date":"ABT 1920","occupation":"Guru"}`), &domain.Person{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"occupation": "Guru"}, patch)
+	})
+
+	t.Run("Spouse metadata date", func(t *testing.T) {
+		base, _ := json.Marshal(domain.Relationship{Type: domain.RelTypeSpouse, Metadata: json.RawMessage(`{"marriage_date":"ABT 1950"}`)})
+		current := &domain.Relationship{Type: domain.RelTypeSpouse, Metadata: json.RawMessage(`{"marriage_date":"ABT 1950","marriage_place":"Bukittinggi"}`)}
+
+		patch, err := domain.PatchFields(base, []byte(`{"metadata":{"marriage_date":"abt 1950","marriage_place":"Padang"}}`), current)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"metadata.marriage_place": "Padang"}, patch)
+
+		fields, err := domain.ThreeWayMerge(base, current, patch)
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.True(t, fields[0].Conflict)
+	})
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	base, _ := json.Marshal(domain.Person{FirstName: "Ahmad", Occupation: stringPtr("Petani"), Religion: stringPtr("Islam")})
+	current := &domain.Person{FirstName: "Ahmad", Occupation: stringPtr("Pedagang"), Religion: stringPtr("Islam"), Education: stringPtr("SMA")}
+	patch := map[string]any{"occupation": "Guru", "religion": "Kristen"}
+
+	fields, err := domain.ThreeWayMerge(base, current, patch)
+
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "occupation", fields[0].Field)
+	assert.True(t, fields[0].Conflict)
+	assert.Equal(t, "Pedagang", fields[0].Current)
+	assert.Equal(t, "religion", fields[1].Field)
+	assert.False(t, fields[1].Conflict)
+}
+
+func TestChangeRequestService_ApproveStale(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	filedAt := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	base := domain.Person{ID: uuid.New(), FirstName: "Ahmad", Occupation: stringPtr("Petani"), IsAlive: false, PrivacyLevel: domain.PrivacyFamily, UpdatedAt: filedAt}
+	snapshot, _ := json.Marshal(base)
+
+	setup := func(payload string) (*mocks.ChangeRequestRepository, *mocks.PersonRepository, changerequest.Service, *domain.ChangeRequest) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockPersonRepo := new(mocks.PersonRepository)
+		mockAuditRepo := new(mocks.AuditLogRepository)
+		mockNotifSvc := new(mocks.NotificationService)
+		personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+		svc := changerequest.NewService(
+			mockCRRepo, nil, mockUserRepo, mockPersonRepo, nil, nil, mockAuditRepo,
+			personSvc, nil, nil,
+		)
+		svc.SetNotificationService(mockNotifSvc)
+
+		cr := &domain.ChangeRequest{
+			ID:            uuid.New(),
+			RequestedBy:   uuid.New(),
+			Status:        domain.StatusPending,
+			EntityType:    domain.EntityPerson,
+			EntityID:      &base.ID,
+			Action:        domain.ActionUpdate,
+			Payload:       []byte(payload),
+			BaseUpdatedAt: &filedAt,
+			BaseSnapshot:  snapshot,
+		}
+		// Someone else set the occupation and the education since.
+		current := base
+		current.Occupation = stringPtr("Pedagang")
+		current.Education = stringPtr("SMA")
+		current.UpdatedAt = filedAt.Add(time.Hour)
+
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+		mockPersonRepo.On("GetByID", ctx, base.ID).Return(&current, nil)
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Maybe()
+		mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil).Maybe()
+		mockNotifSvc.On("NotifyChangeApproved", mock.Anything, cr.ID, reviewer.ID).Return(nil).Maybe()
+		return mockCRRepo, mockPersonRepo, svc, cr
+	}
+
+	t.Run("Non-conflicting fields merge", func(t *testing.T) {
+		_, mockPersonRepo, svc, cr := setup(`{"religion":"Islam"}`)
+		mockPersonRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Person) bool {
+			return *p.Religion == "Islam" && *p.Occupation == "Pedagang" && *p.Education == "SMA"
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		mockPersonRepo.AssertExpectations(t)
+	})
+
+	t.Run("Conflict needs resolution", func(t *testing.T) {
+		_, mockPersonRepo, svc, cr := setup(`{"occupation":"Guru"}`)
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		var conflict *domain.ChangeConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []string{"occupation"}, conflict.Fields)
+		mockPersonRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resolved conflict", func(t *testing.T) {
+		_, mockPersonRepo, svc, cr := setup(`{"occupation":"Guru"}`)
+		mockPersonRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Person) bool {
+			return *p.Occupation == "Guru" && *p.Education == "SMA"
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, map[string]json.RawMessage{"occupation": json.RawMessage(`"Guru"`)}, nil)
+
+		require.NoError(t, err)
+		mockPersonRepo.AssertExpectations(t)
+	})
+
+	t.Run("Resolution clears a field", func(t *testing.T) {
+		_, mockPersonRepo, svc, cr := setup(`{"occupation":"Guru"}`)
+		mockPersonRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Person) bool {
+			return p.Occupation == nil && *p.Education == "SMA"
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, map[string]json.RawMessage{"occupation": json.RawMessage(`null`)}, nil)
+
+		require.NoError(t, err)
+		mockPersonRepo.AssertExpectations(t)
+	})
+
+	t.Run("Required field cannot be cleared", func(t *testing.T) {
+		_, mockPersonRepo, svc, cr := setup(`{"is_alive":null}`)
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		mockPersonRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Merge view", func(t *testing.T) {
+		_, _, svc, cr := setup(`{"occupation":"Guru"}`)
+
+		m, err := svc.Merge(ctx, cr.ID, domain.Viewer{Role: domain.RoleEditor})
+
+		require.NoError(t, err)
+		assert.True(t, m.Stale)
+		assert.Equal(t, []string{"occupation"}, m.Conflicts)
+		require.Len(t, m.Fields, 1)
+		assert.Equal(t, "Petani", m.Fields[0].Base)
+		assert.Equal(t, "Pedagang", m.Fields[0].Current)
+		assert.Equal(t, "Guru", m.Fields[0].Proposed)
+	})
+}
+
+func TestChangeRequestService_ApproveStaleEvent(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	filedAt := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	personID := uuid.New()
+
+	base := domain.Event{
+		ID:        uuid.New(),
+		PersonID:  &personID,
+		Type:      domain.EventType("GRADUATION"),
+		Title:     "Wisuda",
+		Metadata:  json.RawMessage(`{"institution":"UGM"}`),
+		UpdatedAt: filedAt,
+	}
+	snapshot, _ := json.Marshal(base)
+
+	setup := func(payload string) (*mocks.ChangeRequestRepository, *mocks.EventRepository, changerequest.Service, *domain.ChangeRequest) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockEventRepo := new(mocks.EventRepository)
+		mockAuditRepo := new(mocks.AuditLogRepository)
+		mockNotifSvc := new(mocks.NotificationService)
+		svc := changerequest.NewService(
+			mockCRRepo, nil, mockUserRepo, nil, nil, nil, mockAuditRepo,
+			nil, nil, nil,
+		)
+		svc.SetEventService(event.NewService(mockEventRepo, new(mocks.PersonRepository), new(mocks.RelationshipRepository), new(mocks.PlaceRepository), mockAuditRepo))
+		svc.SetNotificationService(mockNotifSvc)
+
+		cr := &domain.ChangeRequest{
+			ID:            uuid.New(),
+			RequestedBy:   uuid.New(),
+			Status:        domain.StatusPending,
+			EntityType:    domain.EntityEvent,
+			EntityID:      &base.ID,
+			Action:        domain.ActionUpdate,
+			Payload:       []byte(payload),
+			BaseUpdatedAt: &filedAt,
+			BaseSnapshot:  snapshot,
+		}
+		// Someone else renamed the event and described it since.
+		current := base
+		current.Title = "Wisuda Sarjana"
+		current.Description = stringPtr("Cum laude")
+		current.UpdatedAt = filedAt.Add(time.Hour)
+
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+		mockEventRepo.On("GetByID", ctx, base.ID).Return(&current, nil)
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Maybe()
+		mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil).Maybe()
+		mockNotifSvc.On("NotifyChangeApproved", mock.Anything, cr.ID, reviewer.ID).Return(nil).Maybe()
+		mockNotifSvc.On("NotifyChangeRequest", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+		return mockCRRepo, mockEventRepo, svc, cr
+	}
+
+	t.Run("Filing records the base", func(t *testing.T) {
+		mockCRRepo, _, svc, _ := setup(`{}`)
+		mockCRRepo.On("Create", ctx, mock.MatchedBy(func(cr *domain.ChangeRequest) bool {
+			return len(cr.BaseSnapshot) > 0 && cr.BaseUpdatedAt != nil && cr.BaseUpdatedAt.Equal(filedAt.Add(time.Hour))
+		})).Return(nil).Once()
+
+		_, err := svc.Create(ctx, uuid.New(), domain.CreateChangeRequestInput{
+			EntityType: domain.EntityEvent,
+			EntityID:   &base.ID,
+			Action:     domain.ActionUpdate,
+			Payload:    json.RawMessage(`{"description":"Lulus tepat waktu"}`),
+		})
+
+		require.NoError(t, err)
+		mockCRRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("Non-conflicting fields merge", func(t *testing.T) {
+		_, mockEventRepo, svc, cr := setup(`{"metadata":{"institution":"Universitas Gadjah Mada"}}`)
+		mockEventRepo.On("Update", ctx, mock.MatchedBy(func(e *domain.Event) bool {
+			return e.Title == "Wisuda Sarjana" && *e.Description == "Cum laude" &&
+				string(e.Metadata) == `{"institution":"Universitas Gadjah Mada"}`
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Conflict needs resolution", func(t *testing.T) {
+		_, mockEventRepo, svc, cr := setup(`{"title":"Wisuda S1"}`)
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		var conflict *domain.ChangeConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []string{"title"}, conflict.Fields)
+		mockEventRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Merge view", func(t *testing.T) {
+		_, _, svc, cr := setup(`{"title":"Wisuda S1"}`)
+
+		m, err := svc.Merge(ctx, cr.ID, domain.Viewer{Role: domain.RoleEditor})
+
+		require.NoError(t, err)
+		assert.True(t, m.Stale)
+		assert.Equal(t, []string{"title"}, m.Conflicts)
+	})
+}
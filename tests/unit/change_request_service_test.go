@@ -8,6 +8,7 @@ import (
 
 	"silsilah-keluarga/internal/domain"
 	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/person"
 	"silsilah-keluarga/tests/mocks"
 
 	"github.com/google/uuid"
@@ -96,9 +97,11 @@ func TestChangeRequestService_Approve(t *testing.T) {
 	mockPersonRepo := new(mocks.PersonRepository)
 	mockNotifSvc := new(mocks.NotificationService)
 
+	personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+
 	svc := changerequest.NewService(
 		mockCRRepo, nil, mockUserRepo, mockPersonRepo, nil, nil, mockAuditRepo,
-		nil, nil, nil,
+		personSvc, nil, nil,
 	)
 	svc.SetNotificationService(mockNotifSvc)
 
@@ -128,8 +131,11 @@ func TestChangeRequestService_Approve(t *testing.T) {
 		// 2. Validate Reviewer
 		mockUserRepo.On("GetByID", ctx, reviewerID).Return(reviewer, nil).Once()
 
-		// 3. Execute Change (Mock PersonRepo Create)
+		// 3. Execute Change through the person service
 		mockPersonRepo.On("Create", ctx, mock.AnythingOfType("*domain.Person")).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(log *domain.AuditLog) bool {
+			return log.Action == "CREATE" && log.UserID == requesterID
+		})).Return(nil).Once()
 
 		// 4. Notify Person Added (triggered by executePersonChange)
 		// Similar to above, match any context, use Maybe because it's async
@@ -147,7 +153,7 @@ func TestChangeRequestService_Approve(t *testing.T) {
 			return log.Action == "APPROVE_CHANGE_REQUEST" && log.UserID == reviewerID
 		})).Return(nil).Once()
 
-		err := svc.Approve(ctx, crID, reviewerID, nil, nil)
+		_, err := svc.Approve(ctx, crID, reviewerID, nil, nil, nil)
 
 		// Give a tiny bit of time for async calls to potentially happen before we assert (optional, but helps with Maybe calls if we wanted to verify they happened)
 		time.Sleep(10 * time.Millisecond)
@@ -162,7 +168,7 @@ func TestChangeRequestService_Approve(t *testing.T) {
 		mockCRRepo.On("GetByID", ctx, crID).Return(cr, nil).Once()
 
 		// Reviewer == Requester
-		err := svc.Approve(ctx, crID, requesterID, nil, nil)
+		_, err := svc.Approve(ctx, crID, requesterID, nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot review own change request")
@@ -0,0 +1,162 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/internal/service/relationship"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangeRequestService_CreateValidates(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	setup := func() (*mocks.ChangeRequestRepository, *mocks.PersonRepository, *mocks.RelationshipRepository, changerequest.Service) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockPersonRepo := new(mocks.PersonRepository)
+		mockRelRepo := new(mocks.RelationshipRepository)
+		mockAuditRepo := new(mocks.AuditLogRepository)
+		personSvc := person.NewService(mockPersonRepo, mockRelRepo, new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+		relSvc := relationship.NewService(mockRelRepo, mockPersonRepo, mockAuditRepo, nil)
+		svc := changerequest.NewService(
+			mockCRRepo, nil, nil, mockPersonRepo, mockRelRepo, nil, mockAuditRepo,
+			personSvc, relSvc, nil,
+		)
+		return mockCRRepo, mockPersonRepo, mockRelRepo, svc
+	}
+
+	t.Run("Person with impossible life dates", func(t *testing.T) {
+		mockCRRepo, _, _, svc := setup()
+		payload, _ := json.Marshal(domain.CreatePersonInput{
+			FirstName: "Siti",
+			Gender:    domain.GenderFemale,
+			BirthDate: domain.NewExactDate(time.Date(1950, 3, 1, 0, 0, 0, 0, time.UTC)),
+			DeathDate: domain.NewExactDate(time.Date(1940, 3, 1, 0, 0, 0, 0, time.UTC)),
+		})
+
+		cr, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityPerson,
+			Action:     domain.ActionCreate,
+			Payload:    payload,
+		})
+
+		assert.Nil(t, cr)
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, domain.ErrInvalidLifeDates)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Person update clearing the first name", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, _, svc := setup()
+		p := &domain.Person{ID: uuid.New(), FirstName: "Siti"}
+		mockPersonRepo.On("GetByID", ctx, p.ID).Return(p, nil)
+
+		cr, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityPerson,
+			EntityID:   &p.ID,
+			Action:     domain.ActionUpdate,
+			Payload:    json.RawMessage(`{"first_name":null}`),
+		})
+
+		assert.Nil(t, cr)
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Relationship with self", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, _, svc := setup()
+		p := &domain.Person{ID: uuid.New(), FirstName: "Budi"}
+		mockPersonRepo.On("GetByID", ctx, p.ID).Return(p, nil)
+		payload, _ := json.Marshal(domain.CreateRelationshipInput{PersonA: p.ID, PersonB: p.ID, Type: domain.RelTypeSpouse})
+
+		cr, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityRelationship,
+			Action:     domain.ActionCreate,
+			Payload:    payload,
+		})
+
+		assert.Nil(t, cr)
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, relationship.ErrSelfRelation)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Relationship that already exists", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, mockRelRepo, svc := setup()
+		husband := &domain.Person{ID: uuid.New(), FirstName: "Budi"}
+		wife := &domain.Person{ID: uuid.New(), FirstName: "Sari"}
+		mockPersonRepo.On("GetByID", ctx, husband.ID).Return(husband, nil)
+		mockPersonRepo.On("GetByID", ctx, wife.ID).Return(wife, nil)
+		mockRelRepo.On("FindPair", ctx, wife.ID, husband.ID, domain.RelTypeSpouse).Return(&domain.Relationship{
+			ID: uuid.New(), PersonA: husband.ID, PersonB: wife.ID, Type: domain.RelTypeSpouse,
+		}, nil)
+		payload, _ := json.Marshal(domain.CreateRelationshipInput{PersonA: wife.ID, PersonB: husband.ID, Type: domain.RelTypeSpouse})
+
+		cr, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityRelationship,
+			Action:     domain.ActionCreate,
+			Payload:    payload,
+		})
+
+		assert.Nil(t, cr)
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, relationship.ErrDuplicateRelationship)
+		mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestChangeRequestService_ApproveValidates(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	parent := &domain.Person{ID: uuid.New(), FirstName: "Ayah"}
+	child := &domain.Person{ID: uuid.New(), FirstName: "Anak"}
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	relSvc := relationship.NewService(mockRelRepo, mockPersonRepo, mockAuditRepo, nil)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, mockUserRepo, mockPersonRepo, mockRelRepo, nil, mockAuditRepo,
+		nil, relSvc, nil,
+	)
+
+	// The request makes child a parent of parent, who was recorded as
+	// child's parent after it was filed.
+	payload, _ := json.Marshal(domain.CreateRelationshipInput{PersonA: parent.ID, PersonB: child.ID, Type: domain.RelTypeParent})
+	cr := &domain.ChangeRequest{
+		ID:          uuid.New(),
+		RequestedBy: uuid.New(),
+		Status:      domain.StatusPending,
+		EntityType:  domain.EntityRelationship,
+		Action:      domain.ActionCreate,
+		Payload:     payload,
+	}
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil)
+	mockPersonRepo.On("GetByID", ctx, parent.ID).Return(parent, nil)
+	mockPersonRepo.On("GetByID", ctx, child.ID).Return(child, nil)
+	mockRelRepo.On("ListByPeople", ctx, []uuid.UUID{parent.ID}).Return([]domain.Relationship{
+		{PersonA: child.ID, PersonB: parent.ID, Type: domain.RelTypeParent},
+	}, nil)
+	mockRelRepo.On("ListByPeople", ctx, []uuid.UUID{child.ID}).Return([]domain.Relationship{}, nil)
+	mockPersonRepo.On("GetByIDs", ctx, mock.Anything).Return([]domain.Person{*child}, nil)
+
+	_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+	assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+	assert.ErrorIs(t, err, relationship.ErrRelationshipCycle)
+	mockRelRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,367 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/event"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/internal/service/relationship"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeset_Validate(t *testing.T) {
+	existing := uuid.New()
+	person := func(ref string) domain.ChangeOperation {
+		return domain.ChangeOperation{Ref: ref, EntityType: domain.EntityPerson, Action: domain.ActionCreate, Payload: json.RawMessage(`{"first_name":"Rahmat"}`)}
+	}
+	parentOf := func(child string) domain.ChangeOperation {
+		return domain.ChangeOperation{
+			EntityType: domain.EntityRelationship,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"person_a":"` + child + `","person_b":"` + existing.String() + `","type":"PARENT"}`),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ops     []domain.ChangeOperation
+		wantErr bool
+	}{
+		{"Ref used after create", []domain.ChangeOperation{person("T1"), parentOf("$T1")}, false},
+		{"Ref used before create", []domain.ChangeOperation{parentOf("$T1"), person("T1")}, true},
+		{"Ref declared twice", []domain.ChangeOperation{person("T1"), person("T1")}, true},
+		{"Ref on a delete", []domain.ChangeOperation{{Ref: "T1", EntityType: domain.EntityPerson, EntityID: &existing, Action: domain.ActionDelete}}, true},
+		{"No operations", nil, true},
+		{"Unknown token is text", []domain.ChangeOperation{parentOf("$T9")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.Changeset{Operations: tt.ops}.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidChangeset)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChangeRequestService_ApproveChangeset(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	wife := &domain.Person{ID: uuid.New(), FirstName: "Siti"}
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockEventRepo := new(mocks.EventRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	mockTx := new(mocks.Transactor)
+	personSvc := person.NewService(mockPersonRepo, mockRelRepo, new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	relSvc := relationship.NewService(mockRelRepo, mockPersonRepo, mockAuditRepo, nil)
+	mockRelRepo.On("FindPair", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	svc := changerequest.NewService(
+		mockCRRepo, nil, mockUserRepo, mockPersonRepo, mockRelRepo, nil, mockAuditRepo,
+		personSvc, relSvc, nil,
+	)
+	svc.SetEventService(event.NewService(mockEventRepo, mockPersonRepo, mockRelRepo, new(mocks.PlaceRepository), mockAuditRepo))
+	svc.SetTransactor(mockTx, nil)
+	mockNotifSvc := new(mocks.NotificationService)
+	mockNotifSvc.On("NotifyChangeApproved", mock.Anything, mock.Anything, reviewer.ID).Return(nil).Maybe()
+	svc.SetNotificationService(mockNotifSvc)
+
+	payload, _ := json.Marshal(domain.Changeset{Operations: []domain.ChangeOperation{
+		{Ref: "T1", EntityType: domain.EntityPerson, Action: domain.ActionCreate, Payload: json.RawMessage(`{"first_name":"Ahmad","gender":"MALE"}`)},
+		{Ref: "T2", EntityType: domain.EntityRelationship, Action: domain.ActionCreate, Payload: json.RawMessage(`{"person_a":"$T1","person_b":"` + wife.ID.String() + `","type":"SPOUSE"}`)},
+		{EntityType: domain.EntityEvent, Action: domain.ActionCreate, Payload: json.RawMessage(`{"relationship_id":"$T2","type":"MARRIAGE","title":"Akad"}`)},
+	}})
+	cr := &domain.ChangeRequest{
+		ID:          uuid.New(),
+		RequestedBy: uuid.New(),
+		Status:      domain.StatusPending,
+		EntityType:  domain.EntityChangeset,
+		Action:      domain.ActionCreate,
+		Payload:     payload,
+	}
+
+	var created domain.Person
+	var spouse domain.Relationship
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	mockTx.On("WithinTx", ctx).Return(nil).Once()
+	mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Once()
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+	mockPersonRepo.On("Create", ctx, mock.AnythingOfType("*domain.Person")).Run(func(args mock.Arguments) {
+		created = *args.Get(1).(*domain.Person)
+		mockPersonRepo.On("GetByID", ctx, created.ID).Return(&created, nil)
+	}).Return(nil).Once()
+	mockPersonRepo.On("GetByID", ctx, wife.ID).Return(wife, nil)
+	mockRelRepo.On("ListByPerson", ctx, mock.Anything).Return([]domain.Relationship{}, nil)
+	mockRelRepo.On("Create", ctx, mock.AnythingOfType("*domain.Relationship")).Run(func(args mock.Arguments) {
+		spouse = *args.Get(1).(*domain.Relationship)
+		mockRelRepo.On("GetByID", ctx, spouse.ID).Return(&spouse, nil)
+	}).Return(nil).Once()
+	mockEventRepo.On("Create", ctx, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.RelationshipID != nil && *e.RelationshipID == spouse.ID
+	})).Return(nil).Once()
+
+	_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, spouse.PersonA)
+	assert.Equal(t, wife.ID, spouse.PersonB)
+	mockTx.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+}
+
+func TestChangeRequestService_DiffChangeset(t *testing.T) {
+	ctx := context.Background()
+	father := &domain.Person{ID: uuid.New(), FirstName: "Ahmad", PrivacyLevel: domain.PrivacyPublic}
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, nil, mockPersonRepo, new(mocks.RelationshipRepository), nil, nil,
+		nil, nil, nil,
+	)
+
+	payload, _ := json.Marshal(domain.Changeset{Operations: []domain.ChangeOperation{
+		{Ref: "T1", EntityType: domain.EntityPerson, Action: domain.ActionCreate, Payload: json.RawMessage(`{"first_name":"Rahmat","privacy_level":"PUBLIC"}`)},
+		{EntityType: domain.EntityRelationship, Action: domain.ActionCreate, Payload: json.RawMessage(`{"person_a":"$T1","person_b":"` + father.ID.String() + `","type":"PARENT"}`)},
+	}})
+	cr := &domain.ChangeRequest{ID: uuid.New(), Status: domain.StatusPending, EntityType: domain.EntityChangeset, Action: domain.ActionCreate, Payload: payload}
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	mockPersonRepo.On("GetByID", mock.Anything, father.ID).Return(father, nil)
+
+	d, err := svc.Diff(ctx, cr.ID, domain.Viewer{Role: domain.RoleEditor})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Create changeset of 2 operations", d.Summary)
+	require.Len(t, d.Operations, 2)
+	assert.Equal(t, "Create relationship Rahmat – Ahmad (PARENT)", d.Operations[1].Summary)
+	personA := findChange(d.Operations[1].Changes, "person_a")
+	require.NotNil(t, personA)
+	assert.Equal(t, "$T1", personA.After)
+	assert.Equal(t, "Rahmat", personA.AfterLabel)
+}
+
+func TestChangeRequestService_CreateChangeset(t *testing.T) {
+	ctx := context.Background()
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), new(mocks.AuditLogRepository), nil)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, nil, mockPersonRepo, nil, nil, nil,
+		personSvc, nil, nil,
+	)
+
+	file := func(ops ...domain.ChangeOperation) error {
+		payload, _ := json.Marshal(domain.Changeset{Operations: ops})
+		_, err := svc.Create(ctx, uuid.New(), domain.CreateChangeRequestInput{
+			EntityType: domain.EntityChangeset,
+			Action:     domain.ActionCreate,
+			Payload:    payload,
+		})
+		return err
+	}
+
+	t.Run("Invalid operation", func(t *testing.T) {
+		err := file(domain.ChangeOperation{
+			EntityType: domain.EntityPerson,
+			Action:     domain.ActionCreate,
+			Payload:    json.RawMessage(`{"first_name":"Rahmat","birth_date":"1990-01-01","death_date":"1980-01-01"}`),
+		})
+
+		assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+		assert.ErrorIs(t, err, domain.ErrInvalidLifeDates)
+	})
+
+	t.Run("Media is not allowed", func(t *testing.T) {
+		id := uuid.New()
+		err := file(domain.ChangeOperation{EntityType: domain.EntityMedia, EntityID: &id, Action: domain.ActionDelete, Payload: json.RawMessage(`{}`)})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidChangeset)
+	})
+
+	mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// markingTx runs fn in a ctx carrying a marker, so a test can check which
+// writes happened inside the transaction.
+type markingTx struct{}
+
+type txMarker struct{}
+
+func (markingTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txMarker{}, true))
+}
+
+func TestChangeRequestService_ApproveChangeset_EventForNewPerson(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	inTx := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(txMarker{}) != nil })
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockEventRepo := new(mocks.EventRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	personSvc := person.NewService(mockPersonRepo, mockRelRepo, new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, mockUserRepo, mockPersonRepo, mockRelRepo, nil, mockAuditRepo,
+		personSvc, nil, nil,
+	)
+	svc.SetEventService(event.NewService(mockEventRepo, mockPersonRepo, mockRelRepo, new(mocks.PlaceRepository), mockAuditRepo))
+	svc.SetTransactor(markingTx{}, nil)
+	mockNotifSvc := new(mocks.NotificationService)
+	mockNotifSvc.On("NotifyChangeApproved", mock.Anything, mock.Anything, reviewer.ID).Return(nil).Maybe()
+	svc.SetNotificationService(mockNotifSvc)
+
+	payload, _ := json.Marshal(domain.Changeset{Operations: []domain.ChangeOperation{
+		{Ref: "T1", EntityType: domain.EntityPerson, Action: domain.ActionCreate, Payload: json.RawMessage(`{"first_name":"Ahmad","gender":"MALE"}`)},
+		{EntityType: domain.EntityEvent, Action: domain.ActionCreate, Payload: json.RawMessage(`{"person_id":"$T1","type":"GRADUATION","title":"Wisuda S1","metadata":{"institution":"Universitas Indonesia"}}`)},
+	}})
+	cr := &domain.ChangeRequest{
+		ID:          uuid.New(),
+		RequestedBy: uuid.New(),
+		Status:      domain.StatusPending,
+		EntityType:  domain.EntityChangeset,
+		Action:      domain.ActionCreate,
+		Payload:     payload,
+	}
+
+	var created domain.Person
+	mockCRRepo.On("GetByID", mock.Anything, cr.ID).Return(cr, nil)
+	mockUserRepo.On("GetByID", mock.Anything, reviewer.ID).Return(reviewer, nil)
+	mockCRRepo.On("UpdateStatus", inTx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Once()
+	mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockPersonRepo.On("Create", inTx, mock.AnythingOfType("*domain.Person")).Run(func(args mock.Arguments) {
+		created = *args.Get(1).(*domain.Person)
+		mockPersonRepo.On("GetByID", mock.Anything, created.ID).Return(&created, nil)
+	}).Return(nil).Once()
+	mockEventRepo.On("Create", inTx, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.PersonID != nil && *e.PersonID == created.ID
+	})).Return(nil).Once()
+
+	_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+	require.NoError(t, err)
+	mockPersonRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+}
+
+func TestChangeRequestService_ChangesetPolicy(t *testing.T) {
+	ctx := context.Background()
+	personID := uuid.New()
+	linked := &domain.User{ID: uuid.New(), Role: string(domain.RoleMember)}
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockApprovalRepo := new(mocks.ApprovalRepository)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, mockUserRepo, nil, nil, nil, nil,
+		nil, nil, nil,
+	)
+	svc.SetApprovalRepository(mockApprovalRepo)
+
+	payload, _ := json.Marshal(domain.Changeset{Operations: []domain.ChangeOperation{
+		{EntityType: domain.EntityPerson, Action: domain.ActionCreate, Payload: json.RawMessage(`{"first_name":"Rahmat"}`)},
+		{EntityType: domain.EntityPerson, EntityID: &personID, Action: domain.ActionDelete, Payload: json.RawMessage(`{}`)},
+	}})
+	cr := &domain.ChangeRequest{ID: uuid.New(), RequestedBy: uuid.New(), Status: domain.StatusPending, EntityType: domain.EntityChangeset, Action: domain.ActionCreate, Payload: payload}
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	mockApprovalRepo.On("GetPolicy", ctx, domain.EntityChangeset, domain.ActionCreate).Return(nil, nil)
+	mockApprovalRepo.On("GetPolicy", ctx, domain.EntityPerson, domain.ActionCreate).Return(nil, nil)
+	mockApprovalRepo.On("GetPolicy", ctx, domain.EntityPerson, domain.ActionDelete).Return(&domain.ApprovalPolicy{
+		EntityType: domain.EntityPerson, Action: domain.ActionDelete, RequiredApprovals: 2, RequireConsent: true,
+	}, nil)
+	mockApprovalRepo.On("ListApprovals", ctx, cr.ID).Return([]domain.ChangeRequestApproval{}, nil)
+	mockUserRepo.On("GetByLinkedPerson", ctx, personID).Return(linked, nil)
+
+	p, err := svc.Approvals(ctx, cr.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Policy.RequiredApprovals, "the person delete sets the bar")
+	assert.True(t, p.Policy.RequireConsent)
+	assert.Equal(t, []uuid.UUID{linked.ID}, p.ConsentUserIDs)
+}
+
+func TestChangeRequestService_ChangesetStaleUpdate(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+	filedAt := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	base := domain.Person{ID: uuid.New(), FirstName: "Ahmad", Occupation: stringPtr("Petani"), PrivacyLevel: domain.PrivacyFamily, UpdatedAt: filedAt}
+	current := base
+
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	svc := changerequest.NewService(
+		mockCRRepo, nil, mockUserRepo, mockPersonRepo, nil, nil, mockAuditRepo,
+		personSvc, nil, nil,
+	)
+	mockNotifSvc := new(mocks.NotificationService)
+	mockNotifSvc.On("NotifyChangeRequest", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockNotifSvc.On("NotifyChangeApproved", mock.Anything, mock.Anything, reviewer.ID).Return(nil).Maybe()
+	svc.SetNotificationService(mockNotifSvc)
+	mockUserRepo.On("GetByRoles", mock.Anything, mock.Anything).Return([]domain.User{}, nil).Maybe()
+	mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+	mockPersonRepo.On("GetByID", mock.Anything, base.ID).Return(&current, nil)
+	mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	payload, _ := json.Marshal(domain.Changeset{Operations: []domain.ChangeOperation{
+		{EntityType: domain.EntityPerson, EntityID: &base.ID, Action: domain.ActionUpdate, Payload: json.RawMessage(`{"occupation":"Guru"}`)},
+	}})
+	var cr *domain.ChangeRequest
+	mockCRRepo.On("Create", ctx, mock.AnythingOfType("*domain.ChangeRequest")).Run(func(args mock.Arguments) {
+		cr = args.Get(1).(*domain.ChangeRequest)
+	}).Return(nil).Once()
+
+	_, err := svc.Create(ctx, uuid.New(), domain.CreateChangeRequestInput{
+		EntityType: domain.EntityChangeset,
+		Action:     domain.ActionCreate,
+		Payload:    payload,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, cr.BaseSnapshot, "the update's base is recorded when filed")
+	mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+	mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil)
+
+	// Someone else changed the occupation since.
+	current.Occupation = stringPtr("Pedagang")
+	current.UpdatedAt = filedAt.Add(time.Hour)
+
+	t.Run("Conflict needs resolution", func(t *testing.T) {
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		var conflict *domain.ChangeConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, []string{"1.occupation"}, conflict.Fields)
+		mockPersonRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resolved conflict", func(t *testing.T) {
+		mockPersonRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Person) bool {
+			return *p.Occupation == "Guru"
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, map[string]json.RawMessage{"1.occupation": json.RawMessage(`"Guru"`)}, nil)
+
+		require.NoError(t, err)
+		mockPersonRepo.AssertExpectations(t)
+	})
+}
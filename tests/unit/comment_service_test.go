@@ -101,7 +101,7 @@ func TestCommentService_Delete(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo.On("GetByID", ctx, commentID).Return(existingComment, nil).Once()
-		mockRepo.On("Delete", ctx, commentID).Return(nil).Once()
+		mockRepo.On("Delete", ctx, commentID, userID).Return(nil).Once()
 
 		err := svc.Delete(ctx, userID, commentID)
 
@@ -0,0 +1,184 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/customfield"
+	"silsilah-keluarga/internal/service/export"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomField_Validate(t *testing.T) {
+	maxLen := 3
+	tests := []struct {
+		name  string
+		field domain.CustomField
+		valid bool
+	}{
+		{"Text field", domain.CustomField{Key: "marga", Label: "Marga", Type: domain.CustomFieldText, Visibility: domain.RoleMember}, true},
+		{"Invalid key", domain.CustomField{Key: "Marga Batak", Label: "Marga", Type: domain.CustomFieldText, Visibility: domain.RoleMember}, false},
+		{"Enum without options", domain.CustomField{Key: "blood_type", Label: "Golongan darah", Type: domain.CustomFieldEnum, Visibility: domain.RoleMember}, false},
+		{"Duplicate options", domain.CustomField{Key: "blood_type", Label: "Golongan darah", Type: domain.CustomFieldEnum, Options: []string{"A", "a"}, Visibility: domain.RoleMember}, false},
+		{"Length rule on number", domain.CustomField{Key: "height", Label: "Tinggi", Type: domain.CustomFieldNumber, Rules: domain.CustomFieldRules{MaxLength: &maxLen}, Visibility: domain.RoleMember}, false},
+		{"Unknown visibility", domain.CustomField{Key: "marga", Label: "Marga", Type: domain.CustomFieldText, Visibility: "public"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidCustomField)
+			}
+		})
+	}
+}
+
+func TestCustomField_ParseValue(t *testing.T) {
+	minHeight, maxHeight := 50.0, 250.0
+	pattern := `^[A-Z]`
+	personID := uuid.New()
+
+	tests := []struct {
+		name    string
+		field   domain.CustomField
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"Text", domain.CustomField{Type: domain.CustomFieldText, Rules: domain.CustomFieldRules{Pattern: &pattern}}, " Siregar ", "Siregar", false},
+		{"Text pattern", domain.CustomField{Type: domain.CustomFieldText, Rules: domain.CustomFieldRules{Pattern: &pattern}}, "siregar", "", true},
+		{"Number canonical", domain.CustomField{Type: domain.CustomFieldNumber, Rules: domain.CustomFieldRules{Min: &minHeight, Max: &maxHeight}}, "170.50", "170.5", false},
+		{"Number out of range", domain.CustomField{Type: domain.CustomFieldNumber, Rules: domain.CustomFieldRules{Min: &minHeight, Max: &maxHeight}}, "300", "", true},
+		{"Approximate date", domain.CustomField{Type: domain.CustomFieldDate}, "abt 1945", "ABT 1945", false},
+		{"Enum ignores case", domain.CustomField{Type: domain.CustomFieldEnum, Options: []string{"A", "B", "AB", "O"}}, "ab", "AB", false},
+		{"Enum unknown option", domain.CustomField{Type: domain.CustomFieldEnum, Options: []string{"A", "B", "AB", "O"}}, "C", "", true},
+		{"Person", domain.CustomField{Type: domain.CustomFieldPerson}, personID.String(), personID.String(), false},
+		{"Person not an ID", domain.CustomField{Type: domain.CustomFieldPerson}, "Pak Harun", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.field.Label = tt.name
+			v, err := tt.field.ParseValue(tt.raw)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidCustomValue)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.Value)
+		})
+	}
+
+	t.Run("Date bounds", func(t *testing.T) {
+		field := domain.CustomField{Type: domain.CustomFieldDate}
+		v, err := field.ParseValue("1945")
+		require.NoError(t, err)
+		require.NotNil(t, v.DateMin)
+		require.NotNil(t, v.DateMax)
+		assert.Equal(t, "1945-01-01", v.DateMin.Format("2006-01-02"))
+		assert.Equal(t, "1945-12-31", v.DateMax.Format("2006-01-02"))
+	})
+}
+
+func TestCustomFieldService_SetPersonValue(t *testing.T) {
+	mockFieldRepo := new(mocks.CustomFieldRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := customfield.NewService(mockFieldRepo, mockPersonRepo, mockAuditRepo)
+	ctx := context.Background()
+
+	person := &domain.Person{ID: uuid.New(), FirstName: "Ahmad"}
+	teacher := &domain.Person{ID: uuid.New(), FirstName: "Kyai", LastName: stringPtr("Hasyim")}
+	field := &domain.CustomField{ID: uuid.New(), Key: "guru_ngaji", Label: "Guru ngaji", Type: domain.CustomFieldPerson, Visibility: domain.RoleMember}
+
+	mockPersonRepo.On("GetByID", ctx, person.ID).Return(person, nil)
+	mockPersonRepo.On("GetByID", ctx, teacher.ID).Return(teacher, nil)
+	mockFieldRepo.On("GetFieldByID", ctx, field.ID).Return(field, nil)
+	mockFieldRepo.On("GetValuesByPerson", ctx, person.ID).Return([]domain.PersonCustomValue{}, nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	t.Run("Person reference", func(t *testing.T) {
+		mockFieldRepo.On("SetValue", ctx, mock.AnythingOfType("*domain.PersonCustomValue")).Return(nil).Once()
+
+		v, err := svc.SetPersonValue(ctx, uuid.New(), person.ID, field.ID, domain.SetCustomValueInput{Value: teacher.ID.String()})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Kyai Hasyim", v.Display)
+		assert.Equal(t, &teacher.ID, v.PersonRef)
+	})
+
+	t.Run("Self reference", func(t *testing.T) {
+		_, err := svc.SetPersonValue(ctx, uuid.New(), person.ID, field.ID, domain.SetCustomValueInput{Value: person.ID.String()})
+		assert.ErrorIs(t, err, domain.ErrCustomValueSelfRef)
+	})
+}
+
+func TestCustomFieldService_GetPersonValuesVisibility(t *testing.T) {
+	mockFieldRepo := new(mocks.CustomFieldRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	svc := customfield.NewService(mockFieldRepo, mockPersonRepo, new(mocks.AuditLogRepository))
+	ctx := context.Background()
+
+	personID := uuid.New()
+	mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil)
+	mockFieldRepo.On("GetValuesByPerson", ctx, personID).Return([]domain.PersonCustomValue{
+		{Key: "marga", Value: "Nasution", Visibility: domain.RoleMember},
+		{Key: "blood_type", Value: "O", Visibility: domain.RoleEditor},
+	}, nil)
+
+	member, err := svc.GetPersonValues(ctx, personID, domain.Viewer{Role: domain.RoleMember})
+	require.NoError(t, err)
+	require.Len(t, member, 1)
+	assert.Equal(t, "marga", member[0].Key)
+
+	editor, err := svc.GetPersonValues(ctx, personID, domain.Viewer{Role: domain.RoleEditor})
+	require.NoError(t, err)
+	assert.Len(t, editor, 2)
+}
+
+func TestExportService_GEDCOMCustomFields(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockNameRepo := new(mocks.PersonNameRepository)
+	mockEventRepo := new(mocks.EventRepository)
+	mockCitationRepo := new(mocks.CitationRepository)
+	mockCustomFieldRepo := new(mocks.CustomFieldRepository)
+	svc := export.NewService(mockPersonRepo, mockRelRepo, mockNameRepo, mockEventRepo, mockCitationRepo, mockCustomFieldRepo, new(mocks.AuditLogRepository), nil)
+	ctx := context.Background()
+
+	child := domain.Person{ID: uuid.New(), FirstName: "Rahmat", Gender: domain.GenderMale, IsAlive: true}
+	father := domain.Person{ID: uuid.New(), FirstName: "Hamzah", Gender: domain.GenderMale, IsAlive: true}
+	teacherID := uuid.New()
+
+	mockPersonRepo.On("GetByID", ctx, child.ID).Return(&child, nil)
+	mockPersonRepo.On("GetAll", ctx).Return([]domain.Person{child, father}, nil)
+	mockNameRepo.On("GetAll", ctx).Return([]domain.PersonName{}, nil)
+	mockRelRepo.On("GetAll", ctx).Return([]domain.Relationship{
+		{ID: uuid.New(), PersonA: child.ID, PersonB: father.ID, Type: domain.RelTypeParent},
+	}, nil)
+	mockEventRepo.On("GetAll", ctx).Return([]domain.Event{}, nil)
+	mockCitationRepo.On("GetAll", ctx).Return([]domain.Citation{}, nil)
+	mockCustomFieldRepo.On("GetAllValues", ctx).Return([]domain.PersonCustomValue{
+		{PersonID: child.ID, Label: "Marga", Type: domain.CustomFieldText, Value: "Lubis", Display: "Lubis", Visibility: domain.RoleMember},
+		{PersonID: child.ID, Label: "Golongan darah", Type: domain.CustomFieldEnum, Value: "B", Display: "B", Visibility: domain.RoleEditor},
+		{PersonID: child.ID, Label: "Wali nikah", Type: domain.CustomFieldPerson, Value: father.ID.String(), Display: "Hamzah", PersonRef: &father.ID, Visibility: domain.RoleMember},
+		{PersonID: child.ID, Label: "Guru ngaji", Type: domain.CustomFieldPerson, Value: teacherID.String(), Display: "Kyai Hasyim", PersonRef: &teacherID, Visibility: domain.RoleMember},
+	}, nil)
+
+	out, err := svc.ExportGEDCOM(ctx, domain.Viewer{UserID: uuid.New(), Role: domain.RoleMember}, child.ID)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "1 FACT Lubis\r\n2 TYPE Marga\r\n")
+	assert.Contains(t, out, "1 ASSO @I2@\r\n2 RELA Wali nikah\r\n")
+	assert.Contains(t, out, "1 FACT Kyai Hasyim\r\n2 TYPE Guru ngaji\r\n")
+	assert.NotContains(t, out, "Golongan darah")
+}
@@ -0,0 +1,168 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/duplicate"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreDuplicate(t *testing.T) {
+	a := &domain.Person{
+		FirstName:  "Siti",
+		LastName:   stringPtr("Aminah"),
+		Gender:     domain.GenderFemale,
+		BirthDate:  mustDate(t, "1920"),
+		BirthPlace: stringPtr("Yogyakarta"),
+	}
+
+	t.Run("Matching dates and place raise the score", func(t *testing.T) {
+		b := &domain.Person{
+			FirstName:  "Siti",
+			LastName:   stringPtr("Aminah"),
+			Gender:     domain.GenderFemale,
+			BirthDate:  mustDate(t, "ABT 1920"),
+			BirthPlace: stringPtr(" yogyakarta"),
+		}
+		score, signals := domain.ScoreDuplicate(a, b, 1, 1)
+
+		assert.InDelta(t, 1.0, score, 1e-9)
+		require.NotNil(t, signals.BirthYearGap)
+		assert.Equal(t, 0, *signals.BirthYearGap)
+		require.NotNil(t, signals.GenderMatch)
+		assert.True(t, *signals.GenderMatch)
+		assert.True(t, signals.SameBirthPlace)
+		assert.Nil(t, signals.DeathYearGap)
+	})
+
+	t.Run("Distant birth years and other gender pull it down", func(t *testing.T) {
+		b := &domain.Person{
+			FirstName: "Siti",
+			Gender:    domain.GenderMale,
+			BirthDate: mustDate(t, "1950"),
+		}
+		score, signals := domain.ScoreDuplicate(a, b, 0.8, 0)
+
+		assert.Equal(t, 0.0, score)
+		assert.Equal(t, 30, *signals.BirthYearGap)
+		assert.False(t, *signals.GenderMatch)
+	})
+
+	t.Run("Unknown gender is not a signal", func(t *testing.T) {
+		b := &domain.Person{FirstName: "Siti", Gender: domain.GenderUnknown}
+		score, signals := domain.ScoreDuplicate(a, b, 0.5, 0)
+
+		assert.InDelta(t, 0.3, score, 1e-9)
+		assert.Nil(t, signals.GenderMatch)
+		assert.Nil(t, signals.BirthYearGap)
+	})
+}
+
+func TestDuplicateService_FindForPerson(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	svc := duplicate.NewService(mockPersonRepo, mockRelRepo)
+	ctx := context.Background()
+
+	subject := &domain.Person{ID: uuid.New(), FirstName: "Ahmad", LastName: stringPtr("Dahlan"), Gender: domain.GenderMale}
+	twin := domain.Person{ID: uuid.New(), FirstName: "Ahmad", LastName: stringPtr("Dahlan"), Gender: domain.GenderMale}
+	son := domain.Person{ID: uuid.New(), FirstName: "Ahmad", LastName: stringPtr("Dahlan"), Gender: domain.GenderMale}
+	parent := uuid.New()
+
+	mockPersonRepo.On("GetByID", ctx, subject.ID).Return(subject, nil)
+	mockPersonRepo.On("FindSimilar", ctx, "Ahmad Dahlan", subject.ID, 10).Return([]domain.SimilarPerson{
+		{Person: son, Similarity: 1},
+		{Person: twin, Similarity: 0.9},
+	}, nil)
+	mockRelRepo.On("ListByPeople", ctx, []uuid.UUID{subject.ID, son.ID, twin.ID}).Return([]domain.Relationship{
+		{PersonA: son.ID, PersonB: subject.ID, Type: domain.RelTypeParent},
+		{PersonA: subject.ID, PersonB: parent, Type: domain.RelTypeParent},
+		{PersonA: twin.ID, PersonB: parent, Type: domain.RelTypeParent},
+	}, nil)
+
+	candidates, err := svc.FindForPerson(ctx, subject.ID, 10)
+
+	require.NoError(t, err)
+	require.Len(t, candidates, 1, "directly related persons are not duplicates")
+	assert.Equal(t, twin.ID, candidates[0].Person.ID)
+	assert.Equal(t, 1, candidates[0].Signals.SharedRelatives)
+	assert.InDelta(t, 0.6*0.9+0.05+0.1, candidates[0].Score, 1e-9)
+}
+
+func TestDuplicateService_Report(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	svc := duplicate.NewService(mockPersonRepo, mockRelRepo)
+	ctx := context.Background()
+
+	a := domain.Person{ID: uuid.New(), FirstName: "Raden", LastName: stringPtr("Mas Said"), BirthDate: mustDate(t, "1900")}
+	b := domain.Person{ID: uuid.New(), FirstName: "Raden", LastName: stringPtr("Mas Sahid"), BirthDate: mustDate(t, "1901")}
+	c := domain.Person{ID: uuid.New(), FirstName: "Raden", LastName: stringPtr("Mas Sait"), BirthDate: mustDate(t, "1960")}
+
+	mockPersonRepo.On("FindSimilarPairs", ctx, 200).Return([]domain.SimilarPair{
+		{PersonA: a.ID, PersonB: b.ID, Similarity: 0.8},
+		{PersonA: a.ID, PersonB: c.ID, Similarity: 0.7},
+	}, nil)
+	mockPersonRepo.On("GetByIDs", ctx, []uuid.UUID{a.ID, b.ID, c.ID}).Return([]domain.Person{a, b, c}, nil)
+	mockRelRepo.On("ListByPeople", ctx, mock.Anything).Return([]domain.Relationship{}, nil)
+
+	pairs, err := svc.Report(ctx, 0, 50)
+
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, a.ID, pairs[0].PersonA.ID)
+	assert.Equal(t, b.ID, pairs[0].PersonB.ID)
+	assert.Equal(t, 1, *pairs[0].Signals.BirthYearGap)
+}
+
+func TestPersonService_CreateWarnsOnDuplicate(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+
+	svc := person.NewService(mockPersonRepo, mockRelRepo, new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	svc.SetDuplicateDetector(duplicate.NewService(mockPersonRepo, mockRelRepo))
+	ctx := context.Background()
+	userID := uuid.New()
+
+	existing := domain.Person{ID: uuid.New(), FirstName: "Kartini", Gender: domain.GenderFemale, BirthDate: mustDate(t, "21 APR 1879")}
+	input := domain.CreatePersonInput{FirstName: "Kartini", Gender: domain.GenderFemale, BirthDate: mustDate(t, "1879")}
+
+	mockPersonRepo.On("FindSimilar", ctx, "Kartini", uuid.Nil, 10).Return([]domain.SimilarPerson{
+		{Person: existing, Similarity: 1},
+	}, nil)
+
+	t.Run("Likely duplicate is held back", func(t *testing.T) {
+		p, err := svc.Create(ctx, userID, input)
+
+		assert.Nil(t, p)
+		assert.True(t, errors.Is(err, domain.ErrPossibleDuplicate))
+		var warning *domain.DuplicateWarningError
+		require.True(t, errors.As(err, &warning))
+		require.Len(t, warning.Candidates, 1)
+		assert.Equal(t, existing.ID, warning.Candidates[0].Person.ID)
+		mockPersonRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Confirmed creation skips the check", func(t *testing.T) {
+		confirmed := input
+		confirmed.IgnoreDuplicates = true
+		mockPersonRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		p, err := svc.Create(ctx, userID, confirmed)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Kartini", p.FirstName)
+		mockPersonRepo.AssertNumberOfCalls(t, "FindSimilar", 1)
+	})
+}
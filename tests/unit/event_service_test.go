@@ -0,0 +1,187 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"silsilah-keluarga/internal/config"
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/event"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_Create(t *testing.T) {
+	mockEventRepo := new(mocks.EventRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+
+	svc := event.NewService(mockEventRepo, mockPersonRepo, mockRelRepo, new(mocks.PlaceRepository), mockAuditRepo)
+	ctx := context.Background()
+	userID := uuid.New()
+	personID := uuid.New()
+
+	t.Run("Success - Graduation With Valid Metadata", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+		mockEventRepo.On("Create", ctx, mock.MatchedBy(func(e *domain.Event) bool {
+			return e.Type == domain.EventTypeGraduation && *e.PersonID == personID
+		})).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(log *domain.AuditLog) bool {
+			return log.EntityType == "EVENT" && log.Action == "CREATE"
+		})).Return(nil).Once()
+
+		ev, err := svc.Create(ctx, userID, domain.CreateEventInput{
+			PersonID: &personID,
+			Type:     domain.EventTypeGraduation,
+			Title:    "Wisuda S1",
+			Metadata: json.RawMessage(`{"institution":"Universitas Gadjah Mada","degree":"S.T."}`),
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, ev)
+		mockEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Event Type", func(t *testing.T) {
+		ev, err := svc.Create(ctx, userID, domain.CreateEventInput{
+			PersonID: &personID,
+			Type:     domain.EventType("BAPTISM"),
+			Title:    "Unknown",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEventType)
+		assert.Nil(t, ev)
+	})
+
+	t.Run("Missing Required Metadata", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+
+		ev, err := svc.Create(ctx, userID, domain.CreateEventInput{
+			PersonID: &personID,
+			Type:     domain.EventTypeMigration,
+			Title:    "Merantau",
+			Metadata: json.RawMessage(`{"from_place":"Bukittinggi"}`),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEventMetadata)
+		assert.Nil(t, ev)
+	})
+
+	t.Run("Wrong Metadata Type", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+
+		ev, err := svc.Create(ctx, userID, domain.CreateEventInput{
+			PersonID: &personID,
+			Type:     domain.EventTypeAqiqah,
+			Title:    "Aqiqah",
+			Metadata: json.RawMessage(`{"animal_count":"two"}`),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEventMetadata)
+		assert.Nil(t, ev)
+	})
+
+	t.Run("Akad Nikah Requires Relationship", func(t *testing.T) {
+		ev, err := svc.Create(ctx, userID, domain.CreateEventInput{
+			PersonID: &personID,
+			Type:     domain.EventTypeAkadNikah,
+			Title:    "Akad Nikah",
+		})
+
+		assert.ErrorIs(t, err, event.ErrRelationshipRequired)
+		assert.Nil(t, ev)
+	})
+}
+
+func TestEventService_UpdateNullMetadata(t *testing.T) {
+	mockEventRepo := new(mocks.EventRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+
+	svc := event.NewService(mockEventRepo, new(mocks.PersonRepository), new(mocks.RelationshipRepository), new(mocks.PlaceRepository), mockAuditRepo)
+	ctx := context.Background()
+	personID := uuid.New()
+	existing := &domain.Event{
+		ID:       uuid.New(),
+		PersonID: &personID,
+		Type:     domain.EventTypeBirth,
+		Title:    "Kelahiran",
+		Metadata: json.RawMessage(`{"birth_order":2}`),
+	}
+
+	mockEventRepo.On("GetByID", ctx, existing.ID).Return(existing, nil).Once()
+	mockEventRepo.On("Update", ctx, mock.MatchedBy(func(e *domain.Event) bool {
+		return string(e.Metadata) == "{}"
+	})).Return(nil).Once()
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil).Maybe()
+
+	var input domain.UpdateEventInput
+	require.NoError(t, json.Unmarshal([]byte(`{"metadata":null}`), &input))
+
+	ev, err := svc.Update(ctx, uuid.New(), existing.ID, input)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, "{}", string(ev.Metadata))
+	mockEventRepo.AssertExpectations(t)
+}
+
+func TestLoadEventTypes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Invalid file changes nothing", func(t *testing.T) {
+		path := write("invalid.yaml", `
+EVENT_TYPES:
+  - type: TEST_TINGKEBAN
+    scope: PERSON
+    gedcom_tag: EVEN
+  - type: TEST_BROKEN
+    scope: EVERYWHERE
+    gedcom_tag: EVEN
+`)
+
+		err := config.LoadEventTypes(path)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEventType)
+		assert.False(t, domain.EventType("TEST_TINGKEBAN").IsValid())
+	})
+
+	t.Run("Custom type is validated and labelled", func(t *testing.T) {
+		path := write("event_types.yaml", `
+EVENT_TYPES:
+  - type: TEST_MITONI
+    labels:
+      en: "Mitoni"
+    scope: PERSON
+    gedcom_tag: EVEN
+    gedcom_type: Mitoni
+    metadata:
+      - key: dukun
+        type: string
+        required: true
+`)
+		require.NoError(t, config.LoadEventTypes(path))
+
+		def, ok := domain.EventType("TEST_MITONI").Definition()
+		require.True(t, ok)
+		assert.ErrorIs(t, def.ValidateMetadata(json.RawMessage(`{}`)), domain.ErrInvalidEventMetadata)
+		assert.NoError(t, def.ValidateMetadata(json.RawMessage(`{"dukun":"Mbok Sri"}`)))
+
+		svc := event.NewService(nil, nil, nil, nil, nil)
+		types := svc.ListTypes("id")
+		assert.Equal(t, domain.EventTypeOther, types[len(types)-1].Type, "OTHER stays last")
+		assert.Equal(t, domain.EventType("TEST_MITONI"), types[len(types)-2].Type)
+		assert.Equal(t, "Mitoni", types[len(types)-2].Label)
+	})
+}
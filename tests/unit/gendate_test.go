@@ -0,0 +1,141 @@
+package unit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gdDay(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGenDate_ParseRoundTrip(t *testing.T) {
+	cases := []string{
+		"12 MAR 1920",
+		"MAR 1920",
+		"1920",
+		"ABT 1920",
+		"CAL 1875",
+		"EST JUN 1901",
+		"BEF 1900",
+		"AFT 3 JAN 1945",
+		"BET 1900 AND 1910",
+		"INT 1920 (sekitar zaman Jepang)",
+		"ABT 1942 (zaman Jepang)",
+		"(sebelum merdeka)",
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			d, err := domain.ParseGenDate(c)
+			require.NoError(t, err)
+			assert.Equal(t, c, d.String())
+		})
+	}
+}
+
+func TestGenDate_ParseLenientInput(t *testing.T) {
+	d, err := domain.ParseGenDate("1990-01-15T00:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, "15 JAN 1990", d.String())
+	assert.True(t, d.IsExact())
+
+	d, err = domain.ParseGenDate("1920-03")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PrecisionMonth, d.Precision())
+
+	d, err = domain.ParseGenDate("abt 1920")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DateAbout, d.Qualifier)
+
+	for _, bad := range []string{"", "BET 1910 AND 1900", "31 FEB 1920", "BET 1900", "sometime", "ABT", "0 MAR 1920", "1920-03-00", "()", "ABT ()"} {
+		d, err := domain.ParseGenDate(bad)
+		assert.ErrorIs(t, err, domain.ErrInvalidGenDate, bad)
+		assert.Nil(t, d, bad)
+	}
+}
+
+func TestGenDate_Bounds(t *testing.T) {
+	d, _ := domain.ParseGenDate("FEB 1920")
+	assert.Equal(t, gdDay(1920, time.February, 1), *d.Earliest())
+	assert.Equal(t, gdDay(1920, time.February, 29), *d.Latest())
+
+	d, _ = domain.ParseGenDate("BEF 1900")
+	assert.Nil(t, d.Earliest())
+	assert.Equal(t, gdDay(1899, time.December, 31), *d.Latest())
+	assert.Equal(t, gdDay(1899, time.December, 31), *d.SortDate())
+
+	d, _ = domain.ParseGenDate("AFT 1900")
+	assert.Equal(t, gdDay(1901, time.January, 1), *d.Earliest())
+	assert.Nil(t, d.Latest())
+
+	d, _ = domain.ParseGenDate("BET 1900 AND MAR 1910")
+	assert.Equal(t, gdDay(1900, time.January, 1), *d.Earliest())
+	assert.Equal(t, gdDay(1910, time.March, 31), *d.Latest())
+
+	d, _ = domain.ParseGenDate("(tidak diketahui)")
+	assert.Nil(t, d.SortDate())
+}
+
+func TestGenDate_JSON(t *testing.T) {
+	var input struct {
+		Date *domain.GenDate `json:"date"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"date":"ABT 1920"}`), &input))
+	assert.Equal(t, domain.DateAbout, input.Date.Qualifier)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"date":{"qualifier":"BET","date":"1900","end_date":"1910-06","phrase":"masa kolonial"}}`), &input))
+	assert.Equal(t, "BET 1900 AND JUN 1910 (masa kolonial)", input.Date.String())
+
+	out, err := json.Marshal(input.Date)
+	require.NoError(t, err)
+	var back domain.GenDate
+	require.NoError(t, json.Unmarshal(out, &back))
+	assert.Equal(t, *input.Date, back)
+	assert.Contains(t, string(out), `"earliest":"1900-01-01"`)
+	assert.Contains(t, string(out), `"latest":"1910-06-30"`)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"date":"BET 1900"}`), &input))
+}
+
+func TestGenDate_Ordering(t *testing.T) {
+	birth, _ := domain.ParseGenDate("ABT 1920")
+	assert.False(t, mustDate(t, "1920").Before(birth), "overlapping dates are not ordered")
+	assert.True(t, mustDate(t, "BEF 1900").Before(birth))
+	assert.True(t, mustDate(t, "1921").After(birth))
+
+	p := &domain.Person{BirthDate: birth, DeathDate: mustDate(t, "1919")}
+	assert.ErrorIs(t, p.ValidateLifeDates(), domain.ErrInvalidLifeDates)
+	p.DeathDate = mustDate(t, "BET 1915 AND 1925")
+	assert.NoError(t, p.ValidateLifeDates())
+}
+
+func TestAgeAt(t *testing.T) {
+	age := domain.AgeAt(mustDate(t, "10 MAR 1960"), mustDate(t, "9 MAR 1990"))
+	require.NotNil(t, age)
+	assert.Equal(t, domain.Age{Years: 29, Min: 29, Max: 29}, *age)
+
+	age = domain.AgeAt(mustDate(t, "ABT 1960"), mustDate(t, "1990"))
+	require.NotNil(t, age)
+	assert.True(t, age.Approximate)
+	assert.Equal(t, 30, age.Years)
+	assert.Equal(t, 29, age.Min)
+	assert.Equal(t, 30, age.Max)
+
+	assert.Nil(t, domain.AgeAt(mustDate(t, "1960"), mustDate(t, "1950")))
+	assert.Nil(t, domain.AgeAt(mustDate(t, "(tidak diketahui)"), mustDate(t, "1950")))
+}
+
+func mustDate(t *testing.T, s string) *domain.GenDate {
+	t.Helper()
+	d, err := domain.ParseGenDate(s)
+	require.NoError(t, err)
+	return d
+}
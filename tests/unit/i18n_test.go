@@ -27,6 +27,10 @@ func TestI18nLoading(t *testing.T) {
 	assert.Equal(t, "Uncle", i18n.Translate("en", "UNCLE"))
 	assert.Equal(t, "Granddaughter", i18n.Translate("en", "GRANDDAUGHTER"))
 	
+	// Event catalog labels
+	assert.Equal(t, "Akad Nikah", i18n.Translate("id", "EVENT_AKAD_NIKAH"))
+	assert.Equal(t, "Wedding Reception", i18n.Translate("en", "EVENT_RESEPSI"))
+
 	// Test Fallback
 	// Assuming a key that doesn't exist in ID but might in EN (though we made them symmetric)
 	// Let's test non-existent key returns key
@@ -0,0 +1,155 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/living"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func parentRel(child, parent uuid.UUID) domain.Relationship {
+	return domain.Relationship{ID: uuid.New(), PersonA: child, PersonB: parent, Type: domain.RelTypeParent}
+}
+
+func estimateFor(estimates []domain.LivingEstimate, id uuid.UUID) domain.LivingEstimate {
+	for _, e := range estimates {
+		if e.PersonID == id {
+			return e
+		}
+	}
+	return domain.LivingEstimate{}
+}
+
+func TestInferLiving(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// great-grandfather -> grandfather -> father (born 1950) -> son
+	greatGrandfather := domain.Person{ID: uuid.New(), FirstName: "Abdullah", IsAlive: true}
+	grandfather := domain.Person{ID: uuid.New(), FirstName: "Umar", IsAlive: true}
+	father := domain.Person{ID: uuid.New(), FirstName: "Hasan", IsAlive: true, BirthDate: domain.NewYearDate(domain.DateExact, 1950)}
+	son := domain.Person{ID: uuid.New(), FirstName: "Yusuf", IsAlive: true}
+	recorded := domain.Person{ID: uuid.New(), FirstName: "Ali", IsAlive: true, DeathDate: domain.NewYearDate(domain.DateExact, 1999)}
+	loner := domain.Person{ID: uuid.New(), FirstName: "Fatimah", IsAlive: true}
+
+	persons := []domain.Person{greatGrandfather, grandfather, father, son, recorded, loner}
+	rels := []domain.Relationship{
+		parentRel(grandfather.ID, greatGrandfather.ID),
+		parentRel(father.ID, grandfather.ID),
+		parentRel(son.ID, father.ID),
+	}
+
+	estimates := domain.InferLiving(persons, rels, nil, now)
+
+	t.Run("Own birth date", func(t *testing.T) {
+		e := estimateFor(estimates, father.ID)
+		assert.Equal(t, domain.LivingBasisBirth, e.Basis)
+		assert.True(t, e.ProbablyLiving)
+	})
+
+	t.Run("Estimated from child", func(t *testing.T) {
+		e := estimateFor(estimates, grandfather.ID)
+		assert.Equal(t, domain.LivingBasisChildren, e.Basis)
+		assert.Equal(t, 1925, *e.EstimatedBirthYear)
+		assert.True(t, e.ProbablyLiving)
+	})
+
+	t.Run("Estimated from generation depth", func(t *testing.T) {
+		e := estimateFor(estimates, greatGrandfather.ID)
+		assert.Equal(t, domain.LivingBasisGenerations, e.Basis)
+		assert.Equal(t, 1900, *e.EstimatedBirthYear)
+		assert.False(t, e.ProbablyLiving)
+		assert.True(t, e.NeedsCorrection())
+		require.Len(t, e.Issues, 1)
+		assert.Equal(t, domain.IssueAliveBeyondMaxAge, e.Issues[0].Code)
+	})
+
+	t.Run("Estimated from parent", func(t *testing.T) {
+		e := estimateFor(estimates, son.ID)
+		assert.Equal(t, domain.LivingBasisParents, e.Basis)
+		assert.Equal(t, 1975, *e.EstimatedBirthYear)
+		assert.True(t, e.ProbablyLiving)
+	})
+
+	t.Run("Death date while alive", func(t *testing.T) {
+		e := estimateFor(estimates, recorded.ID)
+		assert.Equal(t, domain.LivingBasisDeath, e.Basis)
+		assert.True(t, e.NeedsCorrection())
+		require.Len(t, e.Issues, 1)
+		assert.Equal(t, domain.IssueAliveWithDeath, e.Issues[0].Code)
+	})
+
+	t.Run("Nothing dated", func(t *testing.T) {
+		e := estimateFor(estimates, loner.ID)
+		assert.Equal(t, domain.LivingBasisNone, e.Basis)
+		assert.True(t, e.ProbablyLiving)
+		assert.Empty(t, e.Issues)
+	})
+
+	t.Run("Death event", func(t *testing.T) {
+		estimates := domain.InferLiving([]domain.Person{loner}, nil, []domain.Event{
+			{ID: uuid.New(), PersonID: &loner.ID, Type: domain.EventTypeBurial},
+		}, now)
+		assert.True(t, estimates[0].NeedsCorrection())
+	})
+
+	t.Run("Born before parent", func(t *testing.T) {
+		child := domain.Person{ID: uuid.New(), FirstName: "Zaid", IsAlive: true, BirthDate: domain.NewYearDate(domain.DateExact, 1925)}
+		estimates := domain.InferLiving([]domain.Person{child, father}, []domain.Relationship{parentRel(child.ID, father.ID)}, nil, now)
+		e := estimateFor(estimates, child.ID)
+		require.Len(t, e.Issues, 1)
+		assert.Equal(t, domain.IssueBornBeforeParent, e.Issues[0].Code)
+	})
+}
+
+func TestLivingService_Correct(t *testing.T) {
+	ctx := context.Background()
+	oldBirth := domain.NewYearDate(domain.DateExact, 1850)
+	ancestor := domain.Person{ID: uuid.New(), FirstName: "Sutan", IsAlive: true, BirthDate: oldBirth}
+	living1 := domain.Person{ID: uuid.New(), FirstName: "Rina", IsAlive: true, BirthDate: domain.NewYearDate(domain.DateExact, 1990)}
+
+	setup := func() (*mocks.PersonRepository, *mocks.AuditLogRepository, living.Service) {
+		mockPersonRepo := new(mocks.PersonRepository)
+		mockRelRepo := new(mocks.RelationshipRepository)
+		mockEventRepo := new(mocks.EventRepository)
+		mockAuditRepo := new(mocks.AuditLogRepository)
+		mockPersonRepo.On("GetAll", ctx).Return([]domain.Person{ancestor, living1}, nil)
+		mockRelRepo.On("GetAll", ctx).Return([]domain.Relationship{}, nil)
+		mockEventRepo.On("GetAll", ctx).Return([]domain.Event{}, nil)
+		return mockPersonRepo, mockAuditRepo, living.NewService(mockPersonRepo, mockRelRepo, mockEventRepo, mockAuditRepo, nil)
+	}
+
+	t.Run("Dry run", func(t *testing.T) {
+		mockPersonRepo, _, svc := setup()
+
+		result, err := svc.Correct(ctx, uuid.New(), true)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Corrected)
+		require.Len(t, result.Persons, 1)
+		assert.Equal(t, ancestor.ID, result.Persons[0].PersonID)
+		mockPersonRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Corrects with audit", func(t *testing.T) {
+		mockPersonRepo, mockAuditRepo, svc := setup()
+		mockPersonRepo.On("Update", ctx, mock.MatchedBy(func(p *domain.Person) bool {
+			return p.ID == ancestor.ID && !p.IsAlive
+		})).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		result, err := svc.Correct(ctx, uuid.New(), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Corrected)
+		mockPersonRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+}
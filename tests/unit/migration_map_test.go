@@ -0,0 +1,76 @@
+package unit_test
+
+import (
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestBuildMigrationMap(t *testing.T) {
+	year := func(s string) *domain.GenDate {
+		d, err := domain.ParseGenDate(s)
+		require.NoError(t, err)
+		return d
+	}
+
+	jakarta := &domain.Place{
+		ID: uuid.New(), Name: "DKI Jakarta", Latitude: floatPtr(-6.2), Longitude: floatPtr(106.8),
+		HistoricalNames: domain.HistoricalNames{{Name: "Batavia", ToYear: intPtr(1942)}},
+	}
+	padang := &domain.Place{ID: uuid.New(), Name: "Kota Padang", Latitude: floatPtr(-0.9), Longitude: floatPtr(100.4)}
+
+	grandfather := domain.Person{ID: uuid.New(), FirstName: "Datuk", BirthPlaceID: &padang.ID, BirthDate: year("1890"), DeathPlaceID: &jakarta.ID}
+	father := domain.Person{ID: uuid.New(), FirstName: "Rustam", BirthPlaceID: &jakarta.ID, BirthDate: year("1925")}
+	child := domain.Person{ID: uuid.New(), FirstName: "Sari", BirthPlaceID: &jakarta.ID, BirthDate: year("1960")}
+	unknown := domain.Person{ID: uuid.New(), FirstName: "Siti"}
+
+	m := domain.BuildMigrationMap(domain.MigrationMapInput{
+		RootPerson:  child.ID,
+		Direction:   domain.MigrationAncestors,
+		Persons:     []domain.Person{child, father, grandfather, unknown},
+		Generations: map[uuid.UUID]int{child.ID: 0, father.ID: -1, grandfather.ID: -2, unknown.ID: -1},
+		Links: []domain.ParentChildLink{
+			{Parent: father.ID, Child: child.ID},
+			{Parent: grandfather.ID, Child: father.ID},
+			{Parent: unknown.ID, Child: child.ID},
+		},
+		Places: map[uuid.UUID]*domain.Place{jakarta.ID: jakarta, padang.ID: padang},
+	})
+
+	assert.Equal(t, "FeatureCollection", m.Type)
+	assert.Equal(t, []uuid.UUID{unknown.ID}, m.Unlocated)
+
+	var points, arcs []domain.GeoJSONFeature
+	for _, f := range m.Features {
+		switch f.Geometry.Type {
+		case "Point":
+			points = append(points, f)
+		case "LineString":
+			arcs = append(arcs, f)
+		}
+	}
+	assert.Len(t, points, 4)
+
+	// Only the grandfather's move from Padang; father and child share a
+	// birth place.
+	require.Len(t, arcs, 1)
+	assert.Equal(t, [][2]float64{{100.4, -0.9}, {106.8, -6.2}}, arcs[0].Geometry.Coordinates)
+	assert.Equal(t, "Kota Padang", arcs[0].Properties["from_place"])
+	assert.Equal(t, "Batavia", arcs[0].Properties["to_place"])
+	assert.Equal(t, -1, arcs[0].Properties["generation"])
+
+	for _, f := range points {
+		if f.Properties["person_id"] == child.ID {
+			assert.Equal(t, "DKI Jakarta", f.Properties["place"])
+			assert.Equal(t, [2]float64{106.8, -6.2}, f.Geometry.Coordinates)
+		}
+	}
+}
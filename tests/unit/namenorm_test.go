@@ -0,0 +1,64 @@
+package unit_test
+
+import (
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/namenorm"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamenorm_Normalize(t *testing.T) {
+	cases := map[string]string{
+		"Soeharto":             "suharto",
+		"Tjokroaminoto":        "cokroaminoto",
+		"Sjahrir":              "syahrir",
+		"Njoto":                "nyoto",
+		"Djoko Tjahjono":       "joko cahyono",
+		"Joesoef":              "yusuf",
+		"Chairil Anwar":        "khairil anwar",
+		"Mohamad Hatta":        "muhammad hatta",
+		"Moh. Yamin":           "muhammad yamin",
+		"Achmad Dahlan":        "ahmad dahlan",
+		"Sitti Noerbaja":       "siti nurbaya",
+		"Abd. Rachman":         "abdul rahman",
+		"  José  Rizal-Manua ": "jose rizal manua",
+		"Joko Widodo":          "joko widodo",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, namenorm.Normalize(in), in)
+	}
+}
+
+func TestNamenorm_Phonetic(t *testing.T) {
+	alike := [][2]string{
+		{"Husein", "Husain"},
+		{"Jusuf", "Yusuf"},
+		{"Zaenab", "Zainab"},
+		{"Fatimah", "Fatima"},
+		{"Moehammad Djoenaedi", "Muhammad Junaidi"},
+		{"Hassan", "Hasan"},
+		{"Nur Aisyah", "Noor Aisah"},
+	}
+	for _, pair := range alike {
+		assert.Equal(t, namenorm.Phonetic(pair[0]), namenorm.Phonetic(pair[1]), "%s ~ %s", pair[0], pair[1])
+	}
+
+	different := [][2]string{
+		{"Aisyah", "Isa"},
+		{"Muhammad", "Mahmud"},
+		{"Hasan", "Husein"},
+	}
+	for _, pair := range different {
+		assert.NotEqual(t, namenorm.Phonetic(pair[0]), namenorm.Phonetic(pair[1]), "%s !~ %s", pair[0], pair[1])
+	}
+}
+
+func TestPerson_SyncNameKeys(t *testing.T) {
+	p := &domain.Person{FirstName: "Raden Adjeng", LastName: stringPtr("Kartini")}
+	p.SyncNameKeys()
+
+	assert.Equal(t, "raden ajeng kartini", *p.NameNormalized)
+	assert.Equal(t, namenorm.Phonetic("Raden Ajeng Kartini"), *p.NamePhonetic)
+}
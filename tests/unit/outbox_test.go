@@ -0,0 +1,166 @@
+package unit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/outbox"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxService_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockNotifSvc := new(mocks.NotificationService)
+	svc := outbox.NewService(mockOutboxRepo, mockNotifSvc)
+
+	approved := domain.OutboxMessage{ID: uuid.New(), Type: domain.NotifChangeApproved, EntityID: uuid.New(), ActorID: uuid.New()}
+	added := domain.OutboxMessage{ID: uuid.New(), Type: domain.NotifPersonAdded, EntityID: uuid.New(), ActorID: uuid.New(), Attempts: 2}
+
+	mockOutboxRepo.On("ClaimPending", ctx, 50, outbox.MaxAttempts, outbox.ClaimLease).Return([]domain.OutboxMessage{approved, added}, nil).Once()
+	mockNotifSvc.On("NotifyChangeApproved", ctx, approved.EntityID, approved.ActorID).Return(nil).Once()
+	mockOutboxRepo.On("MarkDispatched", ctx, approved.ID).Return(nil).Once()
+	mockNotifSvc.On("NotifyPersonAdded", ctx, added.EntityID, added.ActorID).Return(errors.New("smtp down")).Once()
+	mockOutboxRepo.On("Release", ctx, added.ID, "smtp down", 4*outbox.RetryBackoff).Return(nil).Once()
+
+	delivered, err := svc.Dispatch(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	mockOutboxRepo.AssertExpectations(t)
+	mockNotifSvc.AssertExpectations(t)
+}
+
+func TestOutboxService_DispatchPrepared(t *testing.T) {
+	ctx := context.Background()
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockNotifSvc := new(mocks.NotificationService)
+	svc := outbox.NewService(mockOutboxRepo, mockNotifSvc)
+
+	notif := &domain.Notification{ID: uuid.New(), UserID: uuid.New(), Type: domain.NotifChangeExpired, Title: "Change Request Expired"}
+	var msg *domain.OutboxMessage
+	mockOutboxRepo.On("Create", ctx, mock.AnythingOfType("*domain.OutboxMessage")).Run(func(args mock.Arguments) {
+		msg = args.Get(1).(*domain.OutboxMessage)
+	}).Return(nil).Once()
+
+	require.NoError(t, svc.EnqueueNotification(ctx, notif, uuid.New(), uuid.Nil))
+	require.NotNil(t, msg)
+	assert.Equal(t, domain.NotifChangeExpired, msg.Type)
+	assert.Equal(t, uuid.Nil, msg.ActorID)
+
+	mockOutboxRepo.On("ClaimPending", ctx, 50, outbox.MaxAttempts, outbox.ClaimLease).Return([]domain.OutboxMessage{*msg}, nil).Once()
+	mockNotifSvc.On("Create", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.ID == notif.ID && n.UserID == notif.UserID && n.Title == notif.Title
+	})).Return(nil).Once()
+	mockOutboxRepo.On("MarkDispatched", ctx, msg.ID).Return(nil).Once()
+
+	delivered, err := svc.Dispatch(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	mockNotifSvc.AssertExpectations(t)
+}
+
+func TestOutboxService_DispatchPreparedAgain(t *testing.T) {
+	ctx := context.Background()
+	mockOutboxRepo := new(mocks.OutboxRepository)
+	mockNotifSvc := new(mocks.NotificationService)
+	svc := outbox.NewService(mockOutboxRepo, mockNotifSvc)
+
+	// The notification was saved, but marking the message dispatched was
+	// lost, so the message is claimed again.
+	msg := domain.OutboxMessage{ID: uuid.New(), Type: domain.NotifChangeExpired, EntityID: uuid.New(), Notification: []byte(`{"id":"` + uuid.NewString() + `"}`)}
+	mockOutboxRepo.On("ClaimPending", ctx, 50, outbox.MaxAttempts, outbox.ClaimLease).Return([]domain.OutboxMessage{msg}, nil).Once()
+	mockNotifSvc.On("Create", ctx, mock.Anything).Return(domain.ErrNotificationExists).Once()
+	mockOutboxRepo.On("MarkDispatched", ctx, msg.ID).Return(nil).Once()
+
+	delivered, err := svc.Dispatch(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	mockOutboxRepo.AssertExpectations(t)
+	mockOutboxRepo.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestService_ApproveTransaction(t *testing.T) {
+	ctx := context.Background()
+	reviewer := &domain.User{ID: uuid.New(), Role: string(domain.RoleEditor)}
+
+	setup := func() (*mocks.ChangeRequestRepository, *mocks.PersonRepository, *mocks.OutboxRepository, *mocks.AuditLogRepository, changerequest.Service, *domain.ChangeRequest) {
+		mockCRRepo := new(mocks.ChangeRequestRepository)
+		mockUserRepo := new(mocks.UserRepository)
+		mockPersonRepo := new(mocks.PersonRepository)
+		mockAuditRepo := new(mocks.AuditLogRepository)
+		mockOutboxRepo := new(mocks.OutboxRepository)
+		mockTx := new(mocks.Transactor)
+		outboxSvc := outbox.NewService(mockOutboxRepo, new(mocks.NotificationService))
+		personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+		personSvc.SetOutbox(outboxSvc)
+		svc := changerequest.NewService(
+			mockCRRepo, nil, mockUserRepo, mockPersonRepo, nil, nil, mockAuditRepo,
+			personSvc, nil, nil,
+		)
+		svc.SetTransactor(mockTx, outboxSvc)
+
+		cr := &domain.ChangeRequest{
+			ID:          uuid.New(),
+			RequestedBy: uuid.New(),
+			Status:      domain.StatusPending,
+			EntityType:  domain.EntityPerson,
+			Action:      domain.ActionCreate,
+			Payload:     []byte(`{"first_name":"Rahmat"}`),
+		}
+		mockCRRepo.On("GetByID", ctx, cr.ID).Return(cr, nil)
+		mockUserRepo.On("GetByID", ctx, reviewer.ID).Return(reviewer, nil)
+		mockTx.On("WithinTx", ctx).Return(nil)
+		// Delivery after commit runs in the background.
+		mockOutboxRepo.On("ClaimPending", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+		return mockCRRepo, mockPersonRepo, mockOutboxRepo, mockAuditRepo, svc, cr
+	}
+
+	t.Run("Change, audit and outbox together", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, mockOutboxRepo, mockAuditRepo, svc, cr := setup()
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(nil).Once()
+		mockPersonRepo.On("Create", ctx, mock.AnythingOfType("*domain.Person")).Return(nil).Once()
+		mockOutboxRepo.On("Create", ctx, mock.MatchedBy(func(m *domain.OutboxMessage) bool {
+			return m.Type == domain.NotifPersonAdded
+		})).Return(nil).Once()
+		mockOutboxRepo.On("Create", ctx, mock.MatchedBy(func(m *domain.OutboxMessage) bool {
+			return m.Type == domain.NotifChangeApproved && m.EntityID == cr.ID
+		})).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(a *domain.AuditLog) bool {
+			return a.Action == "CREATE" && a.EntityType == "PERSON"
+		})).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(a *domain.AuditLog) bool {
+			return string(a.NewValue) == `{"status":"APPROVED"}`
+		})).Return(nil).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		require.NoError(t, err)
+		mockPersonRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+		mockOutboxRepo.AssertExpectations(t)
+		assert.Equal(t, domain.StatusPending, cr.Status)
+	})
+
+	t.Run("Concurrent approval applies nothing", func(t *testing.T) {
+		mockCRRepo, mockPersonRepo, mockOutboxRepo, _, svc, cr := setup()
+		mockCRRepo.On("UpdateStatus", ctx, cr.ID, domain.StatusApproved, reviewer.ID, mock.Anything).Return(domain.ErrChangeRequestNotPending).Once()
+
+		_, err := svc.Approve(ctx, cr.ID, reviewer.ID, nil, nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrChangeRequestNotPending)
+		mockPersonRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockOutboxRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
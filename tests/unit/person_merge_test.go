@@ -0,0 +1,195 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/changerequest"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMergeChoices(t *testing.T) {
+	target := &domain.Person{
+		FirstName: "Soekarno",
+		Gender:    domain.GenderMale,
+		BirthDate: mustDate(t, "1901"),
+	}
+	source := &domain.Person{
+		FirstName:  "Sukarno",
+		Nickname:   stringPtr("Bung Karno"),
+		Gender:     domain.GenderMale,
+		BirthDate:  mustDate(t, "6 JUN 1901"),
+		BirthPlace: stringPtr("Surabaya"),
+	}
+
+	err := domain.ApplyMergeChoices(target, source, map[string]domain.MergeChoice{
+		"birth_date": domain.MergeKeepSource,
+		"nickname":   domain.MergeKeepTarget,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Soekarno", target.FirstName, "unlisted fields keep the target's value")
+	assert.Equal(t, "6 JUN 1901", target.BirthDate.String())
+	assert.Equal(t, "Surabaya", *target.BirthPlace, "empty target fields are filled from the source")
+	assert.Nil(t, target.Nickname, "an explicit TARGET choice keeps the empty value")
+
+	err = domain.ApplyMergeChoices(target, source, map[string]domain.MergeChoice{"created_by": domain.MergeKeepSource})
+	assert.ErrorIs(t, err, domain.ErrInvalidMergeField)
+
+	err = domain.ApplyMergeChoices(target, source, map[string]domain.MergeChoice{"bio": "BOTH"})
+	assert.ErrorIs(t, err, domain.ErrInvalidMergeField)
+}
+
+func TestPlanRelationshipMerge(t *testing.T) {
+	merged, survivor := uuid.New(), uuid.New()
+	father, wife, child := uuid.New(), uuid.New(), uuid.New()
+
+	survivorFather := domain.Relationship{ID: uuid.New(), PersonA: survivor, PersonB: father, Type: domain.RelTypeParent}
+	survivorWife := domain.Relationship{ID: uuid.New(), PersonA: wife, PersonB: survivor, Type: domain.RelTypeSpouse}
+	mergedFather := domain.Relationship{ID: uuid.New(), PersonA: merged, PersonB: father, Type: domain.RelTypeParent}
+	mergedWife := domain.Relationship{ID: uuid.New(), PersonA: merged, PersonB: wife, Type: domain.RelTypeSpouse}
+	mergedChild := domain.Relationship{ID: uuid.New(), PersonA: child, PersonB: merged, Type: domain.RelTypeParent}
+	between := domain.Relationship{ID: uuid.New(), PersonA: merged, PersonB: survivor, Type: domain.RelTypeSpouse}
+
+	plan := domain.PlanRelationshipMerge(merged, survivor, []domain.Relationship{
+		survivorFather, survivorWife, mergedFather, mergedWife, mergedChild, between,
+	})
+
+	assert.Equal(t, []uuid.UUID{mergedChild.ID}, plan.Repoint)
+	require.Len(t, plan.Drop, 3)
+
+	dropped := make(map[uuid.UUID]*uuid.UUID)
+	for _, d := range plan.Drop {
+		dropped[d.ID] = d.ReplacedBy
+	}
+	assert.Equal(t, survivorFather.ID, *dropped[mergedFather.ID])
+	assert.Equal(t, survivorWife.ID, *dropped[mergedWife.ID], "spouses match in either order")
+	assert.Contains(t, dropped, between.ID)
+	assert.Nil(t, dropped[between.ID], "a self-relation has no replacement")
+}
+
+func TestPersonService_Merge(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	source := &domain.Person{ID: uuid.New(), FirstName: "Hatta", Occupation: stringPtr("Economist")}
+	target := &domain.Person{ID: uuid.New(), FirstName: "Mohammad Hatta"}
+
+	t.Run("Same person", func(t *testing.T) {
+		_, err := svc.Merge(ctx, userID, source.ID, domain.MergePersonInput{TargetID: source.ID})
+		assert.ErrorIs(t, err, domain.ErrMergeSamePerson)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, source.ID).Return(source, nil).Once()
+		mockPersonRepo.On("GetByID", ctx, target.ID).Return(target, nil).Once()
+		mockPersonRepo.On("CountLinkedUsers", ctx, []uuid.UUID{source.ID, target.ID}).Return(int64(1), nil).Once()
+		mockPersonRepo.On("Merge", ctx, mock.MatchedBy(func(p *domain.Person) bool {
+			return p.ID == target.ID && p.FirstName == "Mohammad Hatta" && *p.Occupation == "Economist"
+		}), source.ID, userID).Return(&domain.MergeResult{Survivor: target, MergedID: source.ID, Events: 2}, nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(log *domain.AuditLog) bool {
+			return log.Action == "MERGE" && log.EntityType == "PERSON" && log.EntityID == target.ID
+		})).Return(nil).Once()
+
+		result, err := svc.Merge(ctx, userID, source.ID, domain.MergePersonInput{TargetID: target.ID})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Events)
+		mockPersonRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Both persons linked", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, source.ID).Return(source, nil).Once()
+		mockPersonRepo.On("GetByID", ctx, target.ID).Return(target, nil).Once()
+		mockPersonRepo.On("CountLinkedUsers", ctx, []uuid.UUID{source.ID, target.ID}).Return(int64(2), nil).Once()
+
+		_, err := svc.Merge(ctx, userID, source.ID, domain.MergePersonInput{TargetID: target.ID})
+
+		assert.ErrorIs(t, err, domain.ErrMergeLinkedUsers)
+		mockPersonRepo.AssertExpectations(t)
+	})
+}
+
+func TestChangeRequestService_CreateMerge(t *testing.T) {
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockNotifSvc := new(mocks.NotificationService)
+	svc := changerequest.NewService(
+		mockCRRepo, new(mocks.NotificationRepository), new(mocks.UserRepository), new(mocks.PersonRepository),
+		new(mocks.RelationshipRepository), new(mocks.MediaRepository), new(mocks.AuditLogRepository),
+		nil, nil, nil,
+	)
+	svc.SetNotificationService(mockNotifSvc)
+	ctx := context.Background()
+	userID, sourceID := uuid.New(), uuid.New()
+
+	t.Run("Requires target", func(t *testing.T) {
+		_, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityPerson,
+			EntityID:   &sourceID,
+			Action:     domain.ActionMerge,
+			Payload:    json.RawMessage(`{}`),
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		payload, _ := json.Marshal(domain.MergePersonInput{TargetID: uuid.New()})
+		mockCRRepo.On("Create", ctx, mock.MatchedBy(func(cr *domain.ChangeRequest) bool {
+			return cr.Action == domain.ActionMerge && *cr.EntityID == sourceID
+		})).Return(nil).Once()
+		mockNotifSvc.On("NotifyChangeRequest", mock.Anything, mock.Anything, userID).Return(nil).Maybe()
+
+		cr, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+			EntityType: domain.EntityPerson,
+			EntityID:   &sourceID,
+			Action:     domain.ActionMerge,
+			Payload:    payload,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ActionMerge, cr.Action)
+	})
+}
+
+func TestChangeRequestService_CreateMergeLinkedUsers(t *testing.T) {
+	mockCRRepo := new(mocks.ChangeRequestRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	personSvc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), new(mocks.AuditLogRepository), nil)
+	svc := changerequest.NewService(
+		mockCRRepo, new(mocks.NotificationRepository), new(mocks.UserRepository), mockPersonRepo,
+		new(mocks.RelationshipRepository), new(mocks.MediaRepository), new(mocks.AuditLogRepository),
+		personSvc, nil, nil,
+	)
+	ctx := context.Background()
+	userID := uuid.New()
+	source := &domain.Person{ID: uuid.New(), FirstName: "Hatta"}
+	target := &domain.Person{ID: uuid.New(), FirstName: "Mohammad Hatta"}
+
+	mockPersonRepo.On("GetByID", ctx, source.ID).Return(source, nil)
+	mockPersonRepo.On("GetByID", ctx, target.ID).Return(target, nil)
+	mockPersonRepo.On("CountLinkedUsers", ctx, []uuid.UUID{source.ID, target.ID}).Return(int64(2), nil)
+
+	payload, _ := json.Marshal(domain.MergePersonInput{TargetID: target.ID})
+	_, err := svc.Create(ctx, userID, domain.CreateChangeRequestInput{
+		EntityType: domain.EntityPerson,
+		EntityID:   &source.ID,
+		Action:     domain.ActionMerge,
+		Payload:    payload,
+	})
+
+	assert.ErrorIs(t, err, changerequest.ErrInvalidChange)
+	assert.ErrorIs(t, err, domain.ErrMergeLinkedUsers)
+	mockCRRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,100 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/personname"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPersonNameService_Create(t *testing.T) {
+	mockNameRepo := new(mocks.PersonNameRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+
+	svc := personname.NewService(mockNameRepo, mockPersonRepo, mockAuditRepo, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	personID := uuid.New()
+
+	t.Run("Success - Religious Name", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+		mockNameRepo.On("Create", ctx, mock.MatchedBy(func(n *domain.PersonName) bool {
+			return n.PersonID == personID && n.Type == domain.NameTypeReligious && n.Name == "Muhammad Yusuf"
+		})).Return(nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(log *domain.AuditLog) bool {
+			return log.EntityType == "PERSON_NAME" && log.Action == "CREATE"
+		})).Return(nil).Once()
+
+		name, err := svc.Create(ctx, userID, personID, domain.CreatePersonNameInput{
+			Type:      domain.NameTypeReligious,
+			Name:      "Muhammad Yusuf",
+			ValidFrom: mustDate(t, "1975"),
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, name)
+		mockNameRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Type", func(t *testing.T) {
+		name, err := svc.Create(ctx, userID, personID, domain.CreatePersonNameInput{
+			Type: domain.NameType("NICKNAME"),
+			Name: "Ucok",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidNameType)
+		assert.Nil(t, name)
+	})
+
+	t.Run("Validity Ends Before It Starts", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+
+		name, err := svc.Create(ctx, userID, personID, domain.CreatePersonNameInput{
+			Type:      domain.NameTypeMarried,
+			Name:      "Siti Hartono",
+			ValidFrom: mustDate(t, "1980"),
+			ValidTo:   mustDate(t, "1970"),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidNameValidity)
+		assert.Nil(t, name)
+	})
+
+	t.Run("Name Of Another Person", func(t *testing.T) {
+		nameID := uuid.New()
+		mockNameRepo.On("GetByID", ctx, nameID).Return(&domain.PersonName{ID: nameID, PersonID: uuid.New()}, nil).Once()
+
+		err := svc.Delete(ctx, userID, personID, nameID)
+
+		assert.ErrorIs(t, err, domain.ErrPersonNameNotFound)
+	})
+}
+
+func TestPersonName_PatronymicAndTitle(t *testing.T) {
+	father := &domain.Person{FirstName: "Abdullah", Gender: domain.GenderMale}
+
+	son := &domain.Person{FirstName: "Ahmad", Gender: domain.GenderMale}
+	assert.Equal(t, "Ahmad bin Abdullah", *domain.Patronymic(son, father))
+
+	daughter := &domain.Person{FirstName: "Aisyah", Gender: domain.GenderFemale}
+	assert.Equal(t, "Aisyah binti Abdullah", *domain.Patronymic(daughter, father))
+
+	assert.Nil(t, domain.Patronymic(&domain.Person{FirstName: "X", Gender: domain.GenderUnknown}, father))
+	assert.Nil(t, domain.Patronymic(son, nil))
+
+	names := []domain.PersonName{
+		{Type: domain.NameTypeTitle, Name: "Raden Mas", ValidTo: mustDate(t, "1945")},
+		{Type: domain.NameTypeTitle, Name: "Haji", ValidFrom: mustDate(t, "1990")},
+	}
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Haji", *domain.CurrentTitle(names, now))
+	assert.Equal(t, "Raden Mas", *domain.CurrentTitle(names, time.Date(1940, time.January, 1, 0, 0, 0, 0, time.UTC)))
+}
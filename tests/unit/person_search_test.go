@@ -0,0 +1,71 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestPersonSearchInput_Validate(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		input := domain.PersonSearchInput{Limit: 500, Offset: -3}
+		require.NoError(t, input.Validate())
+		assert.Equal(t, domain.SortByName, input.Sort)
+		assert.Equal(t, 100, input.Limit)
+		assert.Equal(t, 0, input.Offset)
+	})
+
+	invalid := map[string]domain.PersonSearchInput{
+		"short query":    {Query: "a"},
+		"unknown sort":   {Sort: "age"},
+		"birth range":    {BirthYearFrom: intPtr(1950), BirthYearTo: intPtr(1900)},
+		"death range":    {DeathYearFrom: intPtr(2000), DeathYearTo: intPtr(1990)},
+		"unknown gender": {Gender: (*domain.Gender)(stringPtr("OTHER"))},
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, input.Validate(), domain.ErrInvalidSearch)
+		})
+	}
+}
+
+func TestPersonService_Search(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	svc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), new(mocks.AuditLogRepository), nil)
+	ctx := context.Background()
+
+	female := domain.GenderFemale
+	input := domain.PersonSearchInput{Gender: &female, NoParents: true, Sort: domain.SortByBirthDate, Offset: 20}
+	facets := &domain.PersonSearchFacets{
+		Gender: []domain.FacetCount{{Value: "FEMALE", Count: 42}},
+	}
+
+	matches := mock.MatchedBy(func(in domain.PersonSearchInput) bool {
+		return *in.Gender == female && in.NoParents && in.Limit == 20 && in.Offset == 20
+	})
+	mockPersonRepo.On("Search", ctx, matches).Return([]domain.Person{{FirstName: "Dewi"}}, int64(42), nil).Once()
+	mockPersonRepo.On("SearchFacets", ctx, matches).Return(facets, nil).Once()
+
+	result, err := svc.Search(ctx, input)
+
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 1)
+	assert.Equal(t, int64(42), result.TotalItems)
+	assert.Equal(t, 20, result.Offset)
+	assert.Equal(t, facets, result.Facets)
+	mockPersonRepo.AssertExpectations(t)
+
+	_, err = svc.Search(ctx, domain.PersonSearchInput{Query: "x"})
+	assert.ErrorIs(t, err, domain.ErrInvalidSearch)
+}
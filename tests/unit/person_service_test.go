@@ -3,6 +3,7 @@ package unit_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"silsilah-keluarga/internal/domain"
@@ -19,7 +20,7 @@ func TestPersonService_Create(t *testing.T) {
 	mockRelRepo := new(mocks.RelationshipRepository)
 	mockAuditRepo := new(mocks.AuditLogRepository)
 
-	svc := person.NewService(mockPersonRepo, mockRelRepo, mockAuditRepo, nil)
+	svc := person.NewService(mockPersonRepo, mockRelRepo, new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
 	ctx := context.Background()
 	userID := uuid.New()
 
@@ -67,7 +68,7 @@ func TestPersonService_GetByID(t *testing.T) {
 	mockRelRepo := new(mocks.RelationshipRepository)
 	mockAuditRepo := new(mocks.AuditLogRepository)
 
-	svc := person.NewService(mockPersonRepo, mockRelRepo, mockAuditRepo, nil)
+	svc := person.NewService(mockPersonRepo, mockRelRepo, new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
 	ctx := context.Background()
 	personID := uuid.New()
 
@@ -91,6 +92,39 @@ func TestPersonService_GetByID(t *testing.T) {
 	})
 }
 
+func TestPersonService_Delete(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), new(mocks.PlaceRepository), mockAuditRepo, nil)
+	ctx := context.Background()
+	userID, personID := uuid.New(), uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		deletionID := uuid.New()
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID, FirstName: "Kartini"}, nil).Once()
+		mockPersonRepo.On("Delete", ctx, personID, userID).Return(deletionID, nil).Once()
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(log *domain.AuditLog) bool {
+			return log.Action == "DELETE" && log.EntityType == "PERSON" && log.EntityID == personID &&
+				strings.Contains(string(log.NewValue), deletionID.String())
+		})).Return(nil).Once()
+
+		err := svc.Delete(ctx, userID, personID)
+
+		assert.NoError(t, err)
+		mockPersonRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Already deleted", func(t *testing.T) {
+		mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil).Once()
+		mockPersonRepo.On("Delete", ctx, personID, userID).Return(uuid.Nil, domain.ErrPersonNotFound).Once()
+
+		err := svc.Delete(ctx, userID, personID)
+
+		assert.ErrorIs(t, err, domain.ErrPersonNotFound)
+	})
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
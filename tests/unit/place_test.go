@@ -0,0 +1,152 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/pkg/gazetteer"
+	"silsilah-keluarga/internal/service/person"
+	"silsilah-keluarga/internal/service/place"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGazetteer_Load(t *testing.T) {
+	entries, err := gazetteer.Load()
+	require.NoError(t, err)
+
+	levels := map[string]domain.PlaceLevel{}
+	provinces := 0
+	for _, e := range entries {
+		_, dup := levels[e.Code]
+		require.False(t, dup, "duplicate code %s", e.Code)
+
+		if e.ParentCode == "" {
+			assert.Equal(t, domain.PlaceLevelCountry, e.Level, e.Code)
+		} else {
+			parentLevel, ok := levels[e.ParentCode]
+			require.True(t, ok, "%s: parent %s must come first", e.Code, e.ParentCode)
+			assert.Greater(t, e.Level.Rank(), parentLevel.Rank(), e.Code)
+		}
+		assert.NoError(t, domain.ValidateCoordinates(&e.Latitude, &e.Longitude), e.Code)
+
+		levels[e.Code] = e.Level
+		if e.Level == domain.PlaceLevelProvinsi {
+			provinces++
+		}
+	}
+	assert.Equal(t, 38, provinces)
+}
+
+func TestPlace_NameAt(t *testing.T) {
+	entries, err := gazetteer.Load()
+	require.NoError(t, err)
+
+	var jakarta *domain.Place
+	for _, e := range entries {
+		if e.Code == "31" {
+			jakarta = &domain.Place{Name: e.Name, HistoricalNames: e.HistoricalNames}
+		}
+	}
+	require.NotNil(t, jakarta)
+
+	assert.Equal(t, "Jayakarta", jakarta.NameAt(1600))
+	assert.Equal(t, "Batavia", jakarta.NameAt(1900))
+	assert.Equal(t, "DKI Jakarta", jakarta.NameAt(1980))
+}
+
+func TestPlaceService_Create(t *testing.T) {
+	mockPlaceRepo := new(mocks.PlaceRepository)
+	svc := place.NewService(mockPlaceRepo, new(mocks.AuditLogRepository))
+	ctx := context.Background()
+
+	provinsi := &domain.Place{ID: uuid.New(), Level: domain.PlaceLevelProvinsi, Name: "Jawa Timur"}
+	mockPlaceRepo.On("GetByID", ctx, provinsi.ID).Return(provinsi, nil)
+
+	t.Run("Level above parent", func(t *testing.T) {
+		_, err := svc.Create(ctx, uuid.New(), domain.CreatePlaceInput{
+			ParentID: &provinsi.ID,
+			Level:    domain.PlaceLevelCountry,
+			Name:     "Indonesia",
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidPlaceHierarchy)
+	})
+
+	t.Run("Missing parent", func(t *testing.T) {
+		_, err := svc.Create(ctx, uuid.New(), domain.CreatePlaceInput{Level: domain.PlaceLevelDesa, Name: "Ketintang"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPlaceHierarchy)
+	})
+
+	t.Run("Half coordinates", func(t *testing.T) {
+		lat := -7.3
+		_, err := svc.Create(ctx, uuid.New(), domain.CreatePlaceInput{
+			ParentID: &provinsi.ID,
+			Level:    domain.PlaceLevelKabupaten,
+			Name:     "Kabupaten Sidoarjo",
+			Latitude: &lat,
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidCoordinates)
+	})
+}
+
+func TestPersonService_CreateWithBirthPlace(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockPlaceRepo := new(mocks.PlaceRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := person.NewService(mockPersonRepo, new(mocks.RelationshipRepository), new(mocks.PersonNameRepository), mockPlaceRepo, mockAuditRepo, nil)
+	ctx := context.Background()
+
+	surabaya := uuid.New()
+	mockPlaceRepo.On("GetAncestors", ctx, surabaya).Return([]domain.Place{
+		{ID: surabaya, Name: "Kota Surabaya"},
+		{Name: "Jawa Timur"},
+		{Name: "Indonesia"},
+	}, nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	t.Run("Fills free text", func(t *testing.T) {
+		mockPersonRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		p, err := svc.Create(ctx, uuid.New(), domain.CreatePersonInput{
+			FirstName:    "Bung",
+			Gender:       domain.GenderMale,
+			BirthPlaceID: &surabaya,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Kota Surabaya, Jawa Timur, Indonesia", *p.BirthPlace)
+		assert.Equal(t, surabaya, *p.BirthPlaceID)
+	})
+
+	t.Run("Keeps given text", func(t *testing.T) {
+		mockPersonRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		p, err := svc.Create(ctx, uuid.New(), domain.CreatePersonInput{
+			FirstName:    "Bung",
+			Gender:       domain.GenderMale,
+			BirthPlace:   stringPtr("Peneleh, Surabaya"),
+			BirthPlaceID: &surabaya,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Peneleh, Surabaya", *p.BirthPlace)
+	})
+
+	t.Run("Unknown place", func(t *testing.T) {
+		missing := uuid.New()
+		mockPlaceRepo.On("GetAncestors", ctx, missing).Return([]domain.Place{}, nil).Once()
+
+		_, err := svc.Create(ctx, uuid.New(), domain.CreatePersonInput{
+			FirstName:    "Bung",
+			Gender:       domain.GenderMale,
+			BirthPlaceID: &missing,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrPlaceNotFound)
+	})
+}
@@ -0,0 +1,140 @@
+package unit_test
+
+import (
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewer_CanSee(t *testing.T) {
+	linked := uuid.New()
+	tests := []struct {
+		name   string
+		viewer domain.Viewer
+		level  domain.PrivacyLevel
+		want   bool
+	}{
+		{"Outsider sees public", domain.Viewer{}, domain.PrivacyPublic, true},
+		{"Outsider not family", domain.Viewer{}, domain.PrivacyFamily, false},
+		{"Member sees family", domain.Viewer{Role: domain.RoleMember}, domain.PrivacyFamily, true},
+		{"Member not editors", domain.Viewer{Role: domain.RoleMember}, domain.PrivacyEditors, false},
+		{"Editor sees editors", domain.Viewer{Role: domain.RoleEditor}, domain.PrivacyEditors, true},
+		{"Editor not private", domain.Viewer{Role: domain.RoleEditor}, domain.PrivacyPrivate, false},
+		{"Developer sees private", domain.Viewer{Role: domain.RoleDeveloper}, domain.PrivacyPrivate, true},
+		{"Linked person sees self", domain.Viewer{Role: domain.RoleMember, PersonID: &linked}, domain.PrivacyPrivate, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.viewer.CanSee(linked, tt.level))
+		})
+	}
+
+	assert.Equal(t, []domain.PrivacyLevel{domain.PrivacyEditors, domain.PrivacyPrivate}, domain.Viewer{Role: domain.RoleMember}.HiddenLevels())
+	assert.Empty(t, domain.Viewer{Role: domain.RoleDeveloper}.HiddenLevels())
+}
+
+func TestPerson_ProbablyLiving(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		person domain.Person
+		want   bool
+	}{
+		{"No dates", domain.Person{IsAlive: true}, true},
+		{"Born 1980", domain.Person{IsAlive: true, BirthDate: domain.NewYearDate(domain.DateExact, 1980)}, true},
+		{"Born 1900", domain.Person{IsAlive: true, BirthDate: domain.NewYearDate(domain.DateExact, 1900)}, false},
+		{"Marked deceased", domain.Person{IsAlive: false}, false},
+		{"Has death date", domain.Person{IsAlive: true, DeathDate: domain.NewYearDate(domain.DateAbout, 2001)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.person.ProbablyLiving(now))
+		})
+	}
+}
+
+func TestPerson_Redact(t *testing.T) {
+	now := time.Now()
+	living := func(level domain.PrivacyLevel) domain.Person {
+		return domain.Person{
+			ID:           uuid.New(),
+			FirstName:    "Siti",
+			LastName:     stringPtr("Aminah"),
+			IsAlive:      true,
+			BirthDate:    domain.NewExactDate(time.Date(1990, 3, 14, 0, 0, 0, 0, time.UTC)),
+			BirthPlace:   stringPtr("Bandung"),
+			Phone:        stringPtr("0812"),
+			Occupation:   stringPtr("Guru"),
+			PrivacyLevel: level,
+		}
+	}
+
+	t.Run("Family person for outsider", func(t *testing.T) {
+		p := living(domain.PrivacyFamily)
+		p.Redact(domain.Viewer{}, now)
+
+		assert.True(t, p.Redacted)
+		assert.Equal(t, "Siti", p.FirstName)
+		assert.Nil(t, p.Phone)
+		assert.Nil(t, p.BirthPlace)
+		assert.Equal(t, 1990, p.BirthDate.Year())
+		assert.Equal(t, domain.PrecisionYear, p.BirthDate.Precision())
+		assert.Equal(t, "Guru", *p.Occupation)
+	})
+
+	t.Run("Private person for member", func(t *testing.T) {
+		p := living(domain.PrivacyPrivate)
+		p.Redact(domain.Viewer{Role: domain.RoleMember}, now)
+
+		assert.True(t, p.Redacted)
+		assert.Equal(t, domain.PrivateName, p.FirstName)
+		assert.Nil(t, p.LastName)
+		assert.Nil(t, p.BirthDate)
+		assert.Nil(t, p.Occupation)
+		assert.Equal(t, domain.PrivateName, p.SharedName(now))
+	})
+
+	t.Run("Private person for themselves", func(t *testing.T) {
+		p := living(domain.PrivacyPrivate)
+		p.Redact(domain.Viewer{Role: domain.RoleMember, PersonID: &p.ID}, now)
+
+		assert.False(t, p.Redacted)
+		assert.Equal(t, "Siti", p.FirstName)
+		assert.Equal(t, "0812", *p.Phone)
+	})
+
+	t.Run("Deceased person is never redacted", func(t *testing.T) {
+		p := living(domain.PrivacyPrivate)
+		p.IsAlive = false
+		p.Redact(domain.Viewer{}, now)
+
+		assert.False(t, p.Redacted)
+		assert.Equal(t, "Bandung", *p.BirthPlace)
+	})
+}
+
+func TestGraphNode_Redact(t *testing.T) {
+	now := time.Now()
+	year := 1995
+	node := func(level domain.PrivacyLevel) domain.GraphNode {
+		return domain.GraphNode{ID: uuid.New(), FirstName: "Budi", LastName: stringPtr("Santoso"), IsAlive: true, BirthYear: &year, PrivacyLevel: level}
+	}
+
+	nodes := []domain.GraphNode{node(domain.PrivacyEditors), node(domain.PrivacyPrivate)}
+	domain.RedactNodes(nodes, domain.Viewer{Role: domain.RoleMember}, now)
+
+	assert.True(t, nodes[0].Redacted)
+	assert.Equal(t, "Budi", nodes[0].FirstName)
+	assert.Equal(t, &year, nodes[0].BirthYear)
+
+	assert.True(t, nodes[1].Redacted)
+	assert.Equal(t, domain.PrivateName, nodes[1].FirstName)
+	assert.Nil(t, nodes[1].LastName)
+	assert.Nil(t, nodes[1].BirthYear)
+}
@@ -48,6 +48,9 @@ func TestRelationshipService_Create(t *testing.T) {
 		// Return empty descendants for p1, so p2 is not a descendant
 		mockRelRepo.On("ListByPeople", ctx, []uuid.UUID{p1ID}).Return([]domain.Relationship{}, nil).Once()
 
+		// Mock Duplicate Check
+		mockRelRepo.On("FindPair", ctx, p1ID, p2ID, domain.RelTypeParent).Return(nil, nil).Once()
+
 		// Mock Create
 		mockRelRepo.On("Create", ctx, mock.AnythingOfType("*domain.Relationship")).Return(nil).Once()
 
@@ -62,6 +65,19 @@ func TestRelationshipService_Create(t *testing.T) {
 		assert.Equal(t, p2ID, rel.PersonB)
 	})
 
+	t.Run("Duplicate Error", func(t *testing.T) {
+		svc, mockPersonRepo, mockRelRepo, _ := setup()
+		mockPersonRepo.On("GetByID", ctx, p1ID).Return(p1, nil).Once()
+		mockPersonRepo.On("GetByID", ctx, p2ID).Return(p2, nil).Once()
+		mockRelRepo.On("ListByPeople", ctx, []uuid.UUID{p1ID}).Return([]domain.Relationship{}, nil).Once()
+		mockRelRepo.On("FindPair", ctx, p1ID, p2ID, domain.RelTypeParent).Return(&domain.Relationship{ID: uuid.New(), PersonA: p1ID, PersonB: p2ID, Type: domain.RelTypeParent}, nil).Once()
+
+		err := svc.ValidateCreate(ctx, input)
+
+		assert.ErrorIs(t, err, relationship.ErrDuplicateRelationship)
+		mockRelRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
 	t.Run("Self Relation Error", func(t *testing.T) {
 		svc, mockPersonRepo, _, _ := setup()
 		inputSelf := domain.CreateRelationshipInput{
@@ -107,8 +123,8 @@ func TestRelationshipService_Create(t *testing.T) {
 		childBirth := now.AddDate(-20, 0, 0)
 		parentBirth := now.AddDate(-10, 0, 0) // Parent is 10, Child is 20
 		
-		p1Young := &domain.Person{ID: p1ID, FirstName: "Child", BirthDate: &childBirth}
-		p2Young := &domain.Person{ID: p2ID, FirstName: "Parent", BirthDate: &parentBirth}
+		p1Young := &domain.Person{ID: p1ID, FirstName: "Child", BirthDate: domain.NewExactDate(childBirth)}
+		p2Young := &domain.Person{ID: p2ID, FirstName: "Parent", BirthDate: domain.NewExactDate(parentBirth)}
 		
 		mockPersonRepo.On("GetByID", ctx, p1ID).Return(p1Young, nil).Once()
 		mockPersonRepo.On("GetByID", ctx, p2ID).Return(p2Young, nil).Once()
@@ -138,6 +154,8 @@ func TestRelationshipService_Create(t *testing.T) {
 		// For spouse, it doesn't run bfsDescendants
 		// validateRelationship only runs bfsDescendants if RelTypeParent
 		
+		mockRelRepo.On("FindPair", ctx, p1ID, p2ID, domain.RelTypeSpouse).Return(nil, nil).Once()
+
 		// Consanguinity Check -> bfsShortestPath(p1, p2)
 		mockRelRepo.On("ListByPerson", ctx, p1ID).Return([]domain.Relationship{}, nil).Once()
 		
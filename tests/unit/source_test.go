@@ -0,0 +1,155 @@
+package unit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/export"
+	"silsilah-keluarga/internal/service/source"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceService_CreateCitation(t *testing.T) {
+	mockSourceRepo := new(mocks.SourceRepository)
+	mockCitationRepo := new(mocks.CitationRepository)
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := source.NewService(mockSourceRepo, mockCitationRepo, mockPersonRepo, new(mocks.RelationshipRepository),
+		new(mocks.EventRepository), new(mocks.MediaRepository), mockAuditRepo)
+	ctx := context.Background()
+
+	src := &domain.Source{ID: uuid.New(), Type: domain.SourceTypeCertificate, Title: "Akta Kelahiran"}
+	personID := uuid.New()
+	mockSourceRepo.On("GetByID", ctx, src.ID).Return(src, nil)
+	mockPersonRepo.On("GetByID", ctx, personID).Return(&domain.Person{ID: personID}, nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	t.Run("Defaults confidence", func(t *testing.T) {
+		mockCitationRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		c, err := svc.CreateCitation(ctx, uuid.New(), domain.CreateCitationInput{
+			SourceID:   src.ID,
+			EntityType: domain.EntityPerson,
+			EntityID:   personID,
+			Field:      stringPtr("birth_date"),
+			Page:       stringPtr("No. 123/1965"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ConfidenceSecondary, c.Confidence)
+		assert.Equal(t, src, c.Source)
+		assert.Empty(t, c.MediaIDs)
+	})
+
+	t.Run("Field not on entity", func(t *testing.T) {
+		_, err := svc.CreateCitation(ctx, uuid.New(), domain.CreateCitationInput{
+			SourceID:   src.ID,
+			EntityType: domain.EntityPerson,
+			EntityID:   personID,
+			Field:      stringPtr("marriage_date"),
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidCitationField)
+	})
+
+	t.Run("Uncitable entity", func(t *testing.T) {
+		_, err := svc.CreateCitation(ctx, uuid.New(), domain.CreateCitationInput{
+			SourceID:   src.ID,
+			EntityType: domain.EntityMedia,
+			EntityID:   uuid.New(),
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidCitationEntity)
+	})
+
+	t.Run("Missing person", func(t *testing.T) {
+		missing := uuid.New()
+		mockPersonRepo.On("GetByID", ctx, missing).Return(nil, nil)
+
+		_, err := svc.CreateCitation(ctx, uuid.New(), domain.CreateCitationInput{
+			SourceID:   src.ID,
+			EntityType: domain.EntityPerson,
+			EntityID:   missing,
+		})
+		assert.ErrorIs(t, err, domain.ErrCitedEntityNotFound)
+	})
+}
+
+func TestSourceService_DeleteCitedSource(t *testing.T) {
+	mockSourceRepo := new(mocks.SourceRepository)
+	mockCitationRepo := new(mocks.CitationRepository)
+	svc := source.NewService(mockSourceRepo, mockCitationRepo, new(mocks.PersonRepository), new(mocks.RelationshipRepository),
+		new(mocks.EventRepository), new(mocks.MediaRepository), new(mocks.AuditLogRepository))
+	ctx := context.Background()
+
+	src := &domain.Source{ID: uuid.New(), Type: domain.SourceTypeBook, Title: "Tambo Minangkabau"}
+	mockSourceRepo.On("GetByID", ctx, src.ID).Return(src, nil)
+	mockCitationRepo.On("CountBySource", ctx, src.ID).Return(int64(2), nil)
+
+	err := svc.DeleteSource(ctx, uuid.New(), src.ID)
+
+	assert.ErrorIs(t, err, domain.ErrSourceInUse)
+	mockSourceRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestExportService_GEDCOMSources(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockNameRepo := new(mocks.PersonNameRepository)
+	mockEventRepo := new(mocks.EventRepository)
+	mockCitationRepo := new(mocks.CitationRepository)
+	mockCustomFieldRepo := new(mocks.CustomFieldRepository)
+	svc := export.NewService(mockPersonRepo, mockRelRepo, mockNameRepo, mockEventRepo, mockCitationRepo, mockCustomFieldRepo, new(mocks.AuditLogRepository), nil)
+	ctx := context.Background()
+
+	birthDate, err := domain.ParseGenDate("1921-06-06")
+	require.NoError(t, err)
+	p := domain.Person{ID: uuid.New(), FirstName: "Sukarni", Gender: domain.GenderMale, BirthDate: birthDate, IsAlive: true, Occupation: stringPtr("Guru")}
+
+	certificate := &domain.Source{ID: uuid.New(), Type: domain.SourceTypeCertificate, Title: "Akta Kelahiran", Repository: stringPtr("Dukcapil Blitar")}
+	interview := &domain.Source{ID: uuid.New(), Type: domain.SourceTypeInterview, Title: "Wawancara keluarga"}
+
+	mockPersonRepo.On("GetByID", ctx, p.ID).Return(&p, nil)
+	mockPersonRepo.On("GetAll", ctx).Return([]domain.Person{p}, nil)
+	mockNameRepo.On("GetAll", ctx).Return([]domain.PersonName{}, nil)
+	mockRelRepo.On("GetAll", ctx).Return([]domain.Relationship{}, nil)
+	mockEventRepo.On("GetAll", ctx).Return([]domain.Event{}, nil)
+	mockCustomFieldRepo.On("GetAllValues", ctx).Return([]domain.PersonCustomValue{}, nil)
+	mockCitationRepo.On("GetAll", ctx).Return([]domain.Citation{
+		{ID: uuid.New(), SourceID: certificate.ID, Source: certificate, EntityType: domain.EntityPerson, EntityID: p.ID,
+			Field: stringPtr("birth_date"), Page: stringPtr("No. 45"), Confidence: domain.ConfidencePrimary},
+		{ID: uuid.New(), SourceID: interview.ID, Source: interview, EntityType: domain.EntityPerson, EntityID: p.ID,
+			Field: stringPtr("religion"), Confidence: domain.ConfidenceQuestionable},
+	}, nil)
+
+	out, err := svc.ExportGEDCOM(ctx, domain.Viewer{UserID: uuid.New(), Role: domain.RoleMember}, p.ID)
+	require.NoError(t, err)
+	lines := strings.Split(out, "\r\n")
+
+	// The birth citation sits under BIRT.
+	birt := indexOf(lines, "1 BIRT")
+	require.NotEqual(t, -1, birt)
+	assert.Equal(t, []string{"2 DATE 6 JUN 1921", "2 SOUR @S1@", "3 PAGE No. 45", "3 QUAY 3"}, lines[birt+1:birt+5])
+
+	// No RELI was exported, so the religion citation falls back to the
+	// record and names its field.
+	assert.Contains(t, out, "1 SOUR @S2@\r\n2 QUAY 1\r\n2 NOTE Field: religion\r\n")
+
+	assert.Contains(t, out, "0 @S1@ SOUR\r\n1 TITL Akta Kelahiran\r\n1 _TYPE CERTIFICATE\r\n1 NOTE Repository: Dukcapil Blitar\r\n")
+	assert.Contains(t, out, "0 @S2@ SOUR\r\n1 TITL Wawancara keluarga\r\n")
+	assert.True(t, strings.HasSuffix(out, "0 TRLR\r\n"))
+}
+
+func indexOf(lines []string, line string) int {
+	for i, l := range lines {
+		if l == line {
+			return i
+		}
+	}
+	return -1
+}
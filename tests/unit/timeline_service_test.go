@@ -0,0 +1,97 @@
+package unit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/timeline"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func tlDate(y int, m time.Month, d int) *domain.GenDate {
+	return domain.NewExactDate(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+func TestTimelineService_GetPersonTimeline(t *testing.T) {
+	mockPersonRepo := new(mocks.PersonRepository)
+	mockRelRepo := new(mocks.RelationshipRepository)
+	mockEventRepo := new(mocks.EventRepository)
+	mockMediaSvc := new(mocks.MediaService)
+
+	svc := timeline.NewService(mockPersonRepo, mockRelRepo, mockEventRepo, mockMediaSvc)
+	ctx := context.Background()
+
+	personID := uuid.New()
+	fatherID := uuid.New()
+	spouseID := uuid.New()
+	childID := uuid.New()
+	spouseRelID := uuid.New()
+
+	t.Run("Merges Events, Graph And Media Chronologically", func(t *testing.T) {
+		person := &domain.Person{ID: personID, FirstName: "Budi", BirthDate: tlDate(1960, time.March, 10)}
+		father := domain.Person{ID: fatherID, FirstName: "Slamet", DeathDate: tlDate(1990, time.June, 1)}
+		spouse := domain.Person{ID: spouseID, FirstName: "Sri"}
+		child := domain.Person{ID: childID, FirstName: "Andi", BirthDate: tlDate(1988, time.January, 5)}
+
+		marriage, _ := json.Marshal(domain.SpouseMetadata{MarriageDate: tlDate(1985, time.May, 20)})
+		rels := []domain.Relationship{
+			{ID: uuid.New(), PersonA: personID, PersonB: fatherID, Type: domain.RelTypeParent},
+			{ID: spouseRelID, PersonA: personID, PersonB: spouseID, Type: domain.RelTypeSpouse, Metadata: marriage},
+			{ID: uuid.New(), PersonA: childID, PersonB: personID, Type: domain.RelTypeParent},
+		}
+		graduation := domain.Event{ID: uuid.New(), PersonID: &personID, Type: domain.EventTypeGraduation, Title: "Wisuda", Date: tlDate(1983, time.August, 1)}
+		takenAt := time.Date(2000, time.December, 25, 0, 0, 0, 0, time.UTC)
+
+		mockPersonRepo.On("GetByID", ctx, personID).Return(person, nil).Once()
+		mockEventRepo.On("ListByPerson", ctx, personID).Return([]domain.Event{graduation}, nil).Once()
+		mockRelRepo.On("ListByPerson", ctx, personID).Return(rels, nil).Once()
+		mockPersonRepo.On("GetByIDs", ctx, mock.Anything).Return([]domain.Person{father, spouse, child}, nil).Once()
+		mockEventRepo.On("ListByRelationship", ctx, spouseRelID).Return([]domain.Event{}, nil).Once()
+		mockMediaSvc.On("ListDatedByPerson", ctx, personID).Return([]domain.Media{{ID: uuid.New(), FileName: "lebaran.jpg", TakenAt: &takenAt}}, nil).Once()
+
+		result, err := svc.GetPersonTimeline(ctx, personID, domain.Viewer{Role: domain.RoleMember})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 6)
+
+		types := make([]domain.TimelineItemType, len(result.Items))
+		for i, item := range result.Items {
+			types[i] = item.Type
+		}
+		assert.Equal(t, []domain.TimelineItemType{
+			domain.TimelineBirth,
+			domain.TimelineItemType(domain.EventTypeGraduation),
+			domain.TimelineMarriage,
+			domain.TimelineChildBirth,
+			domain.TimelineParentDeath,
+			domain.TimelineMediaTaken,
+		}, types)
+
+		assert.Equal(t, 0, result.Items[0].Age.Years)
+		assert.Equal(t, 25, result.Items[2].Age.Years)
+		assert.Equal(t, 30, result.Items[4].Age.Years)
+		assert.False(t, result.Items[4].Age.Approximate)
+		assert.Equal(t, fatherID, result.Items[4].RelatedPerson.ID)
+
+		assert.Empty(t, result.Items[4].Title, "derived items are worded by the client")
+		assert.Equal(t, "Wisuda", result.Items[1].Title)
+		assert.Equal(t, "lebaran.jpg", result.Items[5].Title)
+	})
+
+	t.Run("Person Not Found", func(t *testing.T) {
+		missingID := uuid.New()
+		mockPersonRepo.On("GetByID", ctx, missingID).Return(nil, nil).Once()
+
+		result, err := svc.GetPersonTimeline(ctx, missingID, domain.Viewer{Role: domain.RoleMember})
+
+		assert.ErrorIs(t, err, domain.ErrPersonNotFound)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,74 @@
+package unit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"silsilah-keluarga/internal/domain"
+	"silsilah-keluarga/internal/service/trash"
+	"silsilah-keluarga/tests/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashService_List(t *testing.T) {
+	mockTrashRepo := new(mocks.TrashRepository)
+	svc := trash.NewService(mockTrashRepo, new(mocks.MediaService), new(mocks.AuditLogRepository), nil, 30*24*time.Hour)
+	ctx := context.Background()
+
+	deletedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	mockTrashRepo.On("List", ctx, mock.Anything).Return([]domain.TrashItem{
+		{EntityType: domain.EntityPerson, EntityID: uuid.New(), Label: "Siti Aminah", DeletedAt: deletedAt},
+	}, int64(1), nil)
+
+	t.Run("Sets purge date", func(t *testing.T) {
+		result, err := svc.List(ctx, domain.TrashListParams{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC), result.Data[0].PurgeAfter)
+		assert.Equal(t, 1, result.Page)
+	})
+
+	t.Run("Rejects unknown type", func(t *testing.T) {
+		place := domain.EntityType("PLACE")
+		_, err := svc.List(ctx, domain.TrashListParams{EntityType: &place})
+		assert.ErrorIs(t, err, domain.ErrInvalidTrashEntity)
+	})
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	mockTrashRepo := new(mocks.TrashRepository)
+	mockMediaSvc := new(mocks.MediaService)
+	mockAuditRepo := new(mocks.AuditLogRepository)
+	svc := trash.NewService(mockTrashRepo, mockMediaSvc, mockAuditRepo, nil, 7*24*time.Hour)
+	ctx := context.Background()
+
+	paths := []string{"media/2024/01/a", "media/2024/02/b"}
+	mockTrashRepo.On("Purge", ctx, mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().Add(-7 * 24 * time.Hour)
+		return before.Sub(cutoff).Abs() < time.Minute
+	})).Return(&domain.PurgeResult{Media: 2, StoragePaths: paths}, nil)
+	mockMediaSvc.On("RemoveObjects", ctx, paths).Return(nil)
+	mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	result, err := svc.Purge(ctx, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Media)
+	mockMediaSvc.AssertExpectations(t)
+}
+
+func TestTrashService_RestoreInvalidType(t *testing.T) {
+	mockTrashRepo := new(mocks.TrashRepository)
+	svc := trash.NewService(mockTrashRepo, new(mocks.MediaService), new(mocks.AuditLogRepository), nil, time.Hour)
+
+	_, err := svc.Restore(context.Background(), uuid.New(), domain.EntityType("USER"), uuid.New())
+
+	assert.ErrorIs(t, err, domain.ErrInvalidTrashEntity)
+	mockTrashRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}